@@ -0,0 +1,121 @@
+package main
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+	"github.com/ZephyrDeng/dev-context/internal/tools"
+)
+
+// sourceStatus is a registered source together with its latest collection.
+// Request headers are left out because they may carry API credentials.
+type sourceStatus struct {
+	Name       string                  `json:"name"`
+	Tools      []string                `json:"tools"`
+	Platform   string                  `json:"platform"`
+	Kind       string                  `json:"kind"`
+	URL        string                  `json:"url"`
+	Enabled    bool                    `json:"enabled"`
+	Collection *collector.SourceStatus `json:"collection,omitempty"`
+}
+
+func newSourceStatus(source sources.Source) sourceStatus {
+	return sourceStatus{
+		Name:     source.Name,
+		Tools:    source.Tools,
+		Platform: source.Platform,
+		Kind:     source.Kind,
+		URL:      source.Config.URL,
+		Enabled:  source.Enabled,
+	}
+}
+
+// newAdminHandler mounts the cache monitor endpoints, the tools health check
+// and the per-source collection status on one mux. /clear and /gc change
+// state, so they require token as a bearer token and are refused entirely
+// when no token is configured.
+func newAdminHandler(monitor *cache.CacheMonitor, toolsManager *tools.ToolsManager, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.Handle("/", monitor.Handler(requireToken(token)))
+
+	mux.HandleFunc("/tools/health", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, toolsManager.HealthCheck(r.Context()))
+	})
+
+	mux.HandleFunc("/sources", func(w http.ResponseWriter, r *http.Request) {
+		collections := make(map[string]collector.SourceStatus)
+		for _, status := range collector.SourceStatuses() {
+			collections[status.Source] = status
+		}
+
+		list := toolsManager.SourceRegistry().List()
+		statuses := make([]sourceStatus, len(list))
+		for i, source := range list {
+			statuses[i] = newSourceStatus(source)
+			if collection, ok := collections[source.Name]; ok {
+				statuses[i].Collection = &collection
+			}
+		}
+		writeJSON(w, statuses)
+	})
+
+	return mux
+}
+
+// requireToken rejects requests that don't carry "Authorization: Bearer <token>".
+func requireToken(token string) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if token == "" {
+				http.Error(w, "Admin token not configured", http.StatusForbidden)
+				return
+			}
+			given, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+				w.Header().Set("WWW-Authenticate", `Bearer realm="dev-context admin"`)
+				http.Error(w, "Unauthorized", http.StatusUnauthorized)
+				return
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(v); err != nil {
+		slog.Warn("Failed to write admin response", logging.Err(err))
+	}
+}
+
+// runAdminServer serves handler on addr until ctx is cancelled.
+func runAdminServer(ctx context.Context, addr string, handler http.Handler) {
+	server := &http.Server{
+		Addr:         addr,
+		Handler:      handler,
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		server.Shutdown(shutdownCtx)
+	}()
+
+	slog.Info("Starting admin endpoints", slog.String("addr", addr))
+	if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		slog.Error("Admin server failed", logging.Err(err))
+	}
+}
@@ -5,18 +5,21 @@ import (
 	"context"
 	"flag"
 	"fmt"
-	"log"
 	"log/slog"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/ZephyrDeng/dev-context/internal/cache"
 	"github.com/ZephyrDeng/dev-context/internal/collector"
+	"github.com/ZephyrDeng/dev-context/internal/config"
 	"github.com/ZephyrDeng/dev-context/internal/formatter"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
 	"github.com/ZephyrDeng/dev-context/internal/mcp"
 	"github.com/ZephyrDeng/dev-context/internal/processor"
 	"github.com/ZephyrDeng/dev-context/internal/tools"
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
 )
 
 var (
@@ -29,9 +32,11 @@ func main() {
 	var (
 		configFile = flag.String("config", "", "Path to configuration file")
 		logLevel   = flag.String("log-level", "info", "Logging level (debug, info, warn, error)")
+		logFormat  = flag.String("log-format", "text", "Log output format (text, json)")
 		showVer    = flag.Bool("version", false, "Show version information")
 		transport  = flag.String("transport", "stdio", "Transport type (stdio, http, websocket)")
 		addr       = flag.String("addr", ":8080", "Address to bind (for http/websocket transports)")
+		adminAddr  = flag.String("admin-addr", "", "Address to bind the admin endpoints (monitoring, health, source status)")
 	)
 	flag.Parse()
 
@@ -41,48 +46,73 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Parse log level
-	var level slog.Level
-	switch *logLevel {
-	case "debug":
-		level = slog.LevelDebug
-	case "info":
-		level = slog.LevelInfo
-	case "warn":
-		level = slog.LevelWarn
-	case "error":
-		level = slog.LevelError
-	default:
-		log.Fatalf("Invalid log level: %s", *logLevel)
+	// Load configuration (defaults < config file < environment variables)
+	if *configFile != "" {
+		slog.Info("Loading configuration", slog.String("path", *configFile))
+	}
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		fatal("Failed to load configuration", logging.Err(err))
 	}
 
-	// Create server configuration
-	config := &mcp.Config{
-		Name:        "github.com/ZephyrDeng/dev-context",
-		Version:     version,
-		Description: "Real-time frontend news MCP server",
-		LogLevel:    level,
+	// Command line flags that were set explicitly take precedence
+	applyFlags := func(cfg *config.Config) {
+		flag.Visit(func(f *flag.Flag) {
+			switch f.Name {
+			case "log-level":
+				cfg.Server.LogLevel = *logLevel
+			case "log-format":
+				cfg.Server.LogFormat = *logFormat
+			case "transport":
+				cfg.Server.Transport = *transport
+			case "addr":
+				cfg.Server.Addr = *addr
+			case "admin-addr":
+				cfg.Server.Admin.Addr = *adminAddr
+			}
+		})
+	}
+	applyFlags(cfg)
+	if err := cfg.Validate(); err != nil {
+		fatal("Invalid configuration", logging.Err(err))
 	}
 
-	// Load configuration from file if specified
-	if *configFile != "" {
-		log.Printf("Loading configuration from: %s", *configFile)
-		// TODO: Implement config file loading when needed
+	// Log to stderr in the configured format; once the MCP server exists,
+	// records are also forwarded to clients that asked for them
+	logHandler := logging.NewHandler(os.Stderr, cfg.Server.LogFormat, cfg.SlogLevel())
+	slog.SetDefault(slog.New(logHandler))
+
+	// Export spans when a trace exporter is configured; otherwise they are discarded
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingConfig(version))
+	if err != nil {
+		fatal("Failed to set up tracing", logging.Err(err))
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			slog.Warn("Failed to flush traces", logging.Err(err))
+		}
+	}()
+	if cfg.Tracing.Exporter == tracing.ExporterOTLP {
+		slog.Info("Exporting traces over OTLP", slog.Float64("sample_ratio", cfg.Tracing.SampleRatio))
 	}
 
 	// Create MCP server
-	server := mcp.NewServer(config)
+	server := mcp.NewServer(cfg.MCPConfig(version))
+	logger := slog.New(logging.NewMCPHandler(logHandler, server.GetServer(), cfg.Server.Name))
+	slog.SetDefault(logger)
 
 	// Add basic capabilities
 	if err := server.AddBasicCapabilities(); err != nil {
-		log.Fatalf("Failed to add basic capabilities: %v", err)
+		fatal("Failed to add basic capabilities", logging.Err(err))
 	}
 
 	// Initialize core components
-	cacheManager := initializeCacheManager()
-	collectorManager := initializeCollectorManager()
-	processor := initializeProcessor()
-	formatterFactory := initializeFormatterFactory()
+	cacheManager := initializeCacheManager(cfg, logger)
+	collectorManager := initializeCollectorManager(logger)
+	processor := initializeProcessor(cfg, logger)
+	formatterFactory := initializeFormatterFactory(cfg)
 
 	// Create tools manager
 	toolsManager := tools.NewToolsManager(
@@ -90,8 +120,9 @@ func main() {
 		collectorManager,
 		processor,
 		formatterFactory,
-		10, // max concurrency
+		cfg.Tools.MaxConcurrency,
 	)
+	toolsManager.SetLogger(logger)
 
 	// Create context that cancels on interrupt signals
 	ctx, cancel := context.WithCancel(context.Background())
@@ -99,86 +130,183 @@ func main() {
 
 	// Register tools to MCP server
 	handler := toolsManager.GetHandler()
+	if err := toolsManager.SourceRegistry().Replace(cfg.SourceDefinitions()); err != nil {
+		fatal("Failed to load sources", logging.Err(err))
+	}
+	slog.Info("Loaded news sources", slog.Int("sources", toolsManager.SourceRegistry().Len()))
+	applyToolTTLs(handler, cfg)
 	if err := handler.RegisterTools(server.GetServer()); err != nil {
-		log.Fatalf("Failed to register MCP tools: %v", err)
+		fatal("Failed to register MCP tools", logging.Err(err))
+	}
+
+	// Monitor cache health and log alerts; the admin endpoints also need the
+	// monitor's metrics even when its alerting is disabled
+	var (
+		monitor *cache.CacheMonitor
+		alerts  *cache.AlertDispatcher
+	)
+	if cfg.Monitoring.Enabled || cfg.Server.Admin.Addr != "" {
+		monitor, alerts = initializeCacheMonitor(cfg, cacheManager, logger)
+		defer alerts.Close()
+	}
+	if cfg.Monitoring.Enabled {
+		if err := monitor.Start(ctx); err != nil {
+			fatal("Failed to start cache monitor", logging.Err(err))
+		}
+		defer monitor.Stop()
+	}
+
+	// Serve monitoring, health and source status on a separate listener
+	if cfg.Server.Admin.Addr != "" {
+		if cfg.Server.Admin.Token == "" {
+			slog.Warn("No admin token configured, /clear and /gc are disabled")
+		}
+		go runAdminServer(ctx, cfg.Server.Admin.Addr, newAdminHandler(monitor, toolsManager, cfg.Server.Admin.Token))
+	}
+
+	// Reload configuration on file changes and SIGHUP without dropping sessions
+	reload := &reloader{
+		current:          cfg,
+		overrides:        applyFlags,
+		cacheManager:     cacheManager,
+		toolsManager:     toolsManager,
+		formatterFactory: formatterFactory,
+		monitor:          monitor,
+		alerts:           alerts,
 	}
+	watcher := config.NewWatcher(*configFile, cfg.Server.ReloadInterval.Duration(), reload.apply)
+	watcher.SetLogger(logger)
+	go watcher.Run(ctx)
 
-	// Warmup cache in background
+	hupChan := make(chan os.Signal, 1)
+	signal.Notify(hupChan, syscall.SIGHUP)
 	go func() {
-		if err := toolsManager.WarmupCache(ctx); err != nil {
-			log.Printf("Cache warmup failed: %v", err)
-		} else {
-			log.Printf("Cache warmup completed successfully")
+		for range hupChan {
+			slog.Info("Received SIGHUP, reloading configuration")
+			watcher.Reload()
 		}
 	}()
 
-	log.Printf("MCP Server initialized with %d tools", len(handler.GetToolsInfo()))
+	// Keep popular queries warm by refreshing them before they expire
+	if refresh := cfg.RefreshConfig(); refresh != nil {
+		slog.Info("Refreshing popular queries before their cache TTL expires", slog.Duration("ahead", refresh.Ahead))
+	}
+	toolsManager.RefreshScheduler().Start(ctx, cfg.RefreshConfig())
+	defer toolsManager.RefreshScheduler().Stop()
+
+	slog.Info("MCP server initialized", slog.Int("tools", len(handler.GetToolsInfo())))
 
 	// Handle graceful shutdown
 	sigChan := make(chan os.Signal, 1)
 	signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
 	go func() {
 		sig := <-sigChan
-		log.Printf("Received signal %v, shutting down...", sig)
+		slog.Info("Received signal, shutting down", slog.String("signal", sig.String()))
 		cancel()
 	}()
 
 	// Start the server based on transport type
-	var err error
-	switch *transport {
+	switch cfg.Server.Transport {
 	case "stdio":
-		log.Printf("Starting MCP server on stdio transport")
+		slog.Info("Starting MCP server on stdio transport")
 		err = server.RunStdio(ctx)
 	case "http":
-		log.Printf("Starting MCP server on HTTP transport at %s", *addr)
-		// TODO: Implement HTTP transport when needed
-		log.Fatalf("HTTP transport not yet implemented")
+		slog.Info("Starting MCP server on HTTP transport", slog.String("addr", cfg.Server.Addr))
+		err = server.RunHTTP(ctx, cfg.Server.Addr)
 	case "websocket":
-		log.Printf("Starting MCP server on WebSocket transport at %s", *addr)
-		// TODO: Implement WebSocket transport when needed
-		log.Fatalf("WebSocket transport not yet implemented")
+		slog.Info("Starting MCP server on WebSocket transport", slog.String("addr", cfg.Server.Addr))
+		err = server.RunWebSocket(ctx, cfg.Server.Addr, cfg.WebSocketConfig())
 	default:
-		log.Fatalf("Unsupported transport type: %s", *transport)
+		fatal("Unsupported transport type", slog.String("transport", cfg.Server.Transport))
 	}
 
 	// Handle server errors
 	if err != nil {
 		if ctx.Err() == context.Canceled {
-			log.Printf("Server shutdown completed")
+			slog.Info("Server shutdown completed")
 		} else {
-			log.Fatalf("Server failed: %v", err)
+			fatal("Server failed", logging.Err(err))
 		}
 	}
 
 	// Graceful cleanup
 	if err := server.Close(); err != nil {
-		log.Printf("Error during server cleanup: %v", err)
+		slog.Warn("Error during server cleanup", logging.Err(err))
 	}
 
-	log.Printf("Server stopped")
+	slog.Info("Server stopped")
+}
+
+// fatal logs msg at error level and exits, like log.Fatalf.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
+func initializeCacheManager(cfg *config.Config, logger *slog.Logger) *cache.CacheManager {
+	logger.Debug("Initializing cache manager")
+	cacheConfig := cfg.CacheConfig()
+	cacheConfig.Logger = logger
+	switch cfg.Cache.Backend {
+	case "disk":
+		storage, err := cache.NewFileStorage(cfg.Cache.Dir, cacheConfig.MaxSize)
+		if err != nil {
+			fatal("Failed to open disk cache", logging.Err(err))
+		}
+		logger.Info("Using disk cache", slog.String("dir", storage.Dir()), slog.Int("items", storage.Size()))
+		cacheConfig.Storage = storage
+	case "redis":
+		client, err := cache.NewRedisClient(context.Background(), cfg.RedisConfig())
+		if err != nil {
+			fatal("Failed to open redis cache", logging.Err(err))
+		}
+		logger.Info("Using redis cache", slog.String("addr", cfg.Cache.Redis.Addr))
+		cacheConfig.Storage = cache.NewRedisStorage(client, cacheConfig.MaxSize)
+		if cfg.Cache.Coalescing.Distributed {
+			logger.Info("Coalescing queries across instances", slog.Any("lock_prefixes", cfg.Cache.Coalescing.LockPrefixes))
+			cacheConfig.CoalescingConfig.Lock = cache.NewRedisLock(client)
+		}
+	}
+	return cache.NewCacheManager(cacheConfig)
 }
 
-func initializeCacheManager() *cache.CacheManager {
-	log.Printf("初始化缓存管理器")
-	config := cache.DefaultCacheConfig()
-	return cache.NewCacheManager(config)
+func initializeCacheMonitor(cfg *config.Config, cacheManager *cache.CacheManager, logger *slog.Logger) (*cache.CacheMonitor, *cache.AlertDispatcher) {
+	metrics := cache.NewDetailedCacheMetrics(cacheManager.MaxSize())
+	monitor := cache.NewCacheMonitor(cacheManager, metrics, cfg.MonitoringConfig())
+	monitor.AddAlertCallback(func(alert *cache.Alert) {
+		logger.Warn("Cache alert",
+			slog.String("alert_type", alert.Type),
+			slog.String("level", alert.Level),
+			slog.String("message", alert.Message),
+		)
+	})
+
+	// Deliver alerts to the configured sinks with per-type dedup and rate limiting
+	alerts, err := cache.NewAlertDispatcher(cfg.AlertingConfig())
+	if err != nil {
+		fatal("Failed to configure alert sinks", logging.Err(err))
+	}
+	alerts.SetLogger(logger)
+	if n := len(cfg.Monitoring.Alerts.Sinks); n > 0 {
+		logger.Info("Sending cache alerts to sinks", slog.Int("sinks", n))
+	}
+	monitor.AddAlertCallback(alerts.Dispatch)
+	return monitor, alerts
 }
 
-func initializeCollectorManager() *collector.CollectorManager {
-	log.Printf("初始化数据采集管理器")
+func initializeCollectorManager(logger *slog.Logger) *collector.CollectorManager {
+	logger.Debug("Initializing collector manager")
 	mgr := collector.NewCollectorManager()
+	mgr.SetLogger(logger)
 	return &mgr
 }
 
-func initializeProcessor() *processor.Processor {
-	return processor.NewProcessor(&processor.Config{
-		EnableSummarization: true,
-		EnableSorting:       true,
-		MaxSummaryLength:    200,
-	})
+func initializeProcessor(cfg *config.Config, logger *slog.Logger) *processor.Processor {
+	p := processor.NewProcessor(cfg.ProcessorConfig())
+	p.SetLogger(logger)
+	return p
 }
 
-func initializeFormatterFactory() *formatter.FormatterFactory {
-	config := formatter.DefaultConfig()
-	return formatter.NewFormatterFactory(config)
+func initializeFormatterFactory(cfg *config.Config) *formatter.FormatterFactory {
+	return formatter.NewFormatterFactory(cfg.FormatterConfig())
 }
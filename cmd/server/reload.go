@@ -0,0 +1,96 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"sync"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+	"github.com/ZephyrDeng/dev-context/internal/config"
+	"github.com/ZephyrDeng/dev-context/internal/formatter"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/tools"
+)
+
+// reloader applies a reloaded configuration to the running components so
+// long-lived MCP sessions survive configuration changes.
+type reloader struct {
+	mu               sync.Mutex
+	current          *config.Config
+	overrides        func(*config.Config)
+	cacheManager     *cache.CacheManager
+	toolsManager     *tools.ToolsManager
+	formatterFactory *formatter.FormatterFactory
+	monitor          *cache.CacheMonitor // nil when monitoring and the admin endpoints are disabled
+	alerts           *cache.AlertDispatcher
+}
+
+// apply swaps in sources, TTLs, the refresh schedule, alert thresholds,
+// alert sinks and formatter defaults from next. Cache keys include a
+// fingerprint of the sources each query used, so queries whose sources
+// changed are collected again while every other cached result keeps being
+// served. Results from fetches still running against the old sources land
+// under the old keys and are never returned. Everything that can fail is
+// checked before the first component is touched, so a rejected configuration
+// leaves the running one intact.
+func (r *reloader) apply(next *config.Config) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Command line flags keep precedence over the reloaded file
+	r.overrides(next)
+	if err := next.Validate(); err != nil {
+		return err
+	}
+
+	for _, key := range config.RestartRequired(r.current, next) {
+		slog.Warn("Configuration change requires a restart to take effect", slog.String("key", key))
+	}
+
+	registry := r.toolsManager.SourceRegistry()
+	if err := registry.Replace(next.SourceDefinitions()); err != nil {
+		return fmt.Errorf("failed to update sources: %w", err)
+	}
+
+	applyToolTTLs(r.toolsManager.GetHandler(), next)
+	r.cacheManager.SetDefaultTTL(next.Cache.TTL.Duration())
+	r.formatterFactory.UpdateConfig(next.FormatterConfig())
+	if !reflect.DeepEqual(r.current.Tools.Refresh, next.Tools.Refresh) {
+		r.toolsManager.RefreshScheduler().Update(next.RefreshConfig())
+		slog.Info("Restarted scheduled refresh with the new configuration")
+	}
+	if r.monitor != nil {
+		if err := r.monitor.SetAlertThresholds(next.AlertThresholds()); err != nil {
+			slog.Warn("Failed to update alert thresholds", logging.Err(err))
+		}
+	}
+	if r.alerts != nil && !reflect.DeepEqual(r.current.Monitoring.Alerts, next.Monitoring.Alerts) {
+		if err := r.alerts.Update(next.AlertingConfig()); err != nil {
+			slog.Warn("Failed to update alert sinks, keeping the previous ones", logging.Err(err))
+		}
+	}
+
+	for _, tool := range config.ChangedTools(r.current, next) {
+		slog.Info("Sources changed, affected queries will be collected again", logging.Tool(tool))
+	}
+
+	r.current = next
+	slog.Info("Loaded news sources", slog.Int("sources", registry.Len()))
+	return nil
+}
+
+// applyToolTTLs sets the per-tool fresh and stale cache TTLs from cfg.
+func applyToolTTLs(handler *tools.Handler, cfg *config.Config) {
+	for tool, ttl := range cfg.ToolCacheTTLs() {
+		if err := handler.SetCacheTTL(tool, ttl); err != nil {
+			// Validate guarantees positive TTLs for known tools
+			slog.Warn("Failed to update cache TTL", logging.Tool(tool), logging.Err(err))
+		}
+	}
+	for tool, ttl := range cfg.ToolStaleTTLs() {
+		if err := handler.SetStaleTTL(tool, ttl); err != nil {
+			slog.Warn("Failed to update stale TTL", logging.Tool(tool), logging.Err(err))
+		}
+	}
+}
@@ -0,0 +1,338 @@
+package cache
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+)
+
+// 告警发送目标类型
+const (
+	AlertSinkWebhook = "webhook" // 以JSON发送 Alert 的通用 webhook
+	AlertSinkSlack   = "slack"   // Slack 兼容的 incoming webhook
+	AlertSinkFile    = "file"    // 按行追加JSON到本地文件
+	AlertSinkSyslog  = "syslog"  // 本机 syslog
+)
+
+// AlertingConfig 告警发送配置
+type AlertingConfig struct {
+	DedupWindow time.Duration     // 同类型同级别的告警在该时间内只发送一次，0 表示不去重
+	RateLimit   int               // 每种类型在 RatePeriod 内最多发送的告警数，0 表示不限
+	RatePeriod  time.Duration     // 限流的统计周期
+	Timeout     time.Duration     // 单次发送的超时时间
+	Sinks       []AlertSinkConfig // 发送目标，每条告警发送到所有目标
+}
+
+// AlertSinkConfig 告警发送目标配置
+type AlertSinkConfig struct {
+	Type    string            // webhook、slack、file、syslog
+	URL     string            // webhook 和 slack 的地址
+	Headers map[string]string // webhook 请求附加的头，例如认证信息
+	Path    string            // file 的文件路径
+	Tag     string            // syslog 的标签
+}
+
+// DefaultAlertingConfig 返回默认告警发送配置，没有发送目标
+func DefaultAlertingConfig() *AlertingConfig {
+	return &AlertingConfig{
+		DedupWindow: 15 * time.Minute,
+		RateLimit:   4,
+		RatePeriod:  time.Hour,
+		Timeout:     10 * time.Second,
+	}
+}
+
+// AlertSink 告警发送目标
+type AlertSink interface {
+	Send(ctx context.Context, alert *Alert) error
+}
+
+// NewAlertSink 根据配置创建告警发送目标
+func NewAlertSink(config AlertSinkConfig) (AlertSink, error) {
+	switch config.Type {
+	case AlertSinkWebhook:
+		return &WebhookSink{URL: config.URL, Headers: config.Headers}, nil
+	case AlertSinkSlack:
+		return &SlackSink{URL: config.URL}, nil
+	case AlertSinkFile:
+		return &FileSink{Path: config.Path}, nil
+	case AlertSinkSyslog:
+		return NewSyslogSink(config.Tag)
+	default:
+		return nil, fmt.Errorf("unknown alert sink type: %s", config.Type)
+	}
+}
+
+// WebhookSink 将告警以JSON POST到指定地址
+type WebhookSink struct {
+	URL     string
+	Headers map[string]string
+	Client  *http.Client // 为nil时使用 http.DefaultClient
+}
+
+// Send 实现 AlertSink
+func (s *WebhookSink) Send(ctx context.Context, alert *Alert) error {
+	return postJSON(ctx, s.Client, s.URL, s.Headers, alert)
+}
+
+// SlackSink 以 Slack incoming webhook 的格式发送告警
+type SlackSink struct {
+	URL    string
+	Client *http.Client // 为nil时使用 http.DefaultClient
+}
+
+// Send 实现 AlertSink
+func (s *SlackSink) Send(ctx context.Context, alert *Alert) error {
+	return postJSON(ctx, s.Client, s.URL, nil, map[string]string{"text": slackText(alert)})
+}
+
+// slackText 生成告警的 Slack 消息文本，元数据按键名排序逐行列出
+func slackText(alert *Alert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "*[%s]* %s: %s", strings.ToUpper(alert.Level), alert.Type, alert.Message)
+
+	keys := make([]string, 0, len(alert.Metadata))
+	for key := range alert.Metadata {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		fmt.Fprintf(&b, "\n• %s: %v", key, alert.Metadata[key])
+	}
+	return b.String()
+}
+
+// postJSON 以JSON POST payload，非2xx响应视为失败
+func postJSON(ctx context.Context, client *http.Client, url string, headers map[string]string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("alert webhook returned %s", resp.Status)
+	}
+	return nil
+}
+
+// FileSink 将告警按行追加JSON到文件，每次发送重新打开文件以兼容日志轮转
+type FileSink struct {
+	Path string
+	mu   sync.Mutex
+}
+
+// Send 实现 AlertSink
+func (s *FileSink) Send(ctx context.Context, alert *Alert) error {
+	line, err := json.Marshal(alert)
+	if err != nil {
+		return fmt.Errorf("failed to encode alert: %w", err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	file, err := os.OpenFile(s.Path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// AlertDispatchStats 告警发送统计
+type AlertDispatchStats struct {
+	Sent        int64 `json:"sent"`        // 已发送的告警数
+	Deduped     int64 `json:"deduped"`     // 因去重未发送的告警数
+	RateLimited int64 `json:"rateLimited"` // 因限流未发送的告警数
+	Failed      int64 `json:"failed"`      // 发送失败的次数，按发送目标计
+}
+
+// AlertDispatcher 将监控告警发送到配置的目标
+//
+// 同类型同级别的告警在 DedupWindow 内只发送一次，级别变化（例如 warning 升级为 critical）
+// 会立即发送；每种类型在 RatePeriod 内最多发送 RateLimit 条。
+type AlertDispatcher struct {
+	mu       sync.Mutex
+	config   AlertingConfig
+	sinks    []AlertSink
+	lastSent map[string]time.Time   // 类型和级别 -> 最近发送时间
+	sent     map[string][]time.Time // 类型 -> RatePeriod 内的发送时间
+	stats    AlertDispatchStats
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// NewAlertDispatcher 创建告警发送器
+func NewAlertDispatcher(config *AlertingConfig) (*AlertDispatcher, error) {
+	d := &AlertDispatcher{
+		lastSent: make(map[string]time.Time),
+		sent:     make(map[string][]time.Time),
+		now:      time.Now,
+		logger:   slog.Default(),
+	}
+	if err := d.Update(config); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// SetLogger 设置记录发送失败的日志记录器，需在注册为告警回调前调用
+func (d *AlertDispatcher) SetLogger(logger *slog.Logger) {
+	d.logger = logging.OrDefault(logger)
+}
+
+// Update 以新配置替换发送目标和限流参数，已有的去重和限流记录保留；
+// 创建发送目标失败时保持原配置
+func (d *AlertDispatcher) Update(config *AlertingConfig) error {
+	if config == nil {
+		config = DefaultAlertingConfig()
+	}
+
+	sinks := make([]AlertSink, 0, len(config.Sinks))
+	for i, sinkConfig := range config.Sinks {
+		sink, err := NewAlertSink(sinkConfig)
+		if err != nil {
+			closeSinks(sinks)
+			return fmt.Errorf("alert sink %d: %w", i, err)
+		}
+		sinks = append(sinks, sink)
+	}
+
+	d.mu.Lock()
+	old := d.sinks
+	d.config = *config
+	d.sinks = sinks
+	d.mu.Unlock()
+
+	closeSinks(old)
+	return nil
+}
+
+// Dispatch 发送告警，可直接作为 AlertCallback 注册到 CacheMonitor
+func (d *AlertDispatcher) Dispatch(alert *Alert) {
+	d.mu.Lock()
+	if !d.allow(alert) {
+		d.mu.Unlock()
+		return
+	}
+	sinks := d.sinks
+	timeout := d.config.Timeout
+	d.mu.Unlock()
+
+	if timeout <= 0 {
+		timeout = DefaultAlertingConfig().Timeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	for _, sink := range sinks {
+		wg.Add(1)
+		go func(sink AlertSink) {
+			defer wg.Done()
+			if err := sink.Send(ctx, alert); err != nil {
+				d.mu.Lock()
+				d.stats.Failed++
+				d.mu.Unlock()
+				d.logger.Warn("发送告警失败", slog.String("alert_type", alert.Type), logging.Err(err))
+			}
+		}(sink)
+	}
+	wg.Wait()
+}
+
+// allow 判断告警是否需要发送并记录发送，调用方需持有锁
+func (d *AlertDispatcher) allow(alert *Alert) bool {
+	now := d.now()
+	alertType := alert.Type
+	if alertType == "" {
+		alertType = alert.Level
+	}
+
+	dedupKey := alertType + ":" + alert.Level
+	if last, exists := d.lastSent[dedupKey]; exists && d.config.DedupWindow > 0 && now.Sub(last) < d.config.DedupWindow {
+		d.stats.Deduped++
+		return false
+	}
+
+	if d.config.RateLimit > 0 {
+		recent := d.sent[alertType][:0]
+		for _, t := range d.sent[alertType] {
+			if now.Sub(t) < d.config.RatePeriod {
+				recent = append(recent, t)
+			}
+		}
+		if len(recent) >= d.config.RateLimit {
+			d.sent[alertType] = recent
+			d.stats.RateLimited++
+			return false
+		}
+		d.sent[alertType] = append(recent, now)
+	}
+
+	d.lastSent[dedupKey] = now
+	d.stats.Sent++
+	return true
+}
+
+// Stats 返回发送统计
+func (d *AlertDispatcher) Stats() AlertDispatchStats {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.stats
+}
+
+// Close 关闭需要释放资源的发送目标
+func (d *AlertDispatcher) Close() error {
+	d.mu.Lock()
+	sinks := d.sinks
+	d.sinks = nil
+	d.mu.Unlock()
+	return closeSinks(sinks)
+}
+
+func closeSinks(sinks []AlertSink) error {
+	var errs []error
+	for _, sink := range sinks {
+		if closer, ok := sink.(io.Closer); ok {
+			if err := closer.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,46 @@
+//go:build !windows && !plan9
+
+package cache
+
+import (
+	"context"
+	"fmt"
+	"log/syslog"
+)
+
+// SyslogSink 将告警写入本机 syslog，级别映射为对应的 syslog 优先级
+type SyslogSink struct {
+	writer *syslog.Writer
+}
+
+// NewSyslogSink 连接本机 syslog，tag 为空时使用 dev-context
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	if tag == "" {
+		tag = "dev-context"
+	}
+	writer, err := syslog.New(syslog.LOG_WARNING|syslog.LOG_DAEMON, tag)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %w", err)
+	}
+	return &SyslogSink{writer: writer}, nil
+}
+
+// Send 实现 AlertSink
+func (s *SyslogSink) Send(ctx context.Context, alert *Alert) error {
+	message := fmt.Sprintf("[%s] %s", alert.Type, alert.Message)
+	switch alert.Level {
+	case "critical":
+		return s.writer.Crit(message)
+	case "error":
+		return s.writer.Err(message)
+	case "warning":
+		return s.writer.Warning(message)
+	default:
+		return s.writer.Info(message)
+	}
+}
+
+// Close 关闭 syslog 连接
+func (s *SyslogSink) Close() error {
+	return s.writer.Close()
+}
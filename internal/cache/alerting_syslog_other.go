@@ -0,0 +1,21 @@
+//go:build windows || plan9
+
+package cache
+
+import (
+	"context"
+	"errors"
+)
+
+// SyslogSink 当前平台不支持 syslog
+type SyslogSink struct{}
+
+// NewSyslogSink 当前平台不支持 syslog，始终返回错误
+func NewSyslogSink(tag string) (*SyslogSink, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
+
+// Send 实现 AlertSink
+func (s *SyslogSink) Send(ctx context.Context, alert *Alert) error {
+	return errors.New("syslog is not supported on this platform")
+}
@@ -0,0 +1,172 @@
+package cache
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// alertReceiver 记录收到的告警请求的本地 webhook
+type alertReceiver struct {
+	mu      sync.Mutex
+	bodies  []map[string]interface{}
+	headers []http.Header
+	status  int
+}
+
+func (r *alertReceiver) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	var body map[string]interface{}
+	json.NewDecoder(req.Body).Decode(&body)
+
+	r.mu.Lock()
+	r.bodies = append(r.bodies, body)
+	r.headers = append(r.headers, req.Header.Clone())
+	status := r.status
+	r.mu.Unlock()
+
+	if status != 0 {
+		w.WriteHeader(status)
+	}
+}
+
+func newTestDispatcher(t *testing.T, config *AlertingConfig) (*AlertDispatcher, *time.Time) {
+	t.Helper()
+	dispatcher, err := NewAlertDispatcher(config)
+	if err != nil {
+		t.Fatalf("NewAlertDispatcher failed: %v", err)
+	}
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	dispatcher.now = func() time.Time { return now }
+	return dispatcher, &now
+}
+
+func TestAlertDispatcherWebhookAndSlack(t *testing.T) {
+	webhook := &alertReceiver{}
+	slack := &alertReceiver{}
+	webhookServer := httptest.NewServer(webhook)
+	defer webhookServer.Close()
+	slackServer := httptest.NewServer(slack)
+	defer slackServer.Close()
+
+	config := DefaultAlertingConfig()
+	config.Sinks = []AlertSinkConfig{
+		{Type: AlertSinkWebhook, URL: webhookServer.URL, Headers: map[string]string{"Authorization": "Bearer hook"}},
+		{Type: AlertSinkSlack, URL: slackServer.URL},
+	}
+	dispatcher, _ := newTestDispatcher(t, config)
+
+	dispatcher.Dispatch(&Alert{
+		Type:     AlertTypeHighMemory,
+		Level:    "critical",
+		Message:  "内存使用率过高: 97.00%",
+		Metadata: map[string]interface{}{"usage": 97},
+	})
+
+	if len(webhook.bodies) != 1 || len(slack.bodies) != 1 {
+		t.Fatalf("Expected one request per sink, got webhook=%d slack=%d", len(webhook.bodies), len(slack.bodies))
+	}
+	if webhook.bodies[0]["type"] != AlertTypeHighMemory || webhook.bodies[0]["level"] != "critical" {
+		t.Errorf("Unexpected webhook payload: %v", webhook.bodies[0])
+	}
+	if got := webhook.headers[0].Get("Authorization"); got != "Bearer hook" {
+		t.Errorf("Expected configured header on webhook request, got %q", got)
+	}
+	text, _ := slack.bodies[0]["text"].(string)
+	if !strings.HasPrefix(text, "*[CRITICAL]* high_memory_usage: 内存使用率过高") || !strings.Contains(text, "usage: 97") {
+		t.Errorf("Unexpected slack text: %q", text)
+	}
+	if stats := dispatcher.Stats(); stats.Sent != 1 || stats.Failed != 0 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+}
+
+func TestAlertDispatcherDedupAndRateLimit(t *testing.T) {
+	receiver := &alertReceiver{}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	config := &AlertingConfig{
+		DedupWindow: 10 * time.Minute,
+		RateLimit:   2,
+		RatePeriod:  time.Hour,
+		Sinks:       []AlertSinkConfig{{Type: AlertSinkWebhook, URL: server.URL}},
+	}
+	dispatcher, now := newTestDispatcher(t, config)
+	alert := func(level string) *Alert {
+		return &Alert{Type: AlertTypeLowHitRate, Level: level, Message: "缓存命中率过低"}
+	}
+
+	dispatcher.Dispatch(alert("warning"))
+	dispatcher.Dispatch(alert("warning")) // 去重
+	dispatcher.Dispatch(alert("critical"))
+	*now = now.Add(15 * time.Minute)
+	dispatcher.Dispatch(alert("warning")) // 去重窗口已过，但本小时已发送2条
+	dispatcher.Dispatch(&Alert{Type: AlertTypeErrorCount, Level: "warning"})
+
+	if len(receiver.bodies) != 3 {
+		t.Fatalf("Expected 3 delivered alerts, got %d", len(receiver.bodies))
+	}
+	stats := dispatcher.Stats()
+	if stats.Sent != 3 || stats.Deduped != 1 || stats.RateLimited != 1 {
+		t.Errorf("Unexpected stats: %+v", stats)
+	}
+
+	*now = now.Add(time.Hour)
+	dispatcher.Dispatch(alert("warning"))
+	if len(receiver.bodies) != 4 {
+		t.Errorf("Expected rate limit to reset after the period, got %d deliveries", len(receiver.bodies))
+	}
+}
+
+func TestAlertDispatcherFailures(t *testing.T) {
+	receiver := &alertReceiver{status: http.StatusInternalServerError}
+	server := httptest.NewServer(receiver)
+	defer server.Close()
+
+	path := filepath.Join(t.TempDir(), "alerts.log")
+	config := DefaultAlertingConfig()
+	config.Sinks = []AlertSinkConfig{
+		{Type: AlertSinkWebhook, URL: server.URL},
+		{Type: AlertSinkFile, Path: path},
+	}
+	dispatcher, now := newTestDispatcher(t, config)
+
+	dispatcher.Dispatch(&Alert{Type: AlertTypeSlowOperations, Level: "warning", Message: "慢操作数量过多: 60"})
+	*now = now.Add(time.Hour)
+	dispatcher.Dispatch(&Alert{Type: AlertTypeSlowOperations, Level: "warning", Message: "慢操作数量过多: 80"})
+
+	if stats := dispatcher.Stats(); stats.Sent != 2 || stats.Failed != 2 {
+		t.Errorf("Expected the failing webhook to be counted, got %+v", stats)
+	}
+
+	// 文件目标不受 webhook 失败影响，每条告警一行
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read alert file: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected 2 lines in alert file, got %d: %q", len(lines), data)
+	}
+	var logged Alert
+	if err := json.Unmarshal([]byte(lines[1]), &logged); err != nil || logged.Message != "慢操作数量过多: 80" {
+		t.Errorf("Unexpected alert file line %q: %v", lines[1], err)
+	}
+}
+
+func TestAlertDispatcherUpdateRejectsUnknownSink(t *testing.T) {
+	dispatcher, _ := newTestDispatcher(t, &AlertingConfig{Sinks: []AlertSinkConfig{{Type: AlertSinkFile, Path: "alerts.log"}}})
+
+	if err := dispatcher.Update(&AlertingConfig{Sinks: []AlertSinkConfig{{Type: "pager"}}}); err == nil {
+		t.Fatal("Expected error for unknown sink type")
+	}
+	if len(dispatcher.sinks) != 1 {
+		t.Errorf("Expected the previous sinks to be kept, got %d", len(dispatcher.sinks))
+	}
+}
@@ -323,9 +323,12 @@ func (cm *CleanupManager) collectCleanupItems() []*CleanupItem {
 			Size:         result.EstimateSize(),
 			AccessCount:  result.AccessCount,
 			LastAccessed: result.Timestamp,
-			CreatedAt:    result.Timestamp, // 简化处理，使用时间戳作为创建时间
+			CreatedAt:    result.Created,
 			Expiry:       expiry,
 		}
+		if item.CreatedAt.IsZero() {
+			item.CreatedAt = result.Timestamp
+		}
 		
 		// 计算清理优先级
 		item.CalculatePriority()
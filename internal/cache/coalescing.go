@@ -3,8 +3,16 @@ package cache
 import (
 	"context"
 	"errors"
+	"log/slog"
+	"strings"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
 )
 
 // CoalescingResult 查询合并结果
@@ -36,12 +44,26 @@ func (g *CoalescingGroup) IsExpired() bool {
 	return time.Since(g.created) > g.timeout
 }
 
+// DistributedLock 跨实例的互斥锁，用于在多个副本之间合并相同的查询
+type DistributedLock interface {
+	// TryLock 尝试获取锁，不阻塞；获取成功时返回释放函数
+	TryLock(ctx context.Context, key string, ttl time.Duration) (unlock func(), acquired bool, err error)
+}
+
 // QueryCoalescer 查询合并器
 type QueryCoalescer struct {
 	groups  map[string]*CoalescingGroup
 	mutex   sync.Mutex
 	timeout time.Duration
 	stats   CoalescingStats
+	
+	// 跨实例合并
+	lock         DistributedLock
+	lockPrefixes []string
+	lockTTL      time.Duration
+	lockRetry    time.Duration
+	
+	logger *slog.Logger
 }
 
 // CoalescingStats 查询合并统计
@@ -51,6 +73,7 @@ type CoalescingStats struct {
 	MergedRequests int64 `json:"merged_requests"` // 合并的请求数
 	ActiveGroups   int64 `json:"active_groups"`   // 当前活跃的合并组数
 	SavedQueries   int64 `json:"saved_queries"`   // 节省的查询次数
+	LockWaits      int64 `json:"lock_waits"`      // 等待其他实例释放分布式锁的次数
 }
 
 // GetSavingsRate 获取节省率
@@ -73,6 +96,7 @@ func (s *CoalescingStats) Reset() {
 	s.MergedRequests = 0
 	s.ActiveGroups = 0
 	s.SavedQueries = 0
+	s.LockWaits = 0
 }
 
 // GetStats 获取统计信息
@@ -85,6 +109,7 @@ func (s *CoalescingStats) GetStats() map[string]interface{} {
 		"merged_requests": s.MergedRequests,
 		"active_groups":   s.ActiveGroups,
 		"saved_queries":   s.SavedQueries,
+		"lock_waits":      s.LockWaits,
 		"savings_rate":    s.GetSavingsRate(),
 	}
 }
@@ -93,13 +118,22 @@ func (s *CoalescingStats) GetStats() map[string]interface{} {
 type CoalescingConfig struct {
 	Timeout      time.Duration `json:"timeout"`       // 合并组超时时间
 	CleanupDelay time.Duration `json:"cleanup_delay"` // 清理延迟时间
+	
+	// Lock 跨实例锁，为nil时只在进程内合并。获取锁后才执行查询，
+	// 等待锁的实例在获得锁后通常会直接命中持有者写入共享缓存的结果。
+	Lock         DistributedLock `json:"-"`
+	LockPrefixes []string        `json:"lock_prefixes"` // 使用跨实例锁的键前缀，为空表示全部键
+	LockTTL      time.Duration   `json:"lock_ttl"`      // 锁的过期时间，持有者崩溃后自动释放
+	LockRetry    time.Duration   `json:"lock_retry"`    // 等待锁时的重试间隔
 }
 
 // DefaultCoalescingConfig 默认查询合并配置
 func DefaultCoalescingConfig() *CoalescingConfig {
 	return &CoalescingConfig{
-		Timeout:      30 * time.Second, // 30秒超时
-		CleanupDelay: 1 * time.Minute,  // 1分钟清理延迟
+		Timeout:      30 * time.Second,       // 30秒超时
+		CleanupDelay: 1 * time.Minute,        // 1分钟清理延迟
+		LockTTL:      1 * time.Minute,        // 1分钟锁过期
+		LockRetry:    200 * time.Millisecond, // 200毫秒重试
 	}
 }
 
@@ -110,8 +144,19 @@ func NewQueryCoalescer(config *CoalescingConfig) *QueryCoalescer {
 	}
 	
 	qc := &QueryCoalescer{
-		groups:  make(map[string]*CoalescingGroup),
-		timeout: config.Timeout,
+		groups:       make(map[string]*CoalescingGroup),
+		timeout:      config.Timeout,
+		lock:         config.Lock,
+		lockPrefixes: config.LockPrefixes,
+		lockTTL:      config.LockTTL,
+		lockRetry:    config.LockRetry,
+		logger:       slog.Default(),
+	}
+	if qc.lockTTL <= 0 {
+		qc.lockTTL = time.Minute
+	}
+	if qc.lockRetry <= 0 {
+		qc.lockRetry = 200 * time.Millisecond
 	}
 	
 	// 启动后台清理协程
@@ -120,8 +165,24 @@ func NewQueryCoalescer(config *CoalescingConfig) *QueryCoalescer {
 	return qc
 }
 
+// setLogger 设置合并器和跨实例锁的日志记录器
+func (qc *QueryCoalescer) setLogger(logger *slog.Logger) {
+	qc.logger = logger
+	if setter, ok := qc.lock.(loggerSetter); ok {
+		setter.setLogger(logger)
+	}
+}
+
 // Execute 执行查询合并逻辑
 func (qc *QueryCoalescer) Execute(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	ctx, span := tracing.Start(ctx, "cache.coalesce", attribute.String("cache.key", key))
+	data, err := qc.execute(ctx, key, fn, span)
+	tracing.End(span, err)
+	return data, err
+}
+
+// execute 加入已有的合并组或创建新的合并组执行查询，加入已有合并组时在 span 上标记
+func (qc *QueryCoalescer) execute(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error), span trace.Span) (interface{}, error) {
 	qc.mutex.Lock()
 	
 	// 检查是否已关闭
@@ -139,6 +200,7 @@ func (qc *QueryCoalescer) Execute(ctx context.Context, key string, fn func(ctx c
 	if group, exists := qc.groups[key]; exists && !group.IsExpired() {
 		// 有相同查询在执行，等待结果
 		group.count++
+		span.SetAttributes(attribute.Bool("cache.coalesced", true))
 		qc.stats.mu.Lock()
 		qc.stats.MergedRequests++
 		qc.stats.SavedQueries++
@@ -204,6 +266,16 @@ func (qc *QueryCoalescer) executeQuery(ctx context.Context, key string, fn func(
 		qc.mutex.Unlock()
 	}()
 	
+	// 其他实例正在执行相同查询时等待其完成
+	unlock, err := qc.acquireDistributedLock(ctx, key)
+	if err != nil {
+		qc.broadcastResult(group, CoalescingResult{err: err})
+		return
+	}
+	if unlock != nil {
+		defer unlock()
+	}
+	
 	// 执行函数获取数据
 	data, err := fn(ctx)
 	if err != nil {
@@ -222,6 +294,53 @@ func (qc *QueryCoalescer) executeQuery(ctx context.Context, key string, fn func(
 	qc.broadcastResult(group, result)
 }
 
+// acquireDistributedLock 获取跨实例锁，未配置锁或键不需要加锁时返回nil
+//
+// 锁服务不可用时记录日志并退化为进程内合并，不影响查询本身。
+func (qc *QueryCoalescer) acquireDistributedLock(ctx context.Context, key string) (func(), error) {
+	if qc.lock == nil || !qc.usesDistributedLock(key) {
+		return nil, nil
+	}
+	
+	waited := false
+	for {
+		unlock, acquired, err := qc.lock.TryLock(ctx, key, qc.lockTTL)
+		if err != nil {
+			qc.logger.WarnContext(ctx, "获取分布式锁失败，退化为进程内合并", logging.CacheKey(key), logging.Err(err))
+			return nil, nil
+		}
+		if acquired {
+			return unlock, nil
+		}
+		
+		if !waited {
+			waited = true
+			qc.stats.mu.Lock()
+			qc.stats.LockWaits++
+			qc.stats.mu.Unlock()
+		}
+		
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(qc.lockRetry):
+		}
+	}
+}
+
+// usesDistributedLock 键是否需要跨实例加锁
+func (qc *QueryCoalescer) usesDistributedLock(key string) bool {
+	if len(qc.lockPrefixes) == 0 {
+		return true
+	}
+	for _, prefix := range qc.lockPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // broadcastResult 广播结果到所有等待的goroutine
 func (qc *QueryCoalescer) broadcastResult(group *CoalescingGroup, result CoalescingResult) {
 	// 发送结果到所有等待的goroutine
@@ -0,0 +1,133 @@
+package cache
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// typeRegistry 可持久化的缓存数据类型注册表
+//
+// 持久化存储只保存JSON，需要记录数据的类型名称才能在读取时还原为原始类型，
+// 使调用方的类型断言（如 cached.(*WeeklyNewsResult)）在重启后仍然成立。
+var typeRegistry = struct {
+	mu     sync.RWMutex
+	byName map[string]reflect.Type
+	byType map[reflect.Type]string
+}{
+	byName: make(map[string]reflect.Type),
+	byType: make(map[reflect.Type]string),
+}
+
+func init() {
+	RegisterType("string", "")
+	RegisterType("bytes", []byte(nil))
+	RegisterType("int", 0)
+	RegisterType("int64", int64(0))
+	RegisterType("float64", float64(0))
+	RegisterType("bool", false)
+	RegisterType("map", map[string]interface{}(nil))
+	RegisterType("slice", []interface{}(nil))
+}
+
+// RegisterType 注册可持久化的缓存数据类型
+//
+// name 会写入存储用于解码，必须稳定且唯一；sample 为该类型的任意值，
+// 指针类型解码后仍为指针。重复注册同一名称会覆盖之前的类型。
+func RegisterType(name string, sample interface{}) {
+	t := reflect.TypeOf(sample)
+	if name == "" || t == nil {
+		panic("cache: RegisterType requires a name and a non-nil sample")
+	}
+
+	typeRegistry.mu.Lock()
+	defer typeRegistry.mu.Unlock()
+
+	if old, exists := typeRegistry.byName[name]; exists {
+		delete(typeRegistry.byType, old)
+	}
+	typeRegistry.byName[name] = t
+	typeRegistry.byType[t] = name
+}
+
+// typeName 返回已注册类型的名称
+func typeName(value interface{}) (string, error) {
+	if value == nil {
+		return "", nil
+	}
+
+	typeRegistry.mu.RLock()
+	defer typeRegistry.mu.RUnlock()
+
+	name, exists := typeRegistry.byType[reflect.TypeOf(value)]
+	if !exists {
+		return "", fmt.Errorf("cache data type %T is not registered for persistence", value)
+	}
+	return name, nil
+}
+
+// diskEntry 缓存项的持久化格式
+type diskEntry struct {
+	Key         string          `json:"key"`
+	Type        string          `json:"type"`
+	Timestamp   time.Time       `json:"timestamp"` // 写入时间，访问时间由存储自行记录
+	Expiry      time.Time       `json:"expiry"`
+	AccessCount int64           `json:"access_count"`
+	Data        json.RawMessage `json:"data"`
+}
+
+// encodeEntry 序列化缓存项
+func encodeEntry(key string, result *CachedResult) ([]byte, error) {
+	name, err := typeName(result.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(result.Data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode cache data for %s: %w", key, err)
+	}
+
+	return json.Marshal(diskEntry{
+		Key:         key,
+		Type:        name,
+		Timestamp:   result.Timestamp,
+		Expiry:      result.Expiry,
+		AccessCount: result.AccessCount,
+		Data:        data,
+	})
+}
+
+// decodeEntry 反序列化缓存项的元数据，数据部分由 decodeData 按需解码
+func decodeEntry(raw []byte) (*diskEntry, error) {
+	var entry diskEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, err
+	}
+	if entry.Key == "" {
+		return nil, fmt.Errorf("cache entry has no key")
+	}
+	return &entry, nil
+}
+
+// decodeData 按注册的类型还原缓存数据
+func (e *diskEntry) decodeData() (interface{}, error) {
+	if e.Type == "" {
+		return nil, nil
+	}
+
+	typeRegistry.mu.RLock()
+	t, exists := typeRegistry.byName[e.Type]
+	typeRegistry.mu.RUnlock()
+	if !exists {
+		return nil, fmt.Errorf("cache data type %q is not registered", e.Type)
+	}
+
+	value := reflect.New(t)
+	if err := json.Unmarshal(e.Data, value.Interface()); err != nil {
+		return nil, fmt.Errorf("failed to decode cache data for %s: %w", e.Key, err)
+	}
+	return value.Elem().Interface(), nil
+}
@@ -0,0 +1,464 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+)
+
+const (
+	fileEntryExt  = ".cache"
+	fileTempExt   = ".tmp"
+	fileStorePerm = 0o600
+)
+
+// DefaultFileStorageDir 默认的磁盘缓存目录
+func DefaultFileStorageDir() string {
+	if dir, err := os.UserCacheDir(); err == nil {
+		return filepath.Join(dir, "dev-context")
+	}
+	return filepath.Join(os.TempDir(), "dev-context-cache")
+}
+
+// fileEntry 磁盘缓存项的内存索引
+type fileEntry struct {
+	path        string
+	created     time.Time // 写入时间
+	timestamp   time.Time // 最后访问时间，用于LRU
+	expiry      time.Time
+	accessCount int64
+	size        int64 // 序列化后的字节数
+}
+
+// FileStorage 基于文件的持久化缓存存储
+//
+// 每个缓存项序列化为目录中的一个文件（文件名为键的SHA-256），内存中只保留索引。
+// 启动时扫描目录重建索引并丢弃过期项，因此重启后无需重新采集。
+// 大小按序列化后的字节数计算，超出 MaxSize 时与 CacheStorage 相同：
+// 先清理过期项，再按最后访问时间淘汰最旧的项。数据类型需通过 RegisterType 注册。
+type FileStorage struct {
+	dir       string
+	index     map[string]*fileEntry
+	mutex     sync.Mutex
+	maxSize   int64
+	totalSize int64
+	logger    *slog.Logger
+}
+
+// NewFileStorage 打开（或创建）磁盘缓存目录，dir 为空时使用 DefaultFileStorageDir
+func NewFileStorage(dir string, maxSize int64) (*FileStorage, error) {
+	if dir == "" {
+		dir = DefaultFileStorageDir()
+	}
+	if maxSize <= 0 {
+		maxSize = 512 * 1024 * 1024 // 默认512MB
+	}
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+
+	s := &FileStorage{
+		dir:     dir,
+		index:   make(map[string]*fileEntry),
+		maxSize: maxSize,
+		logger:  slog.Default(),
+	}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	// 目录中的数据可能来自更大的 MaxSize 配置
+	if s.totalSize > s.maxSize {
+		s.evictLRU(0)
+	}
+	return s, nil
+}
+
+// setLogger 实现 loggerSetter；打开目录时清理损坏文件的日志使用 slog.Default()
+func (s *FileStorage) setLogger(logger *slog.Logger) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.logger = logger
+}
+
+// load 扫描目录重建索引，删除过期、损坏的缓存文件和残留的临时文件
+func (s *FileStorage) load() error {
+	files, err := os.ReadDir(s.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read cache directory: %w", err)
+	}
+
+	now := time.Now()
+	for _, file := range files {
+		if file.IsDir() {
+			continue
+		}
+		path := filepath.Join(s.dir, file.Name())
+
+		switch filepath.Ext(file.Name()) {
+		case fileTempExt:
+			os.Remove(path)
+			continue
+		case fileEntryExt:
+		default:
+			continue
+		}
+
+		raw, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		entry, err := decodeEntry(raw)
+		if err != nil {
+			s.logger.Warn("删除损坏的缓存文件", slog.String("path", path), logging.Err(err))
+			os.Remove(path)
+			continue
+		}
+		if now.After(entry.Expiry) || path != s.pathFor(entry.Key) {
+			os.Remove(path)
+			continue
+		}
+
+		// 访问时会更新文件修改时间，用它恢复LRU顺序
+		timestamp := entry.Timestamp
+		if info, err := file.Info(); err == nil && info.ModTime().After(timestamp) {
+			timestamp = info.ModTime()
+		}
+
+		s.index[entry.Key] = &fileEntry{
+			path:        path,
+			created:     entry.Timestamp,
+			timestamp:   timestamp,
+			expiry:      entry.Expiry,
+			accessCount: entry.AccessCount,
+			size:        int64(len(raw)),
+		}
+		s.totalSize += int64(len(raw))
+	}
+	return nil
+}
+
+// pathFor 返回键对应的文件路径
+func (s *FileStorage) pathFor(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.dir, hex.EncodeToString(sum[:])+fileEntryExt)
+}
+
+// Get 获取缓存项
+//
+// 锁只保护索引：文件的读取和解码在锁外进行，读缓存不会让其他读写排队等待磁盘I/O。
+// 读取期间同一键被重新写入或删除时，按新的索引再读一次。
+func (s *FileStorage) Get(key string) (*CachedResult, bool) {
+	for attempt := 0; attempt < 2; attempt++ {
+		s.mutex.Lock()
+		e, exists := s.index[key]
+		if !exists {
+			s.mutex.Unlock()
+			return nil, false
+		}
+		if time.Now().After(e.expiry) {
+			s.remove(key, e)
+			s.mutex.Unlock()
+			return nil, false
+		}
+		s.mutex.Unlock()
+
+		data, err := s.readData(e.path)
+
+		s.mutex.Lock()
+		if s.index[key] != e {
+			// 读取期间被覆盖或删除
+			s.mutex.Unlock()
+			continue
+		}
+		if err != nil {
+			s.logger.Warn("删除无法读取的缓存项", logging.CacheKey(key), logging.Err(err))
+			s.remove(key, e)
+			s.mutex.Unlock()
+			return nil, false
+		}
+
+		// 更新访问统计
+		now := time.Now()
+		e.accessCount++
+		e.timestamp = now
+		result := &CachedResult{
+			Data:        data,
+			Created:     e.created,
+			Timestamp:   e.timestamp,
+			Expiry:      e.expiry,
+			AccessCount: e.accessCount,
+			Size:        e.size,
+		}
+		s.mutex.Unlock()
+
+		// 文件修改时间记录最后访问时间，重启后用于恢复LRU顺序
+		os.Chtimes(e.path, now, now)
+		return result, true
+	}
+	return nil, false
+}
+
+// readData 读取并解码缓存文件中的数据
+func (s *FileStorage) readData(path string) (interface{}, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	entry, err := decodeEntry(raw)
+	if err != nil {
+		return nil, err
+	}
+	return entry.decodeData()
+}
+
+// Set 设置缓存项
+func (s *FileStorage) Set(key string, data interface{}, ttl time.Duration) error {
+	now := time.Now()
+	raw, err := encodeEntry(key, &CachedResult{
+		Data:        data,
+		Timestamp:   now,
+		Expiry:      now.Add(ttl),
+		AccessCount: 1,
+	})
+	if err != nil {
+		return err
+	}
+	size := int64(len(raw))
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	// 如果单个项目超过最大大小，拒绝缓存
+	if size > s.maxSize {
+		return fmt.Errorf("cache item size %d exceeds maximum cache size %d", size, s.maxSize)
+	}
+
+	// 如果键已存在，先移出索引（文件稍后被覆盖）
+	if old, exists := s.index[key]; exists {
+		delete(s.index, key)
+		s.totalSize -= old.size
+	}
+
+	// 检查大小限制：先清理过期项，仍然不足时执行LRU清理
+	if s.totalSize+size > s.maxSize {
+		s.cleanup()
+		if s.totalSize+size > s.maxSize {
+			s.evictLRU(size)
+		}
+	}
+
+	path := s.pathFor(key)
+	if err := writeFileAtomic(path, raw); err != nil {
+		os.Remove(path)
+		return err
+	}
+
+	s.index[key] = &fileEntry{
+		path:        path,
+		created:     now,
+		timestamp:   now,
+		expiry:      now.Add(ttl),
+		accessCount: 1,
+		size:        size,
+	}
+	s.totalSize += size
+	return nil
+}
+
+// writeFileAtomic 先写临时文件再重命名，避免崩溃时留下不完整的缓存文件
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "entry-*"+fileTempExt)
+	if err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Chmod(fileStorePerm); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
+
+// remove 删除缓存项及其文件（调用时必须持有锁）
+func (s *FileStorage) remove(key string, e *fileEntry) {
+	delete(s.index, key)
+	s.totalSize -= e.size
+	os.Remove(e.path)
+}
+
+// Delete 删除缓存项
+func (s *FileStorage) Delete(key string) bool {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	e, exists := s.index[key]
+	if !exists {
+		return false
+	}
+	s.remove(key, e)
+	return true
+}
+
+// ItemSize 返回缓存项序列化后的字节数，不存在时返回0
+func (s *FileStorage) ItemSize(key string) int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if e, exists := s.index[key]; exists {
+		return e.size
+	}
+	return 0
+}
+
+// DeleteByPrefix 删除所有以指定前缀开头的缓存项，返回删除数量
+func (s *FileStorage) DeleteByPrefix(prefix string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	deleted := 0
+	for key, e := range s.index {
+		if strings.HasPrefix(key, prefix) {
+			s.remove(key, e)
+			deleted++
+		}
+	}
+	return deleted
+}
+
+// Clear 清空所有缓存
+func (s *FileStorage) Clear() {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	for key, e := range s.index {
+		s.remove(key, e)
+	}
+	s.totalSize = 0
+}
+
+// Size 返回缓存项数量
+func (s *FileStorage) Size() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return len(s.index)
+}
+
+// TotalSize 返回缓存总大小
+func (s *FileStorage) TotalSize() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.totalSize
+}
+
+// MaxSize 返回最大缓存大小
+func (s *FileStorage) MaxSize() int64 {
+	return s.maxSize
+}
+
+// Dir 返回缓存目录
+func (s *FileStorage) Dir() string {
+	return s.dir
+}
+
+// EvictExpired 按索引清理过期项，不读取缓存文件，也不改变其他项的访问时间
+func (s *FileStorage) EvictExpired() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cleanup()
+}
+
+// cleanup 清理过期项，返回清理数量（调用时必须持有锁）
+func (s *FileStorage) cleanup() int {
+	now := time.Now()
+	cleaned := 0
+	for key, e := range s.index {
+		if now.After(e.expiry) {
+			s.remove(key, e)
+			cleaned++
+		}
+	}
+	return cleaned
+}
+
+// evictLRU 按最后访问时间淘汰最旧的项，直到可以容纳 neededSpace（调用时必须持有锁）
+func (s *FileStorage) evictLRU(neededSpace int64) {
+	keys := make([]string, 0, len(s.index))
+	for key := range s.index {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return s.index[keys[i]].timestamp.Before(s.index[keys[j]].timestamp)
+	})
+
+	for _, key := range keys {
+		if s.totalSize+neededSpace <= s.maxSize {
+			break
+		}
+		s.remove(key, s.index[key])
+	}
+}
+
+// GetKeys 返回所有缓存键（用于调试和监控）
+func (s *FileStorage) GetKeys() []string {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	keys := make([]string, 0, len(s.index))
+	for key := range s.index {
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// GetStats 获取缓存统计信息
+func (s *FileStorage) GetStats() map[string]interface{} {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	totalAccess := int64(0)
+	expiredCount := 0
+	now := time.Now()
+
+	for _, e := range s.index {
+		totalAccess += e.accessCount
+		if now.After(e.expiry) {
+			expiredCount++
+		}
+	}
+
+	return map[string]interface{}{
+		"backend":        "disk",
+		"total_items":    len(s.index),
+		"total_size":     s.totalSize,
+		"max_size":       s.maxSize,
+		"memory_usage":   float64(s.totalSize) / float64(s.maxSize) * 100,
+		"total_accesses": totalAccess,
+		"expired_items":  expiredCount,
+	}
+}
+
+// Close 关闭存储，缓存文件保留在磁盘上供下次启动使用
+func (s *FileStorage) Close() error {
+	return nil
+}
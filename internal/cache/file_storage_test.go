@@ -0,0 +1,227 @@
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type persistedResult struct {
+	Title     string    `json:"title"`
+	Items     []string  `json:"items"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+func init() {
+	RegisterType("test.persistedResult", &persistedResult{})
+}
+
+func TestFileStorage_SurvivesRestart(t *testing.T) {
+	dir := t.TempDir()
+
+	storage, err := NewFileStorage(dir, 1024*1024)
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := storage.Set("weekly_news:react", &persistedResult{Title: "React", Items: []string{"a", "b"}, UpdatedAt: updated}, time.Hour); err != nil {
+		t.Fatalf("Failed to set struct: %v", err)
+	}
+	if err := storage.Set("plain", "value", time.Hour); err != nil {
+		t.Fatalf("Failed to set string: %v", err)
+	}
+	if err := storage.Set("short", "value", 10*time.Millisecond); err != nil {
+		t.Fatalf("Failed to set short-lived item: %v", err)
+	}
+	storage.Close()
+
+	time.Sleep(20 * time.Millisecond)
+
+	reopened, err := NewFileStorage(dir, 1024*1024)
+	if err != nil {
+		t.Fatalf("Failed to reopen storage: %v", err)
+	}
+	defer reopened.Close()
+
+	if reopened.Size() != 2 {
+		t.Fatalf("Expected 2 items after restart (expired item dropped), got %d", reopened.Size())
+	}
+
+	result, found := reopened.Get("weekly_news:react")
+	if !found {
+		t.Fatal("Expected persisted item to be found")
+	}
+	restored, ok := result.Data.(*persistedResult)
+	if !ok {
+		t.Fatalf("Expected *persistedResult, got %T", result.Data)
+	}
+	if restored.Title != "React" || len(restored.Items) != 2 || !restored.UpdatedAt.Equal(updated) {
+		t.Errorf("Unexpected restored value: %+v", restored)
+	}
+	if time.Until(result.Expiry) <= 0 || time.Until(result.Expiry) > time.Hour {
+		t.Errorf("Expected expiry to be preserved, got %v", result.Expiry)
+	}
+
+	if value, found := reopened.Get("plain"); !found || value.Data != "value" {
+		t.Errorf("Expected string value to be restored, got %v", value)
+	}
+}
+
+func TestFileStorage_LRUEviction(t *testing.T) {
+	dir := t.TempDir()
+	value := strings.Repeat("x", 200)
+
+	// 先测量单个缓存项序列化后的大小
+	probe, _ := NewFileStorage(t.TempDir(), 1024*1024)
+	probe.Set("key-0", value, time.Hour)
+	itemSize := probe.TotalSize()
+
+	// 序列化后的时间戳长度不固定，留出余量但不足以容纳第四项
+	storage, err := NewFileStorage(dir, itemSize*3+itemSize/2)
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		storage.Set("key-"+string(rune('0'+i)), value, time.Hour)
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	// 访问最旧的项，使 key-1 成为最近最少使用
+	if _, found := storage.Get("key-0"); !found {
+		t.Fatal("Expected key-0 to be present")
+	}
+	time.Sleep(5 * time.Millisecond)
+
+	if err := storage.Set("key-3", value, time.Hour); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+
+	if _, found := storage.Get("key-1"); found {
+		t.Error("Expected least recently used key-1 to be evicted")
+	}
+	for _, key := range []string{"key-0", "key-2", "key-3"} {
+		if _, found := storage.Get(key); !found {
+			t.Errorf("Expected %s to be kept", key)
+		}
+	}
+	if storage.TotalSize() > storage.MaxSize() {
+		t.Errorf("Total size %d exceeds max size %d", storage.TotalSize(), storage.MaxSize())
+	}
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*"+fileEntryExt))
+	if len(files) != 3 {
+		t.Errorf("Expected evicted file to be removed, found %d files", len(files))
+	}
+
+	if err := storage.Set("huge", strings.Repeat("x", int(itemSize*4)), time.Hour); err == nil {
+		t.Error("Expected error for item larger than max size")
+	}
+}
+
+func TestFileStorage_DeleteAndClear(t *testing.T) {
+	dir := t.TempDir()
+	storage, _ := NewFileStorage(dir, 1024*1024)
+
+	storage.Set("weekly_news:a", "a", time.Hour)
+	storage.Set("weekly_news:b", "b", time.Hour)
+	storage.Set("trending_repos:c", "c", time.Hour)
+
+	if deleted := storage.DeleteByPrefix("weekly_news:"); deleted != 2 {
+		t.Errorf("Expected 2 deleted items, got %d", deleted)
+	}
+	if !storage.Delete("trending_repos:c") || storage.Delete("trending_repos:c") {
+		t.Error("Delete should report whether the key existed")
+	}
+
+	storage.Set("x", "x", time.Hour)
+	storage.Clear()
+	if storage.Size() != 0 || storage.TotalSize() != 0 {
+		t.Errorf("Expected empty storage, got %d items / %d bytes", storage.Size(), storage.TotalSize())
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("Expected cache directory to be empty, found %d files", len(files))
+	}
+}
+
+func TestFileStorage_SkipsCorruptAndUnregistered(t *testing.T) {
+	dir := t.TempDir()
+
+	os.WriteFile(filepath.Join(dir, "broken"+fileEntryExt), []byte("{not json"), 0600)
+	os.WriteFile(filepath.Join(dir, "leftover"+fileTempExt), []byte("partial"), 0600)
+
+	storage, err := NewFileStorage(dir, 1024*1024)
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+	if storage.Size() != 0 {
+		t.Errorf("Expected corrupt file to be ignored, got %d items", storage.Size())
+	}
+	if files, _ := os.ReadDir(dir); len(files) != 0 {
+		t.Errorf("Expected corrupt and temporary files to be removed, found %d", len(files))
+	}
+
+	type unregistered struct{ A int }
+	if err := storage.Set("k", unregistered{A: 1}, time.Hour); err == nil {
+		t.Error("Expected error for unregistered data type")
+	}
+}
+
+func TestCacheManager_WithFileStorage(t *testing.T) {
+	dir := t.TempDir()
+
+	storage, _ := NewFileStorage(dir, 1024*1024)
+	cm := NewCacheManager(&CacheConfig{
+		MaxSize:         1024 * 1024,
+		TTL:             time.Hour,
+		CleanupInterval: time.Minute,
+		Storage:         storage,
+	})
+	cm.Set("persisted", "value")
+	cm.Close()
+
+	storage, _ = NewFileStorage(dir, 1024*1024)
+	cm = NewCacheManager(&CacheConfig{
+		MaxSize:         1024 * 1024,
+		TTL:             time.Hour,
+		CleanupInterval: time.Minute,
+		Storage:         storage,
+	})
+	defer cm.Close()
+
+	if value, found := cm.Get("persisted"); !found || value != "value" {
+		t.Errorf("Expected value to survive manager restart, got %v", value)
+	}
+	if stats := cm.GetStats(); stats["backend"] != "disk" {
+		t.Errorf("Expected disk backend in stats, got %v", stats["backend"])
+	}
+}
+
+func TestFileStorage_EvictExpiredKeepsLRUOrder(t *testing.T) {
+	storage, err := NewFileStorage(t.TempDir(), 1024*1024)
+	if err != nil {
+		t.Fatalf("Failed to open storage: %v", err)
+	}
+	defer storage.Close()
+
+	storage.Set("expired", "value", time.Millisecond)
+	storage.Set("live", "value", time.Hour)
+	before := storage.index["live"].timestamp
+
+	// 删除文件后仍能按索引清理，说明清理不读取缓存文件
+	os.Remove(storage.index["expired"].path)
+	time.Sleep(5 * time.Millisecond)
+
+	if cleaned := storage.EvictExpired(); cleaned != 1 {
+		t.Errorf("Expected 1 expired item to be evicted, got %d", cleaned)
+	}
+	if storage.Size() != 1 {
+		t.Errorf("Expected 1 item left, got %d", storage.Size())
+	}
+	if got := storage.index["live"]; got.timestamp != before || got.accessCount != 1 {
+		t.Error("Evicting expired items must not touch the access time of live items")
+	}
+}
@@ -3,8 +3,15 @@ package cache
 import (
 	"context"
 	"errors"
+	"fmt"
+	"log/slog"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
 )
 
 // CacheMetrics 缓存性能指标
@@ -15,6 +22,8 @@ type CacheMetrics struct {
 	Sets        int64     `json:"sets"`
 	Deletes     int64     `json:"deletes"`
 	Evictions   int64     `json:"evictions"`
+	StaleHits   int64     `json:"stale_hits"`   // 超过软TTL后返回旧结果的次数
+	Refreshes   int64     `json:"refreshes"`    // 后台刷新成功的次数
 	StartTime   time.Time `json:"start_time"`
 	LastReset   time.Time `json:"last_reset"`
 }
@@ -41,6 +50,8 @@ func (m *CacheMetrics) Reset() {
 	m.Sets = 0
 	m.Deletes = 0
 	m.Evictions = 0
+	m.StaleHits = 0
+	m.Refreshes = 0
 	m.LastReset = time.Now()
 }
 
@@ -50,26 +61,37 @@ func (m *CacheMetrics) GetStats() map[string]interface{} {
 	defer m.mu.RUnlock()
 	
 	return map[string]interface{}{
-		"hits":      m.Hits,
-		"misses":    m.Misses,
-		"sets":      m.Sets,
-		"deletes":   m.Deletes,
-		"evictions": m.Evictions,
-		"hit_rate":  m.HitRate(),
-		"uptime":    time.Since(m.StartTime).Seconds(),
+		"hits":       m.Hits,
+		"misses":     m.Misses,
+		"sets":       m.Sets,
+		"deletes":    m.Deletes,
+		"evictions":  m.Evictions,
+		"stale_hits": m.StaleHits,
+		"refreshes":  m.Refreshes,
+		"hit_rate":   m.HitRate(),
+		"uptime":     time.Since(m.StartTime).Seconds(),
 	}
 }
 
 // CacheManager 缓存管理器
 type CacheManager struct {
-	storage            *CacheStorage
+	storage            Storage
 	metrics            *CacheMetrics
+	detailed           *DetailedCacheMetrics
+	detailedMu         sync.RWMutex
 	queryCoalescer     *QueryCoalescer
 	concurrencyManager *ConcurrencyManager
 	ttl                time.Duration
+	ttlMu              sync.RWMutex
 	cleanupInterval    time.Duration
 	stopCleanup        chan struct{}
 	cleanupStopped     chan struct{}
+	logger             *slog.Logger
+	
+	// 后台刷新（stale-while-revalidate）
+	refreshCtx    context.Context
+	refreshCancel context.CancelFunc
+	refreshWG     sync.WaitGroup
 }
 
 // CacheConfig 缓存配置
@@ -79,6 +101,13 @@ type CacheConfig struct {
 	CleanupInterval    time.Duration          `json:"cleanup_interval"`    // 清理间隔
 	CoalescingConfig   *CoalescingConfig      `json:"coalescing_config"`   // 查询合并配置
 	ConcurrencyConfig  *ConcurrencyConfig     `json:"concurrency_config"`  // 并发控制配置
+	Storage            Storage                `json:"-"`                   // 存储后端，为nil时使用内存存储
+	Logger             *slog.Logger           `json:"-"`                   // 日志记录器，为nil时使用 slog.Default()，同时用于存储后端和跨实例锁
+}
+
+// loggerSetter 由需要记录日志的存储后端和跨实例锁实现，NewCacheManager 注入 CacheConfig.Logger
+type loggerSetter interface {
+	setLogger(logger *slog.Logger)
 }
 
 // DefaultCacheConfig 默认缓存配置
@@ -98,8 +127,17 @@ func NewCacheManager(config *CacheConfig) *CacheManager {
 		config = DefaultCacheConfig()
 	}
 	
+	storage := config.Storage
+	if storage == nil {
+		storage = NewCacheStorage(config.MaxSize)
+	}
+	logger := logging.OrDefault(config.Logger)
+	if setter, ok := storage.(loggerSetter); ok {
+		setter.setLogger(logger)
+	}
+	
 	cm := &CacheManager{
-		storage:            NewCacheStorage(config.MaxSize),
+		storage:            storage,
 		metrics:            &CacheMetrics{StartTime: time.Now()},
 		queryCoalescer:     NewQueryCoalescer(config.CoalescingConfig),
 		concurrencyManager: NewConcurrencyManager(config.ConcurrencyConfig),
@@ -107,7 +145,10 @@ func NewCacheManager(config *CacheConfig) *CacheManager {
 		cleanupInterval:    config.CleanupInterval,
 		stopCleanup:        make(chan struct{}),
 		cleanupStopped:     make(chan struct{}),
+		logger:             logger,
 	}
+	cm.queryCoalescer.setLogger(logger)
+	cm.refreshCtx, cm.refreshCancel = context.WithCancel(context.Background())
 	
 	// 启动后台清理协程
 	go cm.backgroundCleanup()
@@ -117,12 +158,25 @@ func NewCacheManager(config *CacheConfig) *CacheManager {
 
 // Get 获取缓存数据
 func (cm *CacheManager) Get(key string) (interface{}, bool) {
+	result, found := cm.get(key)
+	if !found {
+		return nil, false
+	}
+	return result.Data, true
+}
+
+// GetWithAge 获取缓存数据及其写入后经过的时间
+func (cm *CacheManager) GetWithAge(key string) (interface{}, time.Duration, bool) {
+	result, found := cm.get(key)
+	if !found {
+		return nil, 0, false
+	}
+	return result.Data, result.Age(), true
+}
+
+// get 从存储读取缓存项并记录指标
+func (cm *CacheManager) get(key string) (*CachedResult, bool) {
 	start := time.Now()
-	defer func() {
-		// 记录响应时间
-		_ = time.Since(start)
-	}()
-	
 	result, found := cm.storage.Get(key)
 	
 	cm.metrics.mu.Lock()
@@ -133,43 +187,75 @@ func (cm *CacheManager) Get(key string) (interface{}, bool) {
 	}
 	cm.metrics.mu.Unlock()
 	
-	if found {
-		return result.Data, true
+	if detailed := cm.detailedMetrics(); detailed != nil {
+		size := int64(0)
+		if found {
+			size = result.EstimateSize()
+		}
+		detailed.RecordGet(key, found, time.Since(start), size)
 	}
 	
-	return nil, false
+	return result, found
 }
 
 // Set 设置缓存数据
 func (cm *CacheManager) Set(key string, data interface{}) error {
-	err := cm.storage.Set(key, data, cm.ttl)
-	
-	cm.metrics.mu.Lock()
-	cm.metrics.Sets++
-	cm.metrics.mu.Unlock()
-	
-	return err
+	return cm.SetWithTTL(key, data, cm.DefaultTTL())
 }
 
 // SetWithTTL 设置具有自定义TTL的缓存数据
 func (cm *CacheManager) SetWithTTL(key string, data interface{}, ttl time.Duration) error {
+	start := time.Now()
 	err := cm.storage.Set(key, data, ttl)
 	
 	cm.metrics.mu.Lock()
 	cm.metrics.Sets++
 	cm.metrics.mu.Unlock()
 	
+	if detailed := cm.detailedMetrics(); detailed != nil {
+		if err != nil {
+			detailed.RecordError(err)
+		} else {
+			detailed.RecordSet(key, time.Since(start), cm.storage.ItemSize(key), fmt.Sprintf("%T", data))
+		}
+	}
+	
 	return err
 }
 
 // Delete 删除缓存数据
 func (cm *CacheManager) Delete(key string) bool {
+	start := time.Now()
+	detailed := cm.detailedMetrics()
+	
+	// 删除前读取后端记录的大小，与写入时统计的是同一个数
+	size := int64(0)
+	if detailed != nil {
+		size = cm.storage.ItemSize(key)
+	}
 	deleted := cm.storage.Delete(key)
 	
 	if deleted {
 		cm.metrics.mu.Lock()
 		cm.metrics.Deletes++
 		cm.metrics.mu.Unlock()
+		
+		if detailed != nil {
+			detailed.RecordDelete(key, time.Since(start), size)
+		}
+	}
+	
+	return deleted
+}
+
+// DeleteByPrefix 删除所有以指定前缀开头的缓存键，返回删除数量
+func (cm *CacheManager) DeleteByPrefix(prefix string) int {
+	deleted := cm.storage.DeleteByPrefix(prefix)
+	
+	if deleted > 0 {
+		cm.metrics.mu.Lock()
+		cm.metrics.Deletes += int64(deleted)
+		cm.metrics.mu.Unlock()
 	}
 	
 	return deleted
@@ -183,6 +269,13 @@ func (cm *CacheManager) Clear() {
 
 // GetOrSet 获取缓存，如果不存在则执行函数并缓存结果
 func (cm *CacheManager) GetOrSet(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	return cm.GetOrSetWithTTL(ctx, key, cm.DefaultTTL(), fn)
+}
+
+// GetOrSetWithTTL 与 GetOrSet 相同，但使用自定义TTL缓存结果
+//
+// 相同键的并发调用只执行一次 fn；配置了跨实例锁时，多个实例之间同样只有一个执行 fn。
+func (cm *CacheManager) GetOrSetWithTTL(ctx context.Context, key string, ttl time.Duration, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
 	// 先尝试获取缓存
 	if data, found := cm.Get(key); found {
 		return data, nil
@@ -190,7 +283,7 @@ func (cm *CacheManager) GetOrSet(ctx context.Context, key string, fn func(ctx co
 	
 	// 使用新的查询合并器
 	return cm.queryCoalescer.Execute(ctx, key, func(ctx context.Context) (interface{}, error) {
-		// 再次检查缓存（双重检查）
+		// 再次检查缓存（双重检查），其他实例可能已写入共享缓存
 		if data, found := cm.Get(key); found {
 			return data, nil
 		}
@@ -202,7 +295,7 @@ func (cm *CacheManager) GetOrSet(ctx context.Context, key string, fn func(ctx co
 		}
 		
 		// 缓存结果
-		if setErr := cm.Set(key, data); setErr != nil {
+		if setErr := cm.SetWithTTL(key, data, ttl); setErr != nil {
 			// 缓存失败，但返回数据
 			return data, nil
 		}
@@ -211,6 +304,117 @@ func (cm *CacheManager) GetOrSet(ctx context.Context, key string, fn func(ctx co
 	})
 }
 
+// GetOrRevalidate 以 stale-while-revalidate 方式获取缓存，返回数据及其写入后经过的时间
+//
+// 写入不超过 softTTL 的结果直接返回；超过 softTTL 的结果同样立即返回，同时在后台
+// 通过查询合并器刷新（同一键只刷新一次）；缓存项在 hardTTL 后过期，此时同步执行 fn。
+func (cm *CacheManager) GetOrRevalidate(ctx context.Context, key string, softTTL, hardTTL time.Duration, fn func(ctx context.Context) (interface{}, error)) (interface{}, time.Duration, error) {
+	if hardTTL < softTTL {
+		hardTTL = softTTL
+	}
+	
+	ctx, span := tracing.Start(ctx, "cache.lookup", attribute.String("cache.key", key))
+	defer span.End()
+	
+	if data, age, found := cm.GetWithAge(key); found {
+		stale := age >= softTTL
+		span.SetAttributes(
+			attribute.Bool("cache.hit", true),
+			attribute.Bool("cache.stale", stale),
+			attribute.Float64("cache.age_seconds", age.Seconds()),
+		)
+		if stale {
+			cm.metrics.mu.Lock()
+			cm.metrics.StaleHits++
+			cm.metrics.mu.Unlock()
+			
+			cm.revalidate(key, softTTL, hardTTL, fn)
+		}
+		return data, age, nil
+	}
+	
+	span.SetAttributes(attribute.Bool("cache.hit", false))
+	data, err := cm.GetOrSetWithTTL(ctx, key, hardTTL, fn)
+	tracing.RecordError(span, err)
+	return data, 0, err
+}
+
+// revalidate 在后台刷新缓存项，已有相同键的查询在执行时不重复刷新
+func (cm *CacheManager) revalidate(key string, softTTL, hardTTL time.Duration, fn func(ctx context.Context) (interface{}, error)) {
+	if cm.refreshCtx.Err() != nil || cm.queryCoalescer.HasActiveGroup(key) {
+		return
+	}
+	
+	cm.refreshWG.Add(1)
+	go func() {
+		defer cm.refreshWG.Done()
+		
+		ctx := cm.refreshCtx
+		if cm.queryCoalescer.timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, cm.queryCoalescer.timeout)
+			defer cancel()
+		}
+		
+		_, err := cm.queryCoalescer.Execute(ctx, key, func(ctx context.Context) (interface{}, error) {
+			// 等待期间可能已被其他调用或其他实例刷新
+			if result, found := cm.storage.Get(key); found && result.Age() < softTTL {
+				return result.Data, nil
+			}
+			
+			data, err := fn(ctx)
+			if err != nil {
+				return nil, err
+			}
+			if err := cm.SetWithTTL(key, data, hardTTL); err != nil {
+				return nil, err
+			}
+			
+			cm.metrics.mu.Lock()
+			cm.metrics.Refreshes++
+			cm.metrics.mu.Unlock()
+			return data, nil
+		})
+		if err != nil {
+			cm.logger.Warn("后台刷新缓存失败，继续使用旧结果", logging.CacheKey(key), logging.Err(err))
+		}
+	}()
+}
+
+// Refresh 在缓存项写入超过 maxAge 时执行 fn 并以 ttl 写入缓存，用于在结果变旧前主动刷新；
+// maxAge 为0时总是刷新。返回之后缓存项的年龄，刚刷新时为0
+//
+// 仍然新鲜的缓存项（包括磁盘上保留的或其他实例写入共享后端的结果）不重复获取。
+// 相同键的并发调用（包括正在进行的后台刷新）通过查询合并器只执行一次；失败时保留现有结果。
+func (cm *CacheManager) Refresh(ctx context.Context, key string, ttl, maxAge time.Duration, fn func(ctx context.Context) (interface{}, error)) (time.Duration, error) {
+	if result, found := cm.storage.Get(key); found && result.Age() < maxAge {
+		return result.Age(), nil
+	}
+	
+	var age time.Duration
+	_, err := cm.queryCoalescer.Execute(ctx, key, func(ctx context.Context) (interface{}, error) {
+		// 等待期间可能已被其他调用或其他实例刷新
+		if result, found := cm.storage.Get(key); found && result.Age() < maxAge {
+			age = result.Age()
+			return result.Data, nil
+		}
+		
+		data, err := fn(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if err := cm.SetWithTTL(key, data, ttl); err != nil {
+			return nil, err
+		}
+		
+		cm.metrics.mu.Lock()
+		cm.metrics.Refreshes++
+		cm.metrics.mu.Unlock()
+		return data, nil
+	})
+	return age, err
+}
+
 // GetOrSetWithConcurrency 在并发控制下获取或设置缓存
 func (cm *CacheManager) GetOrSetWithConcurrency(ctx context.Context, key string, fn func(ctx context.Context) (interface{}, error)) (interface{}, error) {
 	// 先尝试获取缓存
@@ -277,21 +481,9 @@ func (cm *CacheManager) backgroundCleanup() {
 	}
 }
 
-// cleanupExpired 清理过期项
+// cleanupExpired 清理过期项，由存储后端自行判断过期，不经过 Get 以免改变LRU顺序
 func (cm *CacheManager) cleanupExpired() {
-	keys := cm.storage.GetKeys()
-	cleaned := 0
-	
-	for _, key := range keys {
-		if result, exists := cm.storage.Get(key); !exists {
-			// 项目在Get时已被清理（过期）
-			cleaned++
-		} else if result.IsExpired() {
-			// 手动清理过期项
-			cm.storage.Delete(key)
-			cleaned++
-		}
-	}
+	cleaned := cm.storage.EvictExpired()
 	
 	if cleaned > 0 {
 		cm.metrics.mu.Lock()
@@ -357,6 +549,19 @@ func (cm *CacheManager) Close() error {
 		// 超时等待
 	}
 	
+	// 取消并等待后台刷新
+	cm.refreshCancel()
+	refreshDone := make(chan struct{})
+	go func() {
+		cm.refreshWG.Wait()
+		close(refreshDone)
+	}()
+	select {
+	case <-refreshDone:
+	case <-time.After(5 * time.Second):
+		// 超时等待
+	}
+	
 	// 关闭查询合并器
 	if err := cm.queryCoalescer.Close(); err != nil {
 		return err
@@ -367,6 +572,11 @@ func (cm *CacheManager) Close() error {
 		return err
 	}
 	
+	// 关闭存储后端
+	if err := cm.storage.Close(); err != nil {
+		return err
+	}
+	
 	return nil
 }
 
@@ -402,6 +612,38 @@ func (cm *CacheManager) SetCleanupInterval(interval time.Duration) {
 	go cm.backgroundCleanup()
 }
 
+// SetDetailedMetrics 设置详细指标，之后的 Get、Set 和 Delete 操作都会记录到其中，传入nil停止记录
+func (cm *CacheManager) SetDetailedMetrics(metrics *DetailedCacheMetrics) {
+	if metrics != nil {
+		metrics.MemoryUsage.SetCurrentSize(cm.storage.TotalSize())
+	}
+	
+	cm.detailedMu.Lock()
+	defer cm.detailedMu.Unlock()
+	cm.detailed = metrics
+}
+
+// detailedMetrics 返回当前的详细指标，未设置时为nil
+func (cm *CacheManager) detailedMetrics() *DetailedCacheMetrics {
+	cm.detailedMu.RLock()
+	defer cm.detailedMu.RUnlock()
+	return cm.detailed
+}
+
+// DefaultTTL 返回 Set 使用的默认TTL
+func (cm *CacheManager) DefaultTTL() time.Duration {
+	cm.ttlMu.RLock()
+	defer cm.ttlMu.RUnlock()
+	return cm.ttl
+}
+
+// SetDefaultTTL 设置 Set 使用的默认TTL，只影响之后写入的缓存项
+func (cm *CacheManager) SetDefaultTTL(ttl time.Duration) {
+	cm.ttlMu.Lock()
+	defer cm.ttlMu.Unlock()
+	cm.ttl = ttl
+}
+
 // ForceCleanup 强制执行清理
 func (cm *CacheManager) ForceCleanup() {
 	cm.cleanupExpired()
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -356,6 +357,235 @@ func TestCacheStorage_LRUEviction(t *testing.T) {
 	}
 }
 
+func TestCacheManager_DeleteByPrefix(t *testing.T) {
+	cm := NewCacheManager(nil)
+	defer cm.Close()
+	
+	cm.Set("weekly_news:2024-01-01", "a")
+	cm.Set("weekly_news:2024-01-08", "b")
+	cm.Set("trending_repos:javascript", "c")
+	
+	if deleted := cm.DeleteByPrefix("weekly_news:"); deleted != 2 {
+		t.Fatalf("Expected 2 deleted keys, got %d", deleted)
+	}
+	if _, found := cm.Get("weekly_news:2024-01-01"); found {
+		t.Fatal("Expected prefixed key to be deleted")
+	}
+	if _, found := cm.Get("trending_repos:javascript"); !found {
+		t.Fatal("Expected unrelated key to be kept")
+	}
+	if cm.TotalSize() <= 0 {
+		t.Fatalf("Expected remaining size to be tracked, got %d", cm.TotalSize())
+	}
+	if stats := cm.GetStats(); stats["deletes"].(int64) != 2 {
+		t.Fatalf("Expected 2 deletes, got %v", stats["deletes"])
+	}
+}
+
+func TestCacheManager_DetailedMetricsTrackStoredSize(t *testing.T) {
+	cm := NewCacheManager(nil)
+	defer cm.Close()
+	
+	detailed := NewDetailedCacheMetrics(cm.storage.MaxSize())
+	cm.SetDetailedMetrics(detailed)
+	
+	currentSize := func() int64 {
+		return detailed.MemoryUsage.GetStats()["current_size_bytes"].(int64)
+	}
+	
+	cm.Set("a", strings.Repeat("x", 1000))
+	cm.Set("b", []string{"one", "two", "three"})
+	if got, want := currentSize(), cm.TotalSize(); got != want {
+		t.Fatalf("Expected tracked size %d to match storage size %d after sets", got, want)
+	}
+	
+	cm.Delete("a")
+	if got, want := currentSize(), cm.TotalSize(); got != want {
+		t.Fatalf("Expected tracked size %d to match storage size %d after delete", got, want)
+	}
+}
+
+func TestCacheManager_SetDefaultTTL(t *testing.T) {
+	cm := NewCacheManager(&CacheConfig{
+		MaxSize:         1024 * 1024,
+		TTL:             time.Hour,
+		CleanupInterval: time.Minute,
+	})
+	defer cm.Close()
+	
+	cm.SetDefaultTTL(50 * time.Millisecond)
+	if cm.DefaultTTL() != 50*time.Millisecond {
+		t.Fatalf("Expected updated TTL, got %v", cm.DefaultTTL())
+	}
+	
+	cm.Set("short-lived", "value")
+	time.Sleep(100 * time.Millisecond)
+	if _, found := cm.Get("short-lived"); found {
+		t.Fatal("Expected item to expire with the updated TTL")
+	}
+}
+
+func TestCacheManager_GetOrRevalidate(t *testing.T) {
+	cm := NewCacheManager(&CacheConfig{
+		MaxSize:         1024 * 1024,
+		TTL:             time.Hour,
+		CleanupInterval: time.Minute,
+	})
+	defer cm.Close()
+	
+	var mu sync.Mutex
+	calls := 0
+	fetch := func(ctx context.Context) (interface{}, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		calls++
+		return fmt.Sprintf("v%d", calls), nil
+	}
+	fetchCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return calls
+	}
+	
+	soft, hard := time.Minute, time.Hour
+	ctx := context.Background()
+	
+	// 未命中时同步获取
+	data, age, err := cm.GetOrRevalidate(ctx, "swr", soft, hard, fetch)
+	if err != nil || data != "v1" || age != 0 {
+		t.Fatalf("Expected fresh fetch, got %v age=%v err=%v", data, age, err)
+	}
+	
+	// 软TTL内直接命中
+	data, _, _ = cm.GetOrRevalidate(ctx, "swr", soft, hard, fetch)
+	if data != "v1" || fetchCount() != 1 {
+		t.Fatalf("Expected fresh cache hit, got %v after %d fetches", data, fetchCount())
+	}
+	
+	// 超过软TTL后立即返回旧结果，并在后台刷新
+	backdate(t, cm, "swr", 2*soft)
+	data, age, err = cm.GetOrRevalidate(ctx, "swr", soft, hard, fetch)
+	if err != nil || data != "v1" || age < soft {
+		t.Fatalf("Expected stale result with its age, got %v age=%v err=%v", data, age, err)
+	}
+	
+	cm.refreshWG.Wait()
+	if value, found := cm.Get("swr"); !found || value != "v2" {
+		t.Fatalf("Expected background refresh to replace the stale value, got %v", value)
+	}
+	if fetchCount() != 2 {
+		t.Errorf("Expected exactly one background refresh, got %d fetches", fetchCount())
+	}
+	
+	stats := cm.GetStats()
+	if stats["stale_hits"].(int64) != 1 || stats["refreshes"].(int64) != 1 {
+		t.Errorf("Expected 1 stale hit and 1 refresh, got %v and %v", stats["stale_hits"], stats["refreshes"])
+	}
+}
+
+func TestCacheManager_RevalidateFailureKeepsStale(t *testing.T) {
+	cm := NewCacheManager(&CacheConfig{
+		MaxSize:         1024 * 1024,
+		TTL:             time.Hour,
+		CleanupInterval: time.Minute,
+	})
+	
+	cm.SetWithTTL("swr", "old", time.Hour)
+	backdate(t, cm, "swr", time.Minute)
+	
+	started := make(chan struct{})
+	failing := func(ctx context.Context) (interface{}, error) {
+		close(started)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	
+	data, _, err := cm.GetOrRevalidate(context.Background(), "swr", time.Second, time.Hour, failing)
+	if err != nil || data != "old" {
+		t.Fatalf("Expected stale value, got %v err=%v", data, err)
+	}
+	<-started
+	
+	// 关闭时取消进行中的刷新，旧结果保留
+	if err := cm.Close(); err != nil {
+		t.Fatalf("Close failed: %v", err)
+	}
+	if result, found := cm.storage.Get("swr"); !found || result.Data != "old" {
+		t.Errorf("Expected stale value to be kept after failed refresh, got %v", result)
+	}
+}
+
+func TestCacheManager_Refresh(t *testing.T) {
+	cm := NewCacheManager(nil)
+	defer cm.Close()
+	
+	cm.Set("popular", "old")
+	
+	// maxAge 为0时不论现有结果是否过期都会重新获取
+	_, err := cm.Refresh(context.Background(), "popular", time.Hour, 0, func(ctx context.Context) (interface{}, error) {
+		return "new", nil
+	})
+	if err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	if value, _ := cm.Get("popular"); value != "new" {
+		t.Fatalf("Expected refreshed value, got %v", value)
+	}
+	
+	// 失败时保留现有结果
+	_, err = cm.Refresh(context.Background(), "popular", time.Hour, 0, func(ctx context.Context) (interface{}, error) {
+		return nil, fmt.Errorf("source down")
+	})
+	if err == nil {
+		t.Fatal("Expected refresh error")
+	}
+	if value, _ := cm.Get("popular"); value != "new" {
+		t.Fatalf("Expected value to be kept after failed refresh, got %v", value)
+	}
+	if stats := cm.GetStats(); stats["refreshes"].(int64) != 1 {
+		t.Errorf("Expected 1 refresh, got %v", stats["refreshes"])
+	}
+	
+	// 写入时间未超过 maxAge 的结果不重新获取
+	backdate(t, cm, "popular", 10*time.Minute)
+	calls := 0
+	fetch := func(ctx context.Context) (interface{}, error) {
+		calls++
+		return "newer", nil
+	}
+	age, err := cm.Refresh(context.Background(), "popular", time.Hour, 55*time.Minute, fetch)
+	if err != nil || calls != 0 || age < 10*time.Minute {
+		t.Fatalf("Expected fresh entry to be kept, got age %v, %d calls, err %v", age, calls, err)
+	}
+	
+	backdate(t, cm, "popular", time.Hour)
+	age, err = cm.Refresh(context.Background(), "popular", time.Hour, 55*time.Minute, fetch)
+	if err != nil || calls != 1 || age != 0 {
+		t.Fatalf("Expected old entry to be refreshed, got age %v, %d calls, err %v", age, calls, err)
+	}
+	if value, _ := cm.Get("popular"); value != "newer" {
+		t.Errorf("Expected refreshed value, got %v", value)
+	}
+}
+
+// backdate 将内存缓存项的写入时间提前，模拟已经存在了一段时间的结果
+func backdate(t *testing.T, cm *CacheManager, key string, age time.Duration) {
+	t.Helper()
+	
+	storage, ok := cm.storage.(*CacheStorage)
+	if !ok {
+		t.Fatalf("Expected in-memory storage, got %T", cm.storage)
+	}
+	storage.mutex.Lock()
+	defer storage.mutex.Unlock()
+	
+	result, exists := storage.data[key]
+	if !exists {
+		t.Fatalf("Expected key %q to be cached", key)
+	}
+	result.Created = time.Now().Add(-age)
+}
+
 func BenchmarkCacheStorage_Get(b *testing.B) {
 	storage := NewCacheStorage(1024 * 1024)
 	storage.Set("benchmark-key", "benchmark-value", 5*time.Minute)
@@ -6,6 +6,8 @@ import (
 	"sort"
 	"sync"
 	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/metrics"
 )
 
 // ResponseTimeStats 响应时间统计
@@ -17,6 +19,7 @@ type ResponseTimeStats struct {
 	count       int64
 	minTime     time.Duration
 	maxTime     time.Duration
+	histogram   *metrics.Histogram // 全部响应时间的累计分布（秒），不受样本窗口限制
 }
 
 // NewResponseTimeStats 创建响应时间统计实例
@@ -30,6 +33,7 @@ func NewResponseTimeStats(maxSamples int) *ResponseTimeStats {
 		maxSamples: maxSamples,
 		minTime:    time.Duration(0),
 		maxTime:    time.Duration(0),
+		histogram:  metrics.NewHistogram(metrics.DefBuckets),
 	}
 }
 
@@ -40,6 +44,7 @@ func (s *ResponseTimeStats) Record(duration time.Duration) {
 	
 	s.count++
 	s.totalTime += duration
+	s.histogram.Observe(duration.Seconds())
 	
 	// 更新最小最大值
 	if s.minTime == 0 || duration < s.minTime {
@@ -113,6 +118,11 @@ func (s *ResponseTimeStats) GetStats() map[string]interface{} {
 	return stats
 }
 
+// Histogram 返回响应时间的累计分布，用于输出 Prometheus 直方图
+func (s *ResponseTimeStats) Histogram(labels ...metrics.Label) metrics.HistogramSample {
+	return s.histogram.Snapshot(labels...)
+}
+
 // Clear 清除所有统计数据
 func (s *ResponseTimeStats) Clear() {
 	s.mu.Lock()
@@ -123,6 +133,7 @@ func (s *ResponseTimeStats) Clear() {
 	s.count = 0
 	s.minTime = 0
 	s.maxTime = 0
+	s.histogram.Reset()
 }
 
 // MemoryUsageStats 内存使用统计
@@ -194,6 +205,26 @@ func (s *MemoryUsageStats) RecordDeallocation(size int64) {
 	}
 }
 
+// SetCurrentSize 按存储的实际大小校正当前内存使用
+//
+// 过期清理和LRU淘汰发生在存储内部，不经过 RecordDeallocation，需要定期校正。
+func (s *MemoryUsageStats) SetCurrentSize(size int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	
+	s.currentSize = size
+	if s.currentSize > s.peakSize {
+		s.peakSize = s.currentSize
+	}
+	
+	if len(s.sizeHistory) >= s.maxHistory {
+		copy(s.sizeHistory, s.sizeHistory[1:])
+		s.sizeHistory[len(s.sizeHistory)-1] = s.currentSize
+	} else {
+		s.sizeHistory = append(s.sizeHistory, s.currentSize)
+	}
+}
+
 // GetStats 获取内存使用统计
 func (s *MemoryUsageStats) GetStats() map[string]interface{} {
 	s.mu.RLock()
@@ -10,6 +10,8 @@ import (
 	"strings"
 	"sync"
 	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/metrics"
 )
 
 // MonitoringConfig 监控配置
@@ -56,8 +58,19 @@ func DefaultMonitoringConfig() *MonitoringConfig {
 // AlertCallback 告警回调函数类型
 type AlertCallback func(alert *Alert)
 
+// 告警类型，告警发送按类型去重和限流
+const (
+	AlertTypeHTTPServer     = "http_server"
+	AlertTypeHealthCheck    = "health_check"
+	AlertTypeLowHitRate     = "low_hit_rate"
+	AlertTypeHighMemory     = "high_memory_usage"
+	AlertTypeErrorCount     = "error_count"
+	AlertTypeSlowOperations = "slow_operations"
+)
+
 // Alert 告警信息
 type Alert struct {
+	Type      string                 `json:"type"`       // 告警类型，见 AlertType 常量
 	Level     string                 `json:"level"`      // info, warning, error, critical
 	Message   string                 `json:"message"`
 	Timestamp time.Time              `json:"timestamp"`
@@ -104,6 +117,11 @@ func NewCacheMonitor(cm *CacheManager, metrics *DetailedCacheMetrics, config *Mo
 		debugData:      make(map[string]interface{}),
 	}
 	
+	// 缓存管理器的操作记录到监控使用的详细指标中
+	if cm != nil && metrics != nil {
+		cm.SetDetailedMetrics(metrics)
+	}
+	
 	if config.EnableHTTPEndpoints {
 		monitor.setupHTTPServer()
 	}
@@ -129,7 +147,7 @@ func (m *CacheMonitor) Start(ctx context.Context) error {
 			defer m.wg.Done()
 			
 			if err := m.httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-				m.recordAlert("error", fmt.Sprintf("HTTP监控服务器错误: %v", err), nil)
+				m.recordAlert(AlertTypeHTTPServer, "error", fmt.Sprintf("HTTP监控服务器错误: %v", err), nil)
 			}
 		}()
 	}
@@ -188,20 +206,31 @@ func (m *CacheMonitor) Stop() error {
 	return nil
 }
 
+// Handler 返回监控端点的HTTP处理器，用于挂载到其他HTTP服务器上
+//
+// protect 包装会修改缓存或触发GC的端点（/clear、/gc），为nil时不做处理
+func (m *CacheMonitor) Handler(protect func(http.Handler) http.Handler) http.Handler {
+	if protect == nil {
+		protect = func(h http.Handler) http.Handler { return h }
+	}
+	
+	mux := http.NewServeMux()
+	mux.HandleFunc("/health", m.handleHealth)
+	mux.HandleFunc("/metrics", m.handleMetrics)
+	mux.HandleFunc("/stats", m.handleStats)
+	mux.HandleFunc("/debug", m.handleDebug)
+	mux.HandleFunc("/config", m.handleConfig)
+	mux.HandleFunc("/keys", m.handleKeys)
+	mux.HandleFunc("/keys/", m.handleKeyDetails)
+	mux.Handle("/clear", protect(http.HandlerFunc(m.handleClear)))
+	mux.Handle("/gc", protect(http.HandlerFunc(m.handleGC)))
+	return mux
+}
+
 // setupHTTPServer 设置HTTP监控服务器
 func (m *CacheMonitor) setupHTTPServer() {
 	m.serverMux = http.NewServeMux()
-	
-	// 注册监控端点
-	m.serverMux.HandleFunc("/health", m.handleHealth)
-	m.serverMux.HandleFunc("/metrics", m.handleMetrics)
-	m.serverMux.HandleFunc("/stats", m.handleStats)
-	m.serverMux.HandleFunc("/debug", m.handleDebug)
-	m.serverMux.HandleFunc("/config", m.handleConfig)
-	m.serverMux.HandleFunc("/keys", m.handleKeys)
-	m.serverMux.HandleFunc("/keys/", m.handleKeyDetails)
-	m.serverMux.HandleFunc("/clear", m.handleClear)
-	m.serverMux.HandleFunc("/gc", m.handleGC)
+	m.serverMux.Handle("/", m.Handler(nil))
 	
 	m.httpServer = &http.Server{
 		Addr:    fmt.Sprintf(":%d", m.config.HTTPPort),
@@ -241,13 +270,19 @@ func (m *CacheMonitor) handleHealth(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(health)
 }
 
-// 处理指标端点
+// 处理指标端点，默认输出 Prometheus 格式（包括 metrics.Default 中采集器和工具层的指标），
+// Accept 为 application/json 或带 format=json 参数时返回详细统计的JSON
 func (m *CacheMonitor) handleMetrics(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodGet {
 		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 	
+	if r.URL.Query().Get("format") != "json" && !strings.Contains(r.Header.Get("Accept"), "application/json") {
+		metrics.Handler(m, metrics.Default).ServeHTTP(w, r)
+		return
+	}
+	
 	stats := m.metrics.GetDetailedStats()
 	
 	w.Header().Set("Content-Type", "application/json")
@@ -509,6 +544,7 @@ func (m *CacheMonitor) healthChecker(ctx context.Context) {
 
 // collectMetrics 收集指标
 func (m *CacheMonitor) collectMetrics() {
+	m.metrics.MemoryUsage.SetCurrentSize(m.cacheManager.TotalSize())
 	stats := m.metrics.GetDetailedStats()
 	
 	// 检查告警条件
@@ -532,17 +568,22 @@ func (m *CacheMonitor) performHealthCheck() {
 		metadata := map[string]interface{}{
 			"health_status": health,
 		}
-		m.recordAlert("warning", "缓存健康状态异常", metadata)
+		m.recordAlert(AlertTypeHealthCheck, "warning", "缓存健康状态异常", metadata)
 	}
 }
 
 // checkAlerts 检查告警条件
 func (m *CacheMonitor) checkAlerts(stats map[string]interface{}) {
+	m.mu.RLock()
 	thresholds := m.config.AlertThresholds
+	m.mu.RUnlock()
+	if thresholds == nil {
+		return
+	}
 	
 	// 检查命中率
 	if hitRate, ok := stats["hit_rate"].(float64); ok && hitRate < thresholds.LowHitRatePercent {
-		m.recordAlert("warning", fmt.Sprintf("缓存命中率过低: %.2f%%", hitRate), nil)
+		m.recordAlert(AlertTypeLowHitRate, "warning", fmt.Sprintf("缓存命中率过低: %.2f%%", hitRate), nil)
 	}
 	
 	// 检查内存使用
@@ -551,23 +592,24 @@ func (m *CacheMonitor) checkAlerts(stats map[string]interface{}) {
 		if memUsage > 95 {
 			level = "critical"
 		}
-		m.recordAlert(level, fmt.Sprintf("内存使用率过高: %.2f%%", memUsage), nil)
+		m.recordAlert(AlertTypeHighMemory, level, fmt.Sprintf("内存使用率过高: %.2f%%", memUsage), nil)
 	}
 	
 	// 检查错误数量
 	if errorCount, ok := stats["error_count"].(int64); ok && errorCount > thresholds.MaxErrorCount {
-		m.recordAlert("error", fmt.Sprintf("错误数量过多: %d", errorCount), nil)
+		m.recordAlert(AlertTypeErrorCount, "error", fmt.Sprintf("错误数量过多: %d", errorCount), nil)
 	}
 	
 	// 检查慢操作
 	if slowOps, ok := stats["slow_operation_count"].(int64); ok && slowOps > thresholds.MaxSlowOperations {
-		m.recordAlert("warning", fmt.Sprintf("慢操作数量过多: %d", slowOps), nil)
+		m.recordAlert(AlertTypeSlowOperations, "warning", fmt.Sprintf("慢操作数量过多: %d", slowOps), nil)
 	}
 }
 
 // recordAlert 记录告警
-func (m *CacheMonitor) recordAlert(level, message string, metadata map[string]interface{}) {
+func (m *CacheMonitor) recordAlert(alertType, level, message string, metadata map[string]interface{}) {
 	alert := &Alert{
+		Type:      alertType,
 		Level:     level,
 		Message:   message,
 		Timestamp: time.Now(),
@@ -674,6 +716,23 @@ func (m *CacheMonitor) UpdateConfiguration(config *MonitoringConfig) error {
 	return nil
 }
 
+// SetAlertThresholds 替换告警阈值，下一次指标收集即生效
+func (m *CacheMonitor) SetAlertThresholds(thresholds *AlertThresholds) error {
+	if thresholds == nil {
+		return fmt.Errorf("thresholds cannot be nil")
+	}
+	
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	
+	// 复制配置，避免修改调用方或其他持有旧配置的对象
+	config := *m.config
+	t := *thresholds
+	config.AlertThresholds = &t
+	m.config = &config
+	return nil
+}
+
 // GetMonitoringStats 获取监控器自身的统计
 func (m *CacheMonitor) GetMonitoringStats() map[string]interface{} {
 	m.mu.RLock()
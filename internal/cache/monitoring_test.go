@@ -178,6 +178,7 @@ func TestCacheMonitorHTTPMetricsEndpoint(t *testing.T) {
 	metrics.RecordSet("test_key", 5*time.Millisecond, 100, "string")
 	
 	req := httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/json")
 	rr := httptest.NewRecorder()
 	monitor.handleMetrics(rr, req)
 	
@@ -200,6 +201,50 @@ func TestCacheMonitorHTTPMetricsEndpoint(t *testing.T) {
 	}
 }
 
+func TestCacheMonitorPrometheusMetrics(t *testing.T) {
+	config := DefaultCacheConfig()
+	cm := NewCacheManager(config)
+	defer cm.Close()
+	
+	detailed := NewDetailedCacheMetrics(config.MaxSize)
+	monitor := NewCacheMonitor(cm, detailed, nil)
+	
+	cm.Set("key", "value")
+	cm.Get("key")
+	cm.Get("missing")
+	
+	req := httptest.NewRequest("GET", "/metrics", nil)
+	rr := httptest.NewRecorder()
+	monitor.handleMetrics(rr, req)
+	
+	if ct := rr.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected Prometheus text format, got %q", ct)
+	}
+	body := rr.Body.String()
+	for _, want := range []string{
+		"# TYPE devcontext_cache_hits_total counter\ndevcontext_cache_hits_total 1\n",
+		"devcontext_cache_misses_total 1\n",
+		`devcontext_cache_operation_duration_seconds_count{operation="get"} 2`,
+		`devcontext_cache_operation_duration_seconds_bucket{operation="set",le="+Inf"} 1`,
+		"# TYPE devcontext_cache_memory_bytes gauge\n",
+		"devcontext_cache_items 1\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics to contain %q, got:\n%s", want, body)
+		}
+	}
+	
+	// OpenMetrics 格式的计数器族名不带 _total 后缀，并以 EOF 结束
+	req = httptest.NewRequest("GET", "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rr = httptest.NewRecorder()
+	monitor.handleMetrics(rr, req)
+	body = rr.Body.String()
+	if !strings.Contains(body, "# TYPE devcontext_cache_hits counter\n") || !strings.HasSuffix(body, "# EOF\n") {
+		t.Errorf("Unexpected OpenMetrics output:\n%s", body)
+	}
+}
+
 func TestCacheMonitorHTTPKeysEndpoint(t *testing.T) {
 	config := DefaultCacheConfig()
 	cm := NewCacheManager(config)
@@ -479,9 +524,9 @@ func TestCacheMonitorAlertDeduplication(t *testing.T) {
 	
 	// 连续记录相同的告警
 	message := "测试告警"
-	monitor.recordAlert("warning", message, nil)
-	monitor.recordAlert("warning", message, nil)
-	monitor.recordAlert("warning", message, nil)
+	monitor.recordAlert(AlertTypeLowHitRate, "warning", message, nil)
+	monitor.recordAlert(AlertTypeLowHitRate, "warning", message, nil)
+	monitor.recordAlert(AlertTypeLowHitRate, "warning", message, nil)
 	
 	// 等待异步处理
 	time.Sleep(10 * time.Millisecond)
@@ -566,6 +611,42 @@ func TestCacheMonitorConfigurationManagement(t *testing.T) {
 	}
 }
 
+func TestCacheMonitorSetAlertThresholds(t *testing.T) {
+	config := DefaultCacheConfig()
+	cm := NewCacheManager(config)
+	defer cm.Close()
+	
+	metrics := NewDetailedCacheMetrics(config.MaxSize)
+	originalConfig := DefaultMonitoringConfig()
+	monitor := NewCacheMonitor(cm, metrics, originalConfig)
+	
+	alerts := make(chan *Alert, 1)
+	monitor.AddAlertCallback(func(alert *Alert) {
+		alerts <- alert
+	})
+	
+	thresholds := *originalConfig.AlertThresholds
+	thresholds.LowHitRatePercent = 5.0
+	if err := monitor.SetAlertThresholds(&thresholds); err != nil {
+		t.Fatalf("Failed to set thresholds: %v", err)
+	}
+	if originalConfig.AlertThresholds.LowHitRatePercent != 20.0 {
+		t.Error("SetAlertThresholds must not modify the original configuration")
+	}
+	
+	// 命中率10%高于新阈值5%，不应告警
+	monitor.checkAlerts(map[string]interface{}{"hit_rate": 10.0})
+	select {
+	case alert := <-alerts:
+		t.Errorf("Unexpected alert after raising threshold: %s", alert.Message)
+	case <-time.After(20 * time.Millisecond):
+	}
+	
+	if err := monitor.SetAlertThresholds(nil); err == nil {
+		t.Error("Expected error when setting nil thresholds")
+	}
+}
+
 func TestCacheMonitoringStats(t *testing.T) {
 	config := DefaultCacheConfig()
 	cm := NewCacheManager(config)
@@ -603,14 +684,9 @@ func TestCacheMonitorSimulateLoad(t *testing.T) {
 	metrics := NewDetailedCacheMetrics(config.MaxSize)
 	monitor := NewCacheMonitor(cm, metrics, nil)
 	
-	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
-	defer cancel()
-	
-	// 模拟负载：每秒10次操作
-	go monitor.SimulateLoad(ctx, 200*time.Millisecond, 10)
-	
-	// 等待负载执行
-	time.Sleep(250 * time.Millisecond)
+	// 模拟负载：每秒100次操作，同步执行到结束。
+	// 操作按 get、get、set、delete 轮换，至少需要三次操作才会写入缓存
+	monitor.SimulateLoad(context.Background(), 200*time.Millisecond, 100)
 	
 	// 验证缓存中有一些数据
 	if cm.Size() == 0 {
@@ -637,7 +713,7 @@ func BenchmarkCacheMonitorRecordAlert(b *testing.B) {
 	
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
-		monitor.recordAlert("info", fmt.Sprintf("test alert %d", i), nil)
+		monitor.recordAlert(AlertTypeHealthCheck, "info", fmt.Sprintf("test alert %d", i), nil)
 	}
 }
 
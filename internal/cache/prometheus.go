@@ -0,0 +1,49 @@
+package cache
+
+import (
+	"github.com/ZephyrDeng/dev-context/internal/metrics"
+)
+
+// Collect 以 Prometheus 格式输出缓存指标，实现 metrics.Collector
+//
+// 操作计数来自缓存管理器，响应时间直方图和错误计数来自详细指标，
+// 内存和缓存项数量在抓取时从存储后端读取。
+func (m *CacheMonitor) Collect(w *metrics.Writer) {
+	cm := m.cacheManager
+	stats := cm.GetStats()
+	counter := func(name, help, key string) {
+		value, _ := stats[key].(int64)
+		w.Counter(name, help, metrics.Sample{Value: float64(value)})
+	}
+
+	counter("devcontext_cache_hits_total", "Cache lookups that found a result.", "hits")
+	counter("devcontext_cache_misses_total", "Cache lookups that found no result.", "misses")
+	counter("devcontext_cache_sets_total", "Results written to the cache.", "sets")
+	counter("devcontext_cache_deletes_total", "Results deleted from the cache.", "deletes")
+	counter("devcontext_cache_evictions_total", "Expired results removed from the cache.", "evictions")
+	counter("devcontext_cache_stale_hits_total", "Stale results served while revalidating.", "stale_hits")
+	counter("devcontext_cache_refreshes_total", "Successful background and scheduled refreshes.", "refreshes")
+
+	m.metrics.mu.RLock()
+	errorCount, slowCount := m.metrics.ErrorCount, m.metrics.SlowOperationCount
+	m.metrics.mu.RUnlock()
+	w.Counter("devcontext_cache_errors_total", "Cache operations that failed.",
+		metrics.Sample{Value: float64(errorCount)})
+	w.Counter("devcontext_cache_slow_operations_total", "Cache operations slower than the slow operation threshold.",
+		metrics.Sample{Value: float64(slowCount)})
+
+	w.Histogram("devcontext_cache_operation_duration_seconds", "Cache operation latency.",
+		m.metrics.GetResponseTime.Histogram(metrics.Label{Name: "operation", Value: "get"}),
+		m.metrics.SetResponseTime.Histogram(metrics.Label{Name: "operation", Value: "set"}),
+		m.metrics.DeleteResponseTime.Histogram(metrics.Label{Name: "operation", Value: "delete"}),
+	)
+
+	w.Gauge("devcontext_cache_memory_bytes", "Bytes used by cached results as reported by the storage backend.",
+		metrics.Sample{Value: float64(cm.TotalSize())})
+	w.Gauge("devcontext_cache_memory_max_bytes", "Configured maximum cache size in bytes.",
+		metrics.Sample{Value: float64(cm.MaxSize())})
+	w.Gauge("devcontext_cache_items", "Number of cached results.",
+		metrics.Sample{Value: float64(cm.Size())})
+	w.Gauge("devcontext_cache_coalescing_active_groups", "Queries currently being collected on behalf of coalesced callers.",
+		metrics.Sample{Value: float64(cm.ActiveCoalescingGroupsCount())})
+}
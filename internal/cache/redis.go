@@ -0,0 +1,243 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"time"
+)
+
+// RedisConfig Redis连接配置
+type RedisConfig struct {
+	Addr        string        `json:"addr"`         // host:port
+	Password    string        `json:"password"`     // 为空时不执行AUTH
+	DB          int           `json:"db"`           // SELECT 的数据库编号
+	KeyPrefix   string        `json:"key_prefix"`   // 所有键的命名空间前缀，多个服务共享Redis时避免冲突
+	PoolSize    int           `json:"pool_size"`    // 空闲连接池大小
+	DialTimeout time.Duration `json:"dial_timeout"` // 建立连接超时
+	IOTimeout   time.Duration `json:"io_timeout"`   // 单条命令读写超时
+}
+
+// DefaultRedisConfig 默认Redis配置
+func DefaultRedisConfig() *RedisConfig {
+	return &RedisConfig{
+		Addr:        "127.0.0.1:6379",
+		KeyPrefix:   "dev-context:",
+		PoolSize:    8,
+		DialTimeout: 5 * time.Second,
+		IOTimeout:   5 * time.Second,
+	}
+}
+
+// RedisError Redis服务器返回的错误回复
+type RedisError string
+
+// Error 实现error接口
+func (e RedisError) Error() string {
+	return "redis: " + string(e)
+}
+
+// RedisClient 使用RESP协议的最小Redis客户端，带连接池，可并发使用
+type RedisClient struct {
+	config *RedisConfig
+	idle   chan *redisConn
+}
+
+// redisConn 单个Redis连接
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+// NewRedisClient 创建Redis客户端并检查连接
+func NewRedisClient(ctx context.Context, config *RedisConfig) (*RedisClient, error) {
+	if config == nil {
+		config = DefaultRedisConfig()
+	}
+	poolSize := config.PoolSize
+	if poolSize <= 0 {
+		poolSize = 1
+	}
+
+	c := &RedisClient{
+		config: config,
+		idle:   make(chan *redisConn, poolSize),
+	}
+	if _, err := c.Do(ctx, "PING"); err != nil {
+		return nil, fmt.Errorf("failed to connect to redis at %s: %w", config.Addr, err)
+	}
+	return c, nil
+}
+
+// Do 执行一条命令，返回值为 string（状态回复）、int64、[]byte（批量回复）、
+// []interface{}（数组回复）或 nil（空回复）
+func (c *RedisClient) Do(ctx context.Context, args ...string) (interface{}, error) {
+	conn, err := c.get(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	reply, err := conn.do(ctx, c.config.IOTimeout, args...)
+	var redisErr RedisError
+	if err != nil && !errors.As(err, &redisErr) {
+		// 网络错误后连接状态未知，直接丢弃
+		conn.conn.Close()
+		return nil, err
+	}
+	c.put(conn)
+	return reply, err
+}
+
+// get 从连接池取出连接，池为空时新建
+func (c *RedisClient) get(ctx context.Context) (*redisConn, error) {
+	select {
+	case conn := <-c.idle:
+		return conn, nil
+	default:
+	}
+
+	dialer := net.Dialer{Timeout: c.config.DialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", c.config.Addr)
+	if err != nil {
+		return nil, err
+	}
+	conn := &redisConn{conn: netConn, reader: bufio.NewReader(netConn)}
+
+	if c.config.Password != "" {
+		if _, err := conn.do(ctx, c.config.IOTimeout, "AUTH", c.config.Password); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	if c.config.DB != 0 {
+		if _, err := conn.do(ctx, c.config.IOTimeout, "SELECT", strconv.Itoa(c.config.DB)); err != nil {
+			netConn.Close()
+			return nil, err
+		}
+	}
+	return conn, nil
+}
+
+// put 归还连接，池已满时关闭
+func (c *RedisClient) put(conn *redisConn) {
+	select {
+	case c.idle <- conn:
+	default:
+		conn.conn.Close()
+	}
+}
+
+// Close 关闭所有空闲连接
+func (c *RedisClient) Close() error {
+	for {
+		select {
+		case conn := <-c.idle:
+			conn.conn.Close()
+		default:
+			return nil
+		}
+	}
+}
+
+// do 在连接上发送命令并读取回复
+func (rc *redisConn) do(ctx context.Context, timeout time.Duration, args ...string) (interface{}, error) {
+	deadline := time.Time{}
+	if timeout > 0 {
+		deadline = time.Now().Add(timeout)
+	}
+	if d, ok := ctx.Deadline(); ok && (deadline.IsZero() || d.Before(deadline)) {
+		deadline = d
+	}
+	rc.conn.SetDeadline(deadline)
+
+	if _, err := rc.conn.Write(encodeCommand(args)); err != nil {
+		return nil, err
+	}
+	return readReply(rc.reader)
+}
+
+// encodeCommand 将命令编码为RESP批量字符串数组
+func encodeCommand(args []string) []byte {
+	buf := make([]byte, 0, 64)
+	buf = append(buf, '*')
+	buf = strconv.AppendInt(buf, int64(len(args)), 10)
+	buf = append(buf, '\r', '\n')
+	for _, arg := range args {
+		buf = append(buf, '$')
+		buf = strconv.AppendInt(buf, int64(len(arg)), 10)
+		buf = append(buf, '\r', '\n')
+		buf = append(buf, arg...)
+		buf = append(buf, '\r', '\n')
+	}
+	return buf
+}
+
+// readReply 读取一个RESP回复
+func readReply(r *bufio.Reader) (interface{}, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(line) == 0 {
+		return nil, errors.New("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, RedisError(line[1:])
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		data := make([]byte, n+2)
+		if _, err := io.ReadFull(r, data); err != nil {
+			return nil, err
+		}
+		return data[:n], nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]interface{}, n)
+		for i := range items {
+			if items[i], err = readReply(r); err != nil {
+				var redisErr RedisError
+				if !errors.As(err, &redisErr) {
+					return nil, err
+				}
+				items[i] = err
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
+
+// readLine 读取以CRLF结尾的一行，不含CRLF
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	if len(line) < 2 || line[len(line)-2] != '\r' {
+		return "", fmt.Errorf("redis: malformed line %q", line)
+	}
+	return line[:len(line)-2], nil
+}
@@ -0,0 +1,345 @@
+package cache
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+)
+
+// RedisStorage 基于Redis的共享缓存存储，多个实例共用同一份缓存
+//
+// 缓存项使用与 FileStorage 相同的格式序列化，过期时间映射为Redis原生过期（SET PX），
+// 过期项由Redis自动删除。内存上限由Redis的 maxmemory 策略控制，MaxSize 只用于拒绝超大的单项。
+// 数量和大小统计使用 DBSIZE 和 INFO memory，不遍历键，按整个Redis数据库/实例计算。
+type RedisStorage struct {
+	client  *RedisClient
+	prefix  string
+	maxSize int64
+	logger  *slog.Logger
+}
+
+// NewRedisStorage 创建Redis缓存存储，存储关闭时同时关闭客户端
+func NewRedisStorage(client *RedisClient, maxSize int64) *RedisStorage {
+	if maxSize <= 0 {
+		maxSize = 512 * 1024 * 1024 // 默认512MB
+	}
+	return &RedisStorage{
+		client:  client,
+		prefix:  client.config.KeyPrefix,
+		maxSize: maxSize,
+		logger:  slog.Default(),
+	}
+}
+
+// setLogger 实现 loggerSetter
+func (s *RedisStorage) setLogger(logger *slog.Logger) {
+	s.logger = logger
+}
+
+// context 返回单次操作使用的上下文，超时由客户端的 IOTimeout 控制
+func (s *RedisStorage) context() context.Context {
+	return context.Background()
+}
+
+// Get 获取缓存项
+func (s *RedisStorage) Get(key string) (*CachedResult, bool) {
+	reply, err := s.client.Do(s.context(), "GET", s.prefix+key)
+	if err != nil {
+		s.logger.Warn("Redis缓存读取失败", logging.CacheKey(key), logging.Err(err))
+		return nil, false
+	}
+	raw, ok := reply.([]byte)
+	if !ok {
+		return nil, false
+	}
+
+	entry, err := decodeEntry(raw)
+	if err == nil && time.Now().After(entry.Expiry) {
+		return nil, false
+	}
+	var data interface{}
+	if err == nil {
+		data, err = entry.decodeData()
+	}
+	if err != nil {
+		s.logger.Warn("删除无法解码的Redis缓存项", logging.CacheKey(key), logging.Err(err))
+		s.Delete(key)
+		return nil, false
+	}
+
+	return &CachedResult{
+		Data:        data,
+		Created:     entry.Timestamp,
+		Timestamp:   entry.Timestamp,
+		Expiry:      entry.Expiry,
+		AccessCount: entry.AccessCount,
+		Size:        int64(len(raw)),
+	}, true
+}
+
+// Set 设置缓存项，TTL映射为Redis的PX过期时间
+func (s *RedisStorage) Set(key string, data interface{}, ttl time.Duration) error {
+	now := time.Now()
+	raw, err := encodeEntry(key, &CachedResult{
+		Data:        data,
+		Timestamp:   now,
+		Expiry:      now.Add(ttl),
+		AccessCount: 1,
+	})
+	if err != nil {
+		return err
+	}
+	if int64(len(raw)) > s.maxSize {
+		return fmt.Errorf("cache item size %d exceeds maximum cache size %d", len(raw), s.maxSize)
+	}
+
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		ms = 1
+	}
+	_, err = s.client.Do(s.context(), "SET", s.prefix+key, string(raw), "PX", strconv.FormatInt(ms, 10))
+	return err
+}
+
+// Delete 删除缓存项
+func (s *RedisStorage) Delete(key string) bool {
+	reply, err := s.client.Do(s.context(), "DEL", s.prefix+key)
+	if err != nil {
+		s.logger.Warn("Redis缓存删除失败", logging.CacheKey(key), logging.Err(err))
+		return false
+	}
+	n, _ := reply.(int64)
+	return n > 0
+}
+
+// ItemSize 返回缓存项编码后的字节数（STRLEN），不存在或出错时返回0
+func (s *RedisStorage) ItemSize(key string) int64 {
+	reply, err := s.client.Do(s.context(), "STRLEN", s.prefix+key)
+	if err != nil {
+		return 0
+	}
+	n, _ := reply.(int64)
+	return n
+}
+
+// DeleteByPrefix 删除所有以指定前缀开头的缓存项，返回删除数量
+func (s *RedisStorage) DeleteByPrefix(prefix string) int {
+	keys, err := s.scan(prefix)
+	if err != nil {
+		s.logger.Warn("Redis缓存扫描失败", logging.Err(err))
+		return 0
+	}
+
+	deleted := 0
+	const batchSize = 100
+	for start := 0; start < len(keys); start += batchSize {
+		end := start + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+		reply, err := s.client.Do(s.context(), append([]string{"DEL"}, keys[start:end]...)...)
+		if err != nil {
+			s.logger.Warn("Redis缓存删除失败", logging.Err(err))
+			break
+		}
+		n, _ := reply.(int64)
+		deleted += int(n)
+	}
+	return deleted
+}
+
+// scan 返回命名空间内以 prefix 开头的全部Redis键（含命名空间前缀）
+func (s *RedisStorage) scan(prefix string) ([]string, error) {
+	var keys []string
+	cursor := "0"
+	for {
+		reply, err := s.client.Do(s.context(), "SCAN", cursor, "MATCH", escapeGlob(s.prefix+prefix)+"*", "COUNT", "100")
+		if err != nil {
+			return nil, err
+		}
+		parts, ok := reply.([]interface{})
+		if !ok || len(parts) != 2 {
+			return nil, fmt.Errorf("redis: unexpected SCAN reply %v", reply)
+		}
+		next, _ := parts[0].([]byte)
+		batch, _ := parts[1].([]interface{})
+		for _, item := range batch {
+			if key, ok := item.([]byte); ok {
+				keys = append(keys, string(key))
+			}
+		}
+
+		cursor = string(next)
+		if cursor == "0" || cursor == "" {
+			return keys, nil
+		}
+	}
+}
+
+// escapeGlob 转义Redis MATCH模式中的特殊字符
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, r := range s {
+		switch r {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// EvictExpired Redis按TTL自动删除过期键，无需清理
+func (s *RedisStorage) EvictExpired() int {
+	return 0
+}
+
+// Clear 清空命名空间内的全部缓存
+func (s *RedisStorage) Clear() {
+	s.DeleteByPrefix("")
+}
+
+// Size 返回缓存项数量
+//
+// 使用 DBSIZE 统计，不遍历键：结果包含同一Redis数据库中的全部键（包括锁和其他应用的键），
+// 需要准确数量时应为缓存单独指定 cache.redis.db。
+func (s *RedisStorage) Size() int {
+	reply, err := s.client.Do(s.context(), "DBSIZE")
+	if err != nil {
+		s.logger.Warn("Redis缓存统计失败", logging.Err(err))
+		return 0
+	}
+	n, _ := reply.(int64)
+	return int(n)
+}
+
+// TotalSize 返回Redis已使用的内存（INFO memory 的 used_memory），与 Size 一样按整个实例统计
+func (s *RedisStorage) TotalSize() int64 {
+	used, _ := s.memoryInfo()
+	return used
+}
+
+// memoryInfo 通过 INFO memory 读取已使用内存和 maxmemory（未设置时为0）
+func (s *RedisStorage) memoryInfo() (used, max int64) {
+	reply, err := s.client.Do(s.context(), "INFO", "memory")
+	if err != nil {
+		s.logger.Warn("Redis缓存统计失败", logging.Err(err))
+		return 0, 0
+	}
+	raw, _ := reply.([]byte)
+	for _, line := range strings.Split(string(raw), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), ":")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "used_memory":
+			used, _ = strconv.ParseInt(value, 10, 64)
+		case "maxmemory":
+			max, _ = strconv.ParseInt(value, 10, 64)
+		}
+	}
+	return used, max
+}
+
+// MaxSize 返回最大缓存大小
+func (s *RedisStorage) MaxSize() int64 {
+	return s.maxSize
+}
+
+// GetKeys 返回所有缓存键（不含命名空间前缀）
+func (s *RedisStorage) GetKeys() []string {
+	keys, err := s.scan("")
+	if err != nil {
+		s.logger.Warn("Redis缓存扫描失败", logging.Err(err))
+		return nil
+	}
+	for i, key := range keys {
+		keys[i] = strings.TrimPrefix(key, s.prefix)
+	}
+	return keys
+}
+
+// GetStats 获取缓存统计信息，只发出 DBSIZE 和 INFO 两条命令
+//
+// 设置了 maxmemory 时内存使用率按 maxmemory 计算，否则按 MaxSize 计算。
+func (s *RedisStorage) GetStats() map[string]interface{} {
+	items := s.Size()
+	used, max := s.memoryInfo()
+	if max <= 0 {
+		max = s.maxSize
+	}
+	return map[string]interface{}{
+		"backend":      "redis",
+		"redis_addr":   s.client.config.Addr,
+		"total_items":  items,
+		"total_size":   used,
+		"max_size":     max,
+		"memory_usage": float64(used) / float64(max) * 100,
+	}
+}
+
+// Close 关闭存储及其Redis客户端
+func (s *RedisStorage) Close() error {
+	return s.client.Close()
+}
+
+// redisUnlockScript 只删除仍由当前持有者持有的锁，避免误删过期后被其他实例获取的锁
+const redisUnlockScript = `if redis.call("GET", KEYS[1]) == ARGV[1] then return redis.call("DEL", KEYS[1]) else return 0 end`
+
+// RedisLock 基于Redis SET NX PX的分布式锁
+type RedisLock struct {
+	client *RedisClient
+	prefix string
+	logger *slog.Logger
+}
+
+// NewRedisLock 创建分布式锁，锁键位于客户端命名空间的 lock: 下
+func NewRedisLock(client *RedisClient) *RedisLock {
+	return &RedisLock{
+		client: client,
+		prefix: client.config.KeyPrefix + "lock:",
+		logger: slog.Default(),
+	}
+}
+
+// setLogger 实现 loggerSetter
+func (l *RedisLock) setLogger(logger *slog.Logger) {
+	l.logger = logger
+}
+
+// TryLock 尝试获取锁，不阻塞
+func (l *RedisLock) TryLock(ctx context.Context, key string, ttl time.Duration) (func(), bool, error) {
+	token := make([]byte, 16)
+	if _, err := rand.Read(token); err != nil {
+		return nil, false, err
+	}
+	value := hex.EncodeToString(token)
+	lockKey := l.prefix + key
+
+	ms := ttl.Milliseconds()
+	if ms <= 0 {
+		ms = 1
+	}
+	reply, err := l.client.Do(ctx, "SET", lockKey, value, "NX", "PX", strconv.FormatInt(ms, 10))
+	if err != nil {
+		return nil, false, err
+	}
+	if reply == nil {
+		return nil, false, nil
+	}
+
+	unlock := func() {
+		if _, err := l.client.Do(context.Background(), "EVAL", redisUnlockScript, "1", lockKey, value); err != nil {
+			l.logger.Warn("释放分布式锁失败", logging.CacheKey(key), logging.Err(err))
+		}
+	}
+	return unlock, true, nil
+}
@@ -0,0 +1,383 @@
+package cache
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net"
+	"path"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// fakeRedis 进程内的最小RESP服务器，只实现缓存和锁用到的命令
+type fakeRedis struct {
+	listener net.Listener
+	mu       sync.Mutex
+	data     map[string]string
+	expiry   map[string]time.Time
+	scans    int // SCAN 调用次数
+}
+
+func newFakeRedis(t *testing.T) *fakeRedis {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	s := &fakeRedis{
+		listener: listener,
+		data:     make(map[string]string),
+		expiry:   make(map[string]time.Time),
+	}
+	go s.serve()
+	t.Cleanup(func() { listener.Close() })
+	return s
+}
+
+func (s *fakeRedis) addr() string {
+	return s.listener.Addr().String()
+}
+
+func (s *fakeRedis) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *fakeRedis) handle(conn net.Conn) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	for {
+		reply, err := readReply(reader)
+		if err != nil {
+			return
+		}
+		items, _ := reply.([]interface{})
+		args := make([]string, len(items))
+		for i, item := range items {
+			b, _ := item.([]byte)
+			args[i] = string(b)
+		}
+		if _, err := conn.Write([]byte(s.exec(args))); err != nil {
+			return
+		}
+	}
+}
+
+// lookup 返回未过期的值（调用时必须持有锁）
+func (s *fakeRedis) lookup(key string) (string, bool) {
+	if exp, ok := s.expiry[key]; ok && time.Now().After(exp) {
+		delete(s.data, key)
+		delete(s.expiry, key)
+	}
+	value, ok := s.data[key]
+	return value, ok
+}
+
+func (s *fakeRedis) exec(args []string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(args) == 0 {
+		return "-ERR empty command\r\n"
+	}
+	switch strings.ToUpper(args[0]) {
+	case "PING":
+		return "+PONG\r\n"
+	case "AUTH", "SELECT":
+		return "+OK\r\n"
+	case "GET":
+		if value, ok := s.lookup(args[1]); ok {
+			return bulk(value)
+		}
+		return "$-1\r\n"
+	case "SET":
+		key, value := args[1], args[2]
+		var ttl time.Duration
+		nx := false
+		for i := 3; i < len(args); i++ {
+			switch strings.ToUpper(args[i]) {
+			case "NX":
+				nx = true
+			case "PX":
+				ms, _ := strconv.Atoi(args[i+1])
+				ttl = time.Duration(ms) * time.Millisecond
+				i++
+			}
+		}
+		if _, exists := s.lookup(key); exists && nx {
+			return "$-1\r\n"
+		}
+		s.data[key] = value
+		delete(s.expiry, key)
+		if ttl > 0 {
+			s.expiry[key] = time.Now().Add(ttl)
+		}
+		return "+OK\r\n"
+	case "DEL":
+		n := 0
+		for _, key := range args[1:] {
+			if _, ok := s.lookup(key); ok {
+				delete(s.data, key)
+				delete(s.expiry, key)
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "DBSIZE":
+		n := 0
+		for key := range s.data {
+			if _, ok := s.lookup(key); ok {
+				n++
+			}
+		}
+		return fmt.Sprintf(":%d\r\n", n)
+	case "INFO":
+		used := 0
+		for _, value := range s.data {
+			used += len(value)
+		}
+		return bulk(fmt.Sprintf("# Memory\r\nused_memory:%d\r\nmaxmemory:0\r\n", used))
+	case "SCAN":
+		s.scans++
+		pattern := "*"
+		for i := 2; i < len(args)-1; i++ {
+			if strings.ToUpper(args[i]) == "MATCH" {
+				pattern = args[i+1]
+			}
+		}
+		var keys []string
+		for key := range s.data {
+			if _, ok := s.lookup(key); !ok {
+				continue
+			}
+			if matched, _ := path.Match(pattern, key); matched {
+				keys = append(keys, key)
+			}
+		}
+		reply := "*2\r\n" + bulk("0") + fmt.Sprintf("*%d\r\n", len(keys))
+		for _, key := range keys {
+			reply += bulk(key)
+		}
+		return reply
+	case "EVAL":
+		if args[1] != redisUnlockScript {
+			return "-ERR unknown script\r\n"
+		}
+		if value, ok := s.lookup(args[3]); ok && value == args[4] {
+			delete(s.data, args[3])
+			delete(s.expiry, args[3])
+			return ":1\r\n"
+		}
+		return ":0\r\n"
+	default:
+		return "-ERR unknown command '" + args[0] + "'\r\n"
+	}
+}
+
+func bulk(s string) string {
+	return fmt.Sprintf("$%d\r\n%s\r\n", len(s), s)
+}
+
+func newTestRedisClient(t *testing.T, server *fakeRedis) *RedisClient {
+	t.Helper()
+	config := DefaultRedisConfig()
+	config.Addr = server.addr()
+	config.KeyPrefix = "test:"
+	client, err := NewRedisClient(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+	return client
+}
+
+func TestRedisStorage_Basic(t *testing.T) {
+	server := newFakeRedis(t)
+	storage := NewRedisStorage(newTestRedisClient(t, server), 1024*1024)
+	defer storage.Close()
+
+	updated := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	if err := storage.Set("weekly_news:react", &persistedResult{Title: "React", UpdatedAt: updated}, time.Hour); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+	if err := storage.Set("short", "value", 10*time.Millisecond); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+
+	result, found := storage.Get("weekly_news:react")
+	if !found {
+		t.Fatal("Expected item to be found")
+	}
+	restored, ok := result.Data.(*persistedResult)
+	if !ok || restored.Title != "React" || !restored.UpdatedAt.Equal(updated) {
+		t.Errorf("Unexpected restored value: %#v", result.Data)
+	}
+
+	// TTL映射为Redis原生过期
+	server.mu.Lock()
+	_, hasExpiry := server.expiry["test:short"]
+	server.mu.Unlock()
+	if !hasExpiry {
+		t.Error("Expected native expiry to be set")
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, found := storage.Get("short"); found {
+		t.Error("Expected short-lived item to expire")
+	}
+
+	if storage.Size() != 1 {
+		t.Errorf("Expected 1 item, got %d", storage.Size())
+	}
+	if keys := storage.GetKeys(); len(keys) != 1 || keys[0] != "weekly_news:react" {
+		t.Errorf("Expected keys without namespace prefix, got %v", keys)
+	}
+	if storage.TotalSize() <= 0 {
+		t.Error("Expected total size to be reported")
+	}
+
+	// 统计信息不遍历键
+	server.mu.Lock()
+	server.scans = 0
+	server.mu.Unlock()
+	stats := storage.GetStats()
+	if stats["backend"] != "redis" {
+		t.Errorf("Expected redis backend in stats, got %v", stats["backend"])
+	}
+	if stats["total_items"] != 1 {
+		t.Errorf("Expected 1 item in stats, got %v", stats["total_items"])
+	}
+	if storage.EvictExpired() != 0 {
+		t.Error("Redis expires keys natively, EvictExpired should be a no-op")
+	}
+	server.mu.Lock()
+	scans := server.scans
+	server.mu.Unlock()
+	if scans != 0 {
+		t.Errorf("Stats and expiry sweep must not scan the keyspace, got %d SCAN calls", scans)
+	}
+}
+
+func TestRedisStorage_DeleteByPrefix(t *testing.T) {
+	server := newFakeRedis(t)
+	storage := NewRedisStorage(newTestRedisClient(t, server), 1024*1024)
+	defer storage.Close()
+
+	storage.Set("weekly_news:a", "a", time.Hour)
+	storage.Set("weekly_news:b", "b", time.Hour)
+	storage.Set("weekly_news*", "glob", time.Hour)
+	storage.Set("trending_repos:c", "c", time.Hour)
+
+	if deleted := storage.DeleteByPrefix("weekly_news:"); deleted != 2 {
+		t.Errorf("Expected 2 deleted items, got %d", deleted)
+	}
+	if _, found := storage.Get("weekly_news*"); !found {
+		t.Error("Glob characters in the prefix must be matched literally")
+	}
+	if !storage.Delete("trending_repos:c") || storage.Delete("trending_repos:c") {
+		t.Error("Delete should report whether the key existed")
+	}
+
+	storage.Clear()
+	if storage.Size() != 0 {
+		t.Errorf("Expected empty storage, got %d items", storage.Size())
+	}
+}
+
+func TestRedisLock(t *testing.T) {
+	server := newFakeRedis(t)
+	client := newTestRedisClient(t, server)
+	defer client.Close()
+	lock := NewRedisLock(client)
+	ctx := context.Background()
+
+	unlock, acquired, err := lock.TryLock(ctx, "weekly_news:x", time.Minute)
+	if err != nil || !acquired {
+		t.Fatalf("Expected to acquire lock, got acquired=%v err=%v", acquired, err)
+	}
+	if _, acquired, _ := lock.TryLock(ctx, "weekly_news:x", time.Minute); acquired {
+		t.Error("Lock must be exclusive")
+	}
+	unlock()
+	unlockAgain, acquired, _ := lock.TryLock(ctx, "weekly_news:x", time.Minute)
+	if !acquired {
+		t.Fatal("Expected lock to be released")
+	}
+
+	// 过期后被他人获取的锁不能被旧持有者释放
+	unlockAgain()
+	stale, _, _ := lock.TryLock(ctx, "weekly_news:y", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, acquired, _ = lock.TryLock(ctx, "weekly_news:y", time.Minute)
+	if !acquired {
+		t.Fatal("Expected expired lock to be acquirable")
+	}
+	stale()
+	if _, acquired, _ := lock.TryLock(ctx, "weekly_news:y", time.Minute); acquired {
+		t.Error("Stale holder must not release a lock it no longer owns")
+	}
+}
+
+func TestCacheManager_DistributedCoalescing(t *testing.T) {
+	server := newFakeRedis(t)
+
+	newInstance := func() *CacheManager {
+		client := newTestRedisClient(t, server)
+		coalescing := DefaultCoalescingConfig()
+		coalescing.Lock = NewRedisLock(client)
+		coalescing.LockPrefixes = []string{"weekly_news:"}
+		coalescing.LockRetry = 5 * time.Millisecond
+		return NewCacheManager(&CacheConfig{
+			MaxSize:          1024 * 1024,
+			TTL:              time.Hour,
+			CleanupInterval:  time.Minute,
+			Storage:          NewRedisStorage(client, 1024*1024),
+			CoalescingConfig: coalescing,
+		})
+	}
+	instances := []*CacheManager{newInstance(), newInstance(), newInstance()}
+	defer func() {
+		for _, cm := range instances {
+			cm.Close()
+		}
+	}()
+
+	var calls int32
+	fetch := func(ctx context.Context) (interface{}, error) {
+		atomic.AddInt32(&calls, 1)
+		time.Sleep(50 * time.Millisecond)
+		return "fresh", nil
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(instances))
+	for _, cm := range instances {
+		wg.Add(1)
+		go func(cm *CacheManager) {
+			defer wg.Done()
+			value, err := cm.GetOrSetWithTTL(context.Background(), "weekly_news:react", time.Hour, fetch)
+			if err != nil {
+				errs <- err
+			} else if value != "fresh" {
+				errs <- fmt.Errorf("unexpected value %v", value)
+			}
+		}(cm)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	if calls != 1 {
+		t.Errorf("Expected exactly one fetch across instances, got %d", calls)
+	}
+}
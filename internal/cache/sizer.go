@@ -0,0 +1,155 @@
+package cache
+
+import (
+	"reflect"
+	"time"
+)
+
+// Sizer 由能够自行估算内存占用的缓存数据实现
+//
+// 未实现 Sizer 的数据按反射遍历结果估算，实现 Sizer 可以避免遍历大型结构，
+// 或排除与其他对象共享、不应计入缓存的数据。
+type Sizer interface {
+	// EstimateSize 返回数据占用的内存字节数
+	EstimateSize() int64
+}
+
+var (
+	sizerType = reflect.TypeOf((*Sizer)(nil)).Elem()
+	timeType  = reflect.TypeOf(time.Time{})
+)
+
+// mapEntryOverhead 每个映射项的桶和哈希开销估算
+const mapEntryOverhead = 16
+
+// EstimateDataSize 估算数据占用的内存字节数
+//
+// 字符串和字节切片按内容长度计算；实现 Sizer 的数据使用其自身的估算；
+// 其余数据递归累计结构体字段、切片元素、映射键值和指针指向的数据，
+// 同一指针只计算一次。
+func EstimateDataSize(data interface{}) int64 {
+	switch v := data.(type) {
+	case nil:
+		return 0
+	case string:
+		return int64(len(v))
+	case []byte:
+		return int64(len(v))
+	case Sizer:
+		return v.EstimateSize()
+	}
+
+	value := reflect.ValueOf(data)
+	return int64(value.Type().Size()) + heapSize(value, make(map[uintptr]bool))
+}
+
+// heapSize 返回值引用的堆内存大小，不含值本身
+func heapSize(v reflect.Value, seen map[uintptr]bool) int64 {
+	switch v.Kind() {
+	case reflect.String:
+		return int64(v.Len())
+
+	case reflect.Ptr:
+		if v.IsNil() || seen[v.Pointer()] {
+			return 0
+		}
+		seen[v.Pointer()] = true
+		if size, ok := sizerSize(v); ok {
+			return size
+		}
+		elem := v.Elem()
+		return int64(elem.Type().Size()) + heapSize(elem, seen)
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return 0
+		}
+		elem := v.Elem()
+		if size, ok := sizerSize(elem); ok {
+			return size
+		}
+		return int64(elem.Type().Size()) + heapSize(elem, seen)
+
+	case reflect.Slice:
+		if v.IsNil() {
+			return 0
+		}
+		size := int64(v.Cap()) * int64(v.Type().Elem().Size())
+		if hasPointers(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += heapSize(v.Index(i), seen)
+			}
+		}
+		return size
+
+	case reflect.Array:
+		size := int64(0)
+		if hasPointers(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				size += heapSize(v.Index(i), seen)
+			}
+		}
+		return size
+
+	case reflect.Map:
+		if v.IsNil() {
+			return 0
+		}
+		entrySize := int64(v.Type().Key().Size()+v.Type().Elem().Size()) + mapEntryOverhead
+		size := int64(v.Len()) * entrySize
+		iter := v.MapRange()
+		for iter.Next() {
+			size += heapSize(iter.Key(), seen) + heapSize(iter.Value(), seen)
+		}
+		return size
+
+	case reflect.Struct:
+		// time.Time 的时区指针指向全局共享数据
+		if v.Type() == timeType {
+			return 0
+		}
+		size := int64(0)
+		for i := 0; i < v.NumField(); i++ {
+			size += heapSize(v.Field(i), seen)
+		}
+		return size
+
+	default:
+		return 0
+	}
+}
+
+// sizerSize 如果值实现了 Sizer 则返回其估算的大小
+func sizerSize(v reflect.Value) (int64, bool) {
+	if !v.CanInterface() || !v.Type().Implements(sizerType) {
+		return 0, false
+	}
+	if v.Kind() == reflect.Ptr && v.IsNil() {
+		return 0, false
+	}
+	return v.Interface().(Sizer).EstimateSize(), true
+}
+
+// hasPointers 类型是否可能引用额外的堆内存
+func hasPointers(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return false
+	case reflect.Array:
+		return hasPointers(t.Elem())
+	case reflect.Struct:
+		if t == timeType {
+			return false
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if hasPointers(t.Field(i).Type) {
+				return true
+			}
+		}
+		return false
+	default:
+		return true
+	}
+}
@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+type sizedArticle struct {
+	Title       string
+	Summary     string
+	Tags        []string
+	PublishedAt time.Time
+	Metadata    map[string]interface{}
+}
+
+type sizedResult struct {
+	Articles []sizedArticle
+	Summary  string
+}
+
+type fixedSize struct{ payload []byte }
+
+func (f *fixedSize) EstimateSize() int64 { return 42 }
+
+type node struct {
+	Value string
+	Next  *node
+}
+
+func TestEstimateDataSize_GrowsWithContent(t *testing.T) {
+	newResult := func(articles int) *sizedResult {
+		result := &sizedResult{Summary: "weekly"}
+		for i := 0; i < articles; i++ {
+			result.Articles = append(result.Articles, sizedArticle{
+				Title:       strings.Repeat("t", 80),
+				Summary:     strings.Repeat("s", 400),
+				Tags:        []string{"react", "vue"},
+				PublishedAt: time.Now(),
+				Metadata:    map[string]interface{}{"author": "someone", "score": 12},
+			})
+		}
+		return result
+	}
+
+	small := EstimateDataSize(newResult(1))
+	large := EstimateDataSize(newResult(200))
+
+	if large < 200*480 {
+		t.Errorf("Expected 200 articles to account for at least their text, got %d bytes", large)
+	}
+	if large < small*100 {
+		t.Errorf("Expected size to scale with article count, got %d for 1 and %d for 200", small, large)
+	}
+
+	if size := (&CachedResult{Data: newResult(200)}).EstimateSize(); size <= 1024+64 {
+		t.Errorf("Expected CachedResult size to reflect data, got %d", size)
+	}
+}
+
+func TestEstimateDataSize_Basics(t *testing.T) {
+	if size := EstimateDataSize("hello"); size != 5 {
+		t.Errorf("Expected string size 5, got %d", size)
+	}
+	if size := EstimateDataSize([]byte("hello world")); size != 11 {
+		t.Errorf("Expected byte slice size 11, got %d", size)
+	}
+	if size := EstimateDataSize(nil); size != 0 {
+		t.Errorf("Expected nil size 0, got %d", size)
+	}
+	if size := EstimateDataSize(&fixedSize{payload: make([]byte, 1<<20)}); size != 42 {
+		t.Errorf("Expected Sizer to be used, got %d", size)
+	}
+
+	// 嵌套的 Sizer 同样生效
+	nested := []interface{}{&fixedSize{}, &fixedSize{}}
+	if size := EstimateDataSize(nested); size > 200 {
+		t.Errorf("Expected nested Sizer values to be used, got %d", size)
+	}
+
+	// 循环引用只计算一次
+	a := &node{Value: strings.Repeat("a", 100)}
+	b := &node{Value: strings.Repeat("b", 100), Next: a}
+	a.Next = b
+	if size := EstimateDataSize(a); size < 200 || size > 400 {
+		t.Errorf("Expected cyclic list to be counted once, got %d", size)
+	}
+}
+
+func TestCacheManager_DetailedMetrics(t *testing.T) {
+	config := DefaultCacheConfig()
+	config.MaxSize = 1024 * 1024
+	cm := NewCacheManager(config)
+	defer cm.Close()
+
+	metrics := NewDetailedCacheMetrics(cm.MaxSize())
+	cm.SetDetailedMetrics(metrics)
+
+	value := &sizedResult{Summary: strings.Repeat("x", 100*1024)}
+	if err := cm.Set("weekly_news:react", value); err != nil {
+		t.Fatalf("Failed to set: %v", err)
+	}
+	cm.Get("weekly_news:react")
+	cm.Get("missing")
+
+	stats := metrics.GetDetailedStats()
+	if stats["sets"].(int64) != 1 || stats["hits"].(int64) != 1 || stats["misses"].(int64) != 1 {
+		t.Errorf("Expected operations to be recorded, got sets=%v hits=%v misses=%v", stats["sets"], stats["hits"], stats["misses"])
+	}
+	if usage := stats["memory_usage_percent"].(float64); usage < 9 {
+		t.Errorf("Expected ~10%% memory usage for a 100KB item in 1MB, got %.2f%%", usage)
+	}
+	if cm.TotalSize() < 100*1024 {
+		t.Errorf("Expected storage to account for the full item, got %d", cm.TotalSize())
+	}
+
+	cm.Delete("weekly_news:react")
+	metrics.MemoryUsage.SetCurrentSize(cm.TotalSize())
+	if usage := metrics.GetDetailedStats()["memory_usage_percent"].(float64); usage != 0 {
+		t.Errorf("Expected memory usage to drop after delete, got %.2f%%", usage)
+	}
+}
@@ -3,6 +3,7 @@ package cache
 import (
 	"crypto/md5"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 )
@@ -10,7 +11,8 @@ import (
 // CachedResult 表示缓存的结果数据
 type CachedResult struct {
 	Data        interface{} `json:"data"`
-	Timestamp   time.Time   `json:"timestamp"`
+	Created     time.Time   `json:"created"`   // 写入时间，用于计算结果的新鲜度
+	Timestamp   time.Time   `json:"timestamp"` // 最后访问时间，用于LRU
 	Expiry      time.Time   `json:"expiry"`
 	AccessCount int64       `json:"access_count"`
 	Size        int64       `json:"size"`
@@ -21,6 +23,14 @@ func (r *CachedResult) IsExpired() bool {
 	return time.Now().After(r.Expiry)
 }
 
+// Age 返回缓存项写入后经过的时间
+func (r *CachedResult) Age() time.Duration {
+	if r.Created.IsZero() {
+		return time.Since(r.Timestamp)
+	}
+	return time.Since(r.Created)
+}
+
 // Touch 更新访问计数和时间戳
 func (r *CachedResult) Touch() {
 	r.AccessCount++
@@ -28,28 +38,37 @@ func (r *CachedResult) Touch() {
 }
 
 // EstimateSize 估算缓存项的内存大小
+//
+// 数据大小由 EstimateDataSize 计算，结果在首次调用后缓存在 Size 字段中。
 func (r *CachedResult) EstimateSize() int64 {
 	if r.Size > 0 {
 		return r.Size
 	}
-	// 简单估算：基础结构大小 + 数据大小估算
 	baseSize := int64(64) // 基础结构大小估算
 	
-	// 根据数据类型估算大小
-	switch v := r.Data.(type) {
-	case string:
-		r.Size = baseSize + int64(len(v))
-	case []byte:
-		r.Size = baseSize + int64(len(v))
-	case []interface{}:
-		r.Size = baseSize + int64(len(v)*100) // 粗略估算
-	default:
-		r.Size = baseSize + 1024 // 默认1KB估算
-	}
-	
+	r.Size = baseSize + EstimateDataSize(r.Data)
 	return r.Size
 }
 
+// Storage 缓存存储后端
+//
+// CacheStorage 为默认的内存实现，FileStorage 将缓存持久化到磁盘。
+type Storage interface {
+	Get(key string) (*CachedResult, bool)
+	Set(key string, data interface{}, ttl time.Duration) error
+	Delete(key string) bool
+	ItemSize(key string) int64 // 返回缓存项在后端中计入的大小，不存在时返回0，不影响访问统计
+	DeleteByPrefix(prefix string) int
+	EvictExpired() int // 清理已过期的项并返回数量，不影响其他项的访问统计
+	Clear()
+	Size() int
+	TotalSize() int64
+	MaxSize() int64
+	GetKeys() []string
+	GetStats() map[string]interface{}
+	Close() error
+}
+
 // CacheStorage 提供线程安全的内存缓存存储
 type CacheStorage struct {
 	data      map[string]*CachedResult
@@ -113,8 +132,9 @@ func GenerateKey(queryType string, parameters map[string]interface{}, timeRange
 
 // Get 获取缓存项
 func (s *CacheStorage) Get(key string) (*CachedResult, bool) {
-	s.mutex.RLock()
-	defer s.mutex.RUnlock()
+	// 读取时会更新访问统计，需要写锁
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
 	
 	result, exists := s.data[key]
 	if !exists {
@@ -124,12 +144,8 @@ func (s *CacheStorage) Get(key string) (*CachedResult, bool) {
 	// 检查是否过期
 	if result.IsExpired() {
 		// 过期项在读取时清理
-		s.mutex.RUnlock()
-		s.mutex.Lock()
 		delete(s.data, key)
 		s.totalSize -= result.EstimateSize()
-		s.mutex.Unlock()
-		s.mutex.RLock()
 		return nil, false
 	}
 	
@@ -144,6 +160,7 @@ func (s *CacheStorage) Set(key string, data interface{}, ttl time.Duration) erro
 	now := time.Now()
 	result := &CachedResult{
 		Data:        data,
+		Created:     now,
 		Timestamp:   now,
 		Expiry:      now.Add(ttl),
 		AccessCount: 1,
@@ -196,6 +213,34 @@ func (s *CacheStorage) Delete(key string) bool {
 	return false
 }
 
+// ItemSize 返回缓存项计入总大小的字节数，不存在时返回0
+func (s *CacheStorage) ItemSize(key string) int64 {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+	
+	if result, exists := s.data[key]; exists {
+		return result.Size
+	}
+	return 0
+}
+
+// DeleteByPrefix 删除所有以指定前缀开头的缓存项，返回删除数量
+func (s *CacheStorage) DeleteByPrefix(prefix string) int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	
+	deleted := 0
+	for key, result := range s.data {
+		if strings.HasPrefix(key, prefix) {
+			delete(s.data, key)
+			s.totalSize -= result.EstimateSize()
+			deleted++
+		}
+	}
+	
+	return deleted
+}
+
 // Clear 清空所有缓存
 func (s *CacheStorage) Clear() {
 	s.mutex.Lock()
@@ -226,15 +271,25 @@ func (s *CacheStorage) MaxSize() int64 {
 	return s.maxSize
 }
 
-// cleanup 清理过期项（调用时必须持有写锁）
-func (s *CacheStorage) cleanup() {
+// EvictExpired 清理过期项，返回清理数量
+func (s *CacheStorage) EvictExpired() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.cleanup()
+}
+
+// cleanup 清理过期项，返回清理数量（调用时必须持有写锁）
+func (s *CacheStorage) cleanup() int {
 	now := time.Now()
+	cleaned := 0
 	for key, result := range s.data {
 		if now.After(result.Expiry) {
 			delete(s.data, key)
 			s.totalSize -= result.EstimateSize()
+			cleaned++
 		}
 	}
+	return cleaned
 }
 
 // evictLRU 执行LRU清理（调用时必须持有写锁）
@@ -307,6 +362,7 @@ func (s *CacheStorage) GetStats() map[string]interface{} {
 	}
 	
 	return map[string]interface{}{
+		"backend":        "memory",
 		"total_items":    len(s.data),
 		"total_size":     s.totalSize,
 		"max_size":       s.maxSize,
@@ -314,4 +370,9 @@ func (s *CacheStorage) GetStats() map[string]interface{} {
 		"total_accesses": totalAccess,
 		"expired_items":  expiredCount,
 	}
-}
\ No newline at end of file
+}
+
+// Close 关闭存储，内存存储无需释放资源
+func (s *CacheStorage) Close() error {
+	return nil
+}
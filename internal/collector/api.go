@@ -78,7 +78,8 @@ type DevToOrg struct {
 
 // APICollector API数据采集器
 type APICollector struct {
-	client *http.Client
+	client      *http.Client
+	conditional *conditionalCache
 }
 
 // NewAPICollector 创建API采集器
@@ -87,6 +88,7 @@ func NewAPICollector() *APICollector {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		conditional: newConditionalCache(),
 	}
 }
 
@@ -116,7 +118,7 @@ func (a *APICollector) Validate(config CollectConfig) error {
 // Collect 采集API数据
 func (a *APICollector) Collect(ctx context.Context, config CollectConfig) (CollectResult, error) {
 	if err := a.Validate(config); err != nil {
-		return CollectResult{}, fmt.Errorf("validation failed: %w", err)
+		return CollectResult{}, &ValidationError{Err: err}
 	}
 
 	// 设置超时
@@ -148,7 +150,7 @@ func (a *APICollector) isDevToAPI(apiURL string) bool {
 
 // collectGitHubAPI 采集GitHub API数据
 func (a *APICollector) collectGitHubAPI(ctx context.Context, config CollectConfig) (CollectResult, error) {
-	data, err := a.fetchAPI(ctx, config)
+	data, notModified, err := a.fetchAPI(ctx, config)
 	if err != nil {
 		return CollectResult{}, err
 	}
@@ -160,7 +162,7 @@ func (a *APICollector) collectGitHubAPI(ctx context.Context, config CollectConfi
 		// GitHub Issues API
 		var issues []GitHubIssue
 		if err := json.Unmarshal(data, &issues); err != nil {
-			return CollectResult{}, fmt.Errorf("failed to parse GitHub issues: %w", err)
+			return CollectResult{}, &ParseError{What: "GitHub issues", Err: err}
 		}
 		articles = a.convertGitHubIssues(issues, config)
 	} else if strings.Contains(config.URL, "/search/repositories") || strings.Contains(config.URL, "/users/") && strings.Contains(config.URL, "/repos") {
@@ -171,14 +173,14 @@ func (a *APICollector) collectGitHubAPI(ctx context.Context, config CollectConfi
 				Items []GitHubRepo `json:"items"`
 			}
 			if err := json.Unmarshal(data, &searchResult); err != nil {
-				return CollectResult{}, fmt.Errorf("failed to parse GitHub search results: %w", err)
+				return CollectResult{}, &ParseError{What: "GitHub search results", Err: err}
 			}
 			articles = a.convertGitHubRepos(searchResult.Items, config)
 		} else {
 			// 直接仓库列表
 			var repos []GitHubRepo
 			if err := json.Unmarshal(data, &repos); err != nil {
-				return CollectResult{}, fmt.Errorf("failed to parse GitHub repos: %w", err)
+				return CollectResult{}, &ParseError{What: "GitHub repos", Err: err}
 			}
 			articles = a.convertGitHubRepos(repos, config)
 		}
@@ -190,21 +192,22 @@ func (a *APICollector) collectGitHubAPI(ctx context.Context, config CollectConfi
 	}
 
 	return CollectResult{
-		Articles: articles,
-		Source:   config.URL,
+		Articles:    articles,
+		Source:      config.URL,
+		NotModified: notModified,
 	}, nil
 }
 
 // collectDevToAPI 采集Dev.to API数据
 func (a *APICollector) collectDevToAPI(ctx context.Context, config CollectConfig) (CollectResult, error) {
-	data, err := a.fetchAPI(ctx, config)
+	data, notModified, err := a.fetchAPI(ctx, config)
 	if err != nil {
 		return CollectResult{}, err
 	}
 
 	var devArticles []DevToArticle
 	if err := json.Unmarshal(data, &devArticles); err != nil {
-		return CollectResult{}, fmt.Errorf("failed to parse Dev.to articles: %w", err)
+		return CollectResult{}, &ParseError{What: "Dev.to articles", Err: err}
 	}
 
 	articles := a.convertDevToArticles(devArticles, config)
@@ -215,14 +218,15 @@ func (a *APICollector) collectDevToAPI(ctx context.Context, config CollectConfig
 	}
 
 	return CollectResult{
-		Articles: articles,
-		Source:   config.URL,
+		Articles:    articles,
+		Source:      config.URL,
+		NotModified: notModified,
 	}, nil
 }
 
 // collectGenericAPI 采集通用API数据
 func (a *APICollector) collectGenericAPI(ctx context.Context, config CollectConfig) (CollectResult, error) {
-	data, err := a.fetchAPI(ctx, config)
+	data, notModified, err := a.fetchAPI(ctx, config)
 	if err != nil {
 		return CollectResult{}, err
 	}
@@ -250,16 +254,17 @@ func (a *APICollector) collectGenericAPI(ctx context.Context, config CollectConf
 	}
 
 	return CollectResult{
-		Articles: []Article{article},
-		Source:   config.URL,
+		Articles:    []Article{article},
+		Source:      config.URL,
+		NotModified: notModified,
 	}, nil
 }
 
-// fetchAPI 获取API数据
-func (a *APICollector) fetchAPI(ctx context.Context, config CollectConfig) ([]byte, error) {
+// fetchAPI 获取API数据，notModified 表示数据自上次采集后没有变化
+func (a *APICollector) fetchAPI(ctx context.Context, config CollectConfig) ([]byte, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", config.URL, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// 设置默认头部
@@ -271,22 +276,29 @@ func (a *APICollector) fetchAPI(ctx context.Context, config CollectConfig) ([]by
 		req.Header.Set(key, value)
 	}
 
+	// GitHub 对返回 304 的条件请求不计入速率限制
+	a.conditional.prepare(req)
+
 	resp, err := a.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch API: %w", err)
+		return nil, false, fmt.Errorf("failed to fetch API: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if cached, ok := a.conditional.notModified(req, resp); ok {
+		return cached.Body, true, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, resp.Status)
+		return nil, false, newHTTPStatusError(resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, false, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	return body, nil
+	return body, a.conditional.store(req, resp, body), nil
 }
 
 // convertGitHubRepos 转换GitHub仓库为文章
@@ -0,0 +1,118 @@
+package collector
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	// conditionalMaxEntries 每个采集器最多保存的URL数量，超出时淘汰最早保存的
+	conditionalMaxEntries = 128
+	// conditionalMaxBodySize 超过该大小的响应不保存，下次采集时发送普通请求
+	conditionalMaxBodySize = 2 * 1024 * 1024
+)
+
+// conditionalCache 按URL保存上次成功响应的 ETag、Last-Modified、内容哈希和响应体
+//
+// 下次请求时发送 If-None-Match/If-Modified-Since，服务器返回 304 时使用保存的响应体；
+// 对于忽略验证器的服务器，通过内容哈希判断内容是否变化。nil 值表示不使用条件请求。
+type conditionalCache struct {
+	mu      sync.Mutex
+	entries map[string]*conditionalEntry
+}
+
+// conditionalEntry 单个URL上次的响应
+type conditionalEntry struct {
+	ETag         string
+	LastModified string
+	ContentType  string
+	Hash         string
+	Body         []byte
+	stored       time.Time
+}
+
+func newConditionalCache() *conditionalCache {
+	return &conditionalCache{entries: make(map[string]*conditionalEntry)}
+}
+
+// prepare 为请求添加上次响应的验证器，已显式设置的请求头不覆盖
+func (c *conditionalCache) prepare(req *http.Request) {
+	entry, ok := c.get(req)
+	if !ok {
+		return
+	}
+	if entry.ETag != "" && req.Header.Get("If-None-Match") == "" {
+		req.Header.Set("If-None-Match", entry.ETag)
+	}
+	if entry.LastModified != "" && req.Header.Get("If-Modified-Since") == "" {
+		req.Header.Set("If-Modified-Since", entry.LastModified)
+	}
+}
+
+// notModified 在服务器返回 304 时返回上次保存的响应
+func (c *conditionalCache) notModified(req *http.Request, resp *http.Response) (*conditionalEntry, bool) {
+	if resp.StatusCode != http.StatusNotModified {
+		return nil, false
+	}
+	return c.get(req)
+}
+
+// store 保存成功响应的验证器和响应体，返回内容是否与上次相同
+func (c *conditionalCache) store(req *http.Request, resp *http.Response, body []byte) bool {
+	if c == nil {
+		return false
+	}
+
+	sum := sha256.Sum256(body)
+	hash := hex.EncodeToString(sum[:])
+	key := req.URL.String()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	previous, exists := c.entries[key]
+	unchanged := exists && previous.Hash == hash
+
+	if len(body) > conditionalMaxBodySize {
+		delete(c.entries, key)
+		return unchanged
+	}
+
+	c.entries[key] = &conditionalEntry{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Hash:         hash,
+		Body:         body,
+		stored:       time.Now(),
+	}
+	c.evict()
+	return unchanged
+}
+
+func (c *conditionalCache) get(req *http.Request) (*conditionalEntry, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entry, ok := c.entries[req.URL.String()]
+	return entry, ok
+}
+
+// evict 淘汰最早保存的条目，调用方需持有锁
+func (c *conditionalCache) evict() {
+	for len(c.entries) > conditionalMaxEntries {
+		var oldestKey string
+		var oldest time.Time
+		for key, entry := range c.entries {
+			if oldestKey == "" || entry.stored.Before(oldest) {
+				oldestKey, oldest = key, entry.stored
+			}
+		}
+		delete(c.entries, oldestKey)
+	}
+}
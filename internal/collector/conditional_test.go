@@ -0,0 +1,121 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+const conditionalTestFeed = `<rss version="2.0"><channel><item><title>Vite 6</title><link>https://vite.dev/blog/announcing-vite6</link></item></channel></rss>`
+
+func TestRSSCollector_Collect_ConditionalRequests(t *testing.T) {
+	var mu sync.Mutex
+	var requests []http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests = append(requests, r.Header.Clone())
+		mu.Unlock()
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 04 Mar 2024 10:00:00 GMT")
+		w.Header().Set("Content-Type", "application/rss+xml")
+		w.Write([]byte(conditionalTestFeed))
+	}))
+	defer server.Close()
+
+	collector := NewRSSCollector()
+	first, err := collector.Collect(context.Background(), CollectConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if first.NotModified || len(first.Articles) != 1 {
+		t.Fatalf("Expected fresh result with 1 article, got %+v", first)
+	}
+
+	second, err := collector.Collect(context.Background(), CollectConfig{URL: server.URL, Tags: []string{"vite"}})
+	if err != nil {
+		t.Fatalf("Collect() after 304 error = %v", err)
+	}
+	if !second.NotModified || len(second.Articles) != 1 || second.Articles[0].Title != "Vite 6" {
+		t.Fatalf("Expected not modified result with the previous article, got %+v", second)
+	}
+	if len(second.Articles[0].Tags) != 1 || second.Articles[0].Tags[0] != "vite" {
+		t.Errorf("Expected previous response to be parsed with the current config, got %v", second.Articles[0].Tags)
+	}
+
+	if len(requests) != 2 {
+		t.Fatalf("Expected 2 requests, got %d", len(requests))
+	}
+	if requests[0].Get("If-None-Match") != "" {
+		t.Error("Expected no validators on the first request")
+	}
+	if requests[1].Get("If-None-Match") != `"v1"` || requests[1].Get("If-Modified-Since") != "Mon, 04 Mar 2024 10:00:00 GMT" {
+		t.Errorf("Expected validators on the second request, got %v", requests[1])
+	}
+}
+
+func TestAPICollector_Collect_ContentHash(t *testing.T) {
+	body := `[{"id": 1, "title": "Signals", "url": "https://dev.to/signals", "published_at": "2024-03-04T10:00:00Z"}]`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// 忽略验证器的服务器
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	collector := NewAPICollector()
+	config := CollectConfig{URL: server.URL + "/dev.to/api/articles"}
+
+	first, err := collector.Collect(context.Background(), config)
+	if err != nil || first.NotModified {
+		t.Fatalf("Expected fresh first result, got %+v (%v)", first, err)
+	}
+	second, err := collector.Collect(context.Background(), config)
+	if err != nil || !second.NotModified || len(second.Articles) != 1 {
+		t.Fatalf("Expected unchanged content to be reported as not modified, got %+v (%v)", second, err)
+	}
+
+	body = `[{"id": 2, "title": "Runes", "url": "https://dev.to/runes", "published_at": "2024-03-05T10:00:00Z"}]`
+	third, err := collector.Collect(context.Background(), config)
+	if err != nil || third.NotModified || third.Articles[0].Title != "Runes" {
+		t.Errorf("Expected changed content to be parsed, got %+v (%v)", third, err)
+	}
+}
+
+func TestHTMLCollector_Collect_NotModifiedWithoutCache(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotModified)
+	}))
+	defer server.Close()
+
+	// 没有保存的响应时，304 按普通HTTP错误处理
+	collector := NewHTMLCollector()
+	if _, err := collector.Collect(context.Background(), CollectConfig{URL: server.URL}); err == nil {
+		t.Error("Expected error for 304 without a previous response")
+	}
+}
+
+func TestConditionalCache_Evict(t *testing.T) {
+	cache := newConditionalCache()
+	resp := &http.Response{Header: http.Header{"Etag": []string{`"x"`}}}
+	for i := 0; i <= conditionalMaxEntries; i++ {
+		req := httptest.NewRequest(http.MethodGet, fmt.Sprintf("https://example.com/feed?page=%d", i), nil)
+		cache.store(req, resp, []byte("body"))
+	}
+	if len(cache.entries) != conditionalMaxEntries {
+		t.Errorf("Expected %d entries after eviction, got %d", conditionalMaxEntries, len(cache.entries))
+	}
+
+	var nilCache *conditionalCache
+	req := httptest.NewRequest(http.MethodGet, "https://example.com/feed", nil)
+	nilCache.prepare(req)
+	if nilCache.store(req, resp, []byte("body")) || req.Header.Get("If-None-Match") != "" {
+		t.Error("Expected nil cache to send plain requests")
+	}
+}
@@ -0,0 +1,183 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// 采集错误的分类，用于重试判断、指标和工具输出
+const (
+	ErrorKindValidation = "validation"
+	ErrorKindHTTP       = "http"
+	ErrorKindRateLimit  = "rate_limit"
+	ErrorKindParse      = "parse"
+	ErrorKindTimeout    = "timeout"
+	ErrorKindNetwork    = "network"
+	ErrorKindCanceled   = "canceled"
+	ErrorKindUnknown    = "unknown"
+)
+
+// maxRetryAfter 速率限制的等待时间超过该值时不再重试
+const maxRetryAfter = time.Minute
+
+// ValidationError 采集配置无效，重试不会成功
+type ValidationError struct {
+	Err error
+}
+
+func (e *ValidationError) Error() string {
+	return "validation failed: " + e.Err.Error()
+}
+
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// HTTPStatusError 数据源返回了非成功的状态码
+type HTTPStatusError struct {
+	StatusCode int
+	Status     string
+	RetryAfter time.Duration // 来自 Retry-After 响应头，未提供时为0
+}
+
+func (e *HTTPStatusError) Error() string {
+	return fmt.Sprintf("HTTP error %d: %s", e.StatusCode, e.Status)
+}
+
+// Retryable 判断状态码是否表示临时错误：请求超时、请求过多和服务端错误
+func (e *HTTPStatusError) Retryable() bool {
+	switch e.StatusCode {
+	case http.StatusRequestTimeout, http.StatusTooEarly, http.StatusTooManyRequests:
+		return true
+	}
+	return e.StatusCode >= 500
+}
+
+// RateLimitError 数据源的速率限制或配额已用尽
+type RateLimitError struct {
+	Message    string        // 为空时使用底层错误的信息
+	RetryAfter time.Duration // 距离限制解除的时间，未知时为0
+	Err        error         // 底层错误，通常是 *HTTPStatusError
+}
+
+func (e *RateLimitError) Error() string {
+	switch {
+	case e.Message != "":
+		return e.Message
+	case e.Err != nil:
+		return "rate limited: " + e.Err.Error()
+	}
+	return "rate limited"
+}
+
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// ParseError 响应内容无法解析，重复请求通常得到相同的结果
+type ParseError struct {
+	What string // 解析的内容，如 "feed"、"GitHub issues"
+	Err  error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("failed to parse %s: %v", e.What, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// newHTTPStatusError 根据非成功响应创建错误
+//
+// 429 以及 GitHub 剩余配额为0或触发二级限流（带 Retry-After）时的 403 返回包装了 *HTTPStatusError 的 *RateLimitError，
+// 等待时间取自 Retry-After 或 X-RateLimit-Reset 响应头。
+func newHTTPStatusError(resp *http.Response) error {
+	statusErr := &HTTPStatusError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+
+	exhausted := resp.StatusCode == http.StatusForbidden &&
+		(resp.Header.Get("X-RateLimit-Remaining") == "0" || statusErr.RetryAfter > 0)
+	if resp.StatusCode != http.StatusTooManyRequests && !exhausted {
+		return statusErr
+	}
+
+	retryAfter := statusErr.RetryAfter
+	if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil && retryAfter == 0 {
+		if wait := time.Until(time.Unix(reset, 0)); wait > 0 {
+			retryAfter = wait
+		}
+	}
+	return &RateLimitError{RetryAfter: retryAfter, Err: statusErr}
+}
+
+// parseRetryAfter 解析秒数或HTTP日期形式的 Retry-After
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds > 0 {
+			return time.Duration(seconds) * time.Second
+		}
+		return 0
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
+
+// ErrorKind 返回错误的分类，nil 返回空字符串
+func ErrorKind(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	var validationErr *ValidationError
+	var rateLimitErr *RateLimitError
+	var statusErr *HTTPStatusError
+	var parseErr *ParseError
+	var netErr net.Error
+	var urlErr *url.Error
+
+	switch {
+	case errors.As(err, &validationErr):
+		return ErrorKindValidation
+	case errors.As(err, &rateLimitErr):
+		return ErrorKindRateLimit
+	case errors.As(err, &statusErr):
+		return ErrorKindHTTP
+	case errors.As(err, &parseErr):
+		return ErrorKindParse
+	case errors.Is(err, context.Canceled):
+		return ErrorKindCanceled
+	case errors.Is(err, context.DeadlineExceeded), errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorKindTimeout
+	case errors.As(err, &netErr), errors.As(err, &urlErr):
+		return ErrorKindNetwork
+	}
+	return ErrorKindUnknown
+}
+
+// retryAfter 返回错误中服务器要求的等待时间，未要求时为0
+func retryAfter(err error) time.Duration {
+	var rateLimitErr *RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return rateLimitErr.RetryAfter
+	}
+	var statusErr *HTTPStatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.RetryAfter
+	}
+	return 0
+}
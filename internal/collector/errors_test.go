@@ -0,0 +1,106 @@
+package collector
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPStatusError(t *testing.T) {
+	newResponse := func(code int, header http.Header) *http.Response {
+		return &http.Response{StatusCode: code, Status: fmt.Sprintf("%d %s", code, http.StatusText(code)), Header: header}
+	}
+
+	err := newHTTPStatusError(newResponse(http.StatusServiceUnavailable, http.Header{"Retry-After": {"30"}}))
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.RetryAfter != 30*time.Second || !statusErr.Retryable() {
+		t.Errorf("Expected retryable 503 with Retry-After, got %#v", err)
+	}
+	if err.Error() != "HTTP error 503: 503 Service Unavailable" {
+		t.Errorf("Unexpected message %q", err.Error())
+	}
+
+	err = newHTTPStatusError(newResponse(http.StatusTooManyRequests, http.Header{"Retry-After": {"5"}}))
+	var rateLimitErr *RateLimitError
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter != 5*time.Second {
+		t.Errorf("Expected RateLimitError for 429, got %#v", err)
+	}
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected RateLimitError to wrap the status error, got %#v", err)
+	}
+
+	// GitHub 配额用完时返回 403 和重置时间
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	err = newHTTPStatusError(newResponse(http.StatusForbidden, http.Header{
+		"X-Ratelimit-Remaining": {"0"},
+		"X-Ratelimit-Reset":     {reset},
+	}))
+	if !errors.As(err, &rateLimitErr) || rateLimitErr.RetryAfter < 59*time.Minute {
+		t.Errorf("Expected RateLimitError with reset time for exhausted quota, got %#v", err)
+	}
+
+	err = newHTTPStatusError(newResponse(http.StatusForbidden, http.Header{}))
+	if ErrorKind(err) != ErrorKindHTTP {
+		t.Errorf("Expected plain 403 to be an HTTP error, got %s", ErrorKind(err))
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 11, 26, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value    string
+		expected time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-1", 0},
+		{now.Add(90 * time.Second).Format(http.TimeFormat), 90 * time.Second},
+		{now.Add(-time.Minute).Format(http.TimeFormat), 0},
+		{"soon", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.value, now); got != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.value, got, tt.expected)
+		}
+	}
+}
+
+type timeoutNetError struct{}
+
+func (timeoutNetError) Error() string   { return "i/o timeout" }
+func (timeoutNetError) Timeout() bool   { return true }
+func (timeoutNetError) Temporary() bool { return true }
+
+func TestErrorKind(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		expected string
+	}{
+		{"nil", nil, ""},
+		{"validation", &ValidationError{Err: errors.New("URL is required")}, ErrorKindValidation},
+		{"wrapped http", fmt.Errorf("collection failed: %w", &HTTPStatusError{StatusCode: 500}), ErrorKindHTTP},
+		{"rate limit", &RateLimitError{Err: &HTTPStatusError{StatusCode: 429}}, ErrorKindRateLimit},
+		{"parse", &ParseError{What: "feed", Err: errors.New("EOF")}, ErrorKindParse},
+		{"canceled", fmt.Errorf("failed to fetch RSS: %w", context.Canceled), ErrorKindCanceled},
+		{"deadline", context.DeadlineExceeded, ErrorKindTimeout},
+		{"net timeout", fmt.Errorf("failed to fetch RSS: %w", timeoutNetError{}), ErrorKindTimeout},
+		{"url error", &url.Error{Op: "Get", URL: "https://example.com/feed", Err: errors.New("connection refused")}, ErrorKindNetwork},
+		{"string mentioning 404", errors.New("failed to fetch https://example.com/404"), ErrorKindUnknown},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorKind(tt.err); got != tt.expected {
+				t.Errorf("ErrorKind() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,345 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// githubTagFetchConcurrency 获取标签提交时间时的最大并发数
+const githubTagFetchConcurrency = 4
+
+// GitHubRelease GitHub 发布（/repos/{owner}/{repo}/releases）
+type GitHubRelease struct {
+	ID          int    `json:"id"`
+	TagName     string `json:"tag_name"`
+	Name        string `json:"name"`
+	Body        string `json:"body"`
+	HTMLURL     string `json:"html_url"`
+	Draft       bool   `json:"draft"`
+	Prerelease  bool   `json:"prerelease"`
+	CreatedAt   string `json:"created_at"`
+	PublishedAt string `json:"published_at"`
+	Author      struct {
+		Login string `json:"login"`
+	} `json:"author"`
+}
+
+// GitHubTag GitHub 标签（/repos/{owner}/{repo}/tags）
+type GitHubTag struct {
+	Name   string `json:"name"`
+	Commit struct {
+		SHA string `json:"sha"`
+		URL string `json:"url"`
+	} `json:"commit"`
+}
+
+// gitHubCommit 标签指向的提交，只用于获取提交时间
+type gitHubCommit struct {
+	Commit struct {
+		Author struct {
+			Name string `json:"name"`
+			Date string `json:"date"`
+		} `json:"author"`
+		Committer struct {
+			Date string `json:"date"`
+		} `json:"committer"`
+	} `json:"commit"`
+}
+
+// releaseVersionPattern 从标签中提取版本号，兼容 v1.2.3、create-vite@5.0.0、1.2.0-beta.1 等形式
+var releaseVersionPattern = regexp.MustCompile(`(\d+)\.(\d+)(?:\.(\d+))?(-[0-9A-Za-z.-]+)?`)
+
+// GitHubReleasesCollector GitHub 发布和标签采集器
+//
+// 支持 /repos/{owner}/{repo}/releases 和 /repos/{owner}/{repo}/tags，每个发布或标签转换为
+// 一篇文章，标注版本号和语义化版本级别（major、minor、patch、prerelease）。
+type GitHubReleasesCollector struct {
+	client *http.Client
+}
+
+// NewGitHubReleasesCollector 创建GitHub发布采集器
+func NewGitHubReleasesCollector() *GitHubReleasesCollector {
+	return &GitHubReleasesCollector{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetSourceType 返回采集器类型
+func (g *GitHubReleasesCollector) GetSourceType() string {
+	return "github_releases"
+}
+
+// Validate 验证配置，URL 必须是仓库的 releases 或 tags 接口
+func (g *GitHubReleasesCollector) Validate(config CollectConfig) error {
+	if config.URL == "" {
+		return fmt.Errorf("URL is required")
+	}
+
+	parsedURL, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("URL must use http or https scheme")
+	}
+
+	if _, _, err := g.parseRepoURL(parsedURL); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// parseRepoURL 从地址中取出仓库全名和接口类型（releases 或 tags）
+func (g *GitHubReleasesCollector) parseRepoURL(parsedURL *url.URL) (repo, endpoint string, err error) {
+	parts := strings.Split(strings.Trim(parsedURL.Path, "/"), "/")
+	for i := 0; i+3 < len(parts); i++ {
+		if parts[i] == "repos" && (parts[i+3] == "releases" || parts[i+3] == "tags") {
+			return parts[i+1] + "/" + parts[i+2], parts[i+3], nil
+		}
+	}
+	return "", "", fmt.Errorf("URL must point to /repos/{owner}/{repo}/releases or /repos/{owner}/{repo}/tags")
+}
+
+// Collect 采集仓库的发布或标签
+func (g *GitHubReleasesCollector) Collect(ctx context.Context, config CollectConfig) (CollectResult, error) {
+	if err := g.Validate(config); err != nil {
+		return CollectResult{}, &ValidationError{Err: err}
+	}
+
+	// 设置超时
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	parsedURL, _ := url.Parse(config.URL)
+	repo, endpoint, _ := g.parseRepoURL(parsedURL)
+
+	var articles []Article
+	var err error
+	if endpoint == "tags" {
+		articles, err = g.collectTags(ctx, repo, config)
+	} else {
+		articles, err = g.collectReleases(ctx, repo, config)
+	}
+	if err != nil {
+		return CollectResult{}, err
+	}
+
+	// 限制文章数量
+	if config.MaxArticles > 0 && len(articles) > config.MaxArticles {
+		articles = articles[:config.MaxArticles]
+	}
+
+	return CollectResult{
+		Articles: articles,
+		Source:   config.URL,
+	}, nil
+}
+
+// collectReleases 采集发布，跳过草稿
+func (g *GitHubReleasesCollector) collectReleases(ctx context.Context, repo string, config CollectConfig) ([]Article, error) {
+	var releases []GitHubRelease
+	if err := g.fetchJSON(ctx, config.URL, config.Headers, &releases); err != nil {
+		return nil, err
+	}
+
+	api := &APICollector{}
+	articles := make([]Article, 0, len(releases))
+	for _, release := range releases {
+		if release.Draft {
+			continue
+		}
+
+		publishedAt, _ := time.Parse(time.RFC3339, release.PublishedAt)
+		if publishedAt.IsZero() {
+			publishedAt, _ = time.Parse(time.RFC3339, release.CreatedAt)
+		}
+
+		article := g.newArticle(repo, release.TagName, release.Prerelease, config)
+		article.ID = strconv.Itoa(release.ID)
+		article.Content = release.Body
+		article.Summary = api.extractSummary(release.Body, 200)
+		article.Author = release.Author.Login
+		article.URL = release.HTMLURL
+		article.PublishedAt = publishedAt
+		if release.Name != "" && release.Name != release.TagName {
+			article.Metadata["release_name"] = release.Name
+		}
+
+		g.applyConfigMetadata(&article, config)
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
+
+// collectTags 采集标签。标签接口不包含时间，按列表顺序并发获取前 MaxArticles 个标签的提交时间
+func (g *GitHubReleasesCollector) collectTags(ctx context.Context, repo string, config CollectConfig) ([]Article, error) {
+	var tags []GitHubTag
+	if err := g.fetchJSON(ctx, config.URL, config.Headers, &tags); err != nil {
+		return nil, err
+	}
+
+	if config.MaxArticles > 0 && len(tags) > config.MaxArticles {
+		tags = tags[:config.MaxArticles]
+	}
+
+	commits := make([]*gitHubCommit, len(tags))
+	semaphore := make(chan struct{}, githubTagFetchConcurrency)
+	var wg sync.WaitGroup
+	for i, tag := range tags {
+		if tag.Commit.URL == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, commitURL string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			var commit gitHubCommit
+			// 单个提交失败时保留标签，只是缺少时间
+			if err := g.fetchJSON(ctx, commitURL, config.Headers, &commit); err == nil {
+				commits[i] = &commit
+			}
+		}(i, tag.Commit.URL)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	articles := make([]Article, 0, len(tags))
+	for i, tag := range tags {
+		article := g.newArticle(repo, tag.Name, false, config)
+		article.ID = repo + "@" + tag.Name
+		article.URL = "https://github.com/" + repo + "/releases/tag/" + url.PathEscape(tag.Name)
+		article.Summary = fmt.Sprintf("%s tagged %s", repo, tag.Name)
+		article.Metadata["commit_sha"] = tag.Commit.SHA
+		if commit := commits[i]; commit != nil {
+			date := commit.Commit.Committer.Date
+			if date == "" {
+				date = commit.Commit.Author.Date
+			}
+			article.PublishedAt, _ = time.Parse(time.RFC3339, date)
+			article.Author = commit.Commit.Author.Name
+		}
+
+		g.applyConfigMetadata(&article, config)
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
+
+// newArticle 创建带版本信息的发布文章
+func (g *GitHubReleasesCollector) newArticle(repo, tagName string, prerelease bool, config CollectConfig) Article {
+	version, level := parseReleaseVersion(tagName, prerelease)
+
+	tags := []string{"release"}
+	if level != "" {
+		tags = append(tags, level)
+	}
+
+	article := Article{
+		Title:      repo + " " + tagName,
+		Source:     config.URL,
+		SourceType: g.GetSourceType(),
+		Language:   config.Language,
+		Tags:       append(tags, config.Tags...),
+		Metadata:   make(map[string]string),
+	}
+
+	// 添加元数据
+	article.Metadata["github_release"] = "true"
+	article.Metadata["repository"] = repo
+	article.Metadata["tag_name"] = tagName
+	article.Metadata["version"] = version
+	article.Metadata["semver_level"] = level
+	article.Metadata["prerelease"] = strconv.FormatBool(level == "prerelease")
+
+	return article
+}
+
+// applyConfigMetadata 合并配置中的元数据
+func (g *GitHubReleasesCollector) applyConfigMetadata(article *Article, config CollectConfig) {
+	if config.Metadata != nil {
+		for k, v := range config.Metadata {
+			article.Metadata[k] = v
+		}
+	}
+}
+
+// parseReleaseVersion 从标签中提取版本号并判断语义化版本级别
+//
+// 预发布版本（带 -beta.1 等后缀或被标记为 prerelease）为 prerelease，x.0.0 为 major，
+// x.y.0 为 minor，其余为 patch；无法识别版本号时返回原标签和空级别。
+func parseReleaseVersion(tagName string, prerelease bool) (version, level string) {
+	match := releaseVersionPattern.FindStringSubmatch(tagName)
+	if match == nil {
+		return tagName, ""
+	}
+
+	version = match[0]
+	switch {
+	case prerelease || match[4] != "":
+		level = "prerelease"
+	case match[2] == "0" && (match[3] == "" || match[3] == "0"):
+		level = "major"
+	case match[3] == "" || match[3] == "0":
+		level = "minor"
+	default:
+		level = "patch"
+	}
+	return version, level
+}
+
+// fetchJSON 获取JSON并解析到 v
+func (g *GitHubReleasesCollector) fetchJSON(ctx context.Context, apiURL string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "API Collector/1.0")
+	req.Header.Set("Accept", "application/vnd.github.v3+json")
+
+	// 设置自定义头部
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch GitHub API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newHTTPStatusError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return &ParseError{What: "GitHub response", Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,143 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const githubReleasesResponse = `[
+	{
+		"id": 3, "tag_name": "v6.0.0-beta.1", "name": "v6.0.0-beta.1", "body": "Beta",
+		"html_url": "https://github.com/vitejs/vite/releases/tag/v6.0.0-beta.1",
+		"draft": false, "prerelease": true, "published_at": "2024-03-06T10:00:00Z",
+		"author": {"login": "patak"}
+	},
+	{
+		"id": 2, "tag_name": "v5.1.0", "name": "Vite 5.1", "body": "## Features\n\n- Vite Runtime API",
+		"html_url": "https://github.com/vitejs/vite/releases/tag/v5.1.0",
+		"draft": false, "prerelease": false, "published_at": "2024-03-05T10:00:00Z",
+		"author": {"login": "bluwy"}
+	},
+	{"id": 1, "tag_name": "v5.2.0", "draft": true}
+]`
+
+func TestGitHubReleasesCollector_CollectReleases(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/vitejs/vite/releases" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(githubReleasesResponse))
+	}))
+	defer server.Close()
+
+	collector := NewGitHubReleasesCollector()
+	result, err := collector.Collect(context.Background(), CollectConfig{
+		URL:  server.URL + "/repos/vitejs/vite/releases?per_page=10",
+		Tags: []string{"vite"},
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected drafts to be skipped, got %d articles", len(result.Articles))
+	}
+
+	beta := result.Articles[0]
+	if beta.Metadata["semver_level"] != "prerelease" || beta.Metadata["prerelease"] != "true" || beta.Metadata["version"] != "6.0.0-beta.1" {
+		t.Errorf("Expected prerelease version, got %v", beta.Metadata)
+	}
+
+	minor := result.Articles[1]
+	if minor.Title != "vitejs/vite v5.1.0" || minor.URL != "https://github.com/vitejs/vite/releases/tag/v5.1.0" {
+		t.Errorf("Unexpected title or URL: %q %q", minor.Title, minor.URL)
+	}
+	if minor.Metadata["semver_level"] != "minor" || minor.Metadata["repository"] != "vitejs/vite" || minor.Metadata["release_name"] != "Vite 5.1" {
+		t.Errorf("Expected minor release metadata, got %v", minor.Metadata)
+	}
+	if len(minor.Tags) != 3 || minor.Tags[0] != "release" || minor.Tags[1] != "minor" || minor.Tags[2] != "vite" {
+		t.Errorf("Unexpected tags: %v", minor.Tags)
+	}
+	if !strings.Contains(minor.Content, "Vite Runtime API") || minor.Author != "bluwy" {
+		t.Errorf("Expected release notes and author, got %q by %q", minor.Content, minor.Author)
+	}
+	if !minor.PublishedAt.Equal(time.Date(2024, 3, 5, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected published_at as published time, got %v", minor.PublishedAt)
+	}
+}
+
+func TestGitHubReleasesCollector_CollectTags(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/facebook/react/tags":
+			w.Write([]byte(`[
+				{"name": "v19.0.0", "commit": {"sha": "abc", "url": "` + server.URL + `/repos/facebook/react/commits/abc"}},
+				{"name": "v18.3.1", "commit": {"sha": "def", "url": "` + server.URL + `/repos/facebook/react/commits/def"}}
+			]`))
+		case "/repos/facebook/react/commits/abc":
+			w.Write([]byte(`{"commit": {"author": {"name": "acdlite", "date": "2024-12-05T00:00:00Z"}, "committer": {"date": "2024-12-05T01:00:00Z"}}}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	collector := NewGitHubReleasesCollector()
+	result, err := collector.Collect(context.Background(), CollectConfig{URL: server.URL + "/repos/facebook/react/tags"})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected a tag without commit date to be kept, got %d articles", len(result.Articles))
+	}
+
+	major := result.Articles[0]
+	if major.Metadata["semver_level"] != "major" || major.Metadata["commit_sha"] != "abc" || major.Author != "acdlite" {
+		t.Errorf("Expected major tag with commit details, got %v by %q", major.Metadata, major.Author)
+	}
+	if !major.PublishedAt.Equal(time.Date(2024, 12, 5, 1, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected committer date as published time, got %v", major.PublishedAt)
+	}
+	if result.Articles[1].Metadata["semver_level"] != "patch" || !result.Articles[1].PublishedAt.IsZero() {
+		t.Errorf("Expected patch tag without date, got %+v", result.Articles[1])
+	}
+}
+
+func TestParseReleaseVersion(t *testing.T) {
+	tests := []struct {
+		tag        string
+		prerelease bool
+		version    string
+		level      string
+	}{
+		{"v3.0.0", false, "3.0.0", "major"},
+		{"create-vite@5.2.0", false, "5.2.0", "minor"},
+		{"v14.1", false, "14.1", "minor"},
+		{"18.3.1", false, "18.3.1", "patch"},
+		{"v5.0.0-rc.1", false, "5.0.0-rc.1", "prerelease"},
+		{"v4.2.0", true, "4.2.0", "prerelease"},
+		{"canary", false, "canary", ""},
+	}
+
+	for _, tt := range tests {
+		version, level := parseReleaseVersion(tt.tag, tt.prerelease)
+		if version != tt.version || level != tt.level {
+			t.Errorf("parseReleaseVersion(%q, %v) = %q, %q, want %q, %q", tt.tag, tt.prerelease, version, level, tt.version, tt.level)
+		}
+	}
+}
+
+func TestGitHubReleasesCollector_Validate(t *testing.T) {
+	collector := NewGitHubReleasesCollector()
+	if err := collector.Validate(CollectConfig{URL: "https://api.github.com/repos/vuejs/core"}); err == nil {
+		t.Error("Expected error for URL without releases or tags")
+	}
+	if err := collector.Validate(CollectConfig{URL: "https://api.github.com/repos/vuejs/core/releases"}); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+}
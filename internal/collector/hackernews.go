@@ -0,0 +1,353 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// hackerNewsItemURL HN 讨论页地址前缀
+const hackerNewsItemURL = "https://news.ycombinator.com/item?id="
+
+// hackerNewsFetchConcurrency 通过 Firebase 逐条获取条目时的最大并发数
+const hackerNewsFetchConcurrency = 8
+
+// HackerNewsHit Algolia 搜索结果（/api/v1/search、/api/v1/search_by_date）
+type HackerNewsHit struct {
+	ObjectID    string   `json:"objectID"`
+	Title       string   `json:"title"`
+	URL         string   `json:"url"`
+	Author      string   `json:"author"`
+	Points      int      `json:"points"`
+	NumComments int      `json:"num_comments"`
+	CreatedAtI  int64    `json:"created_at_i"`
+	StoryText   string   `json:"story_text"`
+	Tags        []string `json:"_tags"`
+}
+
+// HackerNewsItem Firebase 条目（/v0/item/<id>.json）
+type HackerNewsItem struct {
+	ID          int    `json:"id"`
+	Type        string `json:"type"`
+	By          string `json:"by"`
+	Time        int64  `json:"time"`
+	Title       string `json:"title"`
+	URL         string `json:"url"`
+	Text        string `json:"text"`
+	Score       int    `json:"score"`
+	Descendants int    `json:"descendants"`
+	Deleted     bool   `json:"deleted"`
+	Dead        bool   `json:"dead"`
+}
+
+// hackerNewsStory Algolia 和 Firebase 条目的统一表示
+type hackerNewsStory struct {
+	ID       string
+	Title    string
+	URL      string
+	Author   string
+	Points   int
+	Comments int
+	Created  time.Time
+	Text     string // HTML
+	Kind     string // story、show_hn、ask_hn、job
+}
+
+// HackerNewsCollector Hacker News 采集器
+//
+// 支持 Algolia 搜索接口（hn.algolia.com/api/v1/search?query=...&tags=front_page|show_hn|ask_hn）
+// 和 Firebase 列表接口（hacker-news.firebaseio.com/v0/topstories.json、showstories.json、
+// askstories.json 等），后者按ID逐条获取条目。
+type HackerNewsCollector struct {
+	client *http.Client
+}
+
+// NewHackerNewsCollector 创建Hacker News采集器
+func NewHackerNewsCollector() *HackerNewsCollector {
+	return &HackerNewsCollector{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetSourceType 返回采集器类型
+func (h *HackerNewsCollector) GetSourceType() string {
+	return "hackernews"
+}
+
+// Validate 验证配置
+func (h *HackerNewsCollector) Validate(config CollectConfig) error {
+	if config.URL == "" {
+		return fmt.Errorf("URL is required")
+	}
+
+	parsedURL, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("URL must use http or https scheme")
+	}
+
+	return nil
+}
+
+// Collect 采集Hacker News条目，URL 以 stories.json 结尾时使用 Firebase 列表接口，否则按 Algolia 搜索结果解析
+func (h *HackerNewsCollector) Collect(ctx context.Context, config CollectConfig) (CollectResult, error) {
+	if err := h.Validate(config); err != nil {
+		return CollectResult{}, &ValidationError{Err: err}
+	}
+
+	// 设置超时
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	var stories []hackerNewsStory
+	var err error
+	if h.isFirebaseList(config.URL) {
+		stories, err = h.collectFirebase(ctx, config)
+	} else {
+		stories, err = h.collectAlgolia(ctx, config)
+	}
+	if err != nil {
+		return CollectResult{}, err
+	}
+
+	// 限制文章数量
+	if config.MaxArticles > 0 && len(stories) > config.MaxArticles {
+		stories = stories[:config.MaxArticles]
+	}
+
+	return CollectResult{
+		Articles: h.convertStories(stories, config),
+		Source:   config.URL,
+	}, nil
+}
+
+// isFirebaseList 判断是否为 Firebase 的条目ID列表接口
+func (h *HackerNewsCollector) isFirebaseList(apiURL string) bool {
+	parsedURL, err := url.Parse(apiURL)
+	return err == nil && strings.HasSuffix(parsedURL.Path, "stories.json")
+}
+
+// collectAlgolia 采集 Algolia 搜索结果
+func (h *HackerNewsCollector) collectAlgolia(ctx context.Context, config CollectConfig) ([]hackerNewsStory, error) {
+	var response struct {
+		Hits []HackerNewsHit `json:"hits"`
+	}
+	if err := h.fetchJSON(ctx, config.URL, config.Headers, &response); err != nil {
+		return nil, err
+	}
+
+	stories := make([]hackerNewsStory, 0, len(response.Hits))
+	for _, hit := range response.Hits {
+		if hit.Title == "" {
+			continue // 评论等非帖子条目
+		}
+		stories = append(stories, hackerNewsStory{
+			ID:       hit.ObjectID,
+			Title:    hit.Title,
+			URL:      hit.URL,
+			Author:   hit.Author,
+			Points:   hit.Points,
+			Comments: hit.NumComments,
+			Created:  time.Unix(hit.CreatedAtI, 0).UTC(),
+			Text:     hit.StoryText,
+			Kind:     hackerNewsKind(hit.Tags),
+		})
+	}
+	return stories, nil
+}
+
+// hackerNewsKind 从 Algolia 的 _tags 中取出帖子类型
+func hackerNewsKind(tags []string) string {
+	for _, kind := range []string{"show_hn", "ask_hn", "job"} {
+		for _, tag := range tags {
+			if tag == kind {
+				return kind
+			}
+		}
+	}
+	return "story"
+}
+
+// collectFirebase 获取 Firebase 列表中的条目ID，再并发获取各条目，保持列表顺序
+func (h *HackerNewsCollector) collectFirebase(ctx context.Context, config CollectConfig) ([]hackerNewsStory, error) {
+	var ids []int
+	if err := h.fetchJSON(ctx, config.URL, config.Headers, &ids); err != nil {
+		return nil, err
+	}
+
+	limit := config.MaxArticles
+	if limit <= 0 {
+		limit = 30
+	}
+	if len(ids) > limit {
+		ids = ids[:limit]
+	}
+
+	// 条目地址与列表使用同一API版本，例如 /v0/topstories.json -> /v0/item/<id>.json
+	listURL, _ := url.Parse(config.URL)
+	base := *listURL
+	base.RawQuery = ""
+	base.Path = listURL.Path[:strings.LastIndex(listURL.Path, "/")+1]
+
+	items := make([]*HackerNewsItem, len(ids))
+	semaphore := make(chan struct{}, hackerNewsFetchConcurrency)
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i, id int) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			itemURL := base
+			itemURL.Path += "item/" + strconv.Itoa(id) + ".json"
+			var item HackerNewsItem
+			// 单个条目失败时跳过，不影响整个列表
+			if err := h.fetchJSON(ctx, itemURL.String(), config.Headers, &item); err == nil {
+				items[i] = &item
+			}
+		}(i, id)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	stories := make([]hackerNewsStory, 0, len(items))
+	for _, item := range items {
+		if item == nil || item.Deleted || item.Dead || item.Title == "" {
+			continue
+		}
+		stories = append(stories, hackerNewsStory{
+			ID:       strconv.Itoa(item.ID),
+			Title:    item.Title,
+			URL:      item.URL,
+			Author:   item.By,
+			Points:   item.Score,
+			Comments: item.Descendants,
+			Created:  time.Unix(item.Time, 0).UTC(),
+			Text:     item.Text,
+			Kind:     firebaseKind(item),
+		})
+	}
+	return stories, nil
+}
+
+// firebaseKind 根据条目类型和标题前缀判断帖子类型
+func firebaseKind(item *HackerNewsItem) string {
+	switch {
+	case item.Type == "job":
+		return "job"
+	case strings.HasPrefix(item.Title, "Show HN:"):
+		return "show_hn"
+	case strings.HasPrefix(item.Title, "Ask HN:"):
+		return "ask_hn"
+	default:
+		return "story"
+	}
+}
+
+// fetchJSON 获取JSON并解析到 v
+func (h *HackerNewsCollector) fetchJSON(ctx context.Context, apiURL string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "API Collector/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	// 设置自定义头部
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := h.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch Hacker News API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newHTTPStatusError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return &ParseError{What: "Hacker News response", Err: err}
+	}
+	return nil
+}
+
+// convertStories 转换HN帖子为文章
+//
+// 外链帖子的 URL 指向原文，Ask HN 等没有外链的帖子指向讨论页；讨论页地址始终保存在
+// 元数据 discussion_url 中。
+func (h *HackerNewsCollector) convertStories(stories []hackerNewsStory, config CollectConfig) []Article {
+	articles := make([]Article, 0, len(stories))
+	api := &APICollector{}
+
+	for _, story := range stories {
+		discussionURL := hackerNewsItemURL + story.ID
+		content := bodyToMarkdown(story.Text)
+		summary := api.extractSummary(content, 200)
+		if summary == "" {
+			summary = fmt.Sprintf("%d points and %d comments on Hacker News", story.Points, story.Comments)
+		}
+
+		article := Article{
+			ID:          story.ID,
+			Title:       story.Title,
+			Content:     content,
+			Summary:     summary,
+			Author:      story.Author,
+			URL:         story.URL,
+			PublishedAt: story.Created,
+			Source:      config.URL,
+			SourceType:  h.GetSourceType(),
+			Language:    config.Language,
+			Tags:        append([]string{"hackernews", story.Kind}, config.Tags...),
+			Metadata:    make(map[string]string),
+		}
+		if article.URL == "" {
+			article.URL = discussionURL
+		}
+
+		// 添加元数据
+		article.Metadata["hackernews_story"] = "true"
+		article.Metadata["points"] = strconv.Itoa(story.Points)
+		article.Metadata["num_comments"] = strconv.Itoa(story.Comments)
+		article.Metadata["story_type"] = story.Kind
+		article.Metadata["discussion_url"] = discussionURL
+
+		if config.Metadata != nil {
+			for k, v := range config.Metadata {
+				article.Metadata[k] = v
+			}
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles
+}
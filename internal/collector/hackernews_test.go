@@ -0,0 +1,140 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const hackerNewsSearchResponse = `{
+	"hits": [
+		{
+			"objectID": "1001", "title": "Show HN: A tiny React state library",
+			"url": "https://example.com/tiny-state", "author": "alice",
+			"points": 312, "num_comments": 140, "created_at_i": 1700000000,
+			"_tags": ["story", "author_alice", "story_1001", "show_hn"]
+		},
+		{
+			"objectID": "1002", "title": "Ask HN: How do you test CSS?", "url": null,
+			"author": "bob", "points": 45, "num_comments": 30, "created_at_i": 1700000100,
+			"story_text": "<p>We use:</p><pre><code>npx playwright test\n</code></pre>",
+			"_tags": ["story", "ask_hn"]
+		},
+		{"objectID": "1003", "title": null, "author": "carol", "_tags": ["comment"]}
+	]
+}`
+
+func TestHackerNewsCollector_CollectAlgolia(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.Query().Get("query")
+		w.Write([]byte(hackerNewsSearchResponse))
+	}))
+	defer server.Close()
+
+	collector := NewHackerNewsCollector()
+	result, err := collector.Collect(context.Background(), CollectConfig{
+		URL:  server.URL + "/api/v1/search?query=react&tags=story",
+		Tags: []string{"react"},
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if gotQuery != "react" {
+		t.Errorf("Expected search query to be sent, got %q", gotQuery)
+	}
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected comments to be skipped, got %d articles", len(result.Articles))
+	}
+
+	show := result.Articles[0]
+	if show.URL != "https://example.com/tiny-state" || show.Metadata["discussion_url"] != "https://news.ycombinator.com/item?id=1001" {
+		t.Errorf("Expected story URL and discussion URL, got %s %v", show.URL, show.Metadata)
+	}
+	if show.Metadata["points"] != "312" || show.Metadata["num_comments"] != "140" || show.Metadata["story_type"] != "show_hn" {
+		t.Errorf("Expected points, comments and story type, got %v", show.Metadata)
+	}
+	if show.Summary != "312 points and 140 comments on Hacker News" {
+		t.Errorf("Expected points summary for link story, got %q", show.Summary)
+	}
+	if len(show.Tags) != 3 || show.Tags[0] != "hackernews" || show.Tags[1] != "show_hn" || show.Tags[2] != "react" {
+		t.Errorf("Unexpected tags: %v", show.Tags)
+	}
+	if !show.PublishedAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expected created_at_i as published time, got %v", show.PublishedAt)
+	}
+
+	ask := result.Articles[1]
+	if ask.URL != "https://news.ycombinator.com/item?id=1002" {
+		t.Errorf("Expected Ask HN to link to the discussion, got %s", ask.URL)
+	}
+	if !strings.Contains(ask.Content, "```\nnpx playwright test\n```") {
+		t.Errorf("Expected story text with code fence, got %q", ask.Content)
+	}
+}
+
+func TestHackerNewsCollector_CollectFirebase(t *testing.T) {
+	items := map[string]string{
+		"1": `{"id": 1, "type": "story", "by": "alice", "time": 1700000000, "title": "Show HN: CSS playground", "url": "https://example.com/css", "score": 99, "descendants": 12}`,
+		"2": `{"id": 2, "deleted": true}`,
+		"3": `{"id": 3, "type": "story", "by": "bob", "time": 1700000200, "title": "Ask HN: Favourite bundler?", "text": "Vite or webpack?", "score": 10, "descendants": 40}`,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/topstories.json" {
+			w.Write([]byte(`[1, 2, 3, 4]`))
+			return
+		}
+		var id string
+		if _, err := fmt.Sscanf(r.URL.Path, "/v0/item/%s", &id); err == nil {
+			if item, ok := items[strings.TrimSuffix(id, ".json")]; ok {
+				w.Write([]byte(item))
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	collector := NewHackerNewsCollector()
+	result, err := collector.Collect(context.Background(), CollectConfig{URL: server.URL + "/v0/topstories.json"})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected deleted and missing items to be skipped, got %d articles", len(result.Articles))
+	}
+	if result.Articles[0].ID != "1" || result.Articles[1].ID != "3" {
+		t.Errorf("Expected list order to be kept, got %s, %s", result.Articles[0].ID, result.Articles[1].ID)
+	}
+	if result.Articles[0].Metadata["story_type"] != "show_hn" || result.Articles[1].Metadata["story_type"] != "ask_hn" {
+		t.Errorf("Expected story types from title prefixes, got %v, %v", result.Articles[0].Metadata, result.Articles[1].Metadata)
+	}
+	if result.Articles[1].Metadata["points"] != "10" || result.Articles[1].Metadata["num_comments"] != "40" {
+		t.Errorf("Expected score and descendants as points and comments, got %v", result.Articles[1].Metadata)
+	}
+}
+
+func TestHackerNewsCollector_FirebaseLimit(t *testing.T) {
+	var itemRequests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v0/askstories.json" {
+			w.Write([]byte(`[1, 2, 3, 4, 5]`))
+			return
+		}
+		atomic.AddInt32(&itemRequests, 1)
+		w.Write([]byte(`{"id": 1, "type": "story", "title": "Ask HN: anything", "time": 1700000000}`))
+	}))
+	defer server.Close()
+
+	collector := NewHackerNewsCollector()
+	collector.Collect(context.Background(), CollectConfig{URL: server.URL + "/v0/askstories.json", MaxArticles: 2})
+	if got := atomic.LoadInt32(&itemRequests); got != 2 {
+		t.Errorf("Expected only MaxArticles items to be fetched, got %d requests", got)
+	}
+}
@@ -10,22 +10,87 @@ import (
 	"strconv"
 	"strings"
 	"time"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
 )
 
 // HTMLSelector 定义HTML选择器配置
+//
+// 选择器使用CSS语法。设置 Item 时每个匹配的元素为一篇文章，其余选择器在该元素内查找；
+// 未设置 Item 时整个页面为一篇文章。未设置的字段使用默认的启发式提取。
 type HTMLSelector struct {
+	Item        string `json:"item"`         // 文章块选择器
 	Title       string `json:"title"`        // 标题选择器
 	Content     string `json:"content"`      // 内容选择器
 	Summary     string `json:"summary"`      // 摘要选择器
 	Author      string `json:"author"`       // 作者选择器
-	PublishedAt string `json:"published_at"` // 发布时间选择器
-	Tags        string `json:"tags"`         // 标签选择器
-	Links       string `json:"links"`        // 链接选择器
+	PublishedAt string `json:"published_at"` // 发布时间选择器，优先读取 datetime、content 属性
+	Tags        string `json:"tags"`         // 标签选择器，每个匹配的元素为一个标签
+	Links       string `json:"links"`        // 链接选择器，读取 href 属性
+}
+
+// Validate 检查各选择器的语法
+func (s *HTMLSelector) Validate() error {
+	_, err := s.compile()
+	return err
+}
+
+// compiledSelector 解析后的 HTMLSelector，未设置的字段为nil
+type compiledSelector struct {
+	item, title, content, summary, author, publishedAt, tags, links cssSelector
+}
+
+// compile 解析各选择器
+func (s *HTMLSelector) compile() (*compiledSelector, error) {
+	compiled := &compiledSelector{}
+	fields := []struct {
+		name     string
+		selector string
+		target   *cssSelector
+	}{
+		{"item", s.Item, &compiled.item},
+		{"title", s.Title, &compiled.title},
+		{"content", s.Content, &compiled.content},
+		{"summary", s.Summary, &compiled.summary},
+		{"author", s.Author, &compiled.author},
+		{"published_at", s.PublishedAt, &compiled.publishedAt},
+		{"tags", s.Tags, &compiled.tags},
+		{"links", s.Links, &compiled.links},
+	}
+	for _, field := range fields {
+		if strings.TrimSpace(field.selector) == "" {
+			continue
+		}
+		selector, err := compileSelector(field.selector)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", field.name, err)
+		}
+		*field.target = selector
+	}
+	return compiled, nil
+}
+
+// articleBlockSelectors 未配置选择器时依次尝试的文章块选择器
+var articleBlockSelectors = []cssSelector{
+	mustCompileSelector("article"),
+	mustCompileSelector(`div[class*="post"]`),
+	mustCompileSelector(`div[class*="item"]`),
+	mustCompileSelector(`div[class*="entry"]`),
+}
+
+func mustCompileSelector(selector string) cssSelector {
+	compiled, err := compileSelector(selector)
+	if err != nil {
+		panic(err)
+	}
+	return compiled
 }
 
 // HTMLCollector HTML网页采集器
 type HTMLCollector struct {
-	client *http.Client
+	client      *http.Client
+	conditional *conditionalCache
 }
 
 // NewHTMLCollector 创建HTML采集器
@@ -34,6 +99,7 @@ func NewHTMLCollector() *HTMLCollector {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		conditional: newConditionalCache(),
 	}
 }
 
@@ -56,6 +122,12 @@ func (h *HTMLCollector) Validate(config CollectConfig) error {
 	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
 		return fmt.Errorf("URL must use http or https scheme")
 	}
+
+	if config.Selector != nil {
+		if err := config.Selector.Validate(); err != nil {
+			return fmt.Errorf("invalid selector: %w", err)
+		}
+	}
 	
 	return nil
 }
@@ -63,7 +135,7 @@ func (h *HTMLCollector) Validate(config CollectConfig) error {
 // Collect 采集HTML页面数据
 func (h *HTMLCollector) Collect(ctx context.Context, config CollectConfig) (CollectResult, error) {
 	if err := h.Validate(config); err != nil {
-		return CollectResult{}, fmt.Errorf("validation failed: %w", err)
+		return CollectResult{}, &ValidationError{Err: err}
 	}
 
 	// 设置超时
@@ -74,7 +146,7 @@ func (h *HTMLCollector) Collect(ctx context.Context, config CollectConfig) (Coll
 	}
 
 	// 获取HTML内容
-	htmlContent, err := h.fetchHTML(ctx, config)
+	htmlContent, notModified, err := h.fetchHTML(ctx, config)
 	if err != nil {
 		return CollectResult{}, err
 	}
@@ -82,7 +154,7 @@ func (h *HTMLCollector) Collect(ctx context.Context, config CollectConfig) (Coll
 	// 解析HTML内容
 	articles, err := h.parseHTML(htmlContent, config)
 	if err != nil {
-		return CollectResult{}, fmt.Errorf("failed to parse HTML: %w", err)
+		return CollectResult{}, &ParseError{What: "HTML", Err: err}
 	}
 
 	// 限制文章数量
@@ -91,16 +163,17 @@ func (h *HTMLCollector) Collect(ctx context.Context, config CollectConfig) (Coll
 	}
 
 	return CollectResult{
-		Articles: articles,
-		Source:   config.URL,
+		Articles:    articles,
+		Source:      config.URL,
+		NotModified: notModified,
 	}, nil
 }
 
-// fetchHTML 获取HTML内容
-func (h *HTMLCollector) fetchHTML(ctx context.Context, config CollectConfig) (string, error) {
+// fetchHTML 获取HTML内容，notModified 表示页面自上次采集后没有变化
+func (h *HTMLCollector) fetchHTML(ctx context.Context, config CollectConfig) (string, bool, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", config.URL, nil)
 	if err != nil {
-		return "", fmt.Errorf("failed to create request: %w", err)
+		return "", false, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	// 设置默认头部
@@ -113,26 +186,37 @@ func (h *HTMLCollector) fetchHTML(ctx context.Context, config CollectConfig) (st
 		req.Header.Set(key, value)
 	}
 
+	h.conditional.prepare(req)
+
 	resp, err := h.client.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to fetch HTML: %w", err)
+		return "", false, fmt.Errorf("failed to fetch HTML: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if cached, ok := h.conditional.notModified(req, resp); ok {
+		return string(cached.Body), true, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("HTTP error %d: %s", resp.StatusCode, resp.Status)
+		return "", false, newHTTPStatusError(resp)
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return "", fmt.Errorf("failed to read response body: %w", err)
+		return "", false, fmt.Errorf("failed to read response body: %w", err)
 	}
 
-	return string(body), nil
+	return string(body), h.conditional.store(req, resp, body), nil
 }
 
 // parseHTML 解析HTML内容
 func (h *HTMLCollector) parseHTML(htmlContent string, config CollectConfig) ([]Article, error) {
+	// 配置了选择器时按选择器提取
+	if config.Selector != nil {
+		return h.parseWithSelector(htmlContent, config)
+	}
+
 	// 检查是否是文章列表页面还是单篇文章页面
 	if h.isArticleListPage(htmlContent) {
 		return h.parseArticleList(htmlContent, config)
@@ -258,34 +342,231 @@ func (h *HTMLCollector) parseSingleArticle(htmlContent string, config CollectCon
 	return []Article{article}, nil
 }
 
-// extractArticleBlocks 提取文章块
+// extractArticleBlocks 提取文章块，返回各块的内部HTML
+//
+// 依次尝试 articleBlockSelectors，使用第一个有匹配的选择器。匹配的元素按父元素分组，
+// 取数量最多的一组作为文章列表，避免把列表容器或块内的子元素（如 post-meta）当作文章。
 func (h *HTMLCollector) extractArticleBlocks(htmlContent string) []string {
-	var blocks []string
+	doc, err := xhtml.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil
+	}
 
-	// 尝试不同的文章块选择器
-	selectors := []string{
-		`<article[^>]*>(.*?)</article>`,
-		`<div[^>]*class="[^"]*post[^"]*"[^>]*>(.*?)</div>`,
-		`<div[^>]*class="[^"]*item[^"]*"[^>]*>(.*?)</div>`,
-		`<div[^>]*class="[^"]*entry[^"]*"[^>]*>(.*?)</div>`,
+	for _, selector := range articleBlockSelectors {
+		nodes := repeatedSiblings(selector.selectAll(doc))
+		if len(nodes) == 0 {
+			continue
+		}
+
+		blocks := make([]string, 0, len(nodes))
+		for _, node := range nodes {
+			blocks = append(blocks, renderInnerHTML(node))
+		}
+		return blocks
 	}
 
-	for _, selector := range selectors {
-		re := regexp.MustCompile(selector)
-		matches := re.FindAllStringSubmatch(htmlContent, -1)
-		if len(matches) > 0 {
-			for _, match := range matches {
-				if len(match) > 1 {
-					blocks = append(blocks, match[1])
+	return nil
+}
+
+// repeatedSiblings 返回同一父元素下数量最多的一组匹配元素，数量相同时取文档中靠前的一组
+func repeatedSiblings(nodes []*xhtml.Node) []*xhtml.Node {
+	groups := make(map[*xhtml.Node][]*xhtml.Node)
+	var parents []*xhtml.Node
+	for _, node := range nodes {
+		if _, exists := groups[node.Parent]; !exists {
+			parents = append(parents, node.Parent)
+		}
+		groups[node.Parent] = append(groups[node.Parent], node)
+	}
+
+	var best []*xhtml.Node
+	for _, parent := range parents {
+		if len(groups[parent]) > len(best) {
+			best = groups[parent]
+		}
+	}
+	return best
+}
+
+// renderInnerHTML 渲染元素的子节点
+func renderInnerHTML(node *xhtml.Node) string {
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		xhtml.Render(&b, child)
+	}
+	return b.String()
+}
+
+// parseWithSelector 按配置的CSS选择器提取文章
+func (h *HTMLCollector) parseWithSelector(htmlContent string, config CollectConfig) ([]Article, error) {
+	selector, err := config.Selector.compile()
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := xhtml.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, err
+	}
+
+	// 未设置文章块选择器时整个页面为一篇文章
+	if selector.item == nil {
+		article := h.extractWithSelector(doc, selector, config, true)
+		article.ID = h.generateID(config.URL)
+		article.Metadata["page_type"] = "single_article"
+		return []Article{article}, nil
+	}
+
+	var articles []Article
+	for i, node := range selector.item.selectAll(doc) {
+		article := h.extractWithSelector(node, selector, config, false)
+		if article.Title == "" && article.Content == "" {
+			continue
+		}
+		if article.URL != config.URL {
+			article.ID = h.generateID(article.URL + article.Title)
+		} else {
+			article.ID = h.generateID(config.URL + article.Title + strconv.Itoa(i))
+		}
+		article.Metadata["block_index"] = strconv.Itoa(i)
+		articles = append(articles, article)
+	}
+	return articles, nil
+}
+
+// extractWithSelector 在 scope 内按选择器提取文章字段，未配置的字段使用默认的提取方法
+func (h *HTMLCollector) extractWithSelector(scope *xhtml.Node, selector *compiledSelector, config CollectConfig, page bool) Article {
+	article := Article{
+		Source:      config.URL,
+		SourceType:  h.GetSourceType(),
+		Language:    config.Language,
+		URL:         config.URL,
+		PublishedAt: time.Now(),
+		Tags:        append([]string(nil), config.Tags...),
+		Metadata:    make(map[string]string),
+	}
+	block := renderInnerHTML(scope)
+
+	switch {
+	case selector.title != nil:
+		article.Title = selectedValue(selector.title.selectFirst(scope))
+	case page:
+		article.Title = h.extractTitle(block)
+	default:
+		article.Title = h.extractTitleFromBlock(block)
+	}
+
+	switch {
+	case selector.content != nil:
+		article.Content = selectedText(selector.content.selectFirst(scope))
+	case page:
+		article.Content = h.extractContent(block)
+	default:
+		article.Content = h.cleanHTML(block)
+	}
+
+	if selector.summary != nil {
+		article.Summary = selectedValue(selector.summary.selectFirst(scope))
+	}
+	if article.Summary == "" {
+		article.Summary = h.extractSummary(article.Content, 200)
+	}
+
+	switch {
+	case selector.author != nil:
+		article.Author = selectedValue(selector.author.selectFirst(scope))
+	case page:
+		article.Author = h.extractAuthor(block)
+	}
+
+	if selector.links != nil {
+		if href := selectedHref(selector.links.selectFirst(scope)); href != "" {
+			article.URL = h.resolveURL(href, config.URL)
+		}
+	} else if !page {
+		if href := attrValue(scope, "href"); scope.DataAtom == atom.A && href != "" {
+			article.URL = h.resolveURL(href, config.URL)
+		} else if link := h.extractLinkFromBlock(block, config.URL); link != "" {
+			article.URL = link
+		}
+	}
+
+	var published time.Time
+	if selector.publishedAt != nil {
+		if node := selector.publishedAt.selectFirst(scope); node != nil {
+			for _, value := range []string{attrValue(node, "datetime"), attrValue(node, "content"), selectedText(node)} {
+				if parsed, err := h.parseTime(value); value != "" && err == nil {
+					published = parsed
+					break
+				}
+			}
+		}
+	} else {
+		published = h.extractPublishedAt(block)
+	}
+	if !published.IsZero() {
+		article.PublishedAt = published
+	}
+
+	if selector.tags != nil {
+		for _, node := range selector.tags.selectAll(scope) {
+			for _, tag := range strings.Split(selectedValue(node), ",") {
+				if tag = strings.TrimSpace(tag); tag != "" {
+					article.Tags = append(article.Tags, tag)
 				}
 			}
-			break
+		}
+	} else if page {
+		article.Tags = append(article.Tags, h.extractTags(block)...)
+	}
+
+	// 添加元数据
+	if config.Metadata != nil {
+		for k, v := range config.Metadata {
+			article.Metadata[k] = v
 		}
 	}
+	article.Metadata["extraction"] = "selector"
 
-	return blocks
+	return article
 }
 
+// selectedText 返回元素中以空白分隔的文本
+func selectedText(node *xhtml.Node) string {
+	if node == nil {
+		return ""
+	}
+	return strings.Join(strings.Fields(nodeText(node)), " ")
+}
+
+// selectedValue 返回元素的值：<meta> 读取 content 属性，其余元素读取文本
+func selectedValue(node *xhtml.Node) string {
+	if node == nil {
+		return ""
+	}
+	if node.DataAtom == atom.Meta {
+		return strings.TrimSpace(attrValue(node, "content"))
+	}
+	return selectedText(node)
+}
+
+// selectedHref 返回元素的 href 属性，元素本身没有时使用其中第一个链接
+func selectedHref(node *xhtml.Node) string {
+	if node == nil {
+		return ""
+	}
+	if href := attrValue(node, "href"); href != "" {
+		return href
+	}
+	if link := hrefSelector.selectFirst(node); link != nil {
+		return attrValue(link, "href")
+	}
+	return ""
+}
+
+// hrefSelector 带 href 属性的链接
+var hrefSelector = mustCompileSelector("a[href]")
+
 // parseArticleBlock 解析单个文章块
 func (h *HTMLCollector) parseArticleBlock(block string, config CollectConfig, index int) Article {
 	article := Article{
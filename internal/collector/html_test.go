@@ -744,4 +744,102 @@ func BenchmarkHTMLCollector_cleanHTML(b *testing.B) {
 	for i := 0; i < b.N; i++ {
 		collector.cleanHTML(htmlContent)
 	}
-}
\ No newline at end of file
+}
+func TestHTMLCollector_Collect_Selector(t *testing.T) {
+	htmlContent := `<!DOCTYPE html>
+<html>
+<head><title>Framework Blog</title></head>
+<body>
+    <ul class="posts">
+        <li class="post">
+            <div class="card"><div class="card-body">
+                <h2><a class="permalink" href="/blog/vue-3-5">Announcing Vue 3.5</a></h2>
+                <p class="excerpt">Reactivity refactor and <code>useId()</code>.</p>
+            </div></div>
+            <span class="byline">Evan You</span>
+            <time datetime="2024-09-01T10:00:00Z">Sep 1</time>
+            <a class="tag">vue</a><a class="tag">release</a>
+        </li>
+        <li class="post">
+            <h2><a class="permalink" href="https://example.org/vapor">Vapor mode preview</a></h2>
+            <p class="excerpt">No virtual DOM.</p>
+        </li>
+    </ul>
+</body>
+</html>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(htmlContent))
+	}))
+	defer server.Close()
+
+	collector := NewHTMLCollector()
+	config := CollectConfig{
+		URL:  server.URL + "/blog/",
+		Tags: []string{"vue"},
+		Selector: &HTMLSelector{
+			Item:        "ul.posts > li.post",
+			Title:       "h2",
+			Summary:     ".excerpt",
+			Author:      ".byline",
+			PublishedAt: "time",
+			Tags:        "a.tag",
+			Links:       "h2",
+		},
+	}
+
+	result, err := collector.Collect(context.Background(), config)
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected 2 articles, got %d", len(result.Articles))
+	}
+
+	first := result.Articles[0]
+	if first.Title != "Announcing Vue 3.5" || first.URL != server.URL+"/blog/vue-3-5" {
+		t.Errorf("Unexpected title or URL: %q %q", first.Title, first.URL)
+	}
+	if first.Summary != "Reactivity refactor and useId()." || first.Author != "Evan You" {
+		t.Errorf("Unexpected summary or author: %q %q", first.Summary, first.Author)
+	}
+	if !first.PublishedAt.Equal(time.Date(2024, 9, 1, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected datetime attribute as published time, got %v", first.PublishedAt)
+	}
+	if len(first.Tags) != 3 || first.Tags[1] != "vue" || first.Tags[2] != "release" {
+		t.Errorf("Unexpected tags: %v", first.Tags)
+	}
+	if !strings.Contains(first.Content, "Evan You") {
+		t.Errorf("Expected content from the whole item, got %q", first.Content)
+	}
+
+	second := result.Articles[1]
+	if second.URL != "https://example.org/vapor" || second.Author != "" || len(second.Tags) != 1 {
+		t.Errorf("Unexpected second article: %+v", second)
+	}
+}
+
+func TestHTMLCollector_Validate_Selector(t *testing.T) {
+	collector := NewHTMLCollector()
+	config := CollectConfig{URL: "https://example.com", Selector: &HTMLSelector{Title: "h2[class"}}
+	if err := collector.Validate(config); err == nil || !strings.Contains(err.Error(), "title") {
+		t.Errorf("Expected invalid title selector error, got %v", err)
+	}
+}
+
+func TestHTMLCollector_extractArticleBlocks_NestedDivs(t *testing.T) {
+	collector := NewHTMLCollector()
+	htmlContent := `<div class="posts">
+		<div class="post"><div class="post-meta"><div>by alice</div></div><h2><a href="/a">First</a></h2></div>
+		<div class="post"><div class="post-meta">by bob</div><h2><a href="/b">Second</a></h2></div>
+	</div>`
+
+	blocks := collector.extractArticleBlocks(htmlContent)
+	if len(blocks) != 2 {
+		t.Fatalf("Expected the two posts as blocks, got %d: %q", len(blocks), blocks)
+	}
+	if !strings.Contains(blocks[0], "by alice") || !strings.Contains(blocks[0], `<a href="/a">First</a>`) {
+		t.Errorf("Expected the whole post including nested divs, got %q", blocks[0])
+	}
+}
@@ -2,6 +2,7 @@ package collector
 
 import (
 	"context"
+	"log/slog"
 	"time"
 )
 
@@ -26,10 +27,18 @@ type CollectResult struct {
 	Articles []Article `json:"articles"`
 	Source   string    `json:"source"`
 	Error    error     `json:"error,omitempty"`
+
+	// ErrorKind 为 Error 的分类（见 ErrorKind 函数），采集成功时为空
+	ErrorKind string `json:"error_kind,omitempty"`
+
+	// NotModified 表示数据源自上次采集后没有新内容（服务器返回 304 或内容哈希未变），
+	// 此时 Articles 为上次响应按本次配置重新解析的结果
+	NotModified bool `json:"not_modified,omitempty"`
 }
 
 // CollectConfig 表示采集配置
 type CollectConfig struct {
+	Name        string            `json:"name,omitempty"` // 数据源名称，用于日志和指标，为空时使用URL的主机名
 	URL         string            `json:"url"`
 	Headers     map[string]string `json:"headers,omitempty"`
 	Timeout     time.Duration     `json:"timeout,omitempty"`
@@ -37,6 +46,7 @@ type CollectConfig struct {
 	Language    string            `json:"language,omitempty"`
 	Tags        []string          `json:"tags,omitempty"`
 	Metadata    map[string]string `json:"metadata,omitempty"`
+	Selector    *HTMLSelector     `json:"selector,omitempty"` // HTML采集器使用的CSS选择器，为nil时使用默认的启发式提取
 }
 
 // DataCollector 定义数据采集器的统一接口
@@ -70,4 +80,7 @@ type CollectorManager interface {
 	
 	// GetCollector 根据类型获取采集器
 	GetCollector(sourceType string) (DataCollector, bool)
+	
+	// SetLogger 设置日志记录器
+	SetLogger(logger *slog.Logger)
 }
\ No newline at end of file
@@ -0,0 +1,182 @@
+package collector
+
+import (
+	"encoding/json"
+	"html"
+	"strconv"
+	"strings"
+)
+
+// jsonFeedVersionPrefix JSON Feed 的 version 字段前缀，1.0 和 1.1 均以此开头
+const jsonFeedVersionPrefix = "https://jsonfeed.org/version/"
+
+// JSON Feed 1.1 结构定义，同时兼容 1.0 的单个 author 字段
+type JSONFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	FeedURL     string           `json:"feed_url"`
+	Language    string           `json:"language"`
+	Authors     []JSONFeedAuthor `json:"authors"`
+	Author      *JSONFeedAuthor  `json:"author"`
+	Items       []JSONFeedItem   `json:"items"`
+}
+
+type JSONFeedItem struct {
+	ID            json.RawMessage      `json:"id"` // 规范要求为字符串，部分feed使用数字
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	Image         string               `json:"image"`
+	BannerImage   string               `json:"banner_image"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []JSONFeedAuthor     `json:"authors"`
+	Author        *JSONFeedAuthor      `json:"author"`
+	Tags          []string             `json:"tags"`
+	Language      string               `json:"language"`
+	Attachments   []JSONFeedAttachment `json:"attachments"`
+}
+
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+type JSONFeedAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+// isValid 检查是否为 JSON Feed 文档
+func (f JSONFeed) isValid() bool {
+	return strings.HasPrefix(f.Version, jsonFeedVersionPrefix)
+}
+
+// jsonFeedAuthorName 返回第一个作者名称，1.1 的 authors 优先于 1.0 的 author
+func jsonFeedAuthorName(authors []JSONFeedAuthor, author *JSONFeedAuthor) string {
+	for _, a := range authors {
+		if name := strings.TrimSpace(a.Name); name != "" {
+			return name
+		}
+	}
+	if author != nil {
+		return strings.TrimSpace(author.Name)
+	}
+	return ""
+}
+
+// parseJSONFeedItems 解析JSON Feed条目
+func (r *RSSCollector) parseJSONFeedItems(feed JSONFeed, config CollectConfig) ([]Article, error) {
+	articles := make([]Article, 0, len(feed.Items))
+	feedAuthor := jsonFeedAuthorName(feed.Authors, feed.Author)
+
+	for _, item := range feed.Items {
+		article := Article{
+			ID:         jsonFeedItemID(item.ID),
+			Title:      html.UnescapeString(strings.TrimSpace(item.Title)),
+			Author:     jsonFeedAuthorName(item.Authors, item.Author),
+			URL:        strings.TrimSpace(item.URL),
+			Source:     config.URL,
+			SourceType: r.GetSourceType(),
+			Language:   config.Language,
+			Tags:       item.Tags,
+			Metadata:   make(map[string]string),
+		}
+
+		// content_html 转换为保留代码块的文本，否则使用 content_text
+		if item.ContentHTML != "" {
+			article.Content = bodyToMarkdown(item.ContentHTML)
+		} else {
+			article.Content = strings.TrimSpace(item.ContentText)
+		}
+		if item.Summary != "" {
+			article.Summary = r.extractSummary(item.Summary, 200)
+		} else {
+			article.Summary = r.extractSummary(article.Content, 200)
+		}
+		// 微博客类条目可以没有标题
+		if article.Title == "" {
+			article.Title = r.extractSummary(article.Content, 80)
+		}
+
+		if article.URL == "" {
+			article.URL = strings.TrimSpace(item.ExternalURL)
+		} else if item.ExternalURL != "" {
+			article.Metadata["external_url"] = item.ExternalURL
+		}
+		if article.ID == "" {
+			article.ID = r.generateID(article.URL + article.Title)
+		}
+		if article.Author == "" {
+			article.Author = feedAuthor
+		}
+		if article.Language == "" {
+			article.Language = item.Language
+			if article.Language == "" {
+				article.Language = feed.Language
+			}
+		}
+
+		// 解析发布时间
+		timeStr := item.DatePublished
+		if timeStr == "" {
+			timeStr = item.DateModified
+		}
+		if timeStr != "" {
+			if pubTime, err := r.parseTime(timeStr); err == nil {
+				article.PublishedAt = pubTime
+			}
+		}
+
+		if len(config.Tags) > 0 {
+			article.Tags = append(article.Tags, config.Tags...)
+		}
+
+		// 头图和附件
+		if item.Image != "" {
+			article.Metadata["lead_image"] = item.Image
+		} else if item.BannerImage != "" {
+			article.Metadata["lead_image"] = item.BannerImage
+		}
+		for _, attachment := range item.Attachments {
+			if attachment.URL == "" {
+				continue
+			}
+			article.Metadata["enclosure_url"] = attachment.URL
+			if attachment.MimeType != "" {
+				article.Metadata["enclosure_type"] = attachment.MimeType
+			}
+			if attachment.SizeInBytes > 0 {
+				article.Metadata["enclosure_length"] = strconv.FormatInt(attachment.SizeInBytes, 10)
+			}
+			break
+		}
+
+		// 添加元数据
+		for k, v := range config.Metadata {
+			article.Metadata[k] = v
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles, nil
+}
+
+// jsonFeedItemID 将字符串或数字形式的 id 转换为字符串
+func jsonFeedItemID(raw json.RawMessage) string {
+	var id string
+	if err := json.Unmarshal(raw, &id); err == nil {
+		return strings.TrimSpace(id)
+	}
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		return number.String()
+	}
+	return ""
+}
@@ -2,28 +2,41 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"sync"
 	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
 )
 
 // CollectorManagerImpl 采集器管理器实现
 type CollectorManagerImpl struct {
 	collectors map[string]DataCollector
 	mutex      sync.RWMutex
+	logger     *slog.Logger
 }
 
 // NewCollectorManager 创建采集器管理器
 func NewCollectorManager() CollectorManager {
 	manager := &CollectorManagerImpl{
 		collectors: make(map[string]DataCollector),
+		logger:     slog.Default(),
 	}
 
 	// 注册默认采集器
 	manager.RegisterCollector("rss", NewRSSCollector())
 	manager.RegisterCollector("api", NewAPICollector())
 	manager.RegisterCollector("html", NewHTMLCollector())
+	manager.RegisterCollector("reddit", NewRedditCollector())
+	manager.RegisterCollector("stackexchange", NewStackExchangeCollector())
+	manager.RegisterCollector("hackernews", NewHackerNewsCollector())
+	manager.RegisterCollector("github_releases", NewGitHubReleasesCollector())
+	manager.RegisterCollector("npm", NewNpmCollector())
 
 	return manager
 }
@@ -35,6 +48,20 @@ func (cm *CollectorManagerImpl) RegisterCollector(sourceType string, collector D
 	cm.collectors[sourceType] = collector
 }
 
+// SetLogger 设置日志记录器，logger 为nil时使用 slog.Default()
+func (cm *CollectorManagerImpl) SetLogger(logger *slog.Logger) {
+	cm.mutex.Lock()
+	defer cm.mutex.Unlock()
+	cm.logger = logging.OrDefault(logger)
+}
+
+// log 返回当前的日志记录器
+func (cm *CollectorManagerImpl) log() *slog.Logger {
+	cm.mutex.RLock()
+	defer cm.mutex.RUnlock()
+	return cm.logger
+}
+
 // GetCollector 根据类型获取采集器
 func (cm *CollectorManagerImpl) GetCollector(sourceType string) (DataCollector, bool) {
 	cm.mutex.RLock()
@@ -79,16 +106,46 @@ func (cm *CollectorManagerImpl) CollectAll(ctx context.Context, configs []Collec
 	return results
 }
 
-// collectSingle 采集单个数据源
+// collectSingle 采集单个数据源，并记录该数据源的采集指标和 span
 func (cm *CollectorManagerImpl) collectSingle(ctx context.Context, config CollectConfig) CollectResult {
 	// 确定采集器类型
 	sourceType := cm.determineSourceType(config)
 	
+	source := sourceLabel(config)
+	ctx, span := tracing.Start(ctx, "collect "+source,
+		attribute.String("collector.source", source),
+		attribute.String("collector.type", sourceType),
+	)
+	
+	started := time.Now()
+	result := cm.collectWith(ctx, sourceType, config)
+	result.ErrorKind = ErrorKind(result.Error)
+	observeCollect(config, sourceType, started, result)
+	cm.log().DebugContext(ctx, "数据源采集完成",
+		logging.Source(source),
+		slog.String("type", sourceType),
+		logging.Duration(time.Since(started)),
+		slog.Int("articles", len(result.Articles)),
+		slog.Bool("not_modified", result.NotModified),
+		slog.String("error_kind", result.ErrorKind),
+		logging.Err(result.Error),
+	)
+	
+	span.SetAttributes(
+		attribute.Int("collector.articles", len(result.Articles)),
+		attribute.Bool("collector.not_modified", result.NotModified),
+	)
+	tracing.End(span, result.Error)
+	return result
+}
+
+// collectWith 使用指定类型的采集器采集单个数据源
+func (cm *CollectorManagerImpl) collectWith(ctx context.Context, sourceType string, config CollectConfig) CollectResult {
 	collector, exists := cm.GetCollector(sourceType)
 	if !exists {
 		return CollectResult{
 			Source: config.URL,
-			Error:  fmt.Errorf("no collector found for source type: %s", sourceType),
+			Error:  &ValidationError{Err: fmt.Errorf("no collector found for source type: %s", sourceType)},
 		}
 	}
 
@@ -110,14 +167,23 @@ func (cm *CollectorManagerImpl) CollectWithRetry(ctx context.Context, config Col
 	
 	for attempt := 0; attempt <= retryConfig.MaxRetries; attempt++ {
 		if attempt > 0 {
-			// 等待重试延迟
+			// 等待重试延迟，服务器通过 Retry-After 要求更长的等待时间时以其为准
+			delay := retryConfig.RetryDelay
+			if after := retryAfter(lastErr); after > delay {
+				delay = after
+			}
 			select {
 			case <-ctx.Done():
 				return CollectResult{}, ctx.Err()
-			case <-time.After(retryConfig.RetryDelay):
+			case <-time.After(delay):
 			}
 			
-			log.Printf("Retrying collection attempt %d/%d for %s", attempt, retryConfig.MaxRetries, config.URL)
+			cm.log().InfoContext(ctx, "重试采集",
+				logging.Source(sourceLabel(config)),
+				slog.Int("attempt", attempt),
+				slog.Int("max_retries", retryConfig.MaxRetries),
+				logging.Err(lastErr),
+			)
 		}
 
 		result := cm.collectSingle(ctx, config)
@@ -137,47 +203,26 @@ func (cm *CollectorManagerImpl) CollectWithRetry(ctx context.Context, config Col
 }
 
 // shouldRetry 判断是否应该重试
+//
+// 配置错误、解析错误、取消和非临时的HTTP状态码不重试；速率限制只在等待时间已知且不超过
+// maxRetryAfter 时重试；超时、网络错误和其他未知错误默认重试。
 func (cm *CollectorManagerImpl) shouldRetry(err error) bool {
 	if err == nil {
 		return false
 	}
 
-	errStr := err.Error()
-	
-	// 不重试的错误类型
-	nonRetryableErrors := []string{
-		"validation failed",
-		"invalid URL",
-		"no collector found",
-		"404",
-		"401", 
-		"403",
-	}
-
-	for _, nonRetryable := range nonRetryableErrors {
-		if contains(errStr, nonRetryable) {
-			return false
-		}
-	}
-
-	// 可重试的错误类型
-	retryableErrors := []string{
-		"timeout",
-		"connection",
-		"network",
-		"500",
-		"502",
-		"503",
-		"504",
-	}
-
-	for _, retryable := range retryableErrors {
-		if contains(errStr, retryable) {
-			return true
-		}
+	var rateLimitErr *RateLimitError
+	var statusErr *HTTPStatusError
+	switch ErrorKind(err) {
+	case ErrorKindValidation, ErrorKindParse, ErrorKindCanceled:
+		return false
+	case ErrorKindRateLimit:
+		errors.As(err, &rateLimitErr)
+		return rateLimitErr.RetryAfter > 0 && rateLimitErr.RetryAfter <= maxRetryAfter
+	case ErrorKindHTTP:
+		errors.As(err, &statusErr)
+		return statusErr.Retryable()
 	}
-
-	// 默认重试
 	return true
 }
 
@@ -191,7 +236,27 @@ func (cm *CollectorManagerImpl) determineSourceType(config CollectConfig) string
 	}
 
 	// 根据URL特征自动判断
-	if contains(url, ".xml") || contains(url, "/rss") || contains(url, "/feed") || contains(url, "/atom") {
+	if contains(url, "reddit.com/r/") {
+		return "reddit"
+	}
+
+	if contains(url, "api.stackexchange.com") {
+		return "stackexchange"
+	}
+
+	if contains(url, "hn.algolia.com") || contains(url, "hacker-news.firebaseio.com") {
+		return "hackernews"
+	}
+
+	if contains(url, "api.github.com/repos/") && (contains(url, "/releases") || contains(url, "/tags")) {
+		return "github_releases"
+	}
+
+	if contains(url, "registry.npmjs.org") {
+		return "npm"
+	}
+
+	if contains(url, ".xml") || contains(url, ".rdf") || contains(url, "/rss") || contains(url, "/feed") || contains(url, "/atom") {
 		return "rss"
 	}
 
@@ -2,6 +2,8 @@ package collector
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 )
@@ -94,6 +96,16 @@ func TestCollectorManagerImpl_determineSourceType(t *testing.T) {
 			config:   CollectConfig{URL: "https://example.com/atom"},
 			expected: "rss",
 		},
+		{
+			name:     "RDF URL with .rdf",
+			config:   CollectConfig{URL: "https://example.com/index.rdf"},
+			expected: "rss",
+		},
+		{
+			name:     "JSON Feed URL",
+			config:   CollectConfig{URL: "https://example.com/feed.json"},
+			expected: "rss",
+		},
 		{
 			name:     "GitHub API URL",
 			config:   CollectConfig{URL: "https://api.github.com/repos/owner/repo"},
@@ -109,6 +121,36 @@ func TestCollectorManagerImpl_determineSourceType(t *testing.T) {
 			config:   CollectConfig{URL: "https://example.com/api/data"},
 			expected: "api",
 		},
+		{
+			name:     "Reddit search URL",
+			config:   CollectConfig{URL: "https://www.reddit.com/r/webdev/search.json?q=react"},
+			expected: "reddit",
+		},
+		{
+			name:     "Stack Exchange API URL",
+			config:   CollectConfig{URL: "https://api.stackexchange.com/2.3/search/advanced?q=react&site=stackoverflow"},
+			expected: "stackexchange",
+		},
+		{
+			name:     "Hacker News Algolia URL",
+			config:   CollectConfig{URL: "https://hn.algolia.com/api/v1/search?query=react"},
+			expected: "hackernews",
+		},
+		{
+			name:     "Hacker News Firebase URL",
+			config:   CollectConfig{URL: "https://hacker-news.firebaseio.com/v0/topstories.json"},
+			expected: "hackernews",
+		},
+		{
+			name:     "GitHub releases URL",
+			config:   CollectConfig{URL: "https://api.github.com/repos/vitejs/vite/releases?per_page=10"},
+			expected: "github_releases",
+		},
+		{
+			name:     "npm registry URL",
+			config:   CollectConfig{URL: "https://registry.npmjs.org/-/v1/search?text=keywords:react"},
+			expected: "npm",
+		},
 		{
 			name:     "HTML page",
 			config:   CollectConfig{URL: "https://example.com/article"},
@@ -151,32 +193,72 @@ func TestCollectorManagerImpl_shouldRetry(t *testing.T) {
 		},
 		{
 			name:     "validation error",
-			err:      &ValidationError{message: "validation failed"},
+			err:      &ValidationError{Err: errors.New("URL is required")},
 			expected: false,
 		},
 		{
 			name:     "404 error",
-			err:      &HTTPError{statusCode: 404},
+			err:      &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"},
 			expected: false,
 		},
 		{
 			name:     "401 error",
-			err:      &HTTPError{statusCode: 401},
+			err:      &HTTPStatusError{StatusCode: 401, Status: "401 Unauthorized"},
 			expected: false,
 		},
 		{
 			name:     "403 error",
-			err:      &HTTPError{statusCode: 403},
+			err:      &HTTPStatusError{StatusCode: 403, Status: "403 Forbidden"},
 			expected: false,
 		},
 		{
 			name:     "500 error",
-			err:      &HTTPError{statusCode: 500},
+			err:      &HTTPStatusError{StatusCode: 500, Status: "500 Internal Server Error"},
 			expected: true,
 		},
 		{
 			name:     "502 error",
-			err:      &HTTPError{statusCode: 502},
+			err:      &HTTPStatusError{StatusCode: 502, Status: "502 Bad Gateway"},
+			expected: true,
+		},
+		{
+			name:     "wrapped 404 error",
+			err:      fmt.Errorf("collection failed: %w", &HTTPStatusError{StatusCode: 404, Status: "404 Not Found"}),
+			expected: false,
+		},
+		{
+			name:     "429 error",
+			err:      &HTTPStatusError{StatusCode: 429, Status: "429 Too Many Requests"},
+			expected: true,
+		},
+		{
+			name:     "rate limit with short retry after",
+			err:      &RateLimitError{RetryAfter: 2 * time.Second},
+			expected: true,
+		},
+		{
+			name:     "rate limit with long retry after",
+			err:      &RateLimitError{RetryAfter: time.Hour},
+			expected: false,
+		},
+		{
+			name:     "rate limit without retry after",
+			err:      &RateLimitError{Message: "quota exhausted"},
+			expected: false,
+		},
+		{
+			name:     "parse error",
+			err:      &ParseError{What: "feed", Err: errors.New("unexpected EOF")},
+			expected: false,
+		},
+		{
+			name:     "canceled",
+			err:      fmt.Errorf("failed to fetch RSS: %w", context.Canceled),
+			expected: false,
+		},
+		{
+			name:     "URL containing 404",
+			err:      errors.New("failed to fetch https://example.com/posts/404-page: connection reset"),
 			expected: true,
 		},
 		{
@@ -695,34 +777,6 @@ func (e *TestError) Error() string {
 	return e.message
 }
 
-type ValidationError struct {
-	message string
-}
-
-func (e *ValidationError) Error() string {
-	return "validation failed: " + e.message
-}
-
-type HTTPError struct {
-	statusCode int
-}
-
-func (e *HTTPError) Error() string {
-	if e.statusCode == 404 {
-		return "HTTP error 404: Not Found"
-	}
-	if e.statusCode == 401 {
-		return "HTTP error 401: Unauthorized"
-	}
-	if e.statusCode == 403 {
-		return "HTTP error 403: Forbidden"
-	}
-	if e.statusCode >= 500 {
-		return "HTTP error 500: Internal Server Error"
-	}
-	return "HTTP error"
-}
-
 type TimeoutError struct{}
 
 func (e *TimeoutError) Error() string {
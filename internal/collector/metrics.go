@@ -0,0 +1,116 @@
+package collector
+
+import (
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/metrics"
+)
+
+// 各数据源的采集指标，注册到 metrics.Default
+var (
+	collectRequests = metrics.NewCounterVec("devcontext_collector_requests_total",
+		"Collection requests per source and collector type.", "source", "type", "status")
+	collectDuration = metrics.NewHistogramVec("devcontext_collector_duration_seconds",
+		"Collection latency per source and collector type.", nil, "source", "type")
+	collectArticles = metrics.NewCounterVec("devcontext_collector_articles_total",
+		"Articles collected per source.", "source")
+)
+
+func init() {
+	metrics.Default.Register(collectRequests, collectDuration, collectArticles)
+}
+
+// observeCollect 记录一次采集的耗时和结果
+func observeCollect(config CollectConfig, sourceType string, started time.Time, result CollectResult) {
+	source := sourceLabel(config)
+	status := "ok"
+	if result.Error != nil {
+		status = "error"
+	} else if result.NotModified {
+		status = "not_modified"
+	}
+
+	duration := time.Since(started)
+	collectRequests.Inc(source, sourceType, status)
+	collectDuration.Observe(duration.Seconds(), source, sourceType)
+	if result.Error == nil {
+		collectArticles.Add(float64(len(result.Articles)), source)
+	}
+	sourceStatuses.record(source, sourceType, started, duration, result)
+}
+
+// SourceStatus 单个数据源最近的采集状态
+type SourceStatus struct {
+	Source        string        `json:"source"`
+	Type          string        `json:"type"`
+	Requests      int64         `json:"requests"`
+	Errors        int64         `json:"errors"`
+	LastCollect   time.Time     `json:"lastCollect"`
+	LastSuccess   time.Time     `json:"lastSuccess,omitempty"`
+	LastDuration  time.Duration `json:"lastDuration"`
+	LastArticles  int           `json:"lastArticles"`
+	LastError     string        `json:"lastError,omitempty"`
+	LastErrorKind string        `json:"lastErrorKind,omitempty"` // 见 ErrorKind
+}
+
+// statusTracker 按数据源记录最近的采集状态
+type statusTracker struct {
+	mu       sync.Mutex
+	statuses map[string]*SourceStatus
+}
+
+var sourceStatuses = &statusTracker{statuses: make(map[string]*SourceStatus)}
+
+func (t *statusTracker) record(source, sourceType string, started time.Time, duration time.Duration, result CollectResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	status, exists := t.statuses[source]
+	if !exists {
+		status = &SourceStatus{Source: source}
+		t.statuses[source] = status
+	}
+	status.Type = sourceType
+	status.Requests++
+	status.LastCollect = started
+	status.LastDuration = duration
+	if result.Error != nil {
+		status.Errors++
+		status.LastError = result.Error.Error()
+		status.LastErrorKind = result.ErrorKind
+		return
+	}
+	status.LastSuccess = started
+	status.LastArticles = len(result.Articles)
+	status.LastError = ""
+	status.LastErrorKind = ""
+}
+
+// SourceStatuses 返回进程启动以来各数据源的采集状态，按数据源名称排序
+func SourceStatuses() []SourceStatus {
+	sourceStatuses.mu.Lock()
+	defer sourceStatuses.mu.Unlock()
+
+	statuses := make([]SourceStatus, 0, len(sourceStatuses.statuses))
+	for _, status := range sourceStatuses.statuses {
+		statuses = append(statuses, *status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Source < statuses[j].Source
+	})
+	return statuses
+}
+
+// sourceLabel 返回指标中的数据源名称，未命名时使用主机名，避免完整URL带来的高基数
+func sourceLabel(config CollectConfig) string {
+	if config.Name != "" {
+		return config.Name
+	}
+	if u, err := url.Parse(config.URL); err == nil && u.Host != "" {
+		return u.Host
+	}
+	return "unknown"
+}
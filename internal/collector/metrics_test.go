@@ -0,0 +1,40 @@
+package collector
+
+import (
+	"context"
+	"testing"
+)
+
+func TestCollectorManagerImpl_RecordsSourceStatus(t *testing.T) {
+	manager := NewCollectorManager()
+	manager.RegisterCollector("test", &TestCollector{})
+	manager.RegisterCollector("failing", &FailingTestCollector{failTimes: 1})
+
+	ok := CollectConfig{Name: "status-ok", URL: "test://ok.example.com", Metadata: map[string]string{"source_type": "test"}}
+	failing := CollectConfig{URL: "https://status-failing.example.com/feed", Metadata: map[string]string{"source_type": "failing"}}
+	manager.CollectAll(context.Background(), []CollectConfig{ok, failing})
+	manager.CollectAll(context.Background(), []CollectConfig{failing})
+
+	statuses := make(map[string]SourceStatus)
+	for _, status := range SourceStatuses() {
+		statuses[status.Source] = status
+	}
+
+	okStatus := statuses["status-ok"]
+	if okStatus.Requests != 1 || okStatus.Errors != 0 || okStatus.LastArticles != 1 || okStatus.LastSuccess.IsZero() {
+		t.Errorf("Unexpected status for named source: %+v", okStatus)
+	}
+
+	// 未命名的数据源以主机名记录，失败后成功会清除最近的错误
+	failingStatus := statuses["status-failing.example.com"]
+	if failingStatus.Requests != 2 || failingStatus.Errors != 1 || failingStatus.LastError != "" || failingStatus.Type != "failing" {
+		t.Errorf("Unexpected status for unnamed source: %+v", failingStatus)
+	}
+
+	if got := collectRequests.Value("status-failing.example.com", "failing", "error"); got != 1 {
+		t.Errorf("Expected 1 failed request in metrics, got %v", got)
+	}
+	if got := collectArticles.Value("status-ok"); got != 1 {
+		t.Errorf("Expected 1 collected article in metrics, got %v", got)
+	}
+}
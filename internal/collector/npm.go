@@ -0,0 +1,395 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// npmDownloadsURL npm 下载量统计接口地址
+	npmDownloadsURL = "https://api.npmjs.org"
+
+	// npmPackageURL npm 网站上的包页面地址前缀
+	npmPackageURL = "https://www.npmjs.com/package/"
+
+	// npmFetchConcurrency 获取包元数据和下载量时的最大并发数
+	npmFetchConcurrency = 8
+
+	// npmFastGrowthPercent 周下载量环比增长达到该百分比视为快速增长
+	npmFastGrowthPercent = 50.0
+
+	// npmFastGrowthMinDownloads 快速增长要求的最低周下载量，过滤基数过小的包
+	npmFastGrowthMinDownloads = 1000
+)
+
+// NpmSearchResult npm 搜索结果（/-/v1/search）
+type NpmSearchResult struct {
+	Objects []struct {
+		Package struct {
+			Name string `json:"name"`
+		} `json:"package"`
+	} `json:"objects"`
+}
+
+// NpmPackument npm 包元数据（/<package>），只解析需要的字段
+type NpmPackument struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description"`
+	DistTags    map[string]string `json:"dist-tags"`
+	Time        map[string]string `json:"time"`
+	Keywords    []string          `json:"keywords"`
+	Homepage    string            `json:"homepage"`
+	Repository  json.RawMessage   `json:"repository"` // 字符串或 {"type": "git", "url": "..."}
+	Maintainers []struct {
+		Name string `json:"name"`
+	} `json:"maintainers"`
+}
+
+// NpmDownloadRange npm 按天统计的下载量（/downloads/range/<period>/<package>）
+type NpmDownloadRange struct {
+	Downloads []struct {
+		Downloads int    `json:"downloads"`
+		Day       string `json:"day"`
+	} `json:"downloads"`
+}
+
+// npmPackage 包元数据和下载趋势
+type npmPackage struct {
+	NpmPackument
+	WeeklyDownloads   int
+	PreviousDownloads int
+	HasDownloads      bool
+}
+
+// NpmCollector npm 仓库采集器
+//
+// 支持搜索接口（registry.npmjs.org/-/v1/search?text=keywords:react）和单个包的元数据接口
+// （registry.npmjs.org/<package>）。每个包获取 dist-tags、发布时间和最近两周的下载量，
+// 标注最新版本的语义化版本级别以及下载量是否快速增长。
+type NpmCollector struct {
+	client       *http.Client
+	downloadsURL string
+}
+
+// NewNpmCollector 创建npm采集器
+func NewNpmCollector() *NpmCollector {
+	return &NpmCollector{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		downloadsURL: npmDownloadsURL,
+	}
+}
+
+// GetSourceType 返回采集器类型
+func (n *NpmCollector) GetSourceType() string {
+	return "npm"
+}
+
+// Validate 验证配置
+func (n *NpmCollector) Validate(config CollectConfig) error {
+	if config.URL == "" {
+		return fmt.Errorf("URL is required")
+	}
+
+	parsedURL, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("URL must use http or https scheme")
+	}
+
+	if strings.Trim(parsedURL.Path, "/") == "" {
+		return fmt.Errorf("URL must point to a package or the search endpoint")
+	}
+
+	return nil
+}
+
+// Collect 采集npm包，搜索接口返回的每个包单独获取元数据和下载量
+func (n *NpmCollector) Collect(ctx context.Context, config CollectConfig) (CollectResult, error) {
+	if err := n.Validate(config); err != nil {
+		return CollectResult{}, &ValidationError{Err: err}
+	}
+
+	// 设置超时
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	parsedURL, _ := url.Parse(config.URL)
+	registry := parsedURL.Scheme + "://" + parsedURL.Host
+
+	var packages []*npmPackage
+	if strings.HasSuffix(parsedURL.Path, "/-/v1/search") {
+		var search NpmSearchResult
+		if err := n.fetchJSON(ctx, config.URL, config.Headers, &search); err != nil {
+			return CollectResult{}, err
+		}
+
+		names := make([]string, 0, len(search.Objects))
+		for _, object := range search.Objects {
+			names = append(names, object.Package.Name)
+		}
+		if config.MaxArticles > 0 && len(names) > config.MaxArticles {
+			names = names[:config.MaxArticles]
+		}
+		packages = n.fetchPackages(ctx, registry, names, config.Headers)
+		if err := ctx.Err(); err != nil {
+			return CollectResult{}, err
+		}
+	} else {
+		pkg := &npmPackage{}
+		if err := n.fetchJSON(ctx, config.URL, config.Headers, &pkg.NpmPackument); err != nil {
+			return CollectResult{}, err
+		}
+		n.fetchDownloads(ctx, pkg, config.Headers)
+		packages = append(packages, pkg)
+	}
+
+	return CollectResult{
+		Articles: n.convertPackages(packages, config),
+		Source:   config.URL,
+	}, nil
+}
+
+// fetchPackages 并发获取包元数据和下载量，保持搜索结果顺序；获取元数据失败的包被跳过
+func (n *NpmCollector) fetchPackages(ctx context.Context, registry string, names []string, headers map[string]string) []*npmPackage {
+	results := make([]*npmPackage, len(names))
+	semaphore := make(chan struct{}, npmFetchConcurrency)
+	var wg sync.WaitGroup
+	for i, name := range names {
+		wg.Add(1)
+		go func(i int, name string) {
+			defer wg.Done()
+			semaphore <- struct{}{}
+			defer func() { <-semaphore }()
+
+			pkg := &npmPackage{}
+			if err := n.fetchJSON(ctx, registry+"/"+name, headers, &pkg.NpmPackument); err != nil {
+				return
+			}
+			n.fetchDownloads(ctx, pkg, headers)
+			results[i] = pkg
+		}(i, name)
+	}
+	wg.Wait()
+
+	packages := make([]*npmPackage, 0, len(results))
+	for _, pkg := range results {
+		if pkg != nil {
+			packages = append(packages, pkg)
+		}
+	}
+	return packages
+}
+
+// fetchDownloads 获取最近一个月的每日下载量，计算最近一周和前一周的下载量；失败时不影响包本身
+func (n *NpmCollector) fetchDownloads(ctx context.Context, pkg *npmPackage, headers map[string]string) {
+	var downloads NpmDownloadRange
+	if err := n.fetchJSON(ctx, n.downloadsURL+"/downloads/range/last-month/"+pkg.Name, headers, &downloads); err != nil {
+		return
+	}
+
+	days := downloads.Downloads
+	for i := len(days) - 1; i >= 0 && i >= len(days)-14; i-- {
+		if i >= len(days)-7 {
+			pkg.WeeklyDownloads += days[i].Downloads
+		} else {
+			pkg.PreviousDownloads += days[i].Downloads
+		}
+	}
+	pkg.HasDownloads = len(days) > 0
+}
+
+// downloadGrowth 返回周下载量环比增长百分比，前一周没有下载时返回 false
+func (p *npmPackage) downloadGrowth() (float64, bool) {
+	if !p.HasDownloads || p.PreviousDownloads == 0 {
+		return 0, false
+	}
+	return float64(p.WeeklyDownloads-p.PreviousDownloads) / float64(p.PreviousDownloads) * 100, true
+}
+
+// fastGrowing 判断下载量是否快速增长
+func (p *npmPackage) fastGrowing() bool {
+	growth, ok := p.downloadGrowth()
+	return ok && growth >= npmFastGrowthPercent && p.WeeklyDownloads >= npmFastGrowthMinDownloads
+}
+
+// repositoryURL 返回源码仓库地址，去掉 git+ 前缀和 .git 后缀
+func (p *npmPackage) repositoryURL() string {
+	var repository struct {
+		URL string `json:"url"`
+	}
+	if err := json.Unmarshal(p.Repository, &repository); err != nil {
+		json.Unmarshal(p.Repository, &repository.URL)
+	}
+
+	repoURL := strings.TrimPrefix(repository.URL, "git+")
+	repoURL = strings.TrimSuffix(repoURL, ".git")
+	if strings.HasPrefix(repoURL, "git://") {
+		repoURL = "https://" + strings.TrimPrefix(repoURL, "git://")
+	}
+	return repoURL
+}
+
+// fetchJSON 获取JSON并解析到 v
+func (n *NpmCollector) fetchJSON(ctx context.Context, apiURL string, headers map[string]string, v interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "API Collector/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	// 设置自定义头部
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := n.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch npm registry: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newHTTPStatusError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	if err := json.Unmarshal(body, v); err != nil {
+		return &ParseError{What: "npm response", Err: err}
+	}
+	return nil
+}
+
+// convertPackages 转换npm包为文章
+//
+// 文章标题为包名，发布时间为 latest 版本的发布时间。最新版本为新的主版本时添加 major 标签，
+// 下载量快速增长时添加 fast-growing 标签。
+func (n *NpmCollector) convertPackages(packages []*npmPackage, config CollectConfig) []Article {
+	articles := make([]Article, 0, len(packages))
+
+	for _, pkg := range packages {
+		latest := pkg.DistTags["latest"]
+		publishedAt, _ := time.Parse(time.RFC3339, pkg.Time[latest])
+		_, level := parseReleaseVersion(latest, false)
+		growth, hasGrowth := pkg.downloadGrowth()
+		fastGrowing := pkg.fastGrowing()
+
+		tags := []string{"npm"}
+		if level == "major" {
+			tags = append(tags, "major")
+		}
+		if fastGrowing {
+			tags = append(tags, "fast-growing")
+		}
+		for i, keyword := range pkg.Keywords {
+			if i >= 5 {
+				break
+			}
+			tags = append(tags, strings.ToLower(keyword))
+		}
+
+		article := Article{
+			ID:          pkg.Name,
+			Title:       pkg.Name,
+			Content:     n.packageContent(pkg, latest, publishedAt),
+			Summary:     pkg.Description,
+			URL:         npmPackageURL + pkg.Name,
+			PublishedAt: publishedAt,
+			Source:      config.URL,
+			SourceType:  n.GetSourceType(),
+			Language:    config.Language,
+			Tags:        append(tags, config.Tags...),
+			Metadata:    make(map[string]string),
+		}
+		if len(pkg.Maintainers) > 0 {
+			article.Author = pkg.Maintainers[0].Name
+		}
+		if article.Summary == "" {
+			article.Summary = fmt.Sprintf("%s %s on npm", pkg.Name, latest)
+		}
+
+		// 添加元数据
+		article.Metadata["npm_package"] = "true"
+		article.Metadata["version"] = latest
+		article.Metadata["semver_level"] = level
+		article.Metadata["dist_tags"] = formatDistTags(pkg.DistTags)
+		article.Metadata["fast_growing"] = strconv.FormatBool(fastGrowing)
+		if pkg.HasDownloads {
+			article.Metadata["weekly_downloads"] = strconv.Itoa(pkg.WeeklyDownloads)
+			article.Metadata["previous_weekly_downloads"] = strconv.Itoa(pkg.PreviousDownloads)
+		}
+		if hasGrowth {
+			article.Metadata["download_growth"] = strconv.FormatFloat(growth, 'f', 1, 64)
+		}
+		if repoURL := pkg.repositoryURL(); repoURL != "" {
+			article.Metadata["repository_url"] = repoURL
+		}
+		if pkg.Homepage != "" {
+			article.Metadata["homepage"] = pkg.Homepage
+		}
+
+		if config.Metadata != nil {
+			for k, v := range config.Metadata {
+				article.Metadata[k] = v
+			}
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles
+}
+
+// packageContent 生成包的版本和下载量说明
+func (n *NpmCollector) packageContent(pkg *npmPackage, latest string, publishedAt time.Time) string {
+	var b strings.Builder
+	if pkg.Description != "" {
+		b.WriteString(pkg.Description + "\n\n")
+	}
+	fmt.Fprintf(&b, "- Latest: %s", latest)
+	if !publishedAt.IsZero() {
+		fmt.Fprintf(&b, " (%s)", publishedAt.Format("2006-01-02"))
+	}
+	fmt.Fprintf(&b, "\n- Dist-tags: %s\n", formatDistTags(pkg.DistTags))
+	if pkg.HasDownloads {
+		fmt.Fprintf(&b, "- Weekly downloads: %d (previous week: %d)\n", pkg.WeeklyDownloads, pkg.PreviousDownloads)
+	}
+	return b.String()
+}
+
+// formatDistTags 按标签名排序格式化 dist-tags，例如 latest=19.0.0,next=19.1.0-canary
+func formatDistTags(distTags map[string]string) string {
+	names := make([]string, 0, len(distTags))
+	for name := range distTags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	pairs := make([]string, 0, len(names))
+	for _, name := range names {
+		pairs = append(pairs, name+"="+distTags[name])
+	}
+	return strings.Join(pairs, ",")
+}
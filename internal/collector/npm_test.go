@@ -0,0 +1,147 @@
+package collector
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// npmDownloadDays 生成按天统计的下载量，previous 为前一周每天的下载量，weekly 为最近一周每天的下载量
+func npmDownloadDays(previous, weekly int) string {
+	days := make([]string, 0, 16)
+	for i := 0; i < 16; i++ {
+		downloads := weekly
+		if i < 9 {
+			downloads = previous
+		}
+		days = append(days, fmt.Sprintf(`{"downloads": %d, "day": "2024-03-%02d"}`, downloads, i+1))
+	}
+	return `{"downloads": [` + strings.Join(days, ",") + `]}`
+}
+
+func TestNpmCollector_CollectSearch(t *testing.T) {
+	packuments := map[string]string{
+		"/vite-plugin-fast": `{
+			"name": "vite-plugin-fast", "description": "Make Vite faster",
+			"dist-tags": {"latest": "2.0.0", "next": "2.1.0-beta.0"},
+			"time": {"1.4.2": "2024-01-10T00:00:00Z", "2.0.0": "2024-03-12T08:00:00Z"},
+			"keywords": ["Vite", "plugin"],
+			"repository": {"type": "git", "url": "git+https://github.com/example/vite-plugin-fast.git"},
+			"maintainers": [{"name": "alice"}]
+		}`,
+		"/@scope/steady": `{
+			"name": "@scope/steady", "dist-tags": {"latest": "1.2.3"},
+			"time": {"1.2.3": "2024-02-01T00:00:00Z"},
+			"repository": "https://github.com/example/steady"
+		}`,
+	}
+	downloads := map[string]string{
+		"/downloads/range/last-month/vite-plugin-fast": npmDownloadDays(100, 300),
+		"/downloads/range/last-month/@scope/steady":    npmDownloadDays(1000, 1000),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/-/v1/search" {
+			w.Write([]byte(`{"objects": [
+				{"package": {"name": "vite-plugin-fast"}},
+				{"package": {"name": "missing"}},
+				{"package": {"name": "@scope/steady"}}
+			]}`))
+			return
+		}
+		if body, ok := packuments[r.URL.Path]; ok {
+			w.Write([]byte(body))
+			return
+		}
+		if body, ok := downloads[r.URL.Path]; ok {
+			w.Write([]byte(body))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+
+	collector := NewNpmCollector()
+	collector.downloadsURL = server.URL
+	result, err := collector.Collect(context.Background(), CollectConfig{
+		URL:  server.URL + "/-/v1/search?text=keywords:vite&size=20",
+		Tags: []string{"vite"},
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected missing package to be skipped, got %d articles", len(result.Articles))
+	}
+
+	fast := result.Articles[0]
+	if fast.Title != "vite-plugin-fast" || fast.URL != "https://www.npmjs.com/package/vite-plugin-fast" || fast.Author != "alice" {
+		t.Errorf("Unexpected package article: %+v", fast)
+	}
+	if !fast.PublishedAt.Equal(time.Date(2024, 3, 12, 8, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected latest publish time, got %v", fast.PublishedAt)
+	}
+	if fast.Metadata["semver_level"] != "major" || fast.Metadata["dist_tags"] != "latest=2.0.0,next=2.1.0-beta.0" {
+		t.Errorf("Expected major version and dist-tags, got %v", fast.Metadata)
+	}
+	if fast.Metadata["weekly_downloads"] != "2100" || fast.Metadata["previous_weekly_downloads"] != "700" || fast.Metadata["download_growth"] != "200.0" {
+		t.Errorf("Expected weekly downloads and growth, got %v", fast.Metadata)
+	}
+	if fast.Metadata["fast_growing"] != "true" || fast.Metadata["repository_url"] != "https://github.com/example/vite-plugin-fast" {
+		t.Errorf("Expected fast growing package with repository, got %v", fast.Metadata)
+	}
+	if strings.Join(fast.Tags, ",") != "npm,major,fast-growing,vite,plugin,vite" {
+		t.Errorf("Unexpected tags: %v", fast.Tags)
+	}
+
+	steady := result.Articles[1]
+	if steady.Metadata["semver_level"] != "patch" || steady.Metadata["fast_growing"] != "false" || steady.Metadata["download_growth"] != "0.0" {
+		t.Errorf("Expected steady patch release, got %v", steady.Metadata)
+	}
+	if steady.Metadata["repository_url"] != "https://github.com/example/steady" || steady.Summary != "@scope/steady 1.2.3 on npm" {
+		t.Errorf("Expected string repository and fallback summary, got %v %q", steady.Metadata, steady.Summary)
+	}
+}
+
+func TestNpmCollector_CollectPackage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/react" {
+			w.Write([]byte(`{"name": "react", "description": "React", "dist-tags": {"latest": "19.0.0"}, "time": {"19.0.0": "2024-12-05T00:00:00Z"}}`))
+			return
+		}
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	collector := NewNpmCollector()
+	collector.downloadsURL = server.URL
+	result, err := collector.Collect(context.Background(), CollectConfig{URL: server.URL + "/react"})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if len(result.Articles) != 1 {
+		t.Fatalf("Expected one package, got %d", len(result.Articles))
+	}
+
+	react := result.Articles[0]
+	if react.Metadata["version"] != "19.0.0" || react.Metadata["semver_level"] != "major" {
+		t.Errorf("Expected latest version, got %v", react.Metadata)
+	}
+	if _, ok := react.Metadata["weekly_downloads"]; ok {
+		t.Errorf("Expected no download metadata when the downloads API fails, got %v", react.Metadata)
+	}
+}
+
+func TestNpmCollector_Validate(t *testing.T) {
+	collector := NewNpmCollector()
+	if err := collector.Validate(CollectConfig{URL: "https://registry.npmjs.org/"}); err == nil {
+		t.Error("Expected error without package or search path")
+	}
+	if err := collector.Validate(CollectConfig{URL: "https://registry.npmjs.org/vue"}); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+}
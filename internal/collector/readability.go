@@ -0,0 +1,274 @@
+package collector
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// readableMinLength 正文纯文本的最小长度，低于该值视为未找到正文
+const readableMinLength = 140
+
+// readingWordsPerMinute 估算阅读时间使用的每分钟阅读词数（中日韩文字按单字计）
+const readingWordsPerMinute = 200
+
+// ReadableContent 从文章页面中提取出的正文
+type ReadableContent struct {
+	Title       string        `json:"title"`
+	Content     string        `json:"content"` // 正文文本，代码块保留为 ``` 围栏
+	LeadImage   string        `json:"lead_image,omitempty"`
+	WordCount   int           `json:"word_count"`
+	ReadingTime time.Duration `json:"reading_time"`
+}
+
+var (
+	// unlikelyCandidates 类名或ID命中时视为页面模板内容（导航、评论、推荐等）
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|comment|community|cookie|disqus|footer|header|menu|modal|nav|newsletter|popup|promo|related|share|sidebar|social|sponsor|subscribe|\bads?\b|advert`)
+	// maybeCandidates 类名或ID同时命中时仍保留
+	maybeCandidates = regexp.MustCompile(`(?i)article|body|column|content|main|post|story`)
+	// positiveClass 提高候选节点得分的类名或ID
+	positiveClass = regexp.MustCompile(`(?i)article|blog|body|content|entry|main|post|story|text`)
+	// negativeClass 降低候选节点得分的类名或ID
+	negativeClass = regexp.MustCompile(`(?i)comment|footer|meta|related|share|sidebar|social|sponsor|widget|\bads?\b`)
+)
+
+// ExtractReadable 以类似 Readability 的方式提取文章页面的正文
+//
+// 先移除脚本、导航、页脚、侧边栏等模板节点，再按段落文本长度、逗号数量和类名
+// 为父节点打分，取链接密度修正后得分最高的节点作为正文。<pre> 代码块转换为
+// 带语言标记的 ``` 围栏。pageURL 用于解析头图的相对地址。
+func ExtractReadable(htmlContent, pageURL string) (*ReadableContent, error) {
+	doc, err := xhtml.Parse(strings.NewReader(htmlContent))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse HTML: %w", err)
+	}
+
+	readable := &ReadableContent{
+		Title:     readableTitle(doc),
+		LeadImage: metaContent(doc, "og:image", "twitter:image"),
+	}
+
+	removeBoilerplate(doc)
+	candidate := topCandidate(doc)
+	if candidate == nil {
+		return nil, fmt.Errorf("no readable content found")
+	}
+
+	var b strings.Builder
+	writeMarkdown(&b, candidate)
+	readable.Content = strings.TrimSpace(blankLines.ReplaceAllString(b.String(), "\n\n"))
+	if len([]rune(strings.TrimSpace(nodeText(candidate)))) < readableMinLength {
+		return nil, fmt.Errorf("no readable content found")
+	}
+
+	if readable.LeadImage == "" {
+		if img := mustCompileSelector("img[src]").selectFirst(candidate); img != nil {
+			readable.LeadImage = attrValue(img, "src")
+		}
+	}
+	readable.LeadImage = resolveReference(readable.LeadImage, pageURL)
+
+	readable.WordCount = countWords(readable.Content)
+	minutes := int(math.Ceil(float64(readable.WordCount) / readingWordsPerMinute))
+	if minutes < 1 {
+		minutes = 1
+	}
+	readable.ReadingTime = time.Duration(minutes) * time.Minute
+
+	return readable, nil
+}
+
+// readableTitle 优先使用 og:title，其次是 <title>
+func readableTitle(doc *xhtml.Node) string {
+	if title := metaContent(doc, "og:title", "twitter:title"); title != "" {
+		return title
+	}
+	if title := mustCompileSelector("title").selectFirst(doc); title != nil {
+		return selectedText(title)
+	}
+	return ""
+}
+
+// metaContent 按顺序返回第一个非空的 <meta property|name="..."> 内容
+func metaContent(doc *xhtml.Node, names ...string) string {
+	metas := mustCompileSelector("meta[content]").selectAll(doc)
+	for _, name := range names {
+		for _, meta := range metas {
+			if attrValue(meta, "property") == name || attrValue(meta, "name") == name {
+				if content := strings.TrimSpace(attrValue(meta, "content")); content != "" {
+					return content
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// removeBoilerplate 移除不可能属于正文的节点
+func removeBoilerplate(node *xhtml.Node) {
+	for child := node.FirstChild; child != nil; {
+		next := child.NextSibling
+		switch {
+		case child.Type == xhtml.CommentNode:
+			node.RemoveChild(child)
+		case child.Type == xhtml.ElementNode && isBoilerplate(child):
+			node.RemoveChild(child)
+		default:
+			removeBoilerplate(child)
+		}
+		child = next
+	}
+}
+
+func isBoilerplate(node *xhtml.Node) bool {
+	switch node.DataAtom {
+	case atom.Script, atom.Style, atom.Noscript, atom.Iframe, atom.Nav, atom.Header,
+		atom.Footer, atom.Aside, atom.Form, atom.Button, atom.Template, atom.Svg, atom.Link:
+		return true
+	case atom.Html, atom.Body, atom.Article, atom.Main, atom.Pre, atom.Code:
+		return false
+	}
+
+	if attrValue(node, "role") == "navigation" || attrValue(node, "aria-hidden") == "true" {
+		return true
+	}
+	match := attrValue(node, "class") + " " + attrValue(node, "id")
+	return unlikelyCandidates.MatchString(match) && !maybeCandidates.MatchString(match)
+}
+
+// topCandidate 为段落的父节点和祖父节点打分，返回得分最高的节点
+func topCandidate(doc *xhtml.Node) *xhtml.Node {
+	scores := make(map[*xhtml.Node]float64)
+	var order []*xhtml.Node
+	addScore := func(node *xhtml.Node, score float64) {
+		if node == nil || node.Type != xhtml.ElementNode {
+			return
+		}
+		if _, ok := scores[node]; !ok {
+			scores[node] = initialScore(node)
+			order = append(order, node)
+		}
+		scores[node] += score
+	}
+
+	var walk func(node *xhtml.Node)
+	walk = func(node *xhtml.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type != xhtml.ElementNode {
+				continue
+			}
+			switch child.DataAtom {
+			case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+				text := strings.TrimSpace(nodeText(child))
+				if len(text) < 25 {
+					break
+				}
+				score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "，"))
+				score += math.Min(float64(len(text))/100, 3)
+				addScore(child.Parent, score)
+				if child.Parent != nil {
+					addScore(child.Parent.Parent, score/2)
+				}
+				continue
+			}
+			walk(child)
+		}
+	}
+	walk(doc)
+
+	var best *xhtml.Node
+	bestScore := 0.0
+	for _, node := range order {
+		score := scores[node] * (1 - linkDensity(node))
+		if best == nil || score > bestScore {
+			best, bestScore = node, score
+		}
+	}
+	return best
+}
+
+// initialScore 根据标签和类名给出候选节点的初始得分
+func initialScore(node *xhtml.Node) float64 {
+	score := 0.0
+	switch node.DataAtom {
+	case atom.Article, atom.Main:
+		score += 10
+	case atom.Div:
+		score += 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		score += 3
+	case atom.Ol, atom.Ul, atom.Dl, atom.Form:
+		score -= 3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		score -= 5
+	}
+
+	for _, value := range []string{attrValue(node, "class"), attrValue(node, "id")} {
+		if value == "" {
+			continue
+		}
+		if negativeClass.MatchString(value) {
+			score -= 25
+		}
+		if positiveClass.MatchString(value) {
+			score += 25
+		}
+	}
+	return score
+}
+
+// linkDensity 返回节点文本中链接文本所占比例
+func linkDensity(node *xhtml.Node) float64 {
+	total := len(strings.TrimSpace(nodeText(node)))
+	if total == 0 {
+		return 0
+	}
+	links := 0
+	for _, link := range mustCompileSelector("a").selectAll(node) {
+		links += len(strings.TrimSpace(nodeText(link)))
+	}
+	return float64(links) / float64(total)
+}
+
+// resolveReference 将相对地址解析为基于 pageURL 的绝对地址
+func resolveReference(ref, pageURL string) string {
+	if ref == "" || pageURL == "" {
+		return ref
+	}
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ref
+	}
+	resolved, err := base.Parse(ref)
+	if err != nil {
+		return ref
+	}
+	return resolved.String()
+}
+
+// countWords 统计词数，中日韩文字按单字计
+func countWords(text string) int {
+	count := 0
+	for _, field := range strings.Fields(text) {
+		cjk, other := 0, false
+		for _, r := range field {
+			if unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul) {
+				cjk++
+			} else if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				other = true
+			}
+		}
+		count += cjk
+		if other {
+			count++
+		}
+	}
+	return count
+}
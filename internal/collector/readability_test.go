@@ -0,0 +1,79 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+const readableTestHTML = `<html><head>
+<title>Signals in Angular | Blog</title>
+<meta property="og:title" content="Signals in Angular">
+<script>var tracking = "ignored";</script>
+</head><body>
+<header><nav><a href="/">Home</a> <a href="/blog">Blog</a></nav></header>
+<div class="layout">
+	<aside class="sidebar"><p>Subscribe to our newsletter, follow us on social media, and never miss a post again.</p></aside>
+	<div class="post-content">
+		<h1>Signals in Angular</h1>
+		<img src="/images/signals.png" alt="Signals">
+		<p>Signals are a new reactive primitive in Angular, and they change how components track state, derive values and schedule change detection.</p>
+		<p>A signal wraps a value, notifies consumers when it changes, and lets the framework update only the parts of the view that depend on it.</p>
+		<pre><code class="language-ts">const count = signal(0);
+count.set(1);</code></pre>
+		<p>Computed signals derive new values lazily, and effects run side effects whenever the signals they read are updated.</p>
+	</div>
+	<div class="comments"><p>Great article, thanks for writing this up, it really helped me understand signals!</p></div>
+</div>
+<footer><p>Copyright 2024, Example Inc. All rights reserved, including the right to reproduce.</p></footer>
+</body></html>`
+
+func TestExtractReadable(t *testing.T) {
+	readable, err := ExtractReadable(readableTestHTML, "https://example.com/blog/signals")
+	if err != nil {
+		t.Fatalf("ExtractReadable failed: %v", err)
+	}
+
+	if readable.Title != "Signals in Angular" {
+		t.Errorf("Expected og:title, got %q", readable.Title)
+	}
+	if readable.LeadImage != "https://example.com/images/signals.png" {
+		t.Errorf("Expected first image resolved against the page URL, got %q", readable.LeadImage)
+	}
+	if !strings.Contains(readable.Content, "Signals are a new reactive primitive") || !strings.Contains(readable.Content, "Computed signals derive") {
+		t.Errorf("Expected article paragraphs, got %q", readable.Content)
+	}
+	if !strings.Contains(readable.Content, "```ts\nconst count = signal(0);\ncount.set(1);\n```") {
+		t.Errorf("Expected code block to be preserved, got %q", readable.Content)
+	}
+	for _, boilerplate := range []string{"newsletter", "Great article", "Copyright", "tracking", "Home"} {
+		if strings.Contains(readable.Content, boilerplate) {
+			t.Errorf("Expected %q to be removed, got %q", boilerplate, readable.Content)
+		}
+	}
+	if readable.WordCount < 60 || readable.ReadingTime != time.Minute {
+		t.Errorf("Unexpected word count %d or reading time %v", readable.WordCount, readable.ReadingTime)
+	}
+}
+
+func TestExtractReadable_MetaImage(t *testing.T) {
+	page := `<html><head><meta name="twitter:image" content="https://cdn.example.com/cover.jpg"></head>
+<body><article><p>` + strings.Repeat("前端框架的响应式系统决定了组件如何追踪状态。", 20) + `</p></article></body></html>`
+
+	readable, err := ExtractReadable(page, "https://example.com/post")
+	if err != nil {
+		t.Fatalf("ExtractReadable failed: %v", err)
+	}
+	if readable.LeadImage != "https://cdn.example.com/cover.jpg" {
+		t.Errorf("Expected meta image, got %q", readable.LeadImage)
+	}
+	if readable.WordCount < 400 || readable.ReadingTime != 3*time.Minute {
+		t.Errorf("Expected CJK characters counted individually, got %d words and %v", readable.WordCount, readable.ReadingTime)
+	}
+}
+
+func TestExtractReadable_NoContent(t *testing.T) {
+	if _, err := ExtractReadable(`<html><body><nav><a href="/">Home</a></nav><p>Too short.</p></body></html>`, ""); err == nil {
+		t.Error("Expected error for a page without readable content")
+	}
+}
@@ -0,0 +1,224 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// redditBaseURL 帖子 permalink 的前缀
+const redditBaseURL = "https://www.reddit.com"
+
+// RedditListing Reddit listing 响应结构，subreddit 列表（/r/<sub>/hot.json 等）和
+// 搜索（/r/<sub>/search.json）返回相同的格式
+type RedditListing struct {
+	Data struct {
+		Children []struct {
+			Kind string     `json:"kind"`
+			Data RedditPost `json:"data"`
+		} `json:"children"`
+	} `json:"data"`
+}
+
+// RedditPost Reddit 帖子
+type RedditPost struct {
+	ID            string  `json:"id"`
+	Title         string  `json:"title"`
+	Selftext      string  `json:"selftext"`
+	Author        string  `json:"author"`
+	Subreddit     string  `json:"subreddit"`
+	Permalink     string  `json:"permalink"`
+	URL           string  `json:"url"`
+	Score         int     `json:"score"`
+	NumComments   int     `json:"num_comments"`
+	CreatedUTC    float64 `json:"created_utc"`
+	LinkFlairText string  `json:"link_flair_text"`
+	IsSelf        bool    `json:"is_self"`
+	Stickied      bool    `json:"stickied"`
+}
+
+// RedditCollector Reddit 采集器，采集 subreddit 列表和搜索结果
+type RedditCollector struct {
+	client *http.Client
+}
+
+// NewRedditCollector 创建Reddit采集器
+func NewRedditCollector() *RedditCollector {
+	return &RedditCollector{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+	}
+}
+
+// GetSourceType 返回采集器类型
+func (r *RedditCollector) GetSourceType() string {
+	return "reddit"
+}
+
+// Validate 验证配置
+func (r *RedditCollector) Validate(config CollectConfig) error {
+	if config.URL == "" {
+		return fmt.Errorf("URL is required")
+	}
+
+	parsedURL, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("URL must use http or https scheme")
+	}
+
+	return nil
+}
+
+// Collect 采集Reddit帖子，置顶帖不计入结果
+func (r *RedditCollector) Collect(ctx context.Context, config CollectConfig) (CollectResult, error) {
+	if err := r.Validate(config); err != nil {
+		return CollectResult{}, &ValidationError{Err: err}
+	}
+
+	// 设置超时
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	data, err := r.fetchListing(ctx, config)
+	if err != nil {
+		return CollectResult{}, err
+	}
+
+	var listing RedditListing
+	if err := json.Unmarshal(data, &listing); err != nil {
+		return CollectResult{}, &ParseError{What: "Reddit listing", Err: err}
+	}
+
+	posts := make([]RedditPost, 0, len(listing.Data.Children))
+	for _, child := range listing.Data.Children {
+		if child.Kind != "t3" || child.Data.Stickied {
+			continue
+		}
+		posts = append(posts, child.Data)
+	}
+
+	articles := r.convertPosts(posts, config)
+
+	// 限制文章数量
+	if config.MaxArticles > 0 && len(articles) > config.MaxArticles {
+		articles = articles[:config.MaxArticles]
+	}
+
+	return CollectResult{
+		Articles: articles,
+		Source:   config.URL,
+	}, nil
+}
+
+// fetchListing 获取listing JSON，地址未以 .json 结尾时自动补上
+func (r *RedditCollector) fetchListing(ctx context.Context, config CollectConfig) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", jsonListingURL(config.URL), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	// Reddit 会限制使用通用 User-Agent 的请求
+	req.Header.Set("User-Agent", "dev-context Reddit Collector/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	// 设置自定义头部
+	for key, value := range config.Headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch Reddit listing: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newHTTPStatusError(resp)
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	return body, nil
+}
+
+// jsonListingURL 为 /r/webdev/hot 这类页面地址补上 .json，无法解析的地址原样返回
+func jsonListingURL(rawURL string) string {
+	parsedURL, err := url.Parse(rawURL)
+	if err != nil || strings.HasSuffix(parsedURL.Path, ".json") {
+		return rawURL
+	}
+	parsedURL.Path = strings.TrimSuffix(parsedURL.Path, "/") + ".json"
+	return parsedURL.String()
+}
+
+// convertPosts 转换Reddit帖子为文章，URL 指向讨论页，外链帖子的链接保存在元数据中
+func (r *RedditCollector) convertPosts(posts []RedditPost, config CollectConfig) []Article {
+	articles := make([]Article, 0, len(posts))
+	api := &APICollector{}
+
+	for _, post := range posts {
+		tags := []string{strings.ToLower(post.Subreddit)}
+		if post.LinkFlairText != "" {
+			tags = append(tags, post.LinkFlairText)
+		}
+		tags = append(tags, config.Tags...)
+
+		// Reddit 在 JSON 中转义了 &、< 和 >
+		selftext := html.UnescapeString(post.Selftext)
+
+		article := Article{
+			ID:          post.ID,
+			Title:       html.UnescapeString(post.Title),
+			Content:     selftext,
+			Summary:     api.extractSummary(selftext, 200),
+			Author:      post.Author,
+			URL:         redditBaseURL + post.Permalink,
+			PublishedAt: time.Unix(int64(post.CreatedUTC), 0).UTC(),
+			Source:      config.URL,
+			SourceType:  r.GetSourceType(),
+			Language:    config.Language,
+			Tags:        tags,
+			Metadata:    make(map[string]string),
+		}
+
+		// 添加元数据
+		article.Metadata["reddit_post"] = "true"
+		article.Metadata["subreddit"] = post.Subreddit
+		article.Metadata["score"] = strconv.Itoa(post.Score)
+		article.Metadata["num_comments"] = strconv.Itoa(post.NumComments)
+		if post.LinkFlairText != "" {
+			article.Metadata["flair"] = post.LinkFlairText
+		}
+		if !post.IsSelf && post.URL != "" {
+			article.Metadata["link_url"] = post.URL
+		}
+
+		if config.Metadata != nil {
+			for k, v := range config.Metadata {
+				article.Metadata[k] = v
+			}
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles
+}
@@ -0,0 +1,130 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+const redditListingResponse = `{
+	"kind": "Listing",
+	"data": {
+		"children": [
+			{"kind": "t3", "data": {
+				"id": "pinned", "title": "Weekly showoff thread", "subreddit": "reactjs",
+				"permalink": "/r/reactjs/comments/pinned/weekly/", "stickied": true, "is_self": true
+			}},
+			{"kind": "t3", "data": {
+				"id": "abc123", "title": "useEffect runs twice in dev",
+				"selftext": "Why does this log twice?\n\n` + "```jsx\\nuseEffect(() => console.log('hi'), [])\\n```" + `",
+				"author": "alice", "subreddit": "reactjs", "link_flair_text": "Needs Help",
+				"permalink": "/r/reactjs/comments/abc123/useeffect_runs_twice/",
+				"url": "https://www.reddit.com/r/reactjs/comments/abc123/useeffect_runs_twice/",
+				"score": 42, "num_comments": 17, "created_utc": 1700000000.0, "is_self": true
+			}},
+			{"kind": "t3", "data": {
+				"id": "def456", "title": "React 19 released", "selftext": "",
+				"author": "bob", "subreddit": "reactjs",
+				"permalink": "/r/reactjs/comments/def456/react_19_released/",
+				"url": "https://react.dev/blog/2024/12/05/react-19",
+				"score": 1200, "num_comments": 230, "created_utc": 1733400000.0, "is_self": false
+			}}
+		]
+	}
+}`
+
+func TestRedditCollector_Collect(t *testing.T) {
+	var gotPath, gotUserAgent string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotUserAgent = r.Header.Get("User-Agent")
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(redditListingResponse))
+	}))
+	defer server.Close()
+
+	collector := NewRedditCollector()
+	result, err := collector.Collect(context.Background(), CollectConfig{
+		URL:  server.URL + "/r/reactjs/hot",
+		Tags: []string{"react"},
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if gotPath != "/r/reactjs/hot.json" {
+		t.Errorf("Expected .json listing path, got %s", gotPath)
+	}
+	if !strings.Contains(gotUserAgent, "dev-context") {
+		t.Errorf("Expected descriptive User-Agent, got %q", gotUserAgent)
+	}
+
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected stickied post to be skipped, got %d articles", len(result.Articles))
+	}
+
+	question := result.Articles[0]
+	if question.URL != "https://www.reddit.com/r/reactjs/comments/abc123/useeffect_runs_twice/" {
+		t.Errorf("Expected permalink URL, got %s", question.URL)
+	}
+	if question.Metadata["score"] != "42" || question.Metadata["num_comments"] != "17" {
+		t.Errorf("Expected score and comment count metadata, got %v", question.Metadata)
+	}
+	if !strings.Contains(question.Content, "```jsx") {
+		t.Errorf("Expected selftext as content, got %q", question.Content)
+	}
+	if !question.PublishedAt.Equal(time.Unix(1700000000, 0)) {
+		t.Errorf("Expected created_utc as published time, got %v", question.PublishedAt)
+	}
+	if len(question.Tags) != 3 || question.Tags[0] != "reactjs" || question.Tags[1] != "Needs Help" || question.Tags[2] != "react" {
+		t.Errorf("Expected subreddit, flair and config tags, got %v", question.Tags)
+	}
+	if _, exists := question.Metadata["link_url"]; exists {
+		t.Errorf("Self post should not have link_url, got %v", question.Metadata)
+	}
+
+	link := result.Articles[1]
+	if link.Metadata["link_url"] != "https://react.dev/blog/2024/12/05/react-19" {
+		t.Errorf("Expected link_url for link post, got %v", link.Metadata)
+	}
+}
+
+func TestRedditCollector_CollectSearchWithLimit(t *testing.T) {
+	var gotQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotQuery = r.URL.RawQuery
+		w.Write([]byte(redditListingResponse))
+	}))
+	defer server.Close()
+
+	collector := NewRedditCollector()
+	result, err := collector.Collect(context.Background(), CollectConfig{
+		URL:         server.URL + "/r/reactjs/search.json?q=useEffect&restrict_sr=1",
+		MaxArticles: 1,
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	if gotQuery != "q=useEffect&restrict_sr=1" {
+		t.Errorf("Expected query to be kept, got %s", gotQuery)
+	}
+	if len(result.Articles) != 1 || result.Articles[0].ID != "abc123" {
+		t.Errorf("Expected first post only, got %v", result.Articles)
+	}
+}
+
+func TestRedditCollector_CollectHTTPError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	collector := NewRedditCollector()
+	_, err := collector.Collect(context.Background(), CollectConfig{URL: server.URL + "/r/webdev/new.json"})
+	if err == nil || !strings.Contains(err.Error(), "429") {
+		t.Errorf("Expected HTTP 429 error, got %v", err)
+	}
+}
@@ -1,12 +1,15 @@
 package collector
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
+	"encoding/json"
 	"encoding/xml"
 	"fmt"
 	"html"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
 	"regexp"
@@ -35,6 +38,36 @@ type Item struct {
 	PubDate     string `xml:"pubDate"`
 	Author      string `xml:"author"`
 	Category    string `xml:"category"`
+
+	// 扩展模块：content:encoded 全文、Dublin Core 作者/日期/主题、Media RSS 以及附件
+	ContentEncoded string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Creator        string         `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Date           string         `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Subjects       []string       `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	MediaContent   []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnail []MediaContent `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	Enclosures     []Enclosure    `xml:"enclosure"`
+}
+
+// MediaContent Media RSS 的 media:content 或 media:thumbnail
+type MediaContent struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Medium string `xml:"medium,attr"`
+}
+
+// Enclosure RSS 2.0 附件
+type Enclosure struct {
+	URL    string `xml:"url,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// RDF feed（RSS 1.0）结构定义，条目与 channel 同级
+type RDFFeed struct {
+	XMLName xml.Name `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# RDF"`
+	Channel Channel  `xml:"channel"`
+	Items   []Item   `xml:"item"`
 }
 
 // Atom feed 结构定义
@@ -78,7 +111,8 @@ type AtomCategory struct {
 
 // RSSCollector RSS数据采集器
 type RSSCollector struct {
-	client *http.Client
+	client      *http.Client
+	conditional *conditionalCache
 }
 
 // NewRSSCollector 创建RSS采集器
@@ -87,6 +121,7 @@ func NewRSSCollector() *RSSCollector {
 		client: &http.Client{
 			Timeout: 30 * time.Second,
 		},
+		conditional: newConditionalCache(),
 	}
 }
 
@@ -116,7 +151,7 @@ func (r *RSSCollector) Validate(config CollectConfig) error {
 // Collect 采集RSS数据
 func (r *RSSCollector) Collect(ctx context.Context, config CollectConfig) (CollectResult, error) {
 	if err := r.Validate(config); err != nil {
-		return CollectResult{}, fmt.Errorf("validation failed: %w", err)
+		return CollectResult{}, &ValidationError{Err: err}
 	}
 
 	// 设置超时
@@ -134,30 +169,42 @@ func (r *RSSCollector) Collect(ctx context.Context, config CollectConfig) (Colle
 
 	// 设置头部
 	req.Header.Set("User-Agent", "RSS Collector/1.0")
+	req.Header.Set("Accept", feedAcceptHeader)
 	for key, value := range config.Headers {
 		req.Header.Set(key, value)
 	}
 
+	r.conditional.prepare(req)
+
 	resp, err := r.client.Do(req)
 	if err != nil {
 		return CollectResult{}, fmt.Errorf("failed to fetch RSS feed: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return CollectResult{}, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, resp.Status)
-	}
+	var body []byte
+	var contentType string
+	var notModified bool
+	if cached, ok := r.conditional.notModified(req, resp); ok {
+		body, contentType, notModified = cached.Body, cached.ContentType, true
+	} else {
+		if resp.StatusCode != http.StatusOK {
+			return CollectResult{}, newHTTPStatusError(resp)
+		}
 
-	// 读取响应体
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return CollectResult{}, fmt.Errorf("failed to read response body: %w", err)
+		// 读取响应体
+		body, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return CollectResult{}, fmt.Errorf("failed to read response body: %w", err)
+		}
+		contentType = resp.Header.Get("Content-Type")
+		notModified = r.conditional.store(req, resp, body)
 	}
 
-	// 尝试解析RSS或Atom
-	articles, err := r.parseFeed(body, config)
+	// 按内容类型和内容识别格式后解析
+	articles, err := r.parseFeed(body, contentType, config)
 	if err != nil {
-		return CollectResult{}, fmt.Errorf("failed to parse feed: %w", err)
+		return CollectResult{}, &ParseError{What: "feed", Err: err}
 	}
 
 	// 限制文章数量
@@ -166,26 +213,124 @@ func (r *RSSCollector) Collect(ctx context.Context, config CollectConfig) (Colle
 	}
 
 	return CollectResult{
-		Articles: articles,
-		Source:   config.URL,
+		Articles:    articles,
+		Source:      config.URL,
+		NotModified: notModified,
 	}, nil
 }
 
-// parseFeed 解析RSS或Atom feed
-func (r *RSSCollector) parseFeed(data []byte, config CollectConfig) ([]Article, error) {
-	// 先尝试解析RSS
-	var rssFeed RSSFeed
-	if err := xml.Unmarshal(data, &rssFeed); err == nil && len(rssFeed.Channel.Items) > 0 {
-		return r.parseRSSItems(rssFeed.Channel.Items, config)
+// feedAcceptHeader 请求时声明支持的feed格式
+const feedAcceptHeader = "application/rss+xml, application/atom+xml, application/feed+json, application/rdf+xml, application/xml;q=0.9, */*;q=0.8"
+
+// feed 格式
+const (
+	feedFormatRSS  = "rss"
+	feedFormatAtom = "atom"
+	feedFormatRDF  = "rdf"
+	feedFormatJSON = "json"
+)
+
+// parseFeed 解析RSS 2.0、Atom、RDF（RSS 1.0）或JSON Feed
+//
+// 先按响应的 Content-Type 和内容本身识别格式，识别出的格式解析失败或没有条目时
+// 再依次尝试其余格式，以兼容 Content-Type 不准确的feed。
+func (r *RSSCollector) parseFeed(data []byte, contentType string, config CollectConfig) ([]Article, error) {
+	tried := make(map[string]bool)
+	formats := []string{feedFormatFromContentType(contentType), feedFormatFromPayload(data), feedFormatRSS, feedFormatAtom, feedFormatRDF, feedFormatJSON}
+
+	for _, format := range formats {
+		if format == "" || tried[format] {
+			continue
+		}
+		tried[format] = true
+
+		if articles, ok := r.parseFeedAs(format, data, config); ok {
+			return articles, nil
+		}
 	}
 
-	// 再尝试解析Atom
-	var atomFeed AtomFeed
-	if err := xml.Unmarshal(data, &atomFeed); err == nil && len(atomFeed.Entries) > 0 {
-		return r.parseAtomEntries(atomFeed.Entries, config)
+	return nil, fmt.Errorf("unable to parse as RSS, Atom, RDF or JSON Feed")
+}
+
+// parseFeedAs 按指定格式解析，没有条目时返回 false
+func (r *RSSCollector) parseFeedAs(format string, data []byte, config CollectConfig) ([]Article, bool) {
+	switch format {
+	case feedFormatRSS:
+		var rssFeed RSSFeed
+		if err := xml.Unmarshal(data, &rssFeed); err == nil && len(rssFeed.Channel.Items) > 0 {
+			articles, err := r.parseRSSItems(rssFeed.Channel.Items, config)
+			return articles, err == nil
+		}
+	case feedFormatAtom:
+		var atomFeed AtomFeed
+		if err := xml.Unmarshal(data, &atomFeed); err == nil && len(atomFeed.Entries) > 0 {
+			articles, err := r.parseAtomEntries(atomFeed.Entries, config)
+			return articles, err == nil
+		}
+	case feedFormatRDF:
+		var rdfFeed RDFFeed
+		if err := xml.Unmarshal(data, &rdfFeed); err == nil && len(rdfFeed.Items) > 0 {
+			articles, err := r.parseRSSItems(rdfFeed.Items, config)
+			return articles, err == nil
+		}
+	case feedFormatJSON:
+		var jsonFeed JSONFeed
+		if err := json.Unmarshal(data, &jsonFeed); err == nil && jsonFeed.isValid() && len(jsonFeed.Items) > 0 {
+			articles, err := r.parseJSONFeedItems(jsonFeed, config)
+			return articles, err == nil
+		}
 	}
+	return nil, false
+}
 
-	return nil, fmt.Errorf("unable to parse as RSS or Atom feed")
+// feedFormatFromContentType 根据 Content-Type 识别feed格式，通用类型（如 text/xml）返回空字符串
+func feedFormatFromContentType(contentType string) string {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	switch mediaType {
+	case "application/rss+xml":
+		return feedFormatRSS
+	case "application/atom+xml":
+		return feedFormatAtom
+	case "application/rdf+xml":
+		return feedFormatRDF
+	case "application/feed+json", "application/json":
+		return feedFormatJSON
+	}
+	return ""
+}
+
+// feedFormatFromPayload 根据内容识别feed格式：JSON 对象视为 JSON Feed，XML 按根元素区分
+func feedFormatFromPayload(data []byte) string {
+	trimmed := bytes.TrimLeft(bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")), " \t\r\n")
+	if len(trimmed) == 0 {
+		return ""
+	}
+	if trimmed[0] == '{' {
+		return feedFormatJSON
+	}
+
+	decoder := xml.NewDecoder(bytes.NewReader(trimmed))
+	decoder.Strict = false
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return ""
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			switch strings.ToLower(start.Name.Local) {
+			case "rss":
+				return feedFormatRSS
+			case "feed":
+				return feedFormatAtom
+			case "rdf":
+				return feedFormatRDF
+			}
+			return ""
+		}
+	}
 }
 
 // parseRSSItems 解析RSS条目
@@ -205,6 +350,18 @@ func (r *RSSCollector) parseRSSItems(items []Item, config CollectConfig) ([]Arti
 			Metadata:   make(map[string]string),
 		}
 
+		// content:encoded 为全文HTML，转换为保留代码块的文本
+		if item.ContentEncoded != "" {
+			article.Content = bodyToMarkdown(item.ContentEncoded)
+			if article.Summary == "" {
+				article.Summary = html.UnescapeString(r.extractSummary(item.ContentEncoded, 200))
+			}
+		}
+		if article.Author == "" {
+			article.Author = html.UnescapeString(strings.TrimSpace(item.Creator))
+		}
+		r.addMediaMetadata(&article, item)
+
 		// 生成ID
 		if item.GUID != "" {
 			article.ID = item.GUID
@@ -212,9 +369,13 @@ func (r *RSSCollector) parseRSSItems(items []Item, config CollectConfig) ([]Arti
 			article.ID = r.generateID(item.Link + item.Title)
 		}
 
-		// 解析发布时间
-		if item.PubDate != "" {
-			if pubTime, err := r.parseTime(item.PubDate); err == nil {
+		// 解析发布时间，RDF 使用 dc:date
+		pubDate := item.PubDate
+		if pubDate == "" {
+			pubDate = item.Date
+		}
+		if pubDate != "" {
+			if pubTime, err := r.parseTime(pubDate); err == nil {
 				article.PublishedAt = pubTime
 			}
 		}
@@ -223,6 +384,11 @@ func (r *RSSCollector) parseRSSItems(items []Item, config CollectConfig) ([]Arti
 		if item.Category != "" {
 			article.Tags = []string{strings.TrimSpace(item.Category)}
 		}
+		for _, subject := range item.Subjects {
+			if subject = strings.TrimSpace(subject); subject != "" {
+				article.Tags = append(article.Tags, subject)
+			}
+		}
 		if len(config.Tags) > 0 {
 			article.Tags = append(article.Tags, config.Tags...)
 		}
@@ -240,6 +406,53 @@ func (r *RSSCollector) parseRSSItems(items []Item, config CollectConfig) ([]Arti
 	return articles, nil
 }
 
+// addMediaMetadata 记录头图和附件：头图取第一个图片类型的 media:content、media:thumbnail 或附件，
+// 附件取第一个 enclosure（如播客音频）
+func (r *RSSCollector) addMediaMetadata(article *Article, item Item) {
+	for _, media := range item.MediaContent {
+		if media.URL != "" && (media.Medium == "image" || strings.HasPrefix(media.Type, "image/")) {
+			article.Metadata["lead_image"] = media.URL
+			break
+		}
+	}
+	if _, ok := article.Metadata["lead_image"]; !ok {
+		for _, thumbnail := range item.MediaThumbnail {
+			if thumbnail.URL != "" {
+				article.Metadata["lead_image"] = thumbnail.URL
+				break
+			}
+		}
+	}
+
+	for _, enclosure := range item.Enclosures {
+		if enclosure.URL == "" {
+			continue
+		}
+		if _, ok := article.Metadata["lead_image"]; !ok && strings.HasPrefix(enclosure.Type, "image/") {
+			article.Metadata["lead_image"] = enclosure.URL
+		}
+		if _, ok := article.Metadata["enclosure_url"]; !ok {
+			article.Metadata["enclosure_url"] = enclosure.URL
+			if enclosure.Type != "" {
+				article.Metadata["enclosure_type"] = enclosure.Type
+			}
+			if enclosure.Length != "" && enclosure.Length != "0" {
+				article.Metadata["enclosure_length"] = enclosure.Length
+			}
+		}
+	}
+
+	for _, media := range item.MediaContent {
+		if media.URL != "" && media.Medium != "image" && !strings.HasPrefix(media.Type, "image/") {
+			article.Metadata["media_url"] = media.URL
+			if media.Type != "" {
+				article.Metadata["media_type"] = media.Type
+			}
+			break
+		}
+	}
+}
+
 // parseAtomEntries 解析Atom条目
 func (r *RSSCollector) parseAtomEntries(entries []AtomEntry, config CollectConfig) ([]Article, error) {
 	articles := make([]Article, 0, len(entries))
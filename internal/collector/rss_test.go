@@ -2,8 +2,10 @@ package collector
 
 import (
 	"context"
+	"errors"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
 )
@@ -190,6 +192,212 @@ func TestRSSCollector_Collect_Atom(t *testing.T) {
 	}
 }
 
+func TestRSSCollector_Collect_Extensions(t *testing.T) {
+	rssContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:content="http://purl.org/rss/1.0/modules/content/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Frontend Podcast</title>
+    <item>
+      <title>Episode 42: Signals</title>
+      <description>Short teaser.</description>
+      <content:encoded><![CDATA[<p>We talk about signals &amp; effects.</p><pre><code class="language-js">effect(() => console.log(count()))</code></pre>]]></content:encoded>
+      <link>https://example.com/episodes/42</link>
+      <dc:creator>Jane Doe</dc:creator>
+      <pubDate>Mon, 04 Mar 2024 10:00:00 GMT</pubDate>
+      <media:content url="https://cdn.example.com/42.mp4" type="video/mp4"/>
+      <media:content url="https://cdn.example.com/42.jpg" medium="image"/>
+      <enclosure url="https://cdn.example.com/42.mp3" type="audio/mpeg" length="12345"/>
+    </item>
+  </channel>
+</rss>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml; charset=utf-8")
+		w.Write([]byte(rssContent))
+	}))
+	defer server.Close()
+
+	result, err := NewRSSCollector().Collect(context.Background(), CollectConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(result.Articles) != 1 {
+		t.Fatalf("Expected 1 article, got %d", len(result.Articles))
+	}
+
+	article := result.Articles[0]
+	if article.Author != "Jane Doe" {
+		t.Errorf("Expected dc:creator as author, got %q", article.Author)
+	}
+	if !strings.Contains(article.Content, "signals & effects") || !strings.Contains(article.Content, "```js\neffect(() => console.log(count()))\n```") {
+		t.Errorf("Expected content:encoded with code block, got %q", article.Content)
+	}
+	if article.Summary != "Short teaser." {
+		t.Errorf("Expected description as summary, got %q", article.Summary)
+	}
+	if article.Metadata["lead_image"] != "https://cdn.example.com/42.jpg" {
+		t.Errorf("Expected media:content image, got %v", article.Metadata)
+	}
+	if article.Metadata["media_url"] != "https://cdn.example.com/42.mp4" || article.Metadata["media_type"] != "video/mp4" {
+		t.Errorf("Expected media:content video, got %v", article.Metadata)
+	}
+	if article.Metadata["enclosure_url"] != "https://cdn.example.com/42.mp3" || article.Metadata["enclosure_type"] != "audio/mpeg" || article.Metadata["enclosure_length"] != "12345" {
+		t.Errorf("Expected enclosure, got %v", article.Metadata)
+	}
+}
+
+func TestRSSCollector_Collect_RDF(t *testing.T) {
+	rdfContent := `<?xml version="1.0" encoding="UTF-8"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"
+  xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel rdf:about="https://example.com/">
+    <title>RDF Blog</title>
+    <link>https://example.com/</link>
+  </channel>
+  <item rdf:about="https://example.com/css-nesting">
+    <title>CSS nesting lands everywhere</title>
+    <link>https://example.com/css-nesting</link>
+    <description>Native CSS nesting is now Baseline.</description>
+    <dc:creator>John Smith</dc:creator>
+    <dc:date>2024-03-05T09:30:00Z</dc:date>
+    <dc:subject>css</dc:subject>
+  </item>
+</rdf:RDF>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/rdf+xml")
+		w.Write([]byte(rdfContent))
+	}))
+	defer server.Close()
+
+	result, err := NewRSSCollector().Collect(context.Background(), CollectConfig{URL: server.URL, Tags: []string{"frontend"}})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(result.Articles) != 1 {
+		t.Fatalf("Expected 1 article, got %d", len(result.Articles))
+	}
+
+	article := result.Articles[0]
+	if article.Title != "CSS nesting lands everywhere" || article.URL != "https://example.com/css-nesting" || article.Author != "John Smith" {
+		t.Errorf("Unexpected RDF article: %+v", article)
+	}
+	if !article.PublishedAt.Equal(time.Date(2024, 3, 5, 9, 30, 0, 0, time.UTC)) {
+		t.Errorf("Expected dc:date as publish time, got %v", article.PublishedAt)
+	}
+	if strings.Join(article.Tags, ",") != "css,frontend" {
+		t.Errorf("Expected dc:subject and config tags, got %v", article.Tags)
+	}
+}
+
+func TestRSSCollector_Collect_JSONFeed(t *testing.T) {
+	jsonContent := `{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "JSON Blog",
+		"language": "en",
+		"authors": [{"name": "Feed Author"}],
+		"items": [
+			{
+				"id": "https://example.com/view-transitions",
+				"url": "https://example.com/view-transitions",
+				"title": "Cross-document view transitions",
+				"content_html": "<p>View transitions now work across pages.</p><pre><code>@view-transition { navigation: auto; }</code></pre>",
+				"summary": "View transitions across pages.",
+				"image": "https://example.com/vt.png",
+				"date_published": "2024-03-06T08:00:00+01:00",
+				"tags": ["css"],
+				"authors": [{"name": "Item Author"}],
+				"attachments": [{"url": "https://example.com/vt.mp4", "mime_type": "video/mp4", "size_in_bytes": 2048}]
+			},
+			{
+				"id": 2,
+				"external_url": "https://other.example.com/note",
+				"content_text": "A short note without a title about the new popover API.",
+				"date_modified": "2024-03-07T08:00:00Z"
+			}
+		]
+	}`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Content-Type 不准确时按内容识别
+		w.Header().Set("Content-Type", "text/plain")
+		w.Write([]byte(jsonContent))
+	}))
+	defer server.Close()
+
+	result, err := NewRSSCollector().Collect(context.Background(), CollectConfig{URL: server.URL})
+	if err != nil {
+		t.Fatalf("Collect() error = %v", err)
+	}
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected 2 articles, got %d", len(result.Articles))
+	}
+
+	first := result.Articles[0]
+	if first.ID != "https://example.com/view-transitions" || first.Author != "Item Author" || first.Language != "en" {
+		t.Errorf("Unexpected JSON Feed article: %+v", first)
+	}
+	if !strings.Contains(first.Content, "```\n@view-transition { navigation: auto; }\n```") || first.Summary != "View transitions across pages." {
+		t.Errorf("Unexpected content or summary: %q / %q", first.Content, first.Summary)
+	}
+	if !first.PublishedAt.Equal(time.Date(2024, 3, 6, 7, 0, 0, 0, time.UTC)) {
+		t.Errorf("Expected date_published, got %v", first.PublishedAt)
+	}
+	if first.Metadata["lead_image"] != "https://example.com/vt.png" || first.Metadata["enclosure_url"] != "https://example.com/vt.mp4" || first.Metadata["enclosure_length"] != "2048" {
+		t.Errorf("Unexpected metadata: %v", first.Metadata)
+	}
+
+	second := result.Articles[1]
+	if second.ID != "2" || second.URL != "https://other.example.com/note" || second.Author != "Feed Author" {
+		t.Errorf("Expected numeric id, external URL and feed author, got %+v", second)
+	}
+	if !strings.HasPrefix(second.Title, "A short note without a title") {
+		t.Errorf("Expected title from content_text, got %q", second.Title)
+	}
+}
+
+func TestRSSCollector_parseFeed_Sniffing(t *testing.T) {
+	atom := `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><entry><title>Atom entry</title><id>1</id></entry></feed>`
+	rss := `<rss version="2.0"><channel><item><title>RSS item</title></item></channel></rss>`
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        string
+		want        string
+	}{
+		{"atom by payload", "text/xml", atom, "Atom entry"},
+		{"wrong content type", "application/rss+xml", atom, "Atom entry"},
+		{"json content type with xml payload", "application/json", "\ufeff  " + rss, "RSS item"},
+		{"json feed without content type", "", `{"version": "https://jsonfeed.org/version/1", "items": [{"id": "1", "title": "JSON item"}]}`, "JSON item"},
+	}
+
+	collector := NewRSSCollector()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, err := collector.parseFeed([]byte(tt.data), tt.contentType, CollectConfig{})
+			if err != nil {
+				t.Fatalf("parseFeed() error = %v", err)
+			}
+			if len(articles) != 1 || articles[0].Title != tt.want {
+				t.Errorf("Expected %q, got %+v", tt.want, articles)
+			}
+		})
+	}
+
+	if _, err := collector.parseFeed([]byte(`{"items": [{"title": "not a feed"}]}`), "application/json", CollectConfig{}); err == nil {
+		t.Error("Expected error for JSON without a JSON Feed version")
+	}
+
+	if got := feedFormatFromPayload([]byte("<?xml version=\"1.0\"?>\n<!-- generator -->\n<rdf:RDF xmlns:rdf=\"http://www.w3.org/1999/02/22-rdf-syntax-ns#\"/>")); got != feedFormatRDF {
+		t.Errorf("Expected rdf, got %q", got)
+	}
+	if got := feedFormatFromContentType("application/feed+json; charset=utf-8"); got != feedFormatJSON {
+		t.Errorf("Expected json, got %q", got)
+	}
+}
+
 func TestRSSCollector_Collect_HTTPError(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusNotFound)
@@ -202,8 +410,9 @@ func TestRSSCollector_Collect_HTTPError(t *testing.T) {
 	ctx := context.Background()
 	result, err := collector.Collect(ctx, config)
 
-	if err == nil {
-		t.Error("Expected error for HTTP 404, got nil")
+	var statusErr *HTTPStatusError
+	if !errors.As(err, &statusErr) || statusErr.StatusCode != http.StatusNotFound {
+		t.Errorf("Expected HTTPStatusError with status 404, got %v", err)
 	}
 
 	if len(result.Articles) != 0 {
@@ -224,8 +433,8 @@ func TestRSSCollector_Collect_InvalidXML(t *testing.T) {
 	ctx := context.Background()
 	result, err := collector.Collect(ctx, config)
 
-	if err == nil {
-		t.Error("Expected error for invalid XML, got nil")
+	if ErrorKind(err) != ErrorKindParse {
+		t.Errorf("Expected parse error for invalid XML, got %v", err)
 	}
 
 	if len(result.Articles) != 0 {
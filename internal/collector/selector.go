@@ -0,0 +1,417 @@
+package collector
+
+import (
+	"fmt"
+	"strings"
+
+	xhtml "golang.org/x/net/html"
+)
+
+// cssSelector 解析后的CSS选择器组（逗号分隔），任一选择器匹配即视为匹配
+//
+// 支持的语法：类型选择器、*、#id、.class、[attr]、[attr=v]、[attr~=v]、[attr|=v]、
+// [attr^=v]、[attr$=v]、[attr*=v]、:first-child、:last-child、:first-of-type、
+// :last-of-type 以及后代（空格）、子（>）、相邻兄弟（+）和通用兄弟（~）组合器。
+type cssSelector []cssComplex
+
+// cssComplex 由组合器连接的复合选择器，combinators[i] 连接 compounds[i] 和 compounds[i+1]
+type cssComplex struct {
+	compounds   []cssCompound
+	combinators []byte
+}
+
+// cssCompound 作用于同一元素的简单选择器
+type cssCompound struct {
+	tag     string
+	id      string
+	classes []string
+	attrs   []cssAttr
+	pseudos []string
+}
+
+// cssAttr 属性选择器
+type cssAttr struct {
+	name  string
+	op    string // ""（存在即可）、=、~=、|=、^=、$=、*=
+	value string
+}
+
+// compileSelector 解析CSS选择器
+func compileSelector(selector string) (cssSelector, error) {
+	p := &selectorParser{input: selector}
+	group, err := p.parseGroup()
+	if err != nil {
+		return nil, fmt.Errorf("invalid selector %q: %w", selector, err)
+	}
+	return group, nil
+}
+
+// selectorParser CSS选择器解析器
+type selectorParser struct {
+	input string
+	pos   int
+}
+
+func (p *selectorParser) parseGroup() (cssSelector, error) {
+	var group cssSelector
+	for {
+		p.skipSpace()
+		complex, err := p.parseComplex()
+		if err != nil {
+			return nil, err
+		}
+		group = append(group, complex)
+
+		p.skipSpace()
+		if p.pos >= len(p.input) {
+			return group, nil
+		}
+		if p.input[p.pos] != ',' {
+			return nil, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos], p.pos)
+		}
+		p.pos++
+	}
+}
+
+func (p *selectorParser) parseComplex() (cssComplex, error) {
+	var complex cssComplex
+	compound, err := p.parseCompound()
+	if err != nil {
+		return complex, err
+	}
+	complex.compounds = append(complex.compounds, compound)
+
+	for {
+		hadSpace := p.skipSpace()
+		if p.pos >= len(p.input) || p.input[p.pos] == ',' {
+			return complex, nil
+		}
+
+		combinator := byte(' ')
+		switch c := p.input[p.pos]; c {
+		case '>', '+', '~':
+			combinator = c
+			p.pos++
+			p.skipSpace()
+		default:
+			if !hadSpace {
+				return complex, fmt.Errorf("unexpected %q at offset %d", c, p.pos)
+			}
+		}
+
+		compound, err := p.parseCompound()
+		if err != nil {
+			return complex, err
+		}
+		complex.combinators = append(complex.combinators, combinator)
+		complex.compounds = append(complex.compounds, compound)
+	}
+}
+
+func (p *selectorParser) parseCompound() (cssCompound, error) {
+	var compound cssCompound
+	start := p.pos
+
+	if p.pos < len(p.input) && p.input[p.pos] == '*' {
+		p.pos++
+	} else if name := p.parseIdent(); name != "" {
+		compound.tag = strings.ToLower(name)
+	}
+
+	for p.pos < len(p.input) {
+		switch p.input[p.pos] {
+		case '#':
+			p.pos++
+			if compound.id = p.parseIdent(); compound.id == "" {
+				return compound, fmt.Errorf("missing id at offset %d", p.pos)
+			}
+		case '.':
+			p.pos++
+			class := p.parseIdent()
+			if class == "" {
+				return compound, fmt.Errorf("missing class name at offset %d", p.pos)
+			}
+			compound.classes = append(compound.classes, class)
+		case '[':
+			attr, err := p.parseAttr()
+			if err != nil {
+				return compound, err
+			}
+			compound.attrs = append(compound.attrs, attr)
+		case ':':
+			p.pos++
+			pseudo := strings.ToLower(p.parseIdent())
+			switch pseudo {
+			case "first-child", "last-child", "first-of-type", "last-of-type":
+				compound.pseudos = append(compound.pseudos, pseudo)
+			default:
+				return compound, fmt.Errorf("unsupported pseudo-class %q", pseudo)
+			}
+		default:
+			if p.pos == start {
+				return compound, fmt.Errorf("unexpected %q at offset %d", p.input[p.pos], p.pos)
+			}
+			return compound, nil
+		}
+	}
+
+	if p.pos == start {
+		return compound, fmt.Errorf("empty selector")
+	}
+	return compound, nil
+}
+
+func (p *selectorParser) parseAttr() (cssAttr, error) {
+	var attr cssAttr
+	p.pos++ // [
+	p.skipSpace()
+	if attr.name = strings.ToLower(p.parseIdent()); attr.name == "" {
+		return attr, fmt.Errorf("missing attribute name at offset %d", p.pos)
+	}
+	p.skipSpace()
+
+	if p.pos < len(p.input) && p.input[p.pos] == ']' {
+		p.pos++
+		return attr, nil
+	}
+
+	for _, op := range []string{"=", "~=", "|=", "^=", "$=", "*="} {
+		if strings.HasPrefix(p.input[p.pos:], op) {
+			attr.op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if attr.op == "" {
+		return attr, fmt.Errorf("invalid attribute selector at offset %d", p.pos)
+	}
+	p.skipSpace()
+
+	if p.pos < len(p.input) && (p.input[p.pos] == '"' || p.input[p.pos] == '\'') {
+		quote := p.input[p.pos]
+		end := strings.IndexByte(p.input[p.pos+1:], quote)
+		if end < 0 {
+			return attr, fmt.Errorf("unterminated string at offset %d", p.pos)
+		}
+		attr.value = p.input[p.pos+1 : p.pos+1+end]
+		p.pos += end + 2
+	} else {
+		attr.value = p.parseIdent()
+	}
+
+	p.skipSpace()
+	if p.pos >= len(p.input) || p.input[p.pos] != ']' {
+		return attr, fmt.Errorf("missing ] at offset %d", p.pos)
+	}
+	p.pos++
+	return attr, nil
+}
+
+func (p *selectorParser) parseIdent() string {
+	start := p.pos
+	for p.pos < len(p.input) {
+		c := p.input[p.pos]
+		if c == '-' || c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= 0x80 {
+			p.pos++
+			continue
+		}
+		break
+	}
+	return p.input[start:p.pos]
+}
+
+// skipSpace 跳过空白，返回是否跳过了字符
+func (p *selectorParser) skipSpace() bool {
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte(" \t\n\r\f", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+	return p.pos > start
+}
+
+// selectAll 按文档顺序返回 root 的后代中匹配选择器的元素
+func (s cssSelector) selectAll(root *xhtml.Node) []*xhtml.Node {
+	var matches []*xhtml.Node
+	var walk func(node *xhtml.Node)
+	walk = func(node *xhtml.Node) {
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == xhtml.ElementNode && s.match(child) {
+				matches = append(matches, child)
+			}
+			walk(child)
+		}
+	}
+	walk(root)
+	return matches
+}
+
+// selectFirst 返回第一个匹配的后代元素
+func (s cssSelector) selectFirst(root *xhtml.Node) *xhtml.Node {
+	if matches := s.selectAll(root); len(matches) > 0 {
+		return matches[0]
+	}
+	return nil
+}
+
+// match 判断元素是否匹配选择器组
+func (s cssSelector) match(node *xhtml.Node) bool {
+	for _, complex := range s {
+		if complex.match(node, len(complex.compounds)-1) {
+			return true
+		}
+	}
+	return false
+}
+
+// match 从右向左匹配，index 为当前要匹配的复合选择器
+func (c cssComplex) match(node *xhtml.Node, index int) bool {
+	if !c.compounds[index].match(node) {
+		return false
+	}
+	if index == 0 {
+		return true
+	}
+
+	switch c.combinators[index-1] {
+	case '>':
+		parent := node.Parent
+		return parent != nil && parent.Type == xhtml.ElementNode && c.match(parent, index-1)
+	case '+':
+		prev := previousElement(node)
+		return prev != nil && c.match(prev, index-1)
+	case '~':
+		for prev := previousElement(node); prev != nil; prev = previousElement(prev) {
+			if c.match(prev, index-1) {
+				return true
+			}
+		}
+		return false
+	default:
+		for parent := node.Parent; parent != nil && parent.Type == xhtml.ElementNode; parent = parent.Parent {
+			if c.match(parent, index-1) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+func (c cssCompound) match(node *xhtml.Node) bool {
+	if c.tag != "" && node.Data != c.tag {
+		return false
+	}
+	if c.id != "" && attrValue(node, "id") != c.id {
+		return false
+	}
+	if len(c.classes) > 0 {
+		classes := strings.Fields(attrValue(node, "class"))
+		for _, class := range c.classes {
+			if !containsString(classes, class) {
+				return false
+			}
+		}
+	}
+	for _, attr := range c.attrs {
+		if !attr.match(node) {
+			return false
+		}
+	}
+	for _, pseudo := range c.pseudos {
+		if !matchPseudo(node, pseudo) {
+			return false
+		}
+	}
+	return true
+}
+
+func (a cssAttr) match(node *xhtml.Node) bool {
+	value, ok := "", false
+	for _, attr := range node.Attr {
+		if attr.Key == a.name {
+			value, ok = attr.Val, true
+			break
+		}
+	}
+	if !ok {
+		return false
+	}
+
+	switch a.op {
+	case "":
+		return true
+	case "=":
+		return value == a.value
+	case "~=":
+		return containsString(strings.Fields(value), a.value)
+	case "|=":
+		return value == a.value || strings.HasPrefix(value, a.value+"-")
+	case "^=":
+		return a.value != "" && strings.HasPrefix(value, a.value)
+	case "$=":
+		return a.value != "" && strings.HasSuffix(value, a.value)
+	case "*=":
+		return a.value != "" && strings.Contains(value, a.value)
+	}
+	return false
+}
+
+func matchPseudo(node *xhtml.Node, pseudo string) bool {
+	switch pseudo {
+	case "first-child":
+		return previousElement(node) == nil
+	case "last-child":
+		return nextElement(node) == nil
+	case "first-of-type":
+		for prev := previousElement(node); prev != nil; prev = previousElement(prev) {
+			if prev.Data == node.Data {
+				return false
+			}
+		}
+		return true
+	case "last-of-type":
+		for next := nextElement(node); next != nil; next = nextElement(next) {
+			if next.Data == node.Data {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func previousElement(node *xhtml.Node) *xhtml.Node {
+	for prev := node.PrevSibling; prev != nil; prev = prev.PrevSibling {
+		if prev.Type == xhtml.ElementNode {
+			return prev
+		}
+	}
+	return nil
+}
+
+func nextElement(node *xhtml.Node) *xhtml.Node {
+	for next := node.NextSibling; next != nil; next = next.NextSibling {
+		if next.Type == xhtml.ElementNode {
+			return next
+		}
+	}
+	return nil
+}
+
+// attrValue 返回元素属性值，不存在时返回空字符串
+func attrValue(node *xhtml.Node, name string) string {
+	for _, attr := range node.Attr {
+		if attr.Key == name {
+			return attr.Val
+		}
+	}
+	return ""
+}
+
+func containsString(list []string, item string) bool {
+	for _, s := range list {
+		if s == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,71 @@
+package collector
+
+import (
+	"strings"
+	"testing"
+
+	xhtml "golang.org/x/net/html"
+)
+
+const selectorTestHTML = `<html><body>
+<main id="blog">
+	<ul class="posts featured">
+		<li data-kind="release"><h2 class="title">Vue 3.5</h2><a href="/vue-3-5" rel="bookmark">Read</a></li>
+		<li data-kind="news-item"><h2 class="title">Vite 6</h2><span>skip</span><a href="https://vite.dev/blog">Read</a></li>
+		<li><h3>Untitled</h3></li>
+	</ul>
+</main>
+<footer><h2 class="title">Footer</h2></footer>
+</body></html>`
+
+func TestCompileSelector_Match(t *testing.T) {
+	doc, err := xhtml.Parse(strings.NewReader(selectorTestHTML))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		selector string
+		want     []string
+	}{
+		{"h2.title", []string{"Vue 3.5", "Vite 6", "Footer"}},
+		{"#blog h2", []string{"Vue 3.5", "Vite 6"}},
+		{"ul.posts.featured > li > h2", []string{"Vue 3.5", "Vite 6"}},
+		{"main > h2", nil},
+		{`li[data-kind="release"] h2`, []string{"Vue 3.5"}},
+		{"li[data-kind|=news] h2, footer h2", []string{"Vite 6", "Footer"}},
+		{"li[data-kind] a[href^='https://']", []string{"Read"}},
+		{"a[href$=5]", []string{"Read"}},
+		{"a[rel~=bookmark]", []string{"Read"}},
+		{"h2 + a", []string{"Read"}},
+		{"h2 ~ a", []string{"Read", "Read"}},
+		{"li:first-child h2", []string{"Vue 3.5"}},
+		{"li:last-child > *", []string{"Untitled"}},
+		{"li > :last-of-type", []string{"Vue 3.5", "Read", "Vite 6", "skip", "Read", "Untitled"}},
+		{"li > a:first-of-type", []string{"Read", "Read"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.selector, func(t *testing.T) {
+			selector, err := compileSelector(tt.selector)
+			if err != nil {
+				t.Fatalf("compileSelector failed: %v", err)
+			}
+			var got []string
+			for _, node := range selector.selectAll(doc) {
+				got = append(got, selectedText(node))
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("selectAll(%q) = %q, want %q", tt.selector, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompileSelector_Errors(t *testing.T) {
+	for _, selector := range []string{"", "div[class", "a:hover", "div >", ".", "ul,", "a[href=x"} {
+		if _, err := compileSelector(selector); err == nil {
+			t.Errorf("Expected error for %q", selector)
+		}
+	}
+}
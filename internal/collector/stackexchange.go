@@ -0,0 +1,483 @@
+package collector
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	xhtml "golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// StackExchangeWrapper Stack Exchange API 的通用响应包装
+type StackExchangeWrapper struct {
+	Items          json.RawMessage `json:"items"`
+	HasMore        bool            `json:"has_more"`
+	QuotaMax       int             `json:"quota_max"`
+	QuotaRemaining int             `json:"quota_remaining"`
+	Backoff        int             `json:"backoff"` // 再次请求同一接口前需要等待的秒数
+	ErrorID        int             `json:"error_id"`
+	ErrorName      string          `json:"error_name"`
+	ErrorMessage   string          `json:"error_message"`
+}
+
+// StackExchangeOwner 问题或回答的作者
+type StackExchangeOwner struct {
+	DisplayName string `json:"display_name"`
+}
+
+// StackExchangeQuestion Stack Exchange 问题，正文需要使用 withbody 过滤器
+type StackExchangeQuestion struct {
+	QuestionID       int                `json:"question_id"`
+	Title            string             `json:"title"`
+	Body             string             `json:"body"`
+	Link             string             `json:"link"`
+	Tags             []string           `json:"tags"`
+	Score            int                `json:"score"`
+	AnswerCount      int                `json:"answer_count"`
+	ViewCount        int                `json:"view_count"`
+	IsAnswered       bool               `json:"is_answered"`
+	AcceptedAnswerID int                `json:"accepted_answer_id"`
+	CreationDate     int64              `json:"creation_date"`
+	Owner            StackExchangeOwner `json:"owner"`
+}
+
+// StackExchangeAnswer Stack Exchange 回答
+type StackExchangeAnswer struct {
+	AnswerID   int                `json:"answer_id"`
+	QuestionID int                `json:"question_id"`
+	Body       string             `json:"body"`
+	Score      int                `json:"score"`
+	IsAccepted bool               `json:"is_accepted"`
+	Owner      StackExchangeOwner `json:"owner"`
+}
+
+// throttlePattern 从 throttle_violation 错误信息中提取需要等待的秒数
+var throttlePattern = regexp.MustCompile(`available in (\d+) seconds`)
+
+// StackExchangeCollector Stack Exchange API 采集器，采集问题及其采纳的回答
+//
+// 遵守 API 的限流约定：响应带有 backoff 时，在等待时间内不再请求同一接口；
+// 每日配额用完后到 UTC 零点前不再发送请求。
+type StackExchangeCollector struct {
+	client *http.Client
+
+	mu             sync.Mutex
+	backoffUntil   map[string]time.Time // 接口 -> 可以再次请求的时间
+	throttledUntil time.Time            // 因请求过多被限流时，所有接口可以再次请求的时间
+	quotaResetAt   time.Time            // 配额用完时记录的恢复时间
+	now            func() time.Time
+}
+
+// NewStackExchangeCollector 创建Stack Exchange采集器
+func NewStackExchangeCollector() *StackExchangeCollector {
+	return &StackExchangeCollector{
+		client: &http.Client{
+			Timeout: 30 * time.Second,
+		},
+		backoffUntil: make(map[string]time.Time),
+		now:          time.Now,
+	}
+}
+
+// GetSourceType 返回采集器类型
+func (s *StackExchangeCollector) GetSourceType() string {
+	return "stackexchange"
+}
+
+// Validate 验证配置
+func (s *StackExchangeCollector) Validate(config CollectConfig) error {
+	if config.URL == "" {
+		return fmt.Errorf("URL is required")
+	}
+
+	parsedURL, err := url.Parse(config.URL)
+	if err != nil {
+		return fmt.Errorf("invalid URL: %w", err)
+	}
+
+	if parsedURL.Scheme != "http" && parsedURL.Scheme != "https" {
+		return fmt.Errorf("URL must use http or https scheme")
+	}
+
+	if parsedURL.Query().Get("site") == "" {
+		return fmt.Errorf("site parameter is required")
+	}
+
+	return nil
+}
+
+// Collect 采集问题，并为有采纳回答的问题补充回答内容
+func (s *StackExchangeCollector) Collect(ctx context.Context, config CollectConfig) (CollectResult, error) {
+	if err := s.Validate(config); err != nil {
+		return CollectResult{}, &ValidationError{Err: err}
+	}
+
+	// 设置超时
+	if config.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.Timeout)
+		defer cancel()
+	}
+
+	questionsURL, _ := url.Parse(config.URL)
+	var questions []StackExchangeQuestion
+	if err := s.fetch(ctx, questionsURL, config.Headers, &questions); err != nil {
+		return CollectResult{}, err
+	}
+
+	// 限制文章数量
+	if config.MaxArticles > 0 && len(questions) > config.MaxArticles {
+		questions = questions[:config.MaxArticles]
+	}
+
+	// 采纳的回答只是补充内容，获取失败时仍返回问题
+	answers, _ := s.fetchAcceptedAnswers(ctx, questionsURL, questions, config.Headers)
+
+	return CollectResult{
+		Articles: s.convertQuestions(questions, answers, config),
+		Source:   config.URL,
+	}, nil
+}
+
+// fetchAcceptedAnswers 一次请求获取所有问题的采纳回答，返回问题ID到回答的映射
+func (s *StackExchangeCollector) fetchAcceptedAnswers(ctx context.Context, questionsURL *url.URL, questions []StackExchangeQuestion, headers map[string]string) (map[int]StackExchangeAnswer, error) {
+	var ids []string
+	for _, question := range questions {
+		if question.AcceptedAnswerID != 0 {
+			ids = append(ids, strconv.Itoa(question.AcceptedAnswerID))
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	// 与问题使用同一API版本、站点和密钥
+	query := url.Values{}
+	query.Set("site", questionsURL.Query().Get("site"))
+	query.Set("filter", "withbody")
+	query.Set("pagesize", "100")
+	if key := questionsURL.Query().Get("key"); key != "" {
+		query.Set("key", key)
+	}
+	answersURL := &url.URL{
+		Scheme:   questionsURL.Scheme,
+		Host:     questionsURL.Host,
+		Path:     apiVersionPrefix(questionsURL.Path) + "/answers/" + strings.Join(ids, ";"),
+		RawQuery: query.Encode(),
+	}
+
+	var answers []StackExchangeAnswer
+	if err := s.fetch(ctx, answersURL, headers, &answers); err != nil {
+		return nil, err
+	}
+
+	byQuestion := make(map[int]StackExchangeAnswer, len(answers))
+	for _, answer := range answers {
+		byQuestion[answer.QuestionID] = answer
+	}
+	return byQuestion, nil
+}
+
+// fetch 请求API并将 items 解析到 items，处理错误响应、backoff 和配额
+func (s *StackExchangeCollector) fetch(ctx context.Context, apiURL *url.URL, headers map[string]string, items interface{}) error {
+	method := apiMethod(apiURL.Path)
+	if err := s.waitBackoff(ctx, method); err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, "GET", apiURL.String(), nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+
+	req.Header.Set("User-Agent", "API Collector/1.0")
+	req.Header.Set("Accept", "application/json")
+
+	// 设置自定义头部
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch Stack Exchange API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	var wrapper StackExchangeWrapper
+	if err := json.Unmarshal(body, &wrapper); err != nil {
+		s.recordLimits(method, StackExchangeWrapper{}, resp.Header)
+		if resp.StatusCode != http.StatusOK {
+			return newHTTPStatusError(resp)
+		}
+		return &ParseError{What: "Stack Exchange response", Err: err}
+	}
+
+	s.recordLimits(method, wrapper, resp.Header)
+
+	if wrapper.ErrorID != 0 {
+		apiErr := fmt.Errorf("Stack Exchange API error %d (%s): %s", wrapper.ErrorID, wrapper.ErrorName, wrapper.ErrorMessage)
+		if wrapper.ErrorName == "throttle_violation" {
+			s.mu.Lock()
+			wait := s.throttledUntil.Sub(s.now())
+			s.mu.Unlock()
+			return &RateLimitError{Message: apiErr.Error(), RetryAfter: max(wait, 0), Err: apiErr}
+		}
+		return apiErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		return newHTTPStatusError(resp)
+	}
+
+	if err := json.Unmarshal(wrapper.Items, items); err != nil {
+		return &ParseError{What: "Stack Exchange items", Err: err}
+	}
+	return nil
+}
+
+// waitBackoff 在接口的 backoff 时间内等待，ctx 的截止时间早于等待结束时直接返回错误
+func (s *StackExchangeCollector) waitBackoff(ctx context.Context, method string) error {
+	s.mu.Lock()
+	now := s.now()
+	quotaResetAt := s.quotaResetAt
+	until := s.backoffUntil[method]
+	if s.throttledUntil.After(until) {
+		until = s.throttledUntil
+	}
+	s.mu.Unlock()
+
+	if now.Before(quotaResetAt) {
+		return &RateLimitError{
+			Message:    fmt.Sprintf("Stack Exchange API quota exhausted until %s", quotaResetAt.Format(time.RFC3339)),
+			RetryAfter: quotaResetAt.Sub(now),
+		}
+	}
+
+	wait := until.Sub(now)
+	if wait <= 0 {
+		return nil
+	}
+	if deadline, ok := ctx.Deadline(); ok && deadline.Before(until) {
+		return &RateLimitError{
+			Message:    fmt.Sprintf("Stack Exchange API backoff for %s in effect for another %s", method, wait.Round(time.Second)),
+			RetryAfter: wait,
+		}
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// recordLimits 记录响应中的 backoff 和剩余配额
+//
+// backoff 只对同一接口生效；throttle_violation 错误和 Retry-After 头则对所有接口生效。
+func (s *StackExchangeCollector) recordLimits(method string, wrapper StackExchangeWrapper, header http.Header) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	if wrapper.Backoff > 0 {
+		s.backoffUntil[method] = now.Add(time.Duration(wrapper.Backoff) * time.Second)
+	}
+
+	var throttle time.Duration
+	if match := throttlePattern.FindStringSubmatch(wrapper.ErrorMessage); match != nil {
+		seconds, _ := strconv.Atoi(match[1])
+		throttle = time.Duration(seconds) * time.Second
+	}
+	if seconds, err := strconv.Atoi(header.Get("Retry-After")); err == nil && time.Duration(seconds)*time.Second > throttle {
+		throttle = time.Duration(seconds) * time.Second
+	}
+	if throttle > 0 {
+		s.throttledUntil = now.Add(throttle)
+	}
+
+	// 配额按天计算，用完后等到下一个UTC零点
+	if wrapper.QuotaMax > 0 && wrapper.QuotaRemaining <= 0 {
+		s.quotaResetAt = now.UTC().Truncate(24 * time.Hour).Add(24 * time.Hour)
+	}
+}
+
+// apiMethod 返回用于 backoff 的接口名称，去掉版本号和ID，例如 /2.3/answers/1;2 -> answers
+func apiMethod(path string) string {
+	var parts []string
+	for _, part := range strings.Split(strings.Trim(path, "/"), "/") {
+		if part != "" && (part[0] < '0' || part[0] > '9') {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// apiVersionPrefix 返回路径中的API版本前缀，例如 /2.3/search/advanced -> /2.3
+func apiVersionPrefix(path string) string {
+	version, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	return "/" + version
+}
+
+// convertQuestions 转换问题为文章，采纳的回答追加在问题正文之后
+func (s *StackExchangeCollector) convertQuestions(questions []StackExchangeQuestion, answers map[int]StackExchangeAnswer, config CollectConfig) []Article {
+	articles := make([]Article, 0, len(questions))
+	api := &APICollector{}
+	site := ""
+	if parsedURL, err := url.Parse(config.URL); err == nil {
+		site = parsedURL.Query().Get("site")
+	}
+
+	for _, question := range questions {
+		body := bodyToMarkdown(question.Body)
+
+		article := Article{
+			ID:          strconv.Itoa(question.QuestionID),
+			Title:       html.UnescapeString(question.Title),
+			Content:     body,
+			Summary:     api.extractSummary(body, 200),
+			Author:      html.UnescapeString(question.Owner.DisplayName),
+			URL:         question.Link,
+			PublishedAt: time.Unix(question.CreationDate, 0).UTC(),
+			Source:      config.URL,
+			SourceType:  s.GetSourceType(),
+			Language:    config.Language,
+			Tags:        append(append([]string(nil), question.Tags...), config.Tags...),
+			Metadata:    make(map[string]string),
+		}
+
+		// 添加元数据
+		article.Metadata["stackexchange_question"] = "true"
+		article.Metadata["site"] = site
+		article.Metadata["score"] = strconv.Itoa(question.Score)
+		article.Metadata["answer_count"] = strconv.Itoa(question.AnswerCount)
+		article.Metadata["view_count"] = strconv.Itoa(question.ViewCount)
+		article.Metadata["is_answered"] = strconv.FormatBool(question.IsAnswered)
+		article.Metadata["has_accepted_answer"] = strconv.FormatBool(question.AcceptedAnswerID != 0)
+
+		if answer, exists := answers[question.QuestionID]; exists {
+			article.Content += "\n\n## Accepted answer\n\n" + bodyToMarkdown(answer.Body)
+			article.Metadata["accepted_answer_id"] = strconv.Itoa(answer.AnswerID)
+			article.Metadata["accepted_answer_score"] = strconv.Itoa(answer.Score)
+			article.Metadata["accepted_answer_author"] = html.UnescapeString(answer.Owner.DisplayName)
+		}
+
+		if config.Metadata != nil {
+			for k, v := range config.Metadata {
+				article.Metadata[k] = v
+			}
+		}
+
+		articles = append(articles, article)
+	}
+
+	return articles
+}
+
+// bodyToMarkdown 将问题和回答的HTML正文转换为文本，<pre><code> 转换为带语言标记的 ``` 代码块
+func bodyToMarkdown(body string) string {
+	nodes, err := xhtml.ParseFragment(strings.NewReader(body), &xhtml.Node{
+		Type:     xhtml.ElementNode,
+		DataAtom: atom.Body,
+		Data:     "body",
+	})
+	if err != nil {
+		return body
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		writeMarkdown(&b, node)
+	}
+	return strings.TrimSpace(blankLines.ReplaceAllString(b.String(), "\n\n"))
+}
+
+// blankLines 连续的空行
+var blankLines = regexp.MustCompile(`\n\s*\n(\s*\n)+`)
+
+func writeMarkdown(b *strings.Builder, node *xhtml.Node) {
+	switch node.Type {
+	case xhtml.TextNode:
+		b.WriteString(node.Data)
+		return
+	case xhtml.ElementNode:
+	default:
+		return
+	}
+
+	switch node.DataAtom {
+	case atom.Pre:
+		fmt.Fprintf(b, "\n\n```%s\n%s\n```\n\n", codeLanguage(node), strings.Trim(nodeText(node), "\n"))
+		return
+	case atom.Code:
+		b.WriteString("`" + nodeText(node) + "`")
+		return
+	case atom.Br:
+		b.WriteString("\n")
+		return
+	case atom.Li:
+		b.WriteString("\n- ")
+	}
+
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeMarkdown(b, child)
+	}
+
+	switch node.DataAtom {
+	case atom.P, atom.Div, atom.Blockquote, atom.Ul, atom.Ol, atom.Table,
+		atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		b.WriteString("\n\n")
+	}
+}
+
+// nodeText 返回节点内的全部文本
+func nodeText(node *xhtml.Node) string {
+	if node.Type == xhtml.TextNode {
+		return node.Data
+	}
+	var b strings.Builder
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		b.WriteString(nodeText(child))
+	}
+	return b.String()
+}
+
+// codeLanguage 从 <pre> 或其中 <code> 的 lang-xxx、language-xxx 类名读取代码语言
+func codeLanguage(pre *xhtml.Node) string {
+	nodes := []*xhtml.Node{pre}
+	if pre.FirstChild != nil && pre.FirstChild.DataAtom == atom.Code {
+		nodes = append(nodes, pre.FirstChild)
+	}
+	for _, node := range nodes {
+		for _, attr := range node.Attr {
+			if attr.Key != "class" {
+				continue
+			}
+			for _, class := range strings.Fields(attr.Val) {
+				for _, prefix := range []string{"lang-", "language-"} {
+					if language, ok := strings.CutPrefix(class, prefix); ok && language != "none" {
+						return language
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,184 @@
+package collector
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+const stackExchangeQuestionsResponse = `{
+	"items": [
+		{
+			"question_id": 101, "title": "How to debounce in React &amp; hooks?",
+			"body": "<p>My handler fires on every key:</p>\n\n<pre class=\"lang-js s-code-block\"><code>input.addEventListener(&#39;input&#39;, search)\n</code></pre>\n",
+			"link": "https://stackoverflow.com/questions/101/how-to-debounce",
+			"tags": ["reactjs", "debounce"], "score": 57, "answer_count": 3, "view_count": 9000,
+			"is_answered": true, "accepted_answer_id": 201, "creation_date": 1700000000,
+			"owner": {"display_name": "Al&#233;"}
+		},
+		{
+			"question_id": 102, "title": "Unanswered question", "body": "<p>Anyone?</p>",
+			"link": "https://stackoverflow.com/questions/102/unanswered",
+			"tags": ["javascript"], "score": 0, "answer_count": 0,
+			"is_answered": false, "creation_date": 1700000100,
+			"owner": {"display_name": "bob"}
+		}
+	],
+	"has_more": false, "quota_max": 300, "quota_remaining": 298
+}`
+
+const stackExchangeAnswersResponse = `{
+	"items": [
+		{
+			"answer_id": 201, "question_id": 101, "score": 80, "is_accepted": true,
+			"body": "<p>Use a <code>useMemo</code> wrapped debounce:</p>\n<pre><code class=\"language-jsx\">const debounced = useMemo(() =&gt; debounce(search, 300), [])\n</code></pre>",
+			"owner": {"display_name": "carol"}
+		}
+	],
+	"has_more": false, "quota_max": 300, "quota_remaining": 297
+}`
+
+func TestStackExchangeCollector_Collect(t *testing.T) {
+	var answersPath, answersQuery string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/2.3/search/advanced":
+			w.Write([]byte(stackExchangeQuestionsResponse))
+		case strings.HasPrefix(r.URL.Path, "/2.3/answers/"):
+			answersPath, answersQuery = r.URL.Path, r.URL.RawQuery
+			w.Write([]byte(stackExchangeAnswersResponse))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	collector := NewStackExchangeCollector()
+	result, err := collector.Collect(context.Background(), CollectConfig{
+		URL: server.URL + "/2.3/search/advanced?q=debounce&site=stackoverflow&filter=withbody&key=secret",
+	})
+	if err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+
+	if answersPath != "/2.3/answers/201" {
+		t.Errorf("Expected accepted answers to be fetched by ID, got %s", answersPath)
+	}
+	if !strings.Contains(answersQuery, "site=stackoverflow") || !strings.Contains(answersQuery, "key=secret") || !strings.Contains(answersQuery, "filter=withbody") {
+		t.Errorf("Expected site, key and body filter on answers request, got %s", answersQuery)
+	}
+
+	if len(result.Articles) != 2 {
+		t.Fatalf("Expected 2 questions, got %d", len(result.Articles))
+	}
+
+	question := result.Articles[0]
+	if question.Title != "How to debounce in React & hooks?" || question.Author != "Alé" {
+		t.Errorf("Expected unescaped title and author, got %q by %q", question.Title, question.Author)
+	}
+	if !strings.Contains(question.Content, "```js\ninput.addEventListener('input', search)\n```") {
+		t.Errorf("Expected question code as js fence, got %q", question.Content)
+	}
+	if !strings.Contains(question.Content, "## Accepted answer") || !strings.Contains(question.Content, "```jsx\nconst debounced = useMemo(() => debounce(search, 300), [])\n```") {
+		t.Errorf("Expected accepted answer with jsx fence, got %q", question.Content)
+	}
+	if !strings.Contains(question.Content, "Use a `useMemo` wrapped debounce:") {
+		t.Errorf("Expected inline code as backticks, got %q", question.Content)
+	}
+	if question.Metadata["score"] != "57" || question.Metadata["answer_count"] != "3" || question.Metadata["is_answered"] != "true" {
+		t.Errorf("Expected score, answer count and answered state, got %v", question.Metadata)
+	}
+	if question.Metadata["accepted_answer_id"] != "201" || question.Metadata["accepted_answer_author"] != "carol" {
+		t.Errorf("Expected accepted answer metadata, got %v", question.Metadata)
+	}
+	if question.Metadata["site"] != "stackoverflow" || len(question.Tags) != 2 || question.Tags[0] != "reactjs" {
+		t.Errorf("Expected site and tags, got %v %v", question.Metadata, question.Tags)
+	}
+
+	unanswered := result.Articles[1]
+	if unanswered.Metadata["has_accepted_answer"] != "false" || strings.Contains(unanswered.Content, "Accepted answer") {
+		t.Errorf("Expected question without accepted answer, got %v %q", unanswered.Metadata, unanswered.Content)
+	}
+}
+
+func TestStackExchangeCollector_Backoff(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.Write([]byte(`{"items": [], "quota_max": 300, "quota_remaining": 200, "backoff": 10}`))
+	}))
+	defer server.Close()
+
+	collector := NewStackExchangeCollector()
+	now := time.Now()
+	collector.now = func() time.Time { return now }
+	config := CollectConfig{URL: server.URL + "/2.3/search/advanced?q=css&site=stackoverflow"}
+
+	if _, err := collector.Collect(context.Background(), config); err != nil {
+		t.Fatalf("First collect failed: %v", err)
+	}
+
+	// backoff 内且截止时间早于等待结束：不发送请求
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := collector.Collect(ctx, config); err == nil || !strings.Contains(err.Error(), "backoff") {
+		t.Errorf("Expected backoff error, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected no request during backoff, got %d requests", got)
+	}
+
+	// 其他接口不受影响
+	other := CollectConfig{URL: server.URL + "/2.3/questions?site=stackoverflow"}
+	if _, err := collector.Collect(ctx, other); err != nil {
+		t.Errorf("Expected other method to be allowed, got %v", err)
+	}
+
+	now = now.Add(11 * time.Second)
+	if _, err := collector.Collect(context.Background(), config); err != nil {
+		t.Errorf("Expected request after backoff, got %v", err)
+	}
+}
+
+func TestStackExchangeCollector_QuotaAndErrors(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		w.Write([]byte(`{"error_id": 502, "error_name": "throttle_violation", "error_message": "too many requests from this IP, more requests available in 60 seconds", "quota_max": 300, "quota_remaining": 0}`))
+	}))
+	defer server.Close()
+
+	collector := NewStackExchangeCollector()
+	now := time.Date(2024, 3, 4, 12, 0, 0, 0, time.UTC)
+	collector.now = func() time.Time { return now }
+	config := CollectConfig{URL: server.URL + "/2.3/search/advanced?q=css&site=stackoverflow"}
+
+	_, err := collector.Collect(context.Background(), config)
+	if err == nil || !strings.Contains(err.Error(), "throttle_violation") {
+		t.Fatalf("Expected API error, got %v", err)
+	}
+
+	now = now.Add(time.Hour)
+	_, err = collector.Collect(context.Background(), config)
+	if err == nil || !strings.Contains(err.Error(), "quota exhausted until 2024-03-05T00:00:00Z") {
+		t.Errorf("Expected quota error until UTC midnight, got %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 1 {
+		t.Errorf("Expected no request once quota is exhausted, got %d requests", got)
+	}
+}
+
+func TestStackExchangeCollector_Validate(t *testing.T) {
+	collector := NewStackExchangeCollector()
+	if err := collector.Validate(CollectConfig{URL: "https://api.stackexchange.com/2.3/search?q=css"}); err == nil {
+		t.Error("Expected error without site parameter")
+	}
+	if err := collector.Validate(CollectConfig{URL: "https://api.stackexchange.com/2.3/search?q=css&site=stackoverflow"}); err != nil {
+		t.Errorf("Expected valid config, got %v", err)
+	}
+}
@@ -0,0 +1,797 @@
+// Package config 负责加载服务器配置文件（YAML/TOML/JSON），支持环境变量覆盖和校验
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+	"github.com/ZephyrDeng/dev-context/internal/formatter"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/mcp"
+	"github.com/ZephyrDeng/dev-context/internal/processor"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+	"github.com/ZephyrDeng/dev-context/internal/tools"
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
+)
+
+// EnvPrefix 环境变量覆盖前缀，例如 DEVCONTEXT_CACHE_TTL=30m 覆盖 cache.ttl
+const EnvPrefix = "DEVCONTEXT"
+
+// Config 服务器完整配置
+type Config struct {
+	Server     ServerConfig     `json:"server"`
+	Cache      CacheConfig      `json:"cache"`
+	Processor  ProcessorConfig  `json:"processor"`
+	Formatter  FormatterConfig  `json:"formatter"`
+	Tools      ToolsConfig      `json:"tools"`
+	Monitoring MonitoringConfig `json:"monitoring"`
+	Tracing    TracingConfig    `json:"tracing"`
+	Sources    []SourceConfig   `json:"sources"`
+}
+
+// ServerConfig MCP服务器配置
+type ServerConfig struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	LogLevel    string `json:"log_level"`  // debug, info, warn, error
+	LogFormat   string `json:"log_format"` // text, json
+	Transport   string `json:"transport"`  // stdio, http, websocket
+	Addr        string `json:"addr"`       // http/websocket 监听地址
+
+	// ReloadInterval 配置文件轮询间隔，0 表示只在收到 SIGHUP 时重新加载
+	ReloadInterval Duration `json:"reload_interval"`
+
+	WebSocket WebSocketConfig `json:"websocket"` // websocket 传输配置
+	Admin     AdminConfig     `json:"admin"`     // 管理端点配置
+}
+
+// AdminConfig 管理端点配置
+//
+// 管理端点提供缓存监控、工具健康检查和数据源采集状态，与 MCP 传输使用不同的监听地址。
+type AdminConfig struct {
+	Addr  string `json:"addr"`  // 监听地址，为空时不启动
+	Token string `json:"token"` // 调用 /clear、/gc 需要的令牌，为空时禁止调用
+}
+
+// WebSocketConfig WebSocket传输配置
+type WebSocketConfig struct {
+	MaxMessageSize ByteSize `json:"max_message_size"` // 单条消息的最大大小
+	PingInterval   Duration `json:"ping_interval"`    // 心跳间隔，0 表示关闭
+	AllowedOrigins []string `json:"allowed_origins"`  // 除同源外允许连接的浏览器来源，"*" 表示全部
+}
+
+// CacheConfig 缓存配置
+type CacheConfig struct {
+	Backend         string            `json:"backend"`          // 存储后端：memory、disk、redis
+	Dir             string            `json:"dir"`              // disk 后端的缓存目录，为空时使用用户缓存目录
+	Redis           RedisConfig       `json:"redis"`            // redis 后端的连接配置
+	MaxSize         ByteSize          `json:"max_size"`         // 最大缓存大小，支持 "512MB" 写法
+	TTL             Duration          `json:"ttl"`              // 缓存生存时间
+	CleanupInterval Duration          `json:"cleanup_interval"` // 清理间隔
+	Coalescing      CoalescingConfig  `json:"coalescing"`       // 查询合并配置
+	Concurrency     ConcurrencyConfig `json:"concurrency"`      // 并发控制配置
+}
+
+// RedisConfig Redis连接配置
+type RedisConfig struct {
+	Addr        string   `json:"addr"`
+	Password    string   `json:"password"`
+	DB          int      `json:"db"`
+	KeyPrefix   string   `json:"key_prefix"`
+	PoolSize    int      `json:"pool_size"`
+	DialTimeout Duration `json:"dial_timeout"`
+	IOTimeout   Duration `json:"io_timeout"`
+}
+
+// CoalescingConfig 查询合并配置
+type CoalescingConfig struct {
+	Timeout      Duration `json:"timeout"`
+	CleanupDelay Duration `json:"cleanup_delay"`
+
+	// Distributed 通过Redis锁在多个实例之间合并查询，需要 redis 后端
+	Distributed  bool     `json:"distributed"`
+	LockPrefixes []string `json:"lock_prefixes"` // 跨实例加锁的缓存键前缀，为空表示全部
+	LockTTL      Duration `json:"lock_ttl"`      // 锁的过期时间，应大于一次采集的耗时
+}
+
+// ConcurrencyConfig 缓存并发控制配置
+type ConcurrencyConfig struct {
+	MaxConcurrency int      `json:"max_concurrency"`
+	WorkerPoolSize int      `json:"worker_pool_size"`
+	QueueSize      int      `json:"queue_size"`
+	RateLimit      Duration `json:"rate_limit"`
+	BurstLimit     int      `json:"burst_limit"`
+}
+
+// ProcessorConfig 数据处理配置
+type ProcessorConfig struct {
+	EnableSummarization bool     `json:"enable_summarization"`
+	EnableSorting       bool     `json:"enable_sorting"`
+	MaxSummaryLength    int      `json:"max_summary_length"`
+	ProcessingTimeout   Duration `json:"processing_timeout"`
+	MaxConcurrency      int      `json:"max_concurrency"`
+
+	// Enrichment 抓取原文补全过短的文章内容
+	Enrichment EnrichmentConfig `json:"enrichment"`
+}
+
+// EnrichmentConfig 文章原文补全配置
+type EnrichmentConfig struct {
+	Enabled          bool     `json:"enabled"`
+	MinContentLength int      `json:"min_content_length"` // 内容短于该长度的文章才会抓取原文
+	MaxConcurrency   int      `json:"max_concurrency"`
+	DomainDelay      Duration `json:"domain_delay"` // 同一域名两次请求之间的最小间隔
+	Timeout          Duration `json:"timeout"`      // 单个页面的抓取超时
+	CacheTTL         Duration `json:"cache_ttl"`    // 已抓取页面的缓存时间
+	CacheSize        ByteSize `json:"cache_size"`   // 页面缓存的最大大小
+	MaxPageSize      ByteSize `json:"max_page_size"`
+}
+
+// FormatterConfig 输出格式化配置
+type FormatterConfig struct {
+	Format           string `json:"format"` // json, markdown, text
+	Indent           string `json:"indent"`
+	DateFormat       string `json:"date_format"`
+	IncludeMetadata  bool   `json:"include_metadata"`
+	IncludeContent   bool   `json:"include_content"`
+	MaxSummaryLength int    `json:"max_summary_length"`
+	SortBy           string `json:"sort_by"`
+	SortOrder        string `json:"sort_order"`
+	EnableLinks      bool   `json:"enable_links"`
+	CompactOutput    bool   `json:"compact_output"`
+}
+
+// ToolsConfig MCP工具配置
+type ToolsConfig struct {
+	MaxConcurrency int           `json:"max_concurrency"` // 工具调用最大并发数
+	BuiltinSources bool          `json:"builtin_sources"` // 是否保留内置数据源，关闭后只使用 sources 中的配置
+	CacheTTL       ToolTTLConfig `json:"cache_ttl"`       // 各工具结果的缓存时间
+	StaleTTL       ToolTTLConfig `json:"stale_ttl"`       // 超过缓存时间后仍返回旧结果并在后台刷新的时长，0 表示关闭
+	Refresh        RefreshConfig `json:"refresh"`         // 定时刷新常用查询
+}
+
+// RefreshConfig 定时刷新配置，在常用查询的缓存到期前重新采集
+type RefreshConfig struct {
+	Enabled            bool     `json:"enabled"`
+	Ahead              Duration `json:"ahead"`                // 在工具缓存时间到期前多久刷新
+	Jitter             float64  `json:"jitter"`               // 刷新时间的随机浮动比例，0 到 1
+	MaxBackoff         Duration `json:"max_backoff"`          // 采集失败后重试间隔的上限
+	ActiveHours        string   `json:"active_hours"`         // 每天刷新的时段，例如 "08:00-20:00"，为空表示全天
+	WeeklyNews         []string `json:"weekly_news"`          // 刷新的周报分类，"" 表示不限分类
+	TrendingLanguages  []string `json:"trending_languages"`   // 刷新的热门仓库语言，"" 表示不限语言
+	TrendingTimeRanges []string `json:"trending_time_ranges"` // 刷新的热门仓库时间范围：daily、weekly、monthly
+}
+
+// ToolTTLConfig 各工具结果的缓存时间
+type ToolTTLConfig struct {
+	WeeklyNews    Duration `json:"weekly_news"`
+	TopicSearch   Duration `json:"topic_search"`
+	TrendingRepos Duration `json:"trending_repos"`
+}
+
+// MonitoringConfig 缓存监控配置
+type MonitoringConfig struct {
+	Enabled             bool                  `json:"enabled"` // 是否启动缓存监控，告警写入日志
+	MetricsInterval     Duration              `json:"metrics_interval"`
+	HealthCheckInterval Duration              `json:"health_check_interval"`
+	AlertThresholds     AlertThresholdsConfig `json:"alert_thresholds"`
+	Alerts              AlertsConfig          `json:"alerts"` // 告警发送目标，为空时告警只写入日志
+}
+
+// AlertsConfig 告警发送配置
+type AlertsConfig struct {
+	DedupWindow Duration          `json:"dedup_window"` // 同类型同级别的告警在该时间内只发送一次，0 表示不去重
+	RateLimit   int               `json:"rate_limit"`   // 每种告警在 rate_period 内最多发送次数，0 表示不限
+	RatePeriod  Duration          `json:"rate_period"`
+	Timeout     Duration          `json:"timeout"` // 单次发送超时
+	Sinks       []AlertSinkConfig `json:"sinks"`
+}
+
+// AlertSinkConfig 告警发送目标配置
+type AlertSinkConfig struct {
+	Type    string            `json:"type"`    // webhook、slack、file、syslog
+	URL     string            `json:"url"`     // webhook、slack 的地址
+	Headers map[string]string `json:"headers"` // webhook 请求附加的头
+	Path    string            `json:"path"`    // file 的文件路径
+	Tag     string            `json:"tag"`     // syslog 的标签
+}
+
+// AlertThresholdsConfig 告警阈值配置
+type AlertThresholdsConfig struct {
+	LowHitRatePercent      float64  `json:"low_hit_rate_percent"`
+	HighMemoryUsagePercent float64  `json:"high_memory_usage_percent"`
+	MaxResponseTimeMs      float64  `json:"max_response_time_ms"`
+	MaxErrorCount          int64    `json:"max_error_count"`
+	MaxSlowOperations      int64    `json:"max_slow_operations"`
+	HealthCheckTimeout     Duration `json:"health_check_timeout"`
+}
+
+// TracingConfig 链路追踪配置
+type TracingConfig struct {
+	Exporter    string            `json:"exporter"`     // none、otlp
+	Endpoint    string            `json:"endpoint"`     // OTLP/HTTP 地址（host:port），为空时使用 OTEL_EXPORTER_OTLP_ENDPOINT
+	Insecure    bool              `json:"insecure"`     // 使用 HTTP 而非 HTTPS 连接 endpoint
+	Headers     map[string]string `json:"headers"`      // 导出请求附加的头
+	SampleRatio float64           `json:"sample_ratio"` // 根 span 的采样比例（0-1）
+}
+
+// SourceConfig 数据源配置
+//
+// 名称与内置数据源相同时只覆盖设置了的字段，例如只写 name 和 enabled: false 即可禁用内置数据源。
+type SourceConfig struct {
+	Name        string                  `json:"name"`
+	Tools       []string                `json:"tools"` // 使用该数据源的工具：weekly_news、topic_search、trending_repos
+	URL         string                  `json:"url"`   // 支持 {query}、{language}、{tag}、{time_range}、{category}、{since}、{since_unix} 等模板变量
+	Type        string                  `json:"type"`  // 采集器类型：rss、api、html、reddit、stackexchange、hackernews、github_releases、npm
+	Platform    string                  `json:"platform"`
+	Kind        string                  `json:"kind"` // 结果类型：articles、repositories、discussions
+	Categories  []string                `json:"categories"`
+	Weight      *float64                `json:"weight"`
+	Headers     map[string]string       `json:"headers"`
+	Timeout     Duration                `json:"timeout"`
+	MaxArticles int                     `json:"max_articles"`
+	Tags        []string                `json:"tags"`
+	Metadata    map[string]string       `json:"metadata"`
+	Selector    *collector.HTMLSelector `json:"selector"` // html 采集器的CSS选择器：item、title、content、summary、author、published_at、tags、links
+	Enabled     *bool                   `json:"enabled"`  // 未设置时默认启用
+}
+
+// apply 将配置覆盖到数据源定义上
+func (s SourceConfig) apply(base sources.Source) sources.Source {
+	source := base
+	source.Name = s.Name
+	if len(s.Tools) > 0 {
+		source.Tools = append([]string(nil), s.Tools...)
+	}
+	if s.URL != "" {
+		source.Config.URL = s.URL
+	}
+	if s.Platform != "" {
+		source.Platform = s.Platform
+	}
+	if s.Kind != "" {
+		source.Kind = s.Kind
+	}
+	if len(s.Categories) > 0 {
+		source.Categories = append([]string(nil), s.Categories...)
+	}
+	if s.Weight != nil {
+		source.Weight = *s.Weight
+	}
+	if s.Enabled != nil {
+		source.Enabled = *s.Enabled
+	}
+	if s.Timeout > 0 {
+		source.Config.Timeout = s.Timeout.Duration()
+	}
+	if s.MaxArticles > 0 {
+		source.Config.MaxArticles = s.MaxArticles
+	}
+	if len(s.Tags) > 0 {
+		source.Config.Tags = append([]string(nil), s.Tags...)
+	}
+	if s.Selector != nil {
+		selector := *s.Selector
+		source.Config.Selector = &selector
+	}
+
+	source.Config.Headers = mergeStringMaps(base.Config.Headers, s.Headers)
+	metadata := s.Metadata
+	if s.Type != "" {
+		metadata = mergeStringMaps(metadata, map[string]string{"source_type": s.Type})
+	}
+	source.Config.Metadata = mergeStringMaps(base.Config.Metadata, metadata)
+	return source
+}
+
+// mergeStringMaps 合并两个映射，后者优先，返回新映射
+func mergeStringMaps(base, override map[string]string) map[string]string {
+	if len(base) == 0 && len(override) == 0 {
+		return nil
+	}
+	merged := make(map[string]string, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+// Default 返回默认配置，与各组件的默认配置保持一致
+func Default() *Config {
+	cacheDefaults := cache.DefaultCacheConfig()
+	redisDefaults := cache.DefaultRedisConfig()
+	processorDefaults := processor.DefaultConfig()
+	formatterDefaults := formatter.DefaultConfig()
+	monitoringDefaults := cache.DefaultMonitoringConfig()
+	alertingDefaults := cache.DefaultAlertingConfig()
+	tracingDefaults := tracing.DefaultConfig()
+	thresholdDefaults := monitoringDefaults.AlertThresholds
+	toolTTLDefaults := tools.DefaultCacheTTLs()
+	staleTTLDefaults := tools.DefaultStaleTTLs()
+	refreshDefaults := tools.DefaultRefreshConfig()
+	mcpDefaults := mcp.DefaultConfig()
+	wsDefaults := mcp.DefaultWebSocketConfig()
+
+	return &Config{
+		Server: ServerConfig{
+			Name:        mcpDefaults.Name,
+			Description: mcpDefaults.Description,
+			LogLevel:    "info",
+			LogFormat:   logging.FormatText,
+			Transport:   "stdio",
+			Addr:        ":8080",
+
+			ReloadInterval: Duration(5 * time.Second),
+			WebSocket: WebSocketConfig{
+				MaxMessageSize: ByteSize(wsDefaults.MaxMessageSize),
+				PingInterval:   Duration(wsDefaults.PingInterval),
+			},
+		},
+		Cache: CacheConfig{
+			Backend:         "memory",
+			MaxSize:         ByteSize(cacheDefaults.MaxSize),
+			TTL:             Duration(cacheDefaults.TTL),
+			CleanupInterval: Duration(cacheDefaults.CleanupInterval),
+			Redis: RedisConfig{
+				Addr:        redisDefaults.Addr,
+				KeyPrefix:   redisDefaults.KeyPrefix,
+				PoolSize:    redisDefaults.PoolSize,
+				DialTimeout: Duration(redisDefaults.DialTimeout),
+				IOTimeout:   Duration(redisDefaults.IOTimeout),
+			},
+			Coalescing: CoalescingConfig{
+				Timeout:      Duration(cacheDefaults.CoalescingConfig.Timeout),
+				CleanupDelay: Duration(cacheDefaults.CoalescingConfig.CleanupDelay),
+				LockPrefixes: []string{sources.ToolWeeklyNews + ":"},
+				LockTTL:      Duration(cacheDefaults.CoalescingConfig.LockTTL),
+			},
+			Concurrency: ConcurrencyConfig{
+				MaxConcurrency: cacheDefaults.ConcurrencyConfig.MaxConcurrency,
+				WorkerPoolSize: cacheDefaults.ConcurrencyConfig.WorkerPoolSize,
+				QueueSize:      cacheDefaults.ConcurrencyConfig.QueueSize,
+				RateLimit:      Duration(cacheDefaults.ConcurrencyConfig.RateLimit),
+				BurstLimit:     cacheDefaults.ConcurrencyConfig.BurstLimit,
+			},
+		},
+		Processor: ProcessorConfig{
+			EnableSummarization: processorDefaults.EnableSummarization,
+			EnableSorting:       processorDefaults.EnableSorting,
+			MaxSummaryLength:    processorDefaults.MaxSummaryLength,
+			ProcessingTimeout:   Duration(processorDefaults.ProcessingTimeout),
+			MaxConcurrency:      processorDefaults.MaxConcurrency,
+			Enrichment: EnrichmentConfig{
+				Enabled:          processorDefaults.Enrichment.Enabled,
+				MinContentLength: processorDefaults.Enrichment.MinContentLength,
+				MaxConcurrency:   processorDefaults.Enrichment.MaxConcurrency,
+				DomainDelay:      Duration(processorDefaults.Enrichment.DomainDelay),
+				Timeout:          Duration(processorDefaults.Enrichment.Timeout),
+				CacheTTL:         Duration(processorDefaults.Enrichment.CacheTTL),
+				CacheSize:        ByteSize(processorDefaults.Enrichment.CacheSize),
+				MaxPageSize:      ByteSize(processorDefaults.Enrichment.MaxPageSize),
+			},
+		},
+		Formatter: FormatterConfig{
+			Format:           string(formatterDefaults.Format),
+			Indent:           formatterDefaults.Indent,
+			DateFormat:       formatterDefaults.DateFormat,
+			IncludeMetadata:  true, // 工具的 markdown/text 输出默认附带来源、标签等元数据
+			IncludeContent:   formatterDefaults.IncludeContent,
+			MaxSummaryLength: formatterDefaults.MaxSummaryLength,
+			SortBy:           formatterDefaults.SortBy,
+			SortOrder:        formatterDefaults.SortOrder,
+			EnableLinks:      formatterDefaults.EnableLinks,
+			CompactOutput:    formatterDefaults.CompactOutput,
+		},
+		Tools: ToolsConfig{
+			MaxConcurrency: 10,
+			BuiltinSources: true,
+			CacheTTL: ToolTTLConfig{
+				WeeklyNews:    Duration(toolTTLDefaults[sources.ToolWeeklyNews]),
+				TopicSearch:   Duration(toolTTLDefaults[sources.ToolTopicSearch]),
+				TrendingRepos: Duration(toolTTLDefaults[sources.ToolTrendingRepos]),
+			},
+			StaleTTL: ToolTTLConfig{
+				WeeklyNews:    Duration(staleTTLDefaults[sources.ToolWeeklyNews]),
+				TopicSearch:   Duration(staleTTLDefaults[sources.ToolTopicSearch]),
+				TrendingRepos: Duration(staleTTLDefaults[sources.ToolTrendingRepos]),
+			},
+			Refresh: RefreshConfig{
+				Enabled:            true,
+				Ahead:              Duration(refreshDefaults.Ahead),
+				Jitter:             refreshDefaults.Jitter,
+				MaxBackoff:         Duration(refreshDefaults.MaxBackoff),
+				WeeklyNews:         refreshDefaults.WeeklyNewsCategories,
+				TrendingLanguages:  refreshDefaults.TrendingLanguages,
+				TrendingTimeRanges: refreshDefaults.TrendingTimeRanges,
+			},
+		},
+		Monitoring: MonitoringConfig{
+			Enabled:             false,
+			MetricsInterval:     Duration(monitoringDefaults.MetricsInterval),
+			HealthCheckInterval: Duration(monitoringDefaults.HealthCheckInterval),
+			AlertThresholds: AlertThresholdsConfig{
+				LowHitRatePercent:      thresholdDefaults.LowHitRatePercent,
+				HighMemoryUsagePercent: thresholdDefaults.HighMemoryUsagePercent,
+				MaxResponseTimeMs:      thresholdDefaults.MaxResponseTimeMs,
+				MaxErrorCount:          thresholdDefaults.MaxErrorCount,
+				MaxSlowOperations:      thresholdDefaults.MaxSlowOperations,
+				HealthCheckTimeout:     Duration(thresholdDefaults.HealthCheckTimeout),
+			},
+			Alerts: AlertsConfig{
+				DedupWindow: Duration(alertingDefaults.DedupWindow),
+				RateLimit:   alertingDefaults.RateLimit,
+				RatePeriod:  Duration(alertingDefaults.RatePeriod),
+				Timeout:     Duration(alertingDefaults.Timeout),
+			},
+		},
+		Tracing: TracingConfig{
+			Exporter:    tracingDefaults.Exporter,
+			SampleRatio: tracingDefaults.SampleRatio,
+		},
+	}
+}
+
+// Load 从文件加载配置，格式由扩展名决定（.yaml/.yml/.toml/.json），
+// 随后应用环境变量覆盖并校验。path 为空时只使用默认值和环境变量。
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("读取配置文件失败: %w", err)
+		}
+
+		format, err := formatFromPath(path)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := decodeInto(cfg, data, format); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	if err := applyEnv(cfg, EnvPrefix, os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Parse 从内存数据解析配置，format 为 yaml、toml 或 json，不读取环境变量
+func Parse(data []byte, format string) (*Config, error) {
+	cfg := Default()
+	if err := decodeInto(cfg, data, format); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// formatFromPath 根据文件扩展名判断配置格式
+func formatFromPath(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		return "yaml", nil
+	case ".toml":
+		return "toml", nil
+	case ".json":
+		return "json", nil
+	default:
+		return "", fmt.Errorf("不支持的配置文件格式: %s（支持 .yaml/.yml/.toml/.json）", filepath.Ext(path))
+	}
+}
+
+// decodeInto 将原始数据解析为通用树后写入配置结构体
+func decodeInto(cfg *Config, data []byte, format string) error {
+	var (
+		tree map[string]interface{}
+		err  error
+	)
+	switch format {
+	case "yaml":
+		tree, err = parseYAML(data)
+	case "toml":
+		tree, err = parseTOML(data)
+	case "json":
+		tree, err = parseJSON(data)
+	default:
+		return fmt.Errorf("不支持的配置格式: %s", format)
+	}
+	if err != nil {
+		return err
+	}
+	return decodeTree(tree, cfg)
+}
+
+// SlogLevel 返回日志级别
+func (c *Config) SlogLevel() slog.Level {
+	level, _ := parseLogLevel(c.Server.LogLevel)
+	return level
+}
+
+// MCPConfig 转换为MCP服务器配置
+func (c *Config) MCPConfig(version string) *mcp.Config {
+	return &mcp.Config{
+		Name:        c.Server.Name,
+		Version:     version,
+		Description: c.Server.Description,
+		LogLevel:    c.SlogLevel(),
+	}
+}
+
+// TracingConfig 转换为链路追踪配置，服务名取 server.name
+func (c *Config) TracingConfig(version string) *tracing.Config {
+	return &tracing.Config{
+		Exporter:       c.Tracing.Exporter,
+		Endpoint:       c.Tracing.Endpoint,
+		Insecure:       c.Tracing.Insecure,
+		Headers:        c.Tracing.Headers,
+		SampleRatio:    c.Tracing.SampleRatio,
+		ServiceName:    c.Server.Name,
+		ServiceVersion: version,
+	}
+}
+
+// WebSocketConfig 转换为WebSocket传输配置
+func (c *Config) WebSocketConfig() *mcp.WebSocketConfig {
+	config := mcp.DefaultWebSocketConfig()
+	config.MaxMessageSize = int64(c.Server.WebSocket.MaxMessageSize)
+	config.PingInterval = c.Server.WebSocket.PingInterval.Duration()
+	config.AllowedOrigins = append([]string(nil), c.Server.WebSocket.AllowedOrigins...)
+	return config
+}
+
+// CacheConfig 转换为缓存管理器配置
+func (c *Config) CacheConfig() *cache.CacheConfig {
+	return &cache.CacheConfig{
+		MaxSize:         int64(c.Cache.MaxSize),
+		TTL:             c.Cache.TTL.Duration(),
+		CleanupInterval: c.Cache.CleanupInterval.Duration(),
+		CoalescingConfig: &cache.CoalescingConfig{
+			Timeout:      c.Cache.Coalescing.Timeout.Duration(),
+			CleanupDelay: c.Cache.Coalescing.CleanupDelay.Duration(),
+			LockPrefixes: c.Cache.Coalescing.LockPrefixes,
+			LockTTL:      c.Cache.Coalescing.LockTTL.Duration(),
+		},
+		ConcurrencyConfig: &cache.ConcurrencyConfig{
+			MaxConcurrency: c.Cache.Concurrency.MaxConcurrency,
+			WorkerPoolSize: c.Cache.Concurrency.WorkerPoolSize,
+			QueueSize:      c.Cache.Concurrency.QueueSize,
+			RateLimit:      c.Cache.Concurrency.RateLimit.Duration(),
+			BurstLimit:     c.Cache.Concurrency.BurstLimit,
+		},
+	}
+}
+
+// RedisConfig 转换为Redis客户端配置
+func (c *Config) RedisConfig() *cache.RedisConfig {
+	return &cache.RedisConfig{
+		Addr:        c.Cache.Redis.Addr,
+		Password:    c.Cache.Redis.Password,
+		DB:          c.Cache.Redis.DB,
+		KeyPrefix:   c.Cache.Redis.KeyPrefix,
+		PoolSize:    c.Cache.Redis.PoolSize,
+		DialTimeout: c.Cache.Redis.DialTimeout.Duration(),
+		IOTimeout:   c.Cache.Redis.IOTimeout.Duration(),
+	}
+}
+
+// ProcessorConfig 转换为处理器配置
+func (c *Config) ProcessorConfig() *processor.Config {
+	return &processor.Config{
+		EnableSummarization: c.Processor.EnableSummarization,
+		EnableSorting:       c.Processor.EnableSorting,
+		MaxSummaryLength:    c.Processor.MaxSummaryLength,
+		ProcessingTimeout:   c.Processor.ProcessingTimeout.Duration(),
+		MaxConcurrency:      c.Processor.MaxConcurrency,
+		Enrichment: processor.EnrichmentConfig{
+			Enabled:          c.Processor.Enrichment.Enabled,
+			MinContentLength: c.Processor.Enrichment.MinContentLength,
+			MaxConcurrency:   c.Processor.Enrichment.MaxConcurrency,
+			DomainDelay:      c.Processor.Enrichment.DomainDelay.Duration(),
+			Timeout:          c.Processor.Enrichment.Timeout.Duration(),
+			CacheTTL:         c.Processor.Enrichment.CacheTTL.Duration(),
+			CacheSize:        int64(c.Processor.Enrichment.CacheSize),
+			MaxPageSize:      int64(c.Processor.Enrichment.MaxPageSize),
+		},
+	}
+}
+
+// FormatterConfig 转换为格式化器配置
+func (c *Config) FormatterConfig() *formatter.Config {
+	return &formatter.Config{
+		Format:           formatter.OutputFormat(c.Formatter.Format),
+		Indent:           c.Formatter.Indent,
+		DateFormat:       c.Formatter.DateFormat,
+		IncludeMetadata:  c.Formatter.IncludeMetadata,
+		IncludeContent:   c.Formatter.IncludeContent,
+		MaxSummaryLength: c.Formatter.MaxSummaryLength,
+		SortBy:           c.Formatter.SortBy,
+		SortOrder:        c.Formatter.SortOrder,
+		EnableLinks:      c.Formatter.EnableLinks,
+		CompactOutput:    c.Formatter.CompactOutput,
+	}
+}
+
+// ToolCacheTTLs 返回各工具结果的缓存时间，键为工具名称
+func (c *Config) ToolCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		sources.ToolWeeklyNews:    c.Tools.CacheTTL.WeeklyNews.Duration(),
+		sources.ToolTopicSearch:   c.Tools.CacheTTL.TopicSearch.Duration(),
+		sources.ToolTrendingRepos: c.Tools.CacheTTL.TrendingRepos.Duration(),
+	}
+}
+
+// ToolStaleTTLs 返回各工具结果超过缓存时间后仍可返回旧结果的时长，键为工具名称
+func (c *Config) ToolStaleTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		sources.ToolWeeklyNews:    c.Tools.StaleTTL.WeeklyNews.Duration(),
+		sources.ToolTopicSearch:   c.Tools.StaleTTL.TopicSearch.Duration(),
+		sources.ToolTrendingRepos: c.Tools.StaleTTL.TrendingRepos.Duration(),
+	}
+}
+
+// RefreshConfig 转换为定时刷新配置，未启用时返回nil
+func (c *Config) RefreshConfig() *tools.RefreshConfig {
+	r := c.Tools.Refresh
+	if !r.Enabled {
+		return nil
+	}
+	// Validate 保证时段格式正确
+	from, to, _ := parseActiveHours(r.ActiveHours)
+	return &tools.RefreshConfig{
+		Ahead:                r.Ahead.Duration(),
+		Jitter:               r.Jitter,
+		MaxBackoff:           r.MaxBackoff.Duration(),
+		ActiveFrom:           from,
+		ActiveTo:             to,
+		WeeklyNewsCategories: append([]string(nil), r.WeeklyNews...),
+		TrendingLanguages:    append([]string(nil), r.TrendingLanguages...),
+		TrendingTimeRanges:   append([]string(nil), r.TrendingTimeRanges...),
+	}
+}
+
+// parseActiveHours 解析 "HH:MM-HH:MM" 格式的时段，返回距零点的开始和结束时间，为空表示全天
+func parseActiveHours(hours string) (from, to time.Duration, err error) {
+	if hours == "" {
+		return 0, 0, nil
+	}
+	start, end, ok := strings.Cut(hours, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("应为 HH:MM-HH:MM 格式")
+	}
+	if from, err = parseClock(strings.TrimSpace(start)); err != nil {
+		return 0, 0, err
+	}
+	if to, err = parseClock(strings.TrimSpace(end)); err != nil {
+		return 0, 0, err
+	}
+	return from, to, nil
+}
+
+// parseClock 解析 "HH:MM" 格式的时间，返回距零点的时长
+func parseClock(clock string) (time.Duration, error) {
+	t, err := time.Parse("15:04", clock)
+	if err != nil {
+		return 0, fmt.Errorf("无效的时间 %q，应为 HH:MM 格式", clock)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// MonitoringConfig 转换为缓存监控配置，监控器自身的HTTP端点始终关闭，由管理端点挂载
+func (c *Config) MonitoringConfig() *cache.MonitoringConfig {
+	config := cache.DefaultMonitoringConfig()
+	config.EnableHTTPEndpoints = false
+	config.LogLevel = c.Server.LogLevel
+	config.MetricsInterval = c.Monitoring.MetricsInterval.Duration()
+	config.HealthCheckInterval = c.Monitoring.HealthCheckInterval.Duration()
+	config.AlertThresholds = c.AlertThresholds()
+	return config
+}
+
+// AlertThresholds 转换为缓存告警阈值
+func (c *Config) AlertThresholds() *cache.AlertThresholds {
+	t := c.Monitoring.AlertThresholds
+	return &cache.AlertThresholds{
+		LowHitRatePercent:      t.LowHitRatePercent,
+		HighMemoryUsagePercent: t.HighMemoryUsagePercent,
+		MaxResponseTimeMs:      t.MaxResponseTimeMs,
+		MaxErrorCount:          t.MaxErrorCount,
+		MaxSlowOperations:      t.MaxSlowOperations,
+		HealthCheckTimeout:     t.HealthCheckTimeout.Duration(),
+	}
+}
+
+// AlertingConfig 转换为告警发送配置
+func (c *Config) AlertingConfig() *cache.AlertingConfig {
+	a := c.Monitoring.Alerts
+	config := &cache.AlertingConfig{
+		DedupWindow: a.DedupWindow.Duration(),
+		RateLimit:   a.RateLimit,
+		RatePeriod:  a.RatePeriod.Duration(),
+		Timeout:     a.Timeout.Duration(),
+	}
+	for _, sink := range a.Sinks {
+		config.Sinks = append(config.Sinks, cache.AlertSinkConfig{
+			Type:    sink.Type,
+			URL:     sink.URL,
+			Headers: sink.Headers,
+			Path:    sink.Path,
+			Tag:     sink.Tag,
+		})
+	}
+	return config
+}
+
+// SourceDefinitions 返回内置数据源与配置合并后的完整数据源列表，用于填充数据源注册表
+func (c *Config) SourceDefinitions() []sources.Source {
+	var list []sources.Source
+	index := make(map[string]int)
+
+	if c.Tools.BuiltinSources {
+		for _, source := range sources.DefaultSources() {
+			index[source.Name] = len(list)
+			list = append(list, source)
+		}
+	}
+
+	for _, sc := range c.Sources {
+		if i, exists := index[sc.Name]; exists {
+			list[i] = sc.apply(list[i])
+			continue
+		}
+		index[sc.Name] = len(list)
+		list = append(list, sc.apply(sources.Source{Enabled: true, Weight: 1.0}))
+	}
+
+	return list
+}
+
+// Duration 支持 "15m"、"1h30m" 字符串或秒数的时间配置
+type Duration time.Duration
+
+// Duration 转换为 time.Duration
+func (d Duration) Duration() time.Duration {
+	return time.Duration(d)
+}
+
+// String 返回时间字符串
+func (d Duration) String() string {
+	return time.Duration(d).String()
+}
+
+// ByteSize 支持 "512MB"、"1GB" 或字节数的大小配置
+type ByteSize int64
+
+// parseLogLevel 解析日志级别字符串
+func parseLogLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("未知的日志级别 %q", level)
+	}
+}
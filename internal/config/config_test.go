@@ -0,0 +1,534 @@
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+)
+
+const sampleYAML = `
+server:
+  name: team-context
+  log_level: debug
+  transport: http
+  addr: ":9090"
+  websocket:
+    max_message_size: 1MB
+    ping_interval: 15s
+    allowed_origins: ["https://app.example.com"]
+
+cache:
+  max_size: 256MB
+  ttl: 30m
+  coalescing:
+    timeout: 10s
+
+processor:
+  max_summary_length: 300
+
+formatter:
+  format: markdown
+
+tools:
+  max_concurrency: 20
+
+sources:
+  - name: css-tricks
+    tools: [weekly_news]
+    url: https://css-tricks.com/feed/
+    type: rss
+    max_articles: 15
+    tags:
+      - css
+      - frontend
+  - name: disabled-feed
+    tools: [weekly_news]
+    url: https://example.com/feed.xml
+    enabled: false
+`
+
+const sampleTOML = `
+[server]
+name = "team-context"
+log_level = "debug"
+transport = "http"
+addr = ":9090"
+
+[cache]
+max_size = "256MB"
+ttl = "30m"
+coalescing.timeout = "10s"
+
+[processor]
+max_summary_length = 300
+
+[formatter]
+format = "markdown"
+
+[tools]
+max_concurrency = 20
+
+[[sources]]
+name = "css-tricks"
+tools = ["weekly_news"]
+url = "https://css-tricks.com/feed/"
+type = "rss"
+max_articles = 15
+tags = ["css", "frontend"]
+
+[[sources]]
+name = "disabled-feed"
+tools = ["weekly_news"]
+url = "https://example.com/feed.xml"
+enabled = false
+`
+
+const sampleJSON = `{
+  "server": {"name": "team-context", "log_level": "debug", "transport": "http", "addr": ":9090"},
+  "cache": {"max_size": "256MB", "ttl": "30m", "coalescing": {"timeout": "10s"}},
+  "processor": {"max_summary_length": 300},
+  "formatter": {"format": "markdown"},
+  "tools": {"max_concurrency": 20},
+  "sources": [
+    {"name": "css-tricks", "tools": ["weekly_news"], "url": "https://css-tricks.com/feed/",
+     "type": "rss", "max_articles": 15, "tags": ["css", "frontend"]},
+    {"name": "disabled-feed", "tools": ["weekly_news"], "url": "https://example.com/feed.xml", "enabled": false}
+  ]
+}`
+
+func TestParseFormats(t *testing.T) {
+	tests := []struct {
+		format string
+		data   string
+	}{
+		{"yaml", sampleYAML},
+		{"toml", sampleTOML},
+		{"json", sampleJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			cfg, err := Parse([]byte(tt.data), tt.format)
+			if err != nil {
+				t.Fatalf("Parse failed: %v", err)
+			}
+
+			if cfg.Server.Name != "team-context" || cfg.Server.Transport != "http" || cfg.Server.Addr != ":9090" {
+				t.Errorf("Unexpected server config: %+v", cfg.Server)
+			}
+			if cfg.Cache.MaxSize != 256<<20 {
+				t.Errorf("Expected max_size 256MB, got %d", cfg.Cache.MaxSize)
+			}
+			if cfg.Cache.TTL.Duration() != 30*time.Minute {
+				t.Errorf("Expected ttl 30m, got %s", cfg.Cache.TTL)
+			}
+			if cfg.Cache.Coalescing.Timeout.Duration() != 10*time.Second {
+				t.Errorf("Expected coalescing timeout 10s, got %s", cfg.Cache.Coalescing.Timeout)
+			}
+			// 未配置的键保留默认值
+			if cfg.Cache.CleanupInterval.Duration() != 5*time.Minute {
+				t.Errorf("Expected default cleanup_interval 5m, got %s", cfg.Cache.CleanupInterval)
+			}
+			if !cfg.Processor.EnableSummarization {
+				t.Error("Expected default enable_summarization to be kept")
+			}
+			if cfg.Processor.MaxSummaryLength != 300 {
+				t.Errorf("Expected max_summary_length 300, got %d", cfg.Processor.MaxSummaryLength)
+			}
+			if cfg.Formatter.Format != "markdown" {
+				t.Errorf("Expected formatter format markdown, got %s", cfg.Formatter.Format)
+			}
+			if cfg.Tools.MaxConcurrency != 20 {
+				t.Errorf("Expected tools max_concurrency 20, got %d", cfg.Tools.MaxConcurrency)
+			}
+
+			if len(cfg.Sources) != 2 {
+				t.Fatalf("Expected 2 sources, got %d", len(cfg.Sources))
+			}
+			definitions := make(map[string]sources.Source)
+			for _, source := range cfg.SourceDefinitions() {
+				definitions[source.Name] = source
+			}
+			source, ok := definitions["css-tricks"]
+			if !ok {
+				t.Fatal("Expected css-tricks source")
+			}
+			if !source.Enabled || !source.UsedBy(sources.ToolWeeklyNews) {
+				t.Errorf("Expected css-tricks to be an enabled weekly_news source: %+v", source)
+			}
+			if source.Config.MaxArticles != 15 || source.Config.Metadata["source_type"] != "rss" {
+				t.Errorf("Unexpected source collect config: %+v", source.Config)
+			}
+			if len(source.Config.Tags) != 2 || source.Config.Tags[0] != "css" {
+				t.Errorf("Unexpected source tags: %v", source.Config.Tags)
+			}
+			if definitions["disabled-feed"].Enabled {
+				t.Error("Expected disabled-feed to be disabled")
+			}
+			// 内置数据源保留
+			if _, ok := definitions["dev.to"]; !ok {
+				t.Error("Expected builtin dev.to source to be kept")
+			}
+		})
+	}
+}
+
+func TestSourceDefinitionsOverrideBuiltins(t *testing.T) {
+	input := `
+sources:
+  - name: dev.to
+    enabled: false
+  - name: github_trending
+    weight: 0.1
+    categories: [typescript]
+  - name: release-notes
+    tools: [weekly_news]
+    url: https://example.com/releases.xml
+    type: rss
+  - name: framework-blog
+    tools: [weekly_news]
+    url: https://example.com/blog
+    type: html
+    selector:
+      item: "ul.posts > li"
+      title: h2
+      links: "a.permalink"
+      published_at: time
+`
+	cfg, err := Parse([]byte(input), "yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	definitions := make(map[string]sources.Source)
+	for _, source := range cfg.SourceDefinitions() {
+		definitions[source.Name] = source
+	}
+
+	devto := definitions["dev.to"]
+	if devto.Enabled {
+		t.Error("Expected dev.to to be disabled")
+	}
+	if devto.Config.URL == "" || !devto.UsedBy(sources.ToolWeeklyNews) {
+		t.Error("Expected partial override to keep builtin fields")
+	}
+
+	trending := definitions["github_trending"]
+	if trending.Weight != 0.1 || !trending.HasCategory("typescript") {
+		t.Errorf("Expected weight and categories override, got %+v", trending)
+	}
+
+	custom := definitions["release-notes"]
+	if !custom.Enabled || custom.Weight != 1.0 || custom.Config.Metadata["source_type"] != "rss" {
+		t.Errorf("Unexpected custom source: %+v", custom)
+	}
+
+	blog := definitions["framework-blog"].Config.Selector
+	if blog == nil || blog.Item != "ul.posts > li" || blog.Links != "a.permalink" || blog.PublishedAt != "time" {
+		t.Errorf("Expected HTML selector on framework-blog, got %+v", blog)
+	}
+
+	registry := sources.NewRegistry()
+	if err := registry.Replace(cfg.SourceDefinitions()); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+}
+
+func TestBuiltinSourcesDisabled(t *testing.T) {
+	input := `
+tools:
+  builtin_sources: false
+sources:
+  - name: internal-blog
+    tools: [weekly_news]
+    url: https://blog.internal.example.com/feed
+`
+	cfg, err := Parse([]byte(input), "yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	definitions := cfg.SourceDefinitions()
+	if len(definitions) != 1 || definitions[0].Name != "internal-blog" {
+		t.Errorf("Expected only the configured source, got %d sources", len(definitions))
+	}
+}
+
+func TestDefaultIsValid(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("Default config should be valid: %v", err)
+	}
+}
+
+func TestConverters(t *testing.T) {
+	cfg, err := Parse([]byte(sampleYAML), "yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	mcpConfig := cfg.MCPConfig("v1.2.3")
+	if mcpConfig.Name != "team-context" || mcpConfig.Version != "v1.2.3" {
+		t.Errorf("Unexpected MCP config: %+v", mcpConfig)
+	}
+
+	cacheConfig := cfg.CacheConfig()
+	if cacheConfig.TTL != 30*time.Minute || cacheConfig.MaxSize != 256<<20 {
+		t.Errorf("Unexpected cache config: %+v", cacheConfig)
+	}
+	if cacheConfig.CoalescingConfig == nil || cacheConfig.CoalescingConfig.Timeout != 10*time.Second {
+		t.Errorf("Unexpected coalescing config: %+v", cacheConfig.CoalescingConfig)
+	}
+
+	if cfg.ProcessorConfig().MaxSummaryLength != 300 {
+		t.Error("Processor config not converted")
+	}
+	enrichment := Default().ProcessorConfig().Enrichment
+	if enrichment.Enabled || enrichment.DomainDelay != time.Second || enrichment.CacheSize != 32<<20 {
+		t.Errorf("Unexpected default enrichment config: %+v", enrichment)
+	}
+	enrichmentCfg, err := Parse([]byte("processor:\n  enrichment:\n    enabled: true\n    domain_delay: 2s\n    max_page_size: 1MB\n"), "yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	enrichment = enrichmentCfg.ProcessorConfig().Enrichment
+	if !enrichment.Enabled || enrichment.DomainDelay != 2*time.Second || enrichment.MaxPageSize != 1<<20 || enrichment.MinContentLength != 500 {
+		t.Errorf("Unexpected enrichment config: %+v", enrichment)
+	}
+	if cfg.FormatterConfig().Format != "markdown" {
+		t.Error("Formatter config not converted")
+	}
+
+	wsConfig := cfg.WebSocketConfig()
+	if wsConfig.MaxMessageSize != 1<<20 || wsConfig.PingInterval != 15*time.Second {
+		t.Errorf("Unexpected websocket config: %+v", wsConfig)
+	}
+	if len(wsConfig.AllowedOrigins) != 1 || wsConfig.AllowedOrigins[0] != "https://app.example.com" {
+		t.Errorf("Unexpected allowed origins: %v", wsConfig.AllowedOrigins)
+	}
+
+	refresh := Default().RefreshConfig()
+	if refresh == nil || refresh.Ahead != 5*time.Minute || len(refresh.TrendingTimeRanges) != 1 {
+		t.Errorf("Unexpected default refresh config: %+v", refresh)
+	}
+	refreshCfg, err := Parse([]byte("tools:\n  refresh:\n    active_hours: \"22:00-06:30\"\n    weekly_news: [\"\", react]\n"), "yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	refresh = refreshCfg.RefreshConfig()
+	if refresh.ActiveFrom != 22*time.Hour || refresh.ActiveTo != 6*time.Hour+30*time.Minute {
+		t.Errorf("Unexpected active hours: %v-%v", refresh.ActiveFrom, refresh.ActiveTo)
+	}
+	if len(refresh.WeeklyNewsCategories) != 2 || refresh.WeeklyNewsCategories[1] != "react" {
+		t.Errorf("Unexpected weekly news categories: %v", refresh.WeeklyNewsCategories)
+	}
+	refreshCfg.Tools.Refresh.Enabled = false
+	if refreshCfg.RefreshConfig() != nil {
+		t.Error("Expected disabled refresh to convert to nil")
+	}
+
+	alertsCfg, err := Parse([]byte("monitoring:\n  alerts:\n    rate_limit: 2\n    sinks:\n      - type: webhook\n        url: https://alerts.example.com/hook\n        headers:\n          Authorization: Bearer x\n      - type: file\n        path: /var/log/alerts.log\n"), "yaml")
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+	alerting := alertsCfg.AlertingConfig()
+	if alerting.RateLimit != 2 || alerting.DedupWindow != 15*time.Minute || len(alerting.Sinks) != 2 {
+		t.Errorf("Unexpected alerting config: %+v", alerting)
+	}
+	if alerting.Sinks[0].Headers["Authorization"] != "Bearer x" || alerting.Sinks[1].Path != "/var/log/alerts.log" {
+		t.Errorf("Unexpected alert sinks: %+v", alerting.Sinks)
+	}
+}
+
+func TestValidationErrorsPointAtKey(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		key  string
+	}{
+		{"negative ttl", "cache:\n  ttl: -5m\n", "cache.ttl"},
+		{"bad cache backend", "cache:\n  backend: memcached\n", "cache.backend"},
+		{"empty redis addr", "cache:\n  backend: redis\n  redis:\n    addr: \"\"\n", "cache.redis.addr"},
+		{"distributed without redis", "cache:\n  coalescing:\n    distributed: true\n", "cache.coalescing.distributed"},
+		{"bad log format", "server:\n  log_format: logfmt\n", "server.log_format"},
+		{"bad transport", "server:\n  transport: grpc\n", "server.transport"},
+		{"zero websocket message size", "server:\n  websocket:\n    max_message_size: 0\n", "server.websocket.max_message_size"},
+		{"bad websocket origin", "server:\n  websocket:\n    allowed_origins: [app.example.com]\n", "server.websocket.allowed_origins[0]"},
+		{"admin on mcp addr", "server:\n  transport: http\n  addr: \":8080\"\n  admin:\n    addr: \":8080\"\n", "server.admin.addr"},
+		{"zero enrichment timeout", "processor:\n  enrichment:\n    enabled: true\n    timeout: 0s\n", "processor.enrichment.timeout"},
+		{"bad format", "formatter:\n  format: html\n", "formatter.format"},
+		{"negative stale ttl", "tools:\n  stale_ttl:\n    weekly_news: -1h\n", "tools.stale_ttl.weekly_news"},
+		{"zero concurrency", "tools:\n  max_concurrency: 0\n", "tools.max_concurrency"},
+		{"bad refresh jitter", "tools:\n  refresh:\n    jitter: 1.5\n", "tools.refresh.jitter"},
+		{"bad refresh hours", "tools:\n  refresh:\n    active_hours: \"8am-8pm\"\n", "tools.refresh.active_hours"},
+		{"bad refresh time range", "tools:\n  refresh:\n    trending_time_ranges: [yearly]\n", "tools.refresh.trending_time_ranges[0]"},
+		{"bad alert sink type", "monitoring:\n  alerts:\n    sinks:\n      - type: pager\n", "monitoring.alerts.sinks[0].type"},
+		{"bad slack url", "monitoring:\n  alerts:\n    sinks:\n      - type: slack\n        url: hooks.slack.com\n", "monitoring.alerts.sinks[0].url"},
+		{"file sink without path", "monitoring:\n  alerts:\n    sinks:\n      - type: file\n", "monitoring.alerts.sinks[0].path"},
+		{"alert rate limit without period", "monitoring:\n  alerts:\n    rate_limit: 3\n    rate_period: 0s\n", "monitoring.alerts.rate_period"},
+		{"bad trace exporter", "tracing:\n  exporter: jaeger\n", "tracing.exporter"},
+		{"bad sample ratio", "tracing:\n  sample_ratio: 2\n", "tracing.sample_ratio"},
+		{"bad source url", "sources:\n  - name: x\n    tools: [weekly_news]\n    url: not-a-url\n", "sources[0].url"},
+		{"unknown tool", "sources:\n  - name: x\n    tools: [news]\n    url: https://a.com\n", "sources[0].tools"},
+		{"missing url for new source", "sources:\n  - name: x\n    tools: [weekly_news]\n", "sources[0].url"},
+		{"bad kind", "sources:\n  - name: x\n    tools: [topic_search]\n    url: https://a.com\n    kind: videos\n", "sources[0].kind"},
+		{"bad selector", "sources:\n  - name: x\n    tools: [weekly_news]\n    url: https://a.com\n    type: html\n    selector:\n      item: \"div[class\"\n", "sources[0].selector"},
+		{"duplicate source", "sources:\n  - name: x\n    tools: [weekly_news]\n    url: https://a.com\n  - name: x\n    tools: [weekly_news]\n    url: https://b.com\n", "sources[1].name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml), "yaml")
+			if err == nil {
+				t.Fatal("Expected validation error")
+			}
+			var validationErrs ValidationErrors
+			if !errors.As(err, &validationErrs) {
+				t.Fatalf("Expected ValidationErrors, got %T: %v", err, err)
+			}
+			found := false
+			for _, e := range validationErrs {
+				if e.Key == tt.key {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected error for key %s, got %v", tt.key, err)
+			}
+		})
+	}
+}
+
+func TestDecodeErrorsPointAtKey(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		key  string
+	}{
+		{"unknown key", "cache:\n  tll: 5m\n", "cache.tll"},
+		{"invalid duration", "cache:\n  ttl: soon\n", "cache.ttl"},
+		{"wrong type", "tools:\n  max_concurrency: many\n", "tools.max_concurrency"},
+		{"nested in list", "sources:\n  - name: x\n    max_articles: [1]\n", "sources[0].max_articles"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.yaml), "yaml")
+			if err == nil {
+				t.Fatal("Expected decode error")
+			}
+			var keyErr *KeyError
+			if !errors.As(err, &keyErr) {
+				t.Fatalf("Expected KeyError, got %T: %v", err, err)
+			}
+			if keyErr.Key != tt.key {
+				t.Errorf("Expected key %s, got %s (%v)", tt.key, keyErr.Key, err)
+			}
+		})
+	}
+}
+
+func TestApplyEnv(t *testing.T) {
+	env := map[string]string{
+		"DEVCONTEXT_CACHE_TTL":                      "45m",
+		"DEVCONTEXT_CACHE_MAX_SIZE":                 "1GB",
+		"DEVCONTEXT_CACHE_CONCURRENCY_QUEUE_SIZE":   "64",
+		"DEVCONTEXT_SERVER_TRANSPORT":               "websocket",
+		"DEVCONTEXT_PROCESSOR_ENABLE_SUMMARIZATION": "false",
+		"DEVCONTEXT_TOOLS_MAX_CONCURRENCY":          "5",
+		"DEVCONTEXT_SERVER_ADMIN_TOKEN":             "s3cret",
+	}
+	lookup := func(key string) (string, bool) {
+		value, ok := env[key]
+		return value, ok
+	}
+
+	cfg := Default()
+	if err := applyEnv(cfg, EnvPrefix, lookup); err != nil {
+		t.Fatalf("applyEnv failed: %v", err)
+	}
+
+	if cfg.Cache.TTL.Duration() != 45*time.Minute {
+		t.Errorf("Expected ttl 45m, got %s", cfg.Cache.TTL)
+	}
+	if cfg.Cache.MaxSize != 1<<30 {
+		t.Errorf("Expected max_size 1GB, got %d", cfg.Cache.MaxSize)
+	}
+	if cfg.Cache.Concurrency.QueueSize != 64 {
+		t.Errorf("Expected queue_size 64, got %d", cfg.Cache.Concurrency.QueueSize)
+	}
+	if cfg.Server.Transport != "websocket" {
+		t.Errorf("Expected transport websocket, got %s", cfg.Server.Transport)
+	}
+	if cfg.Processor.EnableSummarization {
+		t.Error("Expected enable_summarization to be overridden to false")
+	}
+	if cfg.Tools.MaxConcurrency != 5 {
+		t.Errorf("Expected tools max_concurrency 5, got %d", cfg.Tools.MaxConcurrency)
+	}
+	if cfg.Server.Admin.Token != "s3cret" {
+		t.Errorf("Expected admin token from environment, got %q", cfg.Server.Admin.Token)
+	}
+}
+
+func TestApplyEnvInvalidValue(t *testing.T) {
+	lookup := func(key string) (string, bool) {
+		if key == "DEVCONTEXT_CACHE_TTL" {
+			return "forever", true
+		}
+		return "", false
+	}
+
+	err := applyEnv(Default(), EnvPrefix, lookup)
+	if err == nil {
+		t.Fatal("Expected error for invalid env value")
+	}
+	if !strings.Contains(err.Error(), "DEVCONTEXT_CACHE_TTL") || !strings.Contains(err.Error(), "cache.ttl") {
+		t.Errorf("Expected error to mention env var and key, got %v", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.toml")
+	if err := os.WriteFile(path, []byte(sampleTOML), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+
+	t.Setenv("DEVCONTEXT_TOOLS_MAX_CONCURRENCY", "7")
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Server.Name != "team-context" {
+		t.Errorf("Expected file value, got %s", cfg.Server.Name)
+	}
+	if cfg.Tools.MaxConcurrency != 7 {
+		t.Errorf("Expected env override 7, got %d", cfg.Tools.MaxConcurrency)
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Load(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Error("Expected error for missing file")
+	}
+
+	ini := filepath.Join(dir, "config.ini")
+	os.WriteFile(ini, []byte("x=1"), 0644)
+	if _, err := Load(ini); err == nil {
+		t.Error("Expected error for unsupported extension")
+	}
+
+	bad := filepath.Join(dir, "bad.yaml")
+	os.WriteFile(bad, []byte("cache:\n  ttl: nope\n"), 0644)
+	_, err := Load(bad)
+	if err == nil || !strings.Contains(err.Error(), bad) || !strings.Contains(err.Error(), "cache.ttl") {
+		t.Errorf("Expected error mentioning file and key, got %v", err)
+	}
+}
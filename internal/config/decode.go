@@ -0,0 +1,424 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	durationType = reflect.TypeOf(Duration(0))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+)
+
+// parseJSON 解析JSON为通用树，整数统一为int64
+func parseJSON(data []byte) (map[string]interface{}, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var raw interface{}
+	if err := decoder.Decode(&raw); err != nil {
+		return nil, fmt.Errorf("JSON解析失败: %w", err)
+	}
+	normalized, err := normalizeTree("", raw)
+	if err != nil {
+		return nil, err
+	}
+	tree, ok := normalized.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("JSON解析失败: 顶层必须是对象")
+	}
+	return tree, nil
+}
+
+// normalizeTree 统一各解析器产生的值类型：整数为int64，浮点数为float64，
+// 日期时间为RFC 3339字符串，对象为map[string]interface{}，列表为[]interface{}。
+// 超出int64范围的无符号整数返回带配置键的错误
+func normalizeTree(path string, value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i, nil
+		}
+		f, _ := v.Float64()
+		return f, nil
+	case int:
+		return int64(v), nil
+	case uint64:
+		if v > math.MaxInt64 {
+			return nil, &KeyError{Key: path, Message: fmt.Sprintf("整数 %d 超出范围", v)}
+		}
+		return int64(v), nil
+	case time.Time:
+		return v.Format(time.RFC3339Nano), nil
+	case map[string]interface{}:
+		for key, item := range v {
+			normalized, err := normalizeTree(joinKey(path, key), item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = normalized
+		}
+		return v, nil
+	case map[interface{}]interface{}:
+		m := make(map[string]interface{}, len(v))
+		for key, item := range v {
+			normalized, err := normalizeTree(joinKey(path, fmt.Sprint(key)), item)
+			if err != nil {
+				return nil, err
+			}
+			m[fmt.Sprint(key)] = normalized
+		}
+		return m, nil
+	case []interface{}:
+		for i, item := range v {
+			normalized, err := normalizeTree(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = normalized
+		}
+		return v, nil
+	case []map[string]interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			normalized, err := normalizeTree(fmt.Sprintf("%s[%d]", path, i), item)
+			if err != nil {
+				return nil, err
+			}
+			items[i] = normalized
+		}
+		return items, nil
+	default:
+		return v, nil
+	}
+}
+
+// decodeTree 将通用树写入目标结构体，错误信息包含出错的配置键
+func decodeTree(tree map[string]interface{}, target interface{}) error {
+	return decodeValue("", tree, reflect.ValueOf(target).Elem())
+}
+
+// fieldKey 返回结构体字段对应的配置键
+func fieldKey(field reflect.StructField) string {
+	tag := field.Tag.Get("json")
+	if name, _, _ := strings.Cut(tag, ","); name != "" {
+		return name
+	}
+	return strings.ToLower(field.Name)
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+func decodeValue(path string, value interface{}, target reflect.Value) error {
+	switch target.Type() {
+	case durationType:
+		d, err := toDuration(value)
+		if err != nil {
+			return &KeyError{Key: path, Message: err.Error()}
+		}
+		target.SetInt(int64(d))
+		return nil
+	case byteSizeType:
+		size, err := toByteSize(value)
+		if err != nil {
+			return &KeyError{Key: path, Message: err.Error()}
+		}
+		target.SetInt(size)
+		return nil
+	}
+
+	switch target.Kind() {
+	case reflect.Ptr:
+		if value == nil {
+			target.Set(reflect.Zero(target.Type()))
+			return nil
+		}
+		elem := reflect.New(target.Type().Elem())
+		if !target.IsNil() {
+			elem.Elem().Set(target.Elem())
+		}
+		if err := decodeValue(path, value, elem.Elem()); err != nil {
+			return err
+		}
+		target.Set(elem)
+		return nil
+
+	case reflect.Struct:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return &KeyError{Key: path, Message: fmt.Sprintf("应为对象，实际为 %s", describe(value))}
+		}
+		fields := make(map[string]int, target.NumField())
+		for i := 0; i < target.NumField(); i++ {
+			fields[fieldKey(target.Type().Field(i))] = i
+		}
+		// 按键排序保证错误信息稳定
+		keys := make([]string, 0, len(m))
+		for key := range m {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			index, ok := fields[key]
+			if !ok {
+				return &KeyError{Key: joinKey(path, key), Message: "未知的配置项"}
+			}
+			if err := decodeValue(joinKey(path, key), m[key], target.Field(index)); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case reflect.Map:
+		m, ok := value.(map[string]interface{})
+		if !ok {
+			return &KeyError{Key: path, Message: fmt.Sprintf("应为对象，实际为 %s", describe(value))}
+		}
+		result := reflect.MakeMapWithSize(target.Type(), len(m))
+		for key, item := range m {
+			elem := reflect.New(target.Type().Elem()).Elem()
+			if err := decodeValue(joinKey(path, key), item, elem); err != nil {
+				return err
+			}
+			result.SetMapIndex(reflect.ValueOf(key), elem)
+		}
+		target.Set(result)
+		return nil
+
+	case reflect.Slice:
+		var items []interface{}
+		switch v := value.(type) {
+		case []interface{}:
+			items = v
+		case string:
+			// 字符串列表允许逗号分隔的写法，便于环境变量覆盖
+			if target.Type().Elem().Kind() != reflect.String {
+				return &KeyError{Key: path, Message: fmt.Sprintf("应为列表，实际为 %s", describe(value))}
+			}
+			for _, part := range strings.Split(v, ",") {
+				if part = strings.TrimSpace(part); part != "" {
+					items = append(items, part)
+				}
+			}
+		default:
+			return &KeyError{Key: path, Message: fmt.Sprintf("应为列表，实际为 %s", describe(value))}
+		}
+		result := reflect.MakeSlice(target.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := decodeValue(fmt.Sprintf("%s[%d]", path, i), item, result.Index(i)); err != nil {
+				return err
+			}
+		}
+		target.Set(result)
+		return nil
+
+	case reflect.String:
+		switch v := value.(type) {
+		case string:
+			target.SetString(v)
+		case int64, float64, bool:
+			target.SetString(fmt.Sprint(v))
+		default:
+			return &KeyError{Key: path, Message: fmt.Sprintf("应为字符串，实际为 %s", describe(value))}
+		}
+		return nil
+
+	case reflect.Bool:
+		switch v := value.(type) {
+		case bool:
+			target.SetBool(v)
+		case string:
+			b, err := strconv.ParseBool(strings.TrimSpace(v))
+			if err != nil {
+				return &KeyError{Key: path, Message: fmt.Sprintf("无效的布尔值 %q", v)}
+			}
+			target.SetBool(b)
+		default:
+			return &KeyError{Key: path, Message: fmt.Sprintf("应为布尔值，实际为 %s", describe(value))}
+		}
+		return nil
+
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := toInt(value)
+		if err != nil {
+			return &KeyError{Key: path, Message: err.Error()}
+		}
+		if target.OverflowInt(i) {
+			return &KeyError{Key: path, Message: fmt.Sprintf("数值 %d 超出范围", i)}
+		}
+		target.SetInt(i)
+		return nil
+
+	case reflect.Float32, reflect.Float64:
+		f, err := toFloat(value)
+		if err != nil {
+			return &KeyError{Key: path, Message: err.Error()}
+		}
+		target.SetFloat(f)
+		return nil
+	}
+
+	return &KeyError{Key: path, Message: fmt.Sprintf("不支持的配置类型 %s", target.Type())}
+}
+
+// describe 返回值类型的可读描述
+func describe(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "空值"
+	case map[string]interface{}:
+		return "对象"
+	case []interface{}:
+		return "列表"
+	case string:
+		return "字符串"
+	case bool:
+		return "布尔值"
+	case int64, float64:
+		return "数字"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func toInt(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		if v != math.Trunc(v) {
+			return 0, fmt.Errorf("应为整数，实际为 %v", v)
+		}
+		return int64(v), nil
+	case string:
+		i, err := strconv.ParseInt(strings.TrimSpace(v), 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("无效的整数 %q", v)
+		}
+		return i, nil
+	default:
+		return 0, fmt.Errorf("应为整数，实际为 %s", describe(value))
+	}
+}
+
+func toFloat(value interface{}) (float64, error) {
+	switch v := value.(type) {
+	case int64:
+		return float64(v), nil
+	case float64:
+		return v, nil
+	case string:
+		f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, fmt.Errorf("无效的数字 %q", v)
+		}
+		return f, nil
+	default:
+		return 0, fmt.Errorf("应为数字，实际为 %s", describe(value))
+	}
+}
+
+// toDuration 解析时间配置，字符串按 time.ParseDuration，数字按秒
+func toDuration(value interface{}) (time.Duration, error) {
+	switch v := value.(type) {
+	case string:
+		d, err := time.ParseDuration(strings.TrimSpace(v))
+		if err != nil {
+			return 0, fmt.Errorf("无效的时间 %q（示例: 30s, 15m, 1h）", v)
+		}
+		return d, nil
+	case int64:
+		return time.Duration(v) * time.Second, nil
+	case float64:
+		return time.Duration(v * float64(time.Second)), nil
+	default:
+		return 0, fmt.Errorf("应为时间，实际为 %s", describe(value))
+	}
+}
+
+// toByteSize 解析大小配置，支持 B/KB/MB/GB 后缀
+func toByteSize(value interface{}) (int64, error) {
+	switch v := value.(type) {
+	case int64:
+		return v, nil
+	case float64:
+		return int64(v), nil
+	case string:
+		return parseByteSize(v)
+	default:
+		return 0, fmt.Errorf("应为大小，实际为 %s", describe(value))
+	}
+}
+
+func parseByteSize(s string) (int64, error) {
+	text := strings.ToUpper(strings.TrimSpace(s))
+	units := []struct {
+		suffix     string
+		multiplier int64
+	}{
+		{"GB", 1 << 30}, {"MB", 1 << 20}, {"KB", 1 << 10},
+		{"G", 1 << 30}, {"M", 1 << 20}, {"K", 1 << 10}, {"B", 1},
+	}
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(text, unit.suffix) {
+			multiplier = unit.multiplier
+			text = strings.TrimSpace(strings.TrimSuffix(text, unit.suffix))
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(text, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("无效的大小 %q（示例: 512MB, 1GB）", s)
+	}
+	return int64(n * float64(multiplier)), nil
+}
+
+// applyEnv 使用环境变量覆盖标量配置项，变量名为 前缀_键路径（大写，点替换为下划线）
+func applyEnv(cfg *Config, prefix string, lookup func(string) (string, bool)) error {
+	return applyEnvValue(prefix, "", reflect.ValueOf(cfg).Elem(), lookup)
+}
+
+func applyEnvValue(envName, path string, target reflect.Value, lookup func(string) (string, bool)) error {
+	if target.Kind() == reflect.Struct && target.Type() != durationType && target.Type() != byteSizeType {
+		for i := 0; i < target.NumField(); i++ {
+			key := fieldKey(target.Type().Field(i))
+			name := envName + "_" + strings.ToUpper(key)
+			if err := applyEnvValue(name, joinKey(path, key), target.Field(i), lookup); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// 对象列表和映射不支持环境变量覆盖
+	switch target.Kind() {
+	case reflect.Map, reflect.Ptr:
+		return nil
+	case reflect.Slice:
+		if target.Type().Elem().Kind() != reflect.String {
+			return nil
+		}
+	}
+
+	value, ok := lookup(envName)
+	if !ok {
+		return nil
+	}
+	if err := decodeValue(path, value, target); err != nil {
+		return fmt.Errorf("环境变量 %s: %w", envName, err)
+	}
+	return nil
+}
@@ -0,0 +1,200 @@
+package config
+
+import (
+	"context"
+	"crypto/sha256"
+	"log/slog"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+)
+
+// Watcher 监视配置文件变化并重新加载
+//
+// 通过轮询文件的修改时间、大小和内容哈希检测变化，不依赖平台相关的文件通知机制，
+// 编辑器先写临时文件再重命名的保存方式同样可以检测到。Reload 用于手动触发（例如收到 SIGHUP）。
+// 加载或应用失败时记录日志并保留当前配置。
+type Watcher struct {
+	path     string
+	interval time.Duration
+	apply    func(*Config) error
+	trigger  chan struct{}
+	logger   *slog.Logger
+
+	modTime time.Time
+	size    int64
+	sum     [sha256.Size]byte
+}
+
+// NewWatcher 创建配置监视器，interval 为0时不轮询，只响应 Reload
+func NewWatcher(path string, interval time.Duration, apply func(*Config) error) *Watcher {
+	return &Watcher{
+		path:     path,
+		interval: interval,
+		apply:    apply,
+		trigger:  make(chan struct{}, 1),
+		logger:   slog.Default(),
+	}
+}
+
+// SetLogger 设置日志记录器，需在 Run 之前调用，logger 为nil时使用 slog.Default()
+func (w *Watcher) SetLogger(logger *slog.Logger) {
+	w.logger = logging.OrDefault(logger)
+}
+
+// Reload 请求重新加载配置，不阻塞；重复请求在处理前会被合并
+func (w *Watcher) Reload() {
+	select {
+	case w.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// Run 运行监视循环，直到上下文取消
+func (w *Watcher) Run(ctx context.Context) error {
+	w.snapshot()
+
+	var tick <-chan time.Time
+	if w.path != "" && w.interval > 0 {
+		ticker := time.NewTicker(w.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-w.trigger:
+			w.snapshot()
+			w.reload("收到重新加载请求")
+		case <-tick:
+			if w.changed() {
+				w.reload("配置文件已修改")
+			}
+		}
+	}
+}
+
+// snapshot 记录配置文件的当前状态
+func (w *Watcher) snapshot() {
+	if w.path == "" {
+		return
+	}
+	info, err := os.Stat(w.path)
+	if err != nil {
+		return
+	}
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return
+	}
+	w.modTime, w.size, w.sum = info.ModTime(), info.Size(), sha256.Sum256(data)
+}
+
+// changed 检查配置文件内容是否变化；只修改时间变化而内容相同时不视为变化
+func (w *Watcher) changed() bool {
+	info, err := os.Stat(w.path)
+	if err != nil {
+		// 保存过程中文件可能短暂不存在，等待下一次轮询
+		return false
+	}
+	if info.ModTime().Equal(w.modTime) && info.Size() == w.size {
+		return false
+	}
+
+	data, err := os.ReadFile(w.path)
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256(data)
+	changed := sum != w.sum
+	w.modTime, w.size, w.sum = info.ModTime(), info.Size(), sum
+	return changed
+}
+
+// reload 加载并应用配置
+func (w *Watcher) reload(reason string) {
+	cfg, err := Load(w.path)
+	if err != nil {
+		w.logger.Error("重新加载配置失败，继续使用当前配置", slog.String("path", w.path), logging.Err(err))
+		return
+	}
+	if err := w.apply(cfg); err != nil {
+		w.logger.Error("应用新配置失败，继续使用当前配置", slog.String("path", w.path), logging.Err(err))
+		return
+	}
+	w.logger.Info("配置已重新加载", slog.String("path", w.path), slog.String("reason", reason))
+}
+
+// ChangedTools 返回两份配置间已启用数据源发生变化的工具
+func ChangedTools(old, new *Config) []string {
+	before := enabledSourcesByTool(old)
+	after := enabledSourcesByTool(new)
+
+	var changed []string
+	for _, tool := range sources.KnownTools() {
+		if !reflect.DeepEqual(before[tool], after[tool]) {
+			changed = append(changed, tool)
+		}
+	}
+	return changed
+}
+
+// enabledSourcesByTool 按工具分组已启用的数据源，组内按名称排序
+func enabledSourcesByTool(c *Config) map[string][]sources.Source {
+	byTool := make(map[string][]sources.Source)
+	for _, source := range c.SourceDefinitions() {
+		if !source.Enabled {
+			continue
+		}
+		for _, tool := range source.Tools {
+			byTool[tool] = append(byTool[tool], source)
+		}
+	}
+	for _, list := range byTool {
+		sort.Slice(list, func(i, j int) bool {
+			return list[i].Name < list[j].Name
+		})
+	}
+	return byTool
+}
+
+// RestartRequired 返回两份配置间无法在运行时应用的变化，值为配置节或配置键名称
+//
+// 运行时可应用的配置：sources、tools.builtin_sources、tools.cache_ttl、tools.refresh、cache.ttl、
+// formatter、monitoring.alert_thresholds、monitoring.alerts。
+func RestartRequired(old, new *Config) []string {
+	var keys []string
+	if !reflect.DeepEqual(old.Server, new.Server) {
+		keys = append(keys, "server")
+	}
+
+	oldCache, newCache := old.Cache, new.Cache
+	oldCache.TTL, newCache.TTL = 0, 0
+	if !reflect.DeepEqual(oldCache, newCache) {
+		keys = append(keys, "cache")
+	}
+
+	if old.Processor != new.Processor {
+		keys = append(keys, "processor")
+	}
+	if old.Tools.MaxConcurrency != new.Tools.MaxConcurrency {
+		keys = append(keys, "tools.max_concurrency")
+	}
+
+	oldMonitoring, newMonitoring := old.Monitoring, new.Monitoring
+	oldMonitoring.AlertThresholds, newMonitoring.AlertThresholds = AlertThresholdsConfig{}, AlertThresholdsConfig{}
+	oldMonitoring.Alerts, newMonitoring.Alerts = AlertsConfig{}, AlertsConfig{}
+	if !reflect.DeepEqual(oldMonitoring, newMonitoring) {
+		keys = append(keys, "monitoring")
+	}
+	if !reflect.DeepEqual(old.Tracing, new.Tracing) {
+		keys = append(keys, "tracing")
+	}
+	return keys
+}
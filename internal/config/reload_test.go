@@ -0,0 +1,140 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+)
+
+// writeConfig 先写临时文件再重命名，避免监视器读到写了一半的文件
+func writeConfig(t *testing.T, path, content string) {
+	t.Helper()
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(content), 0644); err != nil {
+		t.Fatalf("WriteFile failed: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		t.Fatalf("Rename failed: %v", err)
+	}
+}
+
+func TestWatcherReloadsOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "cache:\n  ttl: 10m\n")
+
+	applied := make(chan *Config, 4)
+	watcher := NewWatcher(path, 10*time.Millisecond, func(cfg *Config) error {
+		applied <- cfg
+		return nil
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- watcher.Run(ctx) }()
+
+	// 等待监视器记录初始状态后再修改文件
+	time.Sleep(30 * time.Millisecond)
+	writeConfig(t, path, "cache:\n  ttl: 20m\n")
+
+	select {
+	case cfg := <-applied:
+		if cfg.Cache.TTL.Duration() != 20*time.Minute {
+			t.Errorf("Expected reloaded ttl 20m, got %v", cfg.Cache.TTL)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for reload after file change")
+	}
+
+	// 无效配置不会被应用
+	writeConfig(t, path, "cache:\n  ttl: nope\n")
+	select {
+	case cfg := <-applied:
+		t.Errorf("Invalid configuration must not be applied, got ttl %v", cfg.Cache.TTL)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	// 手动触发时即使文件未变化也重新加载
+	writeConfig(t, path, "cache:\n  ttl: 30m\n")
+	watcher.Reload()
+	select {
+	case cfg := <-applied:
+		if cfg.Cache.TTL.Duration() != 30*time.Minute {
+			t.Errorf("Expected reloaded ttl 30m, got %v", cfg.Cache.TTL)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Timed out waiting for manual reload")
+	}
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Expected context.Canceled, got %v", err)
+	}
+}
+
+func TestWatcherIgnoresTouchWithoutContentChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	writeConfig(t, path, "cache:\n  ttl: 10m\n")
+
+	watcher := NewWatcher(path, time.Second, func(*Config) error { return nil })
+	watcher.snapshot()
+
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatalf("Chtimes failed: %v", err)
+	}
+	if watcher.changed() {
+		t.Error("Touching the file without changing content should not trigger a reload")
+	}
+
+	writeConfig(t, path, "cache:\n  ttl: 20m\n")
+	if !watcher.changed() {
+		t.Error("Expected content change to be detected")
+	}
+}
+
+func TestChangedTools(t *testing.T) {
+	old := Default()
+
+	same := Default()
+	same.Formatter.Format = "markdown"
+	same.Tools.CacheTTL.WeeklyNews = Duration(2 * time.Hour)
+	if changed := ChangedTools(old, same); len(changed) != 0 {
+		t.Errorf("Expected no changed tools, got %v", changed)
+	}
+
+	disabled := false
+	next := Default()
+	next.Sources = []SourceConfig{
+		{Name: "dev.to-vue", Enabled: &disabled},
+		{Name: "css-tricks", Tools: []string{sources.ToolWeeklyNews, sources.ToolTopicSearch}, URL: "https://css-tricks.com/feed/"},
+	}
+	changed := ChangedTools(old, next)
+	if got := strings.Join(changed, ","); got != "weekly_news,topic_search" {
+		t.Errorf("Expected weekly_news and topic_search to change, got %s", got)
+	}
+}
+
+func TestRestartRequired(t *testing.T) {
+	old := Default()
+
+	next := Default()
+	next.Cache.TTL = Duration(time.Hour)
+	next.Monitoring.AlertThresholds.LowHitRatePercent = 5
+	next.Monitoring.Alerts.Sinks = []AlertSinkConfig{{Type: "file", Path: "alerts.log"}}
+	next.Formatter.Format = "text"
+	if keys := RestartRequired(old, next); len(keys) != 0 {
+		t.Errorf("Expected hot-reloadable changes only, got %v", keys)
+	}
+
+	next.Server.Addr = ":9999"
+	next.Cache.MaxSize = ByteSize(1 << 20)
+	next.Tools.MaxConcurrency = 3
+	if got := strings.Join(RestartRequired(old, next), ","); got != "server,cache,tools.max_concurrency" {
+		t.Errorf("Unexpected restart-required keys: %s", got)
+	}
+}
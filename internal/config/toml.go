@@ -0,0 +1,23 @@
+package config
+
+import (
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+)
+
+// parseTOML 解析TOML为通用树
+func parseTOML(data []byte) (map[string]interface{}, error) {
+	var raw map[string]interface{}
+	if err := toml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("TOML解析失败: %w", err)
+	}
+	if raw == nil {
+		return map[string]interface{}{}, nil
+	}
+	normalized, err := normalizeTree("", raw)
+	if err != nil {
+		return nil, err
+	}
+	return normalized.(map[string]interface{}), nil
+}
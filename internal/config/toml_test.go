@@ -0,0 +1,99 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTOML(t *testing.T) {
+	input := `
+# comment
+title = "basic \"quoted\" \u00e9"
+literal = 'C:\path'
+count = 1_000
+hex = 0xff
+ratio = 0.25
+enabled = false
+date = 1979-05-27T07:32:00Z
+list = [
+  "a",  # comment inside array
+  "b",
+]
+inline = { x = 1, y.z = "deep" }
+multi = """
+line one
+line two"""
+
+[server]
+"quoted key" = "v"
+nested.child = 2
+
+[[items]]
+name = "first"
+
+[[items]]
+name = "second"
+[items.extra]
+flag = true
+`
+	tree, err := parseTOML([]byte(input))
+	if err != nil {
+		t.Fatalf("parseTOML failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"title":   "basic \"quoted\" é",
+		"literal": `C:\path`,
+		"count":   int64(1000),
+		"hex":     int64(255),
+		"ratio":   0.25,
+		"enabled": false,
+		"date":    "1979-05-27T07:32:00Z",
+		"list":    []interface{}{"a", "b"},
+		"inline": map[string]interface{}{
+			"x": int64(1),
+			"y": map[string]interface{}{"z": "deep"},
+		},
+		"multi": "line one\nline two",
+		"server": map[string]interface{}{
+			"quoted key": "v",
+			"nested":     map[string]interface{}{"child": int64(2)},
+		},
+		"items": []interface{}{
+			map[string]interface{}{"name": "first"},
+			map[string]interface{}{
+				"name":  "second",
+				"extra": map[string]interface{}{"flag": true},
+			},
+		},
+	}
+
+	for key, want := range expected {
+		if got := tree[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %#v, got %#v", key, want, got)
+		}
+	}
+}
+
+func TestParseTOMLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing equals", "a 1\n"},
+		{"duplicate key", "a = 1\na = 2\n"},
+		{"unquoted string", "a = hello\n"},
+		{"unclosed string", "a = \"abc\n"},
+		{"unclosed array", "a = [1, 2\n"},
+		{"trailing content", "a = 1 2\n"},
+		{"table redefines value", "a = 1\n[a]\nb = 2\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseTOML([]byte(tt.input)); err == nil {
+				t.Errorf("Expected error for %q", tt.input)
+			}
+		})
+	}
+}
@@ -0,0 +1,358 @@
+package config
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
+)
+
+// KeyError 指向具体配置键的错误
+type KeyError struct {
+	Key     string
+	Message string
+}
+
+// Error 实现error接口
+func (e *KeyError) Error() string {
+	if e.Key == "" {
+		return e.Message
+	}
+	return fmt.Sprintf("%s: %s", e.Key, e.Message)
+}
+
+// ValidationErrors 配置校验错误集合
+type ValidationErrors []*KeyError
+
+// Error 实现error接口
+func (errs ValidationErrors) Error() string {
+	messages := make([]string, len(errs))
+	for i, err := range errs {
+		messages[i] = err.Error()
+	}
+	return "配置校验失败: " + strings.Join(messages, "; ")
+}
+
+// Validate 校验配置，返回所有不合法的配置键
+func (c *Config) Validate() error {
+	var errs ValidationErrors
+	add := func(key, format string, args ...interface{}) {
+		errs = append(errs, &KeyError{Key: key, Message: fmt.Sprintf(format, args...)})
+	}
+
+	// server
+	if c.Server.Name == "" {
+		add("server.name", "不能为空")
+	}
+	if _, err := parseLogLevel(c.Server.LogLevel); err != nil {
+		add("server.log_level", "必须是 debug、info、warn 或 error，实际为 %q", c.Server.LogLevel)
+	}
+	switch c.Server.LogFormat {
+	case logging.FormatText, logging.FormatJSON:
+	default:
+		add("server.log_format", "必须是 text 或 json，实际为 %q", c.Server.LogFormat)
+	}
+	switch c.Server.Transport {
+	case "stdio", "http", "websocket":
+	default:
+		add("server.transport", "必须是 stdio、http 或 websocket，实际为 %q", c.Server.Transport)
+	}
+	if c.Server.Transport != "stdio" && c.Server.Addr == "" {
+		add("server.addr", "%s 传输需要监听地址", c.Server.Transport)
+	}
+	if c.Server.Admin.Addr != "" && c.Server.Transport != "stdio" && c.Server.Admin.Addr == c.Server.Addr {
+		add("server.admin.addr", "不能与 server.addr 相同")
+	}
+	if c.Server.ReloadInterval < 0 {
+		add("server.reload_interval", "不能为负数")
+	}
+	if c.Server.WebSocket.MaxMessageSize <= 0 {
+		add("server.websocket.max_message_size", "必须大于0")
+	}
+	if c.Server.WebSocket.PingInterval < 0 {
+		add("server.websocket.ping_interval", "不能为负数")
+	}
+	for i, origin := range c.Server.WebSocket.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+			add(fmt.Sprintf("server.websocket.allowed_origins[%d]", i), "无效的来源 %q，应为 scheme://host[:port]", origin)
+		}
+	}
+
+	// cache
+	switch c.Cache.Backend {
+	case "memory", "disk", "redis":
+	default:
+		add("cache.backend", "必须是 memory、disk 或 redis，实际为 %q", c.Cache.Backend)
+	}
+	if c.Cache.Backend == "redis" {
+		if c.Cache.Redis.Addr == "" {
+			add("cache.redis.addr", "不能为空")
+		}
+		if c.Cache.Redis.DB < 0 {
+			add("cache.redis.db", "不能为负数")
+		}
+		if c.Cache.Redis.PoolSize <= 0 {
+			add("cache.redis.pool_size", "必须大于0")
+		}
+		if c.Cache.Redis.DialTimeout < 0 {
+			add("cache.redis.dial_timeout", "不能为负数")
+		}
+		if c.Cache.Redis.IOTimeout < 0 {
+			add("cache.redis.io_timeout", "不能为负数")
+		}
+	}
+	if c.Cache.MaxSize <= 0 {
+		add("cache.max_size", "必须大于0")
+	}
+	if c.Cache.TTL <= 0 {
+		add("cache.ttl", "必须大于0")
+	}
+	if c.Cache.CleanupInterval <= 0 {
+		add("cache.cleanup_interval", "必须大于0")
+	}
+	if c.Cache.Coalescing.Timeout <= 0 {
+		add("cache.coalescing.timeout", "必须大于0")
+	}
+	if c.Cache.Coalescing.CleanupDelay < 0 {
+		add("cache.coalescing.cleanup_delay", "不能为负数")
+	}
+	if c.Cache.Coalescing.Distributed && c.Cache.Backend != "redis" {
+		add("cache.coalescing.distributed", "需要 redis 缓存后端")
+	}
+	if c.Cache.Coalescing.LockTTL <= 0 {
+		add("cache.coalescing.lock_ttl", "必须大于0")
+	}
+	if c.Cache.Concurrency.MaxConcurrency <= 0 {
+		add("cache.concurrency.max_concurrency", "必须大于0")
+	}
+	if c.Cache.Concurrency.WorkerPoolSize <= 0 {
+		add("cache.concurrency.worker_pool_size", "必须大于0")
+	}
+	if c.Cache.Concurrency.QueueSize <= 0 {
+		add("cache.concurrency.queue_size", "必须大于0")
+	}
+	if c.Cache.Concurrency.RateLimit < 0 {
+		add("cache.concurrency.rate_limit", "不能为负数")
+	}
+	if c.Cache.Concurrency.BurstLimit <= 0 {
+		add("cache.concurrency.burst_limit", "必须大于0")
+	}
+
+	// processor
+	if c.Processor.MaxSummaryLength <= 0 {
+		add("processor.max_summary_length", "必须大于0")
+	}
+	if c.Processor.ProcessingTimeout <= 0 {
+		add("processor.processing_timeout", "必须大于0")
+	}
+	if c.Processor.MaxConcurrency <= 0 {
+		add("processor.max_concurrency", "必须大于0")
+	}
+	if enrichment := c.Processor.Enrichment; enrichment.Enabled {
+		if enrichment.MinContentLength <= 0 {
+			add("processor.enrichment.min_content_length", "必须大于0")
+		}
+		if enrichment.MaxConcurrency <= 0 {
+			add("processor.enrichment.max_concurrency", "必须大于0")
+		}
+		if enrichment.DomainDelay < 0 {
+			add("processor.enrichment.domain_delay", "不能为负数")
+		}
+		if enrichment.Timeout <= 0 {
+			add("processor.enrichment.timeout", "必须大于0")
+		}
+		if enrichment.CacheTTL <= 0 {
+			add("processor.enrichment.cache_ttl", "必须大于0")
+		}
+		if enrichment.MaxPageSize <= 0 {
+			add("processor.enrichment.max_page_size", "必须大于0")
+		}
+	}
+
+	// formatter
+	switch c.Formatter.Format {
+	case "json", "markdown", "text":
+	default:
+		add("formatter.format", "必须是 json、markdown 或 text，实际为 %q", c.Formatter.Format)
+	}
+	if c.Formatter.MaxSummaryLength < 0 {
+		add("formatter.max_summary_length", "不能为负数")
+	}
+	switch c.Formatter.SortOrder {
+	case "asc", "desc":
+	default:
+		add("formatter.sort_order", "必须是 asc 或 desc，实际为 %q", c.Formatter.SortOrder)
+	}
+
+	// tools
+	if c.Tools.MaxConcurrency <= 0 {
+		add("tools.max_concurrency", "必须大于0")
+	}
+	ttls := c.ToolCacheTTLs()
+	for _, tool := range sources.KnownTools() {
+		if ttls[tool] <= 0 {
+			add("tools.cache_ttl."+tool, "必须大于0")
+		}
+	}
+	staleTTLs := c.ToolStaleTTLs()
+	for _, tool := range sources.KnownTools() {
+		if staleTTLs[tool] < 0 {
+			add("tools.stale_ttl."+tool, "不能为负数")
+		}
+	}
+
+	refresh := c.Tools.Refresh
+	if refresh.Ahead < 0 {
+		add("tools.refresh.ahead", "不能为负数")
+	}
+	if refresh.Jitter < 0 || refresh.Jitter > 1 {
+		add("tools.refresh.jitter", "必须在0到1之间")
+	}
+	if refresh.MaxBackoff <= 0 {
+		add("tools.refresh.max_backoff", "必须大于0")
+	}
+	if _, _, err := parseActiveHours(refresh.ActiveHours); err != nil {
+		add("tools.refresh.active_hours", "%v", err)
+	}
+	for i, timeRange := range refresh.TrendingTimeRanges {
+		switch timeRange {
+		case "daily", "weekly", "monthly":
+		default:
+			add(fmt.Sprintf("tools.refresh.trending_time_ranges[%d]", i), "必须是 daily、weekly 或 monthly，实际为 %q", timeRange)
+		}
+	}
+
+	// monitoring
+	if c.Monitoring.MetricsInterval <= 0 {
+		add("monitoring.metrics_interval", "必须大于0")
+	}
+	if c.Monitoring.HealthCheckInterval <= 0 {
+		add("monitoring.health_check_interval", "必须大于0")
+	}
+	thresholds := c.Monitoring.AlertThresholds
+	if thresholds.LowHitRatePercent < 0 || thresholds.LowHitRatePercent > 100 {
+		add("monitoring.alert_thresholds.low_hit_rate_percent", "必须在0到100之间")
+	}
+	if thresholds.HighMemoryUsagePercent < 0 || thresholds.HighMemoryUsagePercent > 100 {
+		add("monitoring.alert_thresholds.high_memory_usage_percent", "必须在0到100之间")
+	}
+	if thresholds.MaxResponseTimeMs < 0 {
+		add("monitoring.alert_thresholds.max_response_time_ms", "不能为负数")
+	}
+	if thresholds.MaxErrorCount < 0 {
+		add("monitoring.alert_thresholds.max_error_count", "不能为负数")
+	}
+	if thresholds.MaxSlowOperations < 0 {
+		add("monitoring.alert_thresholds.max_slow_operations", "不能为负数")
+	}
+	if thresholds.HealthCheckTimeout < 0 {
+		add("monitoring.alert_thresholds.health_check_timeout", "不能为负数")
+	}
+	alerts := c.Monitoring.Alerts
+	if alerts.DedupWindow < 0 {
+		add("monitoring.alerts.dedup_window", "不能为负数")
+	}
+	if alerts.RateLimit < 0 {
+		add("monitoring.alerts.rate_limit", "不能为负数")
+	}
+	if alerts.RateLimit > 0 && alerts.RatePeriod <= 0 {
+		add("monitoring.alerts.rate_period", "设置 rate_limit 时必须大于0")
+	}
+	if alerts.Timeout <= 0 {
+		add("monitoring.alerts.timeout", "必须大于0")
+	}
+	for i, sink := range alerts.Sinks {
+		key := fmt.Sprintf("monitoring.alerts.sinks[%d]", i)
+		switch sink.Type {
+		case cache.AlertSinkWebhook, cache.AlertSinkSlack:
+			if u, err := url.Parse(sink.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+				add(key+".url", "无效的URL %q", sink.URL)
+			}
+		case cache.AlertSinkFile:
+			if sink.Path == "" {
+				add(key+".path", "file 类型需要文件路径")
+			}
+		case cache.AlertSinkSyslog:
+		default:
+			add(key+".type", "必须是 webhook、slack、file 或 syslog，实际为 %q", sink.Type)
+		}
+	}
+
+	// tracing
+	switch c.Tracing.Exporter {
+	case tracing.ExporterNone, tracing.ExporterOTLP:
+	default:
+		add("tracing.exporter", "必须是 none 或 otlp，实际为 %q", c.Tracing.Exporter)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		add("tracing.sample_ratio", "必须在0到1之间")
+	}
+
+	// sources：按合并后的定义校验，内置数据源的局部覆盖无需重复填写URL等字段
+	definitions := make(map[string]sources.Source)
+	for _, source := range c.SourceDefinitions() {
+		definitions[source.Name] = source
+	}
+	names := make(map[string]bool)
+	for i, sc := range c.Sources {
+		key := fmt.Sprintf("sources[%d]", i)
+		if sc.Name == "" {
+			add(key+".name", "不能为空")
+			continue
+		}
+		if names[sc.Name] {
+			add(key+".name", "数据源名称 %q 重复", sc.Name)
+			continue
+		}
+		names[sc.Name] = true
+
+		source := definitions[sc.Name]
+		if u, err := url.Parse(source.Config.URL); err != nil || u.Scheme == "" || u.Host == "" {
+			add(key+".url", "无效的URL %q", source.Config.URL)
+		}
+		switch sc.Type {
+		case "", "rss", "api", "html", "reddit", "stackexchange", "hackernews", "github_releases", "npm":
+		default:
+			add(key+".type", "必须是 rss、api、html、reddit、stackexchange、hackernews、github_releases 或 npm，实际为 %q", sc.Type)
+		}
+		switch source.Kind {
+		case "", sources.KindArticles, sources.KindRepositories, sources.KindDiscussions:
+		default:
+			add(key+".kind", "必须是 articles、repositories 或 discussions，实际为 %q", source.Kind)
+		}
+		if len(source.Tools) == 0 {
+			add(key+".tools", "至少需要指定一个工具")
+		}
+		for _, tool := range source.Tools {
+			if !sources.IsKnownTool(tool) {
+				add(key+".tools", "未知的工具 %q（可选: %s）", tool, strings.Join(sources.KnownTools(), ", "))
+			}
+		}
+		if source.Weight < 0 {
+			add(key+".weight", "不能为负数")
+		}
+		if sc.Selector != nil {
+			if err := sc.Selector.Validate(); err != nil {
+				add(key+".selector", "%v", err)
+			}
+		}
+		if sc.Timeout < 0 {
+			add(key+".timeout", "不能为负数")
+		}
+		if sc.MaxArticles < 0 {
+			add(key+".max_articles", "不能为负数")
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+	return nil
+}
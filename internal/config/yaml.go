@@ -0,0 +1,27 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// parseYAML 解析YAML为通用树
+func parseYAML(data []byte) (map[string]interface{}, error) {
+	var raw interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("YAML解析失败: %w", err)
+	}
+	if raw == nil {
+		return map[string]interface{}{}, nil
+	}
+	normalized, err := normalizeTree("", raw)
+	if err != nil {
+		return nil, err
+	}
+	tree, ok := normalized.(map[string]interface{})
+	if !ok {
+		return nil, fmt.Errorf("YAML解析失败: 顶层必须是映射")
+	}
+	return tree, nil
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestParseYAML(t *testing.T) {
+	input := `
+# comment
+name: "quoted: value"   # trailing comment
+single: 'it''s'
+count: 42
+ratio: 0.5
+enabled: true
+empty: ~
+url: https://example.com/feed?a=1#frag
+inline_list: [a, "b c", 3]
+inline_map: {x: 1, y: two}
+nested:
+  child:
+    leaf: value
+list:
+  - one
+  - two
+objects:
+  - name: first
+    tags:
+      - x
+  - name: second
+same_indent:
+- p
+- q
+literal: |
+  line one
+  line two
+folded: >-
+  folded
+  text
+`
+	tree, err := parseYAML([]byte(input))
+	if err != nil {
+		t.Fatalf("parseYAML failed: %v", err)
+	}
+
+	expected := map[string]interface{}{
+		"name":        "quoted: value",
+		"single":      "it's",
+		"count":       int64(42),
+		"ratio":       0.5,
+		"enabled":     true,
+		"empty":       nil,
+		"url":         "https://example.com/feed?a=1#frag",
+		"inline_list": []interface{}{"a", "b c", int64(3)},
+		"inline_map":  map[string]interface{}{"x": int64(1), "y": "two"},
+		"nested": map[string]interface{}{
+			"child": map[string]interface{}{"leaf": "value"},
+		},
+		"list": []interface{}{"one", "two"},
+		"objects": []interface{}{
+			map[string]interface{}{"name": "first", "tags": []interface{}{"x"}},
+			map[string]interface{}{"name": "second"},
+		},
+		"same_indent": []interface{}{"p", "q"},
+		"literal":     "line one\nline two\n",
+		"folded":      "folded text",
+	}
+
+	for key, want := range expected {
+		if got := tree[key]; !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: expected %#v, got %#v", key, want, got)
+		}
+	}
+}
+
+func TestParseYAMLErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"tab indent", "a:\n\tb: 1\n"},
+		{"bad indent", "a:\n    b: 1\n  c: 2\n"},
+		{"duplicate key", "a: 1\na: 2\n"},
+		{"not a mapping", "just text\n"},
+		{"unclosed flow", "a: [1, 2\n"},
+		{"top level list", "- a\n- b\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseYAML([]byte(tt.input)); err == nil {
+				t.Errorf("Expected error for %q", tt.input)
+			}
+		})
+	}
+}
+
+func TestParseYAMLMultilineFlowAndAnchors(t *testing.T) {
+	input := `
+defaults: &defaults
+  type: rss
+  weight: 0.8
+sources:
+  - <<: *defaults
+    name: a
+    tools: [weekly_news,
+      topic_search]
+`
+	tree, err := parseYAML([]byte(input))
+	if err != nil {
+		t.Fatalf("parseYAML failed: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"type":   "rss",
+		"weight": 0.8,
+		"name":   "a",
+		"tools":  []interface{}{"weekly_news", "topic_search"},
+	}
+	got := tree["sources"].([]interface{})[0]
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %#v, got %#v", want, got)
+	}
+}
+
+func TestParseYAMLIntegerOverflow(t *testing.T) {
+	tree, err := parseYAML([]byte("cache:\n  max: 9223372036854775807\n"))
+	if err != nil {
+		t.Fatalf("Expected MaxInt64 to parse, got %v", err)
+	}
+	if max := tree["cache"].(map[string]interface{})["max"]; max != int64(math.MaxInt64) {
+		t.Errorf("Expected int64 MaxInt64, got %T %v", max, max)
+	}
+
+	_, err = parseYAML([]byte("cache:\n  sizes: [1, 9223372036854775808]\n"))
+	var keyErr *KeyError
+	if !errors.As(err, &keyErr) || keyErr.Key != "cache.sizes[1]" {
+		t.Errorf("Expected KeyError for cache.sizes[1], got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package formatter
 
 import (
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ZephyrDeng/dev-context/internal/models"
@@ -80,8 +81,10 @@ type Formatter interface {
 	GetSupportedFormats() []OutputFormat
 }
 
-// FormatterFactory creates formatters based on configuration
+// FormatterFactory creates formatters based on configuration.
+// It is safe for concurrent use; the configuration can be replaced at runtime.
 type FormatterFactory struct {
+	mu     sync.RWMutex
 	config *Config
 }
 
@@ -95,31 +98,47 @@ func NewFormatterFactory(config *Config) *FormatterFactory {
 
 // CreateFormatter creates a formatter based on the configured format
 func (ff *FormatterFactory) CreateFormatter() (Formatter, error) {
-	switch ff.config.Format {
+	config := ff.GetConfig()
+	return NewFormatter(&config), nil
+}
+
+// NewFormatter creates a formatter for the format in the given configuration,
+// falling back to JSON for unknown formats
+func NewFormatter(config *Config) Formatter {
+	switch config.Format {
 	case FormatJSON:
-		return NewJSONFormatter(ff.config), nil
+		return NewJSONFormatter(config)
 	case FormatMarkdown:
-		return NewMarkdownFormatter(ff.config), nil
+		return NewMarkdownFormatter(config)
 	case FormatText:
-		return NewTextFormatter(ff.config), nil
+		return NewTextFormatter(config)
 	default:
-		return NewJSONFormatter(ff.config), nil
+		return NewJSONFormatter(config)
 	}
 }
 
 // SetFormat updates the output format in the configuration
 func (ff *FormatterFactory) SetFormat(format OutputFormat) {
-	ff.config.Format = format
+	ff.mu.Lock()
+	defer ff.mu.Unlock()
+
+	config := *ff.config
+	config.Format = format
+	ff.config = &config
 }
 
 // GetConfig returns a copy of the current configuration
 func (ff *FormatterFactory) GetConfig() Config {
+	ff.mu.RLock()
+	defer ff.mu.RUnlock()
 	return *ff.config
 }
 
 // UpdateConfig updates the factory configuration
 func (ff *FormatterFactory) UpdateConfig(config *Config) {
 	if config != nil {
+		ff.mu.Lock()
+		defer ff.mu.Unlock()
 		ff.config = config
 	}
 }
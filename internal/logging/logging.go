@@ -0,0 +1,212 @@
+// Package logging 提供各组件共用的结构化日志
+//
+// 缓存、采集、处理和工具层通过注入的 *slog.Logger 记录日志，属性使用本包定义的统一键名，
+// 便于按工具、数据源或缓存键检索。输出支持文本和JSON两种格式；MCPHandler 还会将日志
+// 以 notifications/message 转发给设置了日志级别的 MCP 客户端。
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// 各组件共用的属性键
+const (
+	KeyTool     = "tool"
+	KeySource   = "source"
+	KeyCacheKey = "cache_key"
+	KeyDuration = "duration"
+	KeyError    = "error"
+)
+
+// 日志输出格式
+const (
+	FormatText = "text"
+	FormatJSON = "json"
+)
+
+// NewHandler 创建按 format 输出到 w 的日志处理器，未知格式按文本输出
+func NewHandler(w io.Writer, format string, level slog.Leveler) slog.Handler {
+	options := &slog.HandlerOptions{Level: level}
+	if format == FormatJSON {
+		return slog.NewJSONHandler(w, options)
+	}
+	return slog.NewTextHandler(w, options)
+}
+
+// OrDefault 在 logger 为nil时返回 slog.Default()
+func OrDefault(logger *slog.Logger) *slog.Logger {
+	if logger == nil {
+		return slog.Default()
+	}
+	return logger
+}
+
+// Tool 工具名属性
+func Tool(name string) slog.Attr {
+	return slog.String(KeyTool, name)
+}
+
+// Source 数据源属性
+func Source(name string) slog.Attr {
+	return slog.String(KeySource, name)
+}
+
+// CacheKey 缓存键属性
+func CacheKey(key string) slog.Attr {
+	return slog.String(KeyCacheKey, key)
+}
+
+// Duration 耗时属性
+func Duration(d time.Duration) slog.Attr {
+	return slog.Duration(KeyDuration, d)
+}
+
+// Err 错误属性，err 为nil时返回空属性，输出时会被忽略
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.Attr{}
+	}
+	return slog.Any(KeyError, err)
+}
+
+// notifyTimeout 单条日志通知的发送时间上限，避免阻塞的连接拖住记录日志的调用方
+const notifyTimeout = 5 * time.Second
+
+type sessionKey struct{}
+
+// WithSession 将发起请求的 MCP 会话放入 ctx，之后以该 ctx 记录的日志只转发给这个会话
+func WithSession(ctx context.Context, session *mcp.ServerSession) context.Context {
+	if session == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, sessionKey{}, session)
+}
+
+func sessionFrom(ctx context.Context) *mcp.ServerSession {
+	session, _ := ctx.Value(sessionKey{}).(*mcp.ServerSession)
+	return session
+}
+
+// MCPHandler 将日志交给下一个处理器输出，同时以 notifications/message 转发给 MCP 客户端
+//
+// ctx 中带有会话（见 WithSession）的记录只发给该会话，定时刷新、告警等与请求无关的记录
+// 发给所有会话。是否记录由下一个处理器的级别决定，客户端通过 logging/setLevel
+// 设置级别后只收到不低于该级别的日志，未设置时不发送。
+type MCPHandler struct {
+	next   slog.Handler
+	server *mcp.Server
+	name   string
+
+	// 转发的日志以JSON对象作为 data，format 与 buf 共享同一个锁
+	mu     *sync.Mutex
+	buf    *bytes.Buffer
+	format slog.Handler
+}
+
+// NewMCPHandler 创建转发到 server 所有会话的日志处理器，name 作为通知中的 logger 字段
+func NewMCPHandler(next slog.Handler, server *mcp.Server, name string) *MCPHandler {
+	buf := new(bytes.Buffer)
+	return &MCPHandler{
+		next:   next,
+		server: server,
+		name:   name,
+		mu:     new(sync.Mutex),
+		buf:    buf,
+		format: slog.NewJSONHandler(buf, &slog.HandlerOptions{
+			Level: slog.LevelDebug,
+			ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
+				// 级别已在通知的 level 字段中
+				if len(groups) == 0 && a.Key == slog.LevelKey {
+					return slog.Attr{}
+				}
+				return a
+			},
+		}),
+	}
+}
+
+// Enabled 实现 slog.Handler
+func (h *MCPHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return h.next.Enabled(ctx, level)
+}
+
+// Handle 实现 slog.Handler，转发失败不影响本地输出
+func (h *MCPHandler) Handle(ctx context.Context, r slog.Record) error {
+	err := h.next.Handle(ctx, r)
+	h.forward(ctx, r)
+	return err
+}
+
+// WithAttrs 实现 slog.Handler
+func (h *MCPHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithAttrs(attrs)
+	h2.format = h.format.WithAttrs(attrs)
+	return &h2
+}
+
+// WithGroup 实现 slog.Handler
+func (h *MCPHandler) WithGroup(name string) slog.Handler {
+	h2 := *h
+	h2.next = h.next.WithGroup(name)
+	h2.format = h.format.WithGroup(name)
+	return &h2
+}
+
+func (h *MCPHandler) forward(ctx context.Context, r slog.Record) {
+	var sessions []*mcp.ServerSession
+	if session := sessionFrom(ctx); session != nil {
+		sessions = append(sessions, session)
+	} else if h.server != nil {
+		for session := range h.server.Sessions() {
+			sessions = append(sessions, session)
+		}
+	}
+	if len(sessions) == 0 {
+		return
+	}
+
+	h.mu.Lock()
+	h.buf.Reset()
+	err := h.format.Handle(ctx, r)
+	data := bytes.Clone(bytes.TrimSpace(h.buf.Bytes()))
+	h.mu.Unlock()
+	if err != nil {
+		return
+	}
+
+	params := &mcp.LoggingMessageParams{
+		Logger: h.name,
+		Level:  mcpLevel(r.Level),
+		Data:   json.RawMessage(data),
+	}
+	// 请求超时或取消后记录的错误同样需要送达，发送时间另行限制
+	sendCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), notifyTimeout)
+	defer cancel()
+	for _, session := range sessions {
+		// 会话可能已关闭，发送失败时不再记录日志以免循环
+		session.Log(sendCtx, params)
+	}
+}
+
+// mcpLevel 将 slog 级别映射为 MCP 日志级别
+func mcpLevel(level slog.Level) mcp.LoggingLevel {
+	switch {
+	case level < slog.LevelInfo:
+		return "debug"
+	case level < slog.LevelWarn:
+		return "info"
+	case level < slog.LevelError:
+		return "warning"
+	default:
+		return "error"
+	}
+}
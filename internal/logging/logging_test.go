@@ -0,0 +1,138 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestNewHandlerJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewHandler(&buf, FormatJSON, slog.LevelInfo))
+
+	logger.Debug("ignored")
+	logger.Warn("collect failed", Tool("topic_search"), Source("github"), Duration(time.Second), Err(errors.New("timeout")), Err(nil))
+
+	var record map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+		t.Fatalf("Expected one JSON record, got %q: %v", buf.String(), err)
+	}
+	if record["msg"] != "collect failed" || record["level"] != "WARN" {
+		t.Errorf("Unexpected record: %v", record)
+	}
+	if record[KeyTool] != "topic_search" || record[KeySource] != "github" || record[KeyError] != "timeout" {
+		t.Errorf("Expected tool, source and error attributes, got %v", record)
+	}
+	if _, ok := record[KeyDuration]; !ok {
+		t.Errorf("Expected duration attribute, got %v", record)
+	}
+}
+
+// connect 连接一个内存中的客户端，返回服务端会话和客户端收到的日志通知
+func connect(t *testing.T, server *mcp.Server, level mcp.LoggingLevel) (*mcp.ServerSession, <-chan *mcp.LoggingMessageParams) {
+	t.Helper()
+	ctx := context.Background()
+	messages := make(chan *mcp.LoggingMessageParams, 10)
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{
+		LoggingMessageHandler: func(_ context.Context, req *mcp.LoggingMessageRequest) {
+			messages <- req.Params
+		},
+	})
+
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	session, err := server.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatalf("Server connect failed: %v", err)
+	}
+	clientSession, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatalf("Client connect failed: %v", err)
+	}
+	t.Cleanup(func() { clientSession.Close() })
+
+	if level != "" {
+		if err := clientSession.SetLoggingLevel(ctx, &mcp.SetLoggingLevelParams{Level: level}); err != nil {
+			t.Fatalf("SetLoggingLevel failed: %v", err)
+		}
+	}
+	return session, messages
+}
+
+func receive(t *testing.T, messages <-chan *mcp.LoggingMessageParams) *mcp.LoggingMessageParams {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		return msg
+	case <-time.After(2 * time.Second):
+		t.Fatal("Expected a log notification")
+		return nil
+	}
+}
+
+func expectNone(t *testing.T, messages <-chan *mcp.LoggingMessageParams) {
+	t.Helper()
+	select {
+	case msg := <-messages:
+		t.Errorf("Expected no log notification, got %+v", msg)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestMCPHandlerForwardsToCallingSession(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "server"}, nil)
+	caller, callerMessages := connect(t, server, "info")
+	_, otherMessages := connect(t, server, "info")
+
+	var local bytes.Buffer
+	logger := slog.New(NewMCPHandler(NewHandler(&local, FormatText, slog.LevelInfo), server, "dev-context"))
+	ctx := WithSession(context.Background(), caller)
+
+	logger.With(Tool("weekly_news")).WarnContext(ctx, "source failed", Source("dev.to"))
+
+	msg := receive(t, callerMessages)
+	if msg.Level != "warning" || msg.Logger != "dev-context" {
+		t.Errorf("Unexpected notification: %+v", msg)
+	}
+	var data map[string]any
+	raw, _ := json.Marshal(msg.Data)
+	if err := json.Unmarshal(raw, &data); err != nil {
+		t.Fatalf("Expected JSON object data, got %v", msg.Data)
+	}
+	if data["msg"] != "source failed" || data[KeyTool] != "weekly_news" || data[KeySource] != "dev.to" {
+		t.Errorf("Unexpected data: %v", data)
+	}
+	if _, ok := data["level"]; ok {
+		t.Errorf("Level should only appear in the notification, got %v", data)
+	}
+	expectNone(t, otherMessages)
+
+	if !bytes.Contains(local.Bytes(), []byte("source failed")) {
+		t.Errorf("Expected record in local output, got %q", local.String())
+	}
+}
+
+func TestMCPHandlerBroadcastsBackgroundRecords(t *testing.T) {
+	server := mcp.NewServer(&mcp.Implementation{Name: "server"}, nil)
+	_, infoMessages := connect(t, server, "info")
+	_, errorMessages := connect(t, server, "error")
+	_, silentMessages := connect(t, server, "")
+
+	var local bytes.Buffer
+	logger := slog.New(NewMCPHandler(NewHandler(&local, FormatText, slog.LevelInfo), server, "dev-context"))
+
+	logger.Info("refresh finished")
+	logger.Debug("below server level")
+
+	if msg := receive(t, infoMessages); msg.Level != "info" {
+		t.Errorf("Expected info notification, got %+v", msg)
+	}
+	expectNone(t, infoMessages)
+	expectNone(t, errorMessages)
+	expectNone(t, silentMessages)
+}
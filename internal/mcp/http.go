@@ -0,0 +1,87 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+)
+
+// shutdownTimeout bounds how long in-flight HTTP requests may take to finish
+// once the server context is cancelled.
+const shutdownTimeout = 5 * time.Second
+
+// HTTPHandler returns an http.Handler serving the MCP streamable HTTP protocol.
+// Every session created through the handler shares this server's tools and
+// therefore the same underlying cache and collectors.
+func (s *Server) HTTPHandler() http.Handler {
+	return mcp.NewStreamableHTTPHandler(func(*http.Request) *mcp.Server {
+		return s.server
+	}, nil)
+}
+
+// RunHTTP serves the streamable HTTP transport on addr until ctx is cancelled
+func (s *Server) RunHTTP(ctx context.Context, addr string) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.ServeStreamableHTTP(ctx, listener)
+}
+
+// ServeStreamableHTTP serves the streamable HTTP transport on an existing listener.
+// When ctx is cancelled the server stops accepting connections, waits up to
+// shutdownTimeout for in-flight requests and then closes remaining streams.
+func (s *Server) ServeStreamableHTTP(ctx context.Context, listener net.Listener) error {
+	s.logger().Info("Starting MCP server",
+		slog.String("name", s.config.Name),
+		slog.String("version", s.config.Version),
+		slog.String("url", "http://"+listener.Addr().String()),
+	)
+	return s.serve(ctx, listener, s.HTTPHandler())
+}
+
+// serve runs handler on listener until ctx is cancelled and then shuts down
+// the HTTP server and every MCP session that is still open.
+func (s *Server) serve(ctx context.Context, listener net.Listener, handler http.Handler) error {
+	httpServer := &http.Server{
+		Handler: handler,
+		// Sessions and SSE streams are bound to the request context, so
+		// deriving it from ctx lets cancellation reach long-lived streams.
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- httpServer.Serve(listener)
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return err
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := httpServer.Shutdown(shutdownCtx); err != nil {
+		s.logger().Warn("HTTP server shutdown timed out, closing connections", logging.Err(err))
+		httpServer.Close()
+	}
+	<-errCh
+
+	for session := range s.server.Sessions() {
+		session.Close()
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,68 @@
+package mcp
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestServeStreamableHTTP(t *testing.T) {
+	server := NewServer(nil)
+	if err := server.AddBasicCapabilities(); err != nil {
+		t.Fatalf("AddBasicCapabilities failed: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.ServeStreamableHTTP(ctx, listener)
+	}()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	transport := mcp.NewStreamableClientTransport("http://"+listener.Addr().String(), nil)
+
+	clientCtx, clientCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer clientCancel()
+
+	session, err := client.Connect(clientCtx, transport, nil)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	if session.ID() == "" {
+		t.Error("Expected a session ID from the HTTP transport")
+	}
+
+	result, err := session.CallTool(clientCtx, &mcp.CallToolParams{
+		Name:      "echo",
+		Arguments: map[string]any{"message": "hello"},
+	})
+	if err != nil {
+		t.Fatalf("CallTool failed: %v", err)
+	}
+	if len(result.Content) != 1 {
+		t.Fatalf("Expected 1 content item, got %d", len(result.Content))
+	}
+	text, ok := result.Content[0].(*mcp.TextContent)
+	if !ok || text.Text != "Echo: hello" {
+		t.Errorf("Unexpected echo result: %#v", result.Content[0])
+	}
+	session.Close()
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled after shutdown, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("ServeStreamableHTTP did not return after context cancellation")
+	}
+}
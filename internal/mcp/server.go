@@ -3,10 +3,11 @@ package mcp
 
 import (
 	"context"
-	"log"
 	"log/slog"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
 )
 
 // Config represents the configuration for the MCP server
@@ -15,6 +16,7 @@ type Config struct {
 	Version     string
 	Description string
 	LogLevel    slog.Level
+	Logger      *slog.Logger // nil logs to slog.Default()
 }
 
 // DefaultConfig returns a default configuration for the MCP server
@@ -69,9 +71,15 @@ func (s *Server) GetConfig() *Config {
 	return s.config
 }
 
+// logger returns the configured logger, resolving slog.Default() at call time
+// so a default installed after NewServer is still picked up.
+func (s *Server) logger() *slog.Logger {
+	return logging.OrDefault(s.config.Logger)
+}
+
 // Run starts the server with the specified transport
 func (s *Server) Run(ctx context.Context, transport mcp.Transport) error {
-	log.Printf("Starting MCP server %s %s", s.config.Name, s.config.Version)
+	s.logger().Info("Starting MCP server", slog.String("name", s.config.Name), slog.String("version", s.config.Version))
 	return s.server.Run(ctx, transport)
 }
 
@@ -85,7 +93,7 @@ func (s *Server) RunStdio(ctx context.Context) error {
 func (s *Server) Close() error {
 	// The MCP SDK server doesn't expose a Close method yet,
 	// but we prepare for graceful shutdown here
-	log.Printf("Shutting down MCP server %s", s.config.Name)
+	s.logger().Info("Shutting down MCP server", slog.String("name", s.config.Name))
 	return nil
 }
 
@@ -107,6 +115,6 @@ func (s *Server) AddBasicCapabilities() error {
 		}, nil, nil
 	})
 
-	log.Printf("Basic server capabilities initialized for %s", s.config.Name)
+	s.logger().Debug("Basic server capabilities initialized", slog.String("name", s.config.Name))
 	return nil
 }
@@ -0,0 +1,677 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/modelcontextprotocol/go-sdk/jsonrpc"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+)
+
+// websocketGUID is the magic value from RFC 6455 used to derive Sec-WebSocket-Accept
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// websocketSubprotocol is advertised when the client asks for it
+const websocketSubprotocol = "mcp"
+
+// WebSocket opcodes (RFC 6455 section 5.2)
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// WebSocket close codes (RFC 6455 section 7.4.1)
+const (
+	wsCloseNormal          = 1000
+	wsCloseGoingAway       = 1001
+	wsCloseProtocolError   = 1002
+	wsCloseUnsupportedData = 1003
+	wsCloseInvalidPayload  = 1007
+	wsCloseMessageTooBig   = 1009
+)
+
+// ErrWebSocketMessageTooLarge is returned when a peer sends a message above MaxMessageSize
+var ErrWebSocketMessageTooLarge = errors.New("websocket: message exceeds size limit")
+
+// errWebSocketClosed is returned by Read and Write once the connection is closed
+var errWebSocketClosed = errors.New("websocket: connection closed")
+
+// errWebSocketProtocol wraps frames that violate RFC 6455 framing rules
+var errWebSocketProtocol = errors.New("websocket: protocol error")
+
+// errWebSocketInvalidUTF8 is returned when a text message or close reason is not valid UTF-8
+var errWebSocketInvalidUTF8 = errors.New("websocket: invalid UTF-8 in text frame")
+
+// WebSocketConfig configures the WebSocket transport
+type WebSocketConfig struct {
+	// MaxMessageSize limits the size of a single (reassembled) message in bytes
+	MaxMessageSize int64
+	// PingInterval is how often a ping is sent to the peer; zero disables keepalive
+	PingInterval time.Duration
+	// PongTimeout is how long to wait past PingInterval for any frame before
+	// the connection is considered dead
+	PongTimeout time.Duration
+	// WriteTimeout bounds a single frame write
+	WriteTimeout time.Duration
+	// AllowedOrigins lists origins (scheme://host[:port]) that may open a
+	// connection in addition to the server's own host; "*" allows any origin
+	AllowedOrigins []string
+	// CheckOrigin validates the Origin header of upgrade requests; nil uses
+	// sameOriginOrAllowed with AllowedOrigins
+	CheckOrigin func(r *http.Request) bool
+}
+
+// DefaultWebSocketConfig returns the default WebSocket transport configuration
+func DefaultWebSocketConfig() *WebSocketConfig {
+	return &WebSocketConfig{
+		MaxMessageSize: 4 << 20,
+		PingInterval:   30 * time.Second,
+		PongTimeout:    10 * time.Second,
+		WriteTimeout:   10 * time.Second,
+	}
+}
+
+// WebSocketTransport is a server-side mcp.Transport for a single upgraded connection.
+// Each accepted WebSocket connection gets its own transport and therefore its own session.
+type WebSocketTransport struct {
+	conn *wsConn
+}
+
+// Connect implements mcp.Transport
+func (t *WebSocketTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	if t.conn == nil {
+		return nil, errors.New("websocket: transport has no connection")
+	}
+	return t.conn, nil
+}
+
+// WebSocketClientTransport is a client-side mcp.Transport that dials a WebSocket endpoint
+type WebSocketClientTransport struct {
+	URL    string
+	Config *WebSocketConfig
+}
+
+// Connect implements mcp.Transport by performing the client handshake
+func (t *WebSocketClientTransport) Connect(ctx context.Context) (mcp.Connection, error) {
+	config := t.Config
+	if config == nil {
+		config = DefaultWebSocketConfig()
+	}
+	return dialWebSocket(ctx, t.URL, config)
+}
+
+// WebSocketHandler returns an http.Handler that upgrades requests to WebSocket
+// and serves one MCP session per connection
+func (s *Server) WebSocketHandler(config *WebSocketConfig) http.Handler {
+	if config == nil {
+		config = DefaultWebSocketConfig()
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgradeWebSocket(w, r, config)
+		if err != nil {
+			s.logger().Warn("WebSocket upgrade failed", slog.String("remote_addr", r.RemoteAddr), logging.Err(err))
+			return
+		}
+
+		session, err := s.server.Connect(r.Context(), &WebSocketTransport{conn: conn}, nil)
+		if err != nil {
+			s.logger().Warn("WebSocket session setup failed", slog.String("remote_addr", r.RemoteAddr), logging.Err(err))
+			conn.closeWithCode(wsCloseProtocolError, "session setup failed")
+			return
+		}
+
+		// Hijacked connections are not tracked by http.Server.Shutdown, so
+		// close the session ourselves when the server context ends.
+		done := make(chan struct{})
+		go func() {
+			select {
+			case <-r.Context().Done():
+				conn.closeWithCode(wsCloseGoingAway, "server shutting down")
+			case <-done:
+			}
+		}()
+		session.Wait()
+		close(done)
+	})
+}
+
+// RunWebSocket serves the WebSocket transport on addr until ctx is cancelled
+func (s *Server) RunWebSocket(ctx context.Context, addr string, config *WebSocketConfig) error {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return err
+	}
+	return s.ServeWebSocket(ctx, listener, config)
+}
+
+// ServeWebSocket serves the WebSocket transport on an existing listener
+func (s *Server) ServeWebSocket(ctx context.Context, listener net.Listener, config *WebSocketConfig) error {
+	s.logger().Info("Starting MCP server",
+		slog.String("name", s.config.Name),
+		slog.String("version", s.config.Version),
+		slog.String("url", "ws://"+listener.Addr().String()),
+	)
+	return s.serve(ctx, listener, s.WebSocketHandler(config))
+}
+
+// wsConn is a message-oriented WebSocket connection implementing mcp.Connection
+type wsConn struct {
+	conn     net.Conn
+	reader   *bufio.Reader
+	config   *WebSocketConfig
+	isClient bool // clients mask outgoing frames, servers require masked input
+
+	writeMu sync.Mutex
+
+	incoming chan wsMessage
+	closed   chan struct{}
+	readDone chan struct{}
+
+	closeOnce     sync.Once
+	closeSentOnce sync.Once
+}
+
+type wsMessage struct {
+	data []byte
+	err  error
+}
+
+func newWSConn(conn net.Conn, reader *bufio.Reader, config *WebSocketConfig, isClient bool) *wsConn {
+	c := &wsConn{
+		conn:     conn,
+		reader:   reader,
+		config:   config,
+		isClient: isClient,
+		incoming: make(chan wsMessage),
+		closed:   make(chan struct{}),
+		readDone: make(chan struct{}),
+	}
+	go c.readLoop()
+	if config.PingInterval > 0 {
+		go c.pingLoop()
+	}
+	return c
+}
+
+// Read implements mcp.Connection
+func (c *wsConn) Read(ctx context.Context) (jsonrpc.Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-c.closed:
+		return nil, io.EOF
+	case msg, ok := <-c.incoming:
+		if !ok {
+			return nil, io.EOF
+		}
+		if msg.err != nil {
+			return nil, msg.err
+		}
+		return jsonrpc.DecodeMessage(msg.data)
+	}
+}
+
+// Write implements mcp.Connection
+func (c *wsConn) Write(ctx context.Context, msg jsonrpc.Message) error {
+	data, err := jsonrpc.EncodeMessage(msg)
+	if err != nil {
+		return err
+	}
+	select {
+	case <-c.closed:
+		return errWebSocketClosed
+	default:
+	}
+	return c.writeFrame(wsOpText, data)
+}
+
+// Close implements mcp.Connection with a normal closure
+func (c *wsConn) Close() error {
+	c.closeWithCode(wsCloseNormal, "")
+	return nil
+}
+
+// SessionID implements mcp.Connection; WebSocket sessions are identified by the connection itself
+func (c *wsConn) SessionID() string {
+	return ""
+}
+
+// closeWithCode sends a close frame, waits briefly for the peer to answer and
+// then tears down the underlying connection
+func (c *wsConn) closeWithCode(code int, reason string) {
+	c.closeOnce.Do(func() {
+		close(c.closed)
+		c.sendClose(code, reason)
+
+		select {
+		case <-c.readDone:
+		case <-time.After(time.Second):
+		}
+		c.conn.Close()
+	})
+}
+
+func (c *wsConn) sendClose(code int, reason string) {
+	c.closeSentOnce.Do(func() {
+		payload := make([]byte, 2+len(reason))
+		binary.BigEndian.PutUint16(payload, uint16(code))
+		copy(payload[2:], reason)
+		c.writeFrame(wsOpClose, payload)
+	})
+}
+
+// readLoop reads frames, answers control frames and delivers complete messages
+func (c *wsConn) readLoop() {
+	defer close(c.readDone)
+	defer close(c.incoming)
+
+	var (
+		message       []byte
+		messageOpcode byte
+		inFrag        bool
+	)
+	for {
+		c.extendReadDeadline()
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			// Only errors caused by the peer's frames are answered with a close
+			// code; a dead or reset connection is simply dropped.
+			var netErr net.Error
+			switch {
+			case errors.Is(err, ErrWebSocketMessageTooLarge):
+				c.sendClose(wsCloseMessageTooBig, "message too big")
+			case errors.Is(err, errWebSocketProtocol):
+				c.sendClose(wsCloseProtocolError, "")
+			case errors.As(err, &netErr) && netErr.Timeout():
+				c.sendClose(wsCloseGoingAway, "keepalive timeout")
+			}
+			c.deliver(wsMessage{err: err})
+			go c.closeWithCode(wsCloseNormal, "")
+			return
+		}
+
+		switch opcode {
+		case wsOpPing:
+			c.writeFrame(wsOpPong, payload)
+		case wsOpPong:
+			// any frame refreshes the read deadline, nothing else to do
+		case wsOpClose:
+			code := wsCloseNormal
+			switch {
+			case len(payload) == 1:
+				code = wsCloseProtocolError
+			case len(payload) >= 2:
+				code = int(binary.BigEndian.Uint16(payload))
+				if !utf8.Valid(payload[2:]) {
+					code = wsCloseInvalidPayload
+				}
+			}
+			c.sendClose(code, "")
+			go c.closeWithCode(code, "")
+			return
+		case wsOpText, wsOpBinary, wsOpContinuation:
+			if opcode == wsOpContinuation && !inFrag {
+				c.sendClose(wsCloseProtocolError, "unexpected continuation frame")
+				go c.closeWithCode(wsCloseProtocolError, "")
+				return
+			}
+			if opcode != wsOpContinuation && inFrag {
+				c.sendClose(wsCloseProtocolError, "expected continuation frame")
+				go c.closeWithCode(wsCloseProtocolError, "")
+				return
+			}
+			if c.config.MaxMessageSize > 0 && int64(len(message)+len(payload)) > c.config.MaxMessageSize {
+				c.sendClose(wsCloseMessageTooBig, "message too big")
+				c.deliver(wsMessage{err: ErrWebSocketMessageTooLarge})
+				go c.closeWithCode(wsCloseMessageTooBig, "")
+				return
+			}
+			if opcode != wsOpContinuation {
+				messageOpcode = opcode
+			}
+			message = append(message, payload...)
+			inFrag = !fin
+			if fin {
+				// Text messages must be valid UTF-8 (RFC 6455 section 8.1)
+				if messageOpcode == wsOpText && !utf8.Valid(message) {
+					c.sendClose(wsCloseInvalidPayload, "invalid UTF-8")
+					c.deliver(wsMessage{err: errWebSocketInvalidUTF8})
+					go c.closeWithCode(wsCloseInvalidPayload, "")
+					return
+				}
+				if !c.deliver(wsMessage{data: message}) {
+					return
+				}
+				message = nil
+			}
+		default:
+			c.sendClose(wsCloseUnsupportedData, "unsupported opcode")
+			go c.closeWithCode(wsCloseUnsupportedData, "")
+			return
+		}
+	}
+}
+
+// deliver hands a message to Read, giving up if the connection is closed
+func (c *wsConn) deliver(msg wsMessage) bool {
+	select {
+	case c.incoming <- msg:
+		return true
+	case <-c.closed:
+		return false
+	}
+}
+
+// pingLoop sends periodic pings; a missing peer is detected by the read deadline
+func (c *wsConn) pingLoop() {
+	ticker := time.NewTicker(c.config.PingInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.closed:
+			return
+		case <-ticker.C:
+			if err := c.writeFrame(wsOpPing, nil); err != nil {
+				go c.closeWithCode(wsCloseGoingAway, "")
+				return
+			}
+		}
+	}
+}
+
+func (c *wsConn) extendReadDeadline() {
+	if c.config.PingInterval > 0 {
+		c.conn.SetReadDeadline(time.Now().Add(c.config.PingInterval + c.config.PongTimeout))
+	}
+}
+
+// readFrame reads a single frame and returns its unmasked payload
+func (c *wsConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(c.reader, header[:]); err != nil {
+		return
+	}
+	fin = header[0]&0x80 != 0
+	if header[0]&0x70 != 0 {
+		err = fmt.Errorf("%w: reserved bits set", errWebSocketProtocol)
+		return
+	}
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := int64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(c.reader, ext[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(c.reader, ext[:]); err != nil {
+			return
+		}
+		length = int64(binary.BigEndian.Uint64(ext[:]))
+	}
+
+	if opcode >= wsOpClose && (length > 125 || !fin) {
+		err = fmt.Errorf("%w: invalid control frame", errWebSocketProtocol)
+		return
+	}
+	if !c.isClient && !masked {
+		err = fmt.Errorf("%w: client frame is not masked", errWebSocketProtocol)
+		return
+	}
+	if length < 0 || (c.config.MaxMessageSize > 0 && length > c.config.MaxMessageSize) {
+		err = ErrWebSocketMessageTooLarge
+		return
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.reader, mask[:]); err != nil {
+			return
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(c.reader, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+// writeFrame writes a single unfragmented frame
+func (c *wsConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	header := make([]byte, 0, 14)
+	header = append(header, 0x80|opcode)
+
+	maskBit := byte(0)
+	if c.isClient {
+		maskBit = 0x80
+	}
+	length := len(payload)
+	switch {
+	case length <= 125:
+		header = append(header, maskBit|byte(length))
+	case length <= 0xFFFF:
+		header = append(header, maskBit|126)
+		header = binary.BigEndian.AppendUint16(header, uint16(length))
+	default:
+		header = append(header, maskBit|127)
+		header = binary.BigEndian.AppendUint64(header, uint64(length))
+	}
+
+	data := payload
+	if c.isClient {
+		var mask [4]byte
+		if _, err := rand.Read(mask[:]); err != nil {
+			return err
+		}
+		header = append(header, mask[:]...)
+		data = make([]byte, length)
+		for i := range payload {
+			data[i] = payload[i] ^ mask[i%4]
+		}
+	}
+
+	if c.config.WriteTimeout > 0 {
+		c.conn.SetWriteDeadline(time.Now().Add(c.config.WriteTimeout))
+	}
+	if _, err := c.conn.Write(append(header, data...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// computeAcceptKey derives Sec-WebSocket-Accept from Sec-WebSocket-Key
+func computeAcceptKey(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// headerContainsToken reports whether a comma separated header contains token
+func headerContainsToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, part := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(part), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sameOriginOrAllowed accepts requests without an Origin header (non-browser
+// clients), requests whose Origin matches the Host they were sent to, and
+// origins listed in allowed. Rejecting everything else prevents arbitrary web
+// pages from opening a session in the user's browser and calling tools.
+func sameOriginOrAllowed(r *http.Request, allowed []string) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+	for _, a := range allowed {
+		if a == "*" || strings.EqualFold(strings.TrimSuffix(a, "/"), origin) {
+			return true
+		}
+	}
+	u, err := url.Parse(origin)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return strings.EqualFold(u.Host, r.Host)
+}
+
+// upgradeWebSocket performs the server side of the opening handshake
+func upgradeWebSocket(w http.ResponseWriter, r *http.Request, config *WebSocketConfig) (*wsConn, error) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return nil, fmt.Errorf("unexpected method %s", r.Method)
+	}
+	if !headerContainsToken(r.Header, "Connection", "upgrade") || !headerContainsToken(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return nil, errors.New("missing websocket upgrade headers")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusBadRequest)
+		return nil, errors.New("unsupported websocket version")
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing Sec-WebSocket-Key", http.StatusBadRequest)
+		return nil, errors.New("missing Sec-WebSocket-Key")
+	}
+	checkOrigin := config.CheckOrigin
+	if checkOrigin == nil {
+		checkOrigin = func(r *http.Request) bool { return sameOriginOrAllowed(r, config.AllowedOrigins) }
+	}
+	if !checkOrigin(r) {
+		http.Error(w, "origin not allowed", http.StatusForbidden)
+		return nil, fmt.Errorf("origin %q not allowed", r.Header.Get("Origin"))
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, errors.New("response writer does not support hijacking")
+	}
+	netConn, rw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, err
+	}
+
+	response := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + computeAcceptKey(key) + "\r\n"
+	if headerContainsToken(r.Header, "Sec-WebSocket-Protocol", websocketSubprotocol) {
+		response += "Sec-WebSocket-Protocol: " + websocketSubprotocol + "\r\n"
+	}
+	response += "\r\n"
+
+	// Hijacked connections keep any deadline set by http.Server, so clear them
+	netConn.SetDeadline(time.Time{})
+	if _, err := netConn.Write([]byte(response)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	return newWSConn(netConn, rw.Reader, config, false), nil
+}
+
+// dialWebSocket performs the client side of the opening handshake
+func dialWebSocket(ctx context.Context, rawURL string, config *WebSocketConfig) (*wsConn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "ws", "http":
+	default:
+		return nil, fmt.Errorf("unsupported websocket scheme %q", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		host = net.JoinHostPort(u.Hostname(), "80")
+	}
+
+	var dialer net.Dialer
+	netConn, err := dialer.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		netConn.SetDeadline(deadline)
+	}
+
+	var keyBytes [16]byte
+	if _, err := rand.Read(keyBytes[:]); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(keyBytes[:])
+
+	path := u.RequestURI()
+	request := "GET " + path + " HTTP/1.1\r\n" +
+		"Host: " + u.Host + "\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Key: " + key + "\r\n" +
+		"Sec-WebSocket-Version: 13\r\n" +
+		"Sec-WebSocket-Protocol: " + websocketSubprotocol + "\r\n\r\n"
+	if _, err := netConn.Write([]byte(request)); err != nil {
+		netConn.Close()
+		return nil, err
+	}
+
+	reader := bufio.NewReader(netConn)
+	resp, err := http.ReadResponse(reader, &http.Request{Method: http.MethodGet})
+	if err != nil {
+		netConn.Close()
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		netConn.Close()
+		return nil, fmt.Errorf("websocket handshake failed: %s", resp.Status)
+	}
+	if resp.Header.Get("Sec-WebSocket-Accept") != computeAcceptKey(key) {
+		netConn.Close()
+		return nil, errors.New("websocket handshake failed: invalid Sec-WebSocket-Accept")
+	}
+
+	netConn.SetDeadline(time.Time{})
+	return newWSConn(netConn, reader, config, true), nil
+}
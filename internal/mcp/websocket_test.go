@@ -0,0 +1,332 @@
+package mcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestComputeAcceptKey(t *testing.T) {
+	// Example from RFC 6455 section 1.3
+	got := computeAcceptKey("dGhlIHNhbXBsZSBub25jZQ==")
+	want := "s3pPLMBiTxaQ9kYGzzhZRbK+xOo="
+	if got != want {
+		t.Errorf("Expected accept key %s, got %s", want, got)
+	}
+}
+
+func TestServeWebSocket(t *testing.T) {
+	server := NewServer(nil)
+	if err := server.AddBasicCapabilities(); err != nil {
+		t.Fatalf("AddBasicCapabilities failed: %v", err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Listen failed: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- server.ServeWebSocket(ctx, listener, nil)
+	}()
+
+	clientCtx, clientCancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer clientCancel()
+
+	// Two clients get independent sessions over their own connections
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	var sessions []*mcp.ClientSession
+	for i := 0; i < 2; i++ {
+		transport := &WebSocketClientTransport{URL: "ws://" + listener.Addr().String() + "/"}
+		session, err := client.Connect(clientCtx, transport, nil)
+		if err != nil {
+			t.Fatalf("Connect failed: %v", err)
+		}
+		sessions = append(sessions, session)
+	}
+
+	for _, session := range sessions {
+		result, err := session.CallTool(clientCtx, &mcp.CallToolParams{
+			Name:      "echo",
+			Arguments: map[string]any{"message": "ws"},
+		})
+		if err != nil {
+			t.Fatalf("CallTool failed: %v", err)
+		}
+		text, ok := result.Content[0].(*mcp.TextContent)
+		if !ok || text.Text != "Echo: ws" {
+			t.Errorf("Unexpected echo result: %#v", result.Content[0])
+		}
+	}
+
+	// Closing one client must not affect the other
+	sessions[0].Close()
+	if _, err := sessions[1].CallTool(clientCtx, &mcp.CallToolParams{
+		Name:      "echo",
+		Arguments: map[string]any{"message": "still here"},
+	}); err != nil {
+		t.Fatalf("CallTool after closing other session failed: %v", err)
+	}
+
+	cancel()
+	select {
+	case err := <-done:
+		if err != context.Canceled {
+			t.Errorf("Expected context.Canceled after shutdown, got %v", err)
+		}
+	case <-time.After(10 * time.Second):
+		t.Fatal("ServeWebSocket did not return after context cancellation")
+	}
+
+	// The server closes remaining connections on shutdown
+	waitDone := make(chan error, 1)
+	go func() { waitDone <- sessions[1].Wait() }()
+	select {
+	case <-waitDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Client session was not closed on server shutdown")
+	}
+}
+
+func TestWebSocketMessageSizeLimit(t *testing.T) {
+	server := NewServer(nil)
+	if err := server.AddBasicCapabilities(); err != nil {
+		t.Fatalf("AddBasicCapabilities failed: %v", err)
+	}
+
+	config := DefaultWebSocketConfig()
+	config.MaxMessageSize = 1024
+	ts := httptest.NewServer(server.WebSocketHandler(config))
+	defer ts.Close()
+
+	clientCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "v0.0.1"}, nil)
+	session, err := client.Connect(clientCtx, &WebSocketClientTransport{URL: ts.URL}, nil)
+	if err != nil {
+		t.Fatalf("Connect failed: %v", err)
+	}
+	defer session.Close()
+
+	_, err = session.CallTool(clientCtx, &mcp.CallToolParams{
+		Name:      "echo",
+		Arguments: map[string]any{"message": strings.Repeat("x", 4096)},
+	})
+	if err == nil {
+		t.Fatal("Expected oversized message to be rejected")
+	}
+}
+
+func TestWebSocketHandlerRejectsPlainHTTP(t *testing.T) {
+	server := NewServer(nil)
+	ts := httptest.NewServer(server.WebSocketHandler(nil))
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL)
+	if err != nil {
+		t.Fatalf("GET failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != 426 {
+		t.Errorf("Expected status 426, got %d", resp.StatusCode)
+	}
+}
+
+func TestWebSocketOriginCheck(t *testing.T) {
+	tests := []struct {
+		name    string
+		origin  string
+		allowed []string
+		want    bool
+	}{
+		{"no origin", "", nil, true},
+		{"same origin", "http://example.com:8080", nil, true},
+		{"cross origin", "http://evil.example", nil, false},
+		{"allow-listed", "https://app.example.com", []string{"https://app.example.com/"}, true},
+		{"wildcard", "http://evil.example", []string{"*"}, true},
+		{"malformed", "null", nil, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "http://example.com:8080/", nil)
+			if tt.origin != "" {
+				r.Header.Set("Origin", tt.origin)
+			}
+			if got := sameOriginOrAllowed(r, tt.allowed); got != tt.want {
+				t.Errorf("sameOriginOrAllowed(%q) = %v, want %v", tt.origin, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWebSocketHandlerRejectsCrossOrigin(t *testing.T) {
+	server := NewServer(nil)
+	ts := httptest.NewServer(server.WebSocketHandler(nil))
+	defer ts.Close()
+
+	req, _ := http.NewRequest("GET", ts.URL, nil)
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	req.Header.Set("Sec-WebSocket-Key", "dGhlIHNhbXBsZSBub25jZQ==")
+	req.Header.Set("Origin", "http://evil.example")
+	resp, err := ts.Client().Do(req)
+	if err != nil {
+		t.Fatalf("request failed: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusForbidden {
+		t.Errorf("Expected status 403, got %d", resp.StatusCode)
+	}
+}
+
+// newRawWebSocketPair returns a server-side wsConn and the raw client end of the pipe
+func newRawWebSocketPair(t *testing.T, config *WebSocketConfig) (*wsConn, net.Conn) {
+	serverSide, clientSide := net.Pipe()
+	conn := newWSConn(serverSide, bufio.NewReader(serverSide), config, false)
+	t.Cleanup(func() {
+		clientSide.Close()
+		conn.conn.Close()
+	})
+	return conn, clientSide
+}
+
+// maskedFrame encodes a single client frame with a fixed mask
+func maskedFrame(opcode byte, fin bool, payload []byte) []byte {
+	first := opcode
+	if fin {
+		first |= 0x80
+	}
+	mask := [4]byte{1, 2, 3, 4}
+	frame := []byte{first, 0x80 | byte(len(payload))}
+	frame = append(frame, mask[:]...)
+	for i, b := range payload {
+		frame = append(frame, b^mask[i%4])
+	}
+	return frame
+}
+
+// readCloseCode reads server frames, skipping pings, until a close frame arrives
+func readCloseCode(t *testing.T, conn net.Conn) int {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+	for {
+		var header [2]byte
+		if _, err := io.ReadFull(conn, header[:]); err != nil {
+			t.Fatalf("Expected a close frame, got %v", err)
+		}
+		payload := make([]byte, header[1]&0x7F)
+		if _, err := io.ReadFull(conn, payload); err != nil {
+			t.Fatalf("Reading frame payload failed: %v", err)
+		}
+		if header[0]&0x0F != wsOpClose {
+			continue
+		}
+		if len(payload) < 2 {
+			t.Fatalf("Expected close code, got payload %v", payload)
+		}
+		return int(binary.BigEndian.Uint16(payload))
+	}
+}
+
+func TestWebSocketInvalidUTF8(t *testing.T) {
+	config := DefaultWebSocketConfig()
+	config.PingInterval = 0
+
+	tests := []struct {
+		name   string
+		frames [][]byte
+	}{
+		{"text frame", [][]byte{maskedFrame(wsOpText, true, []byte{'{', 0xff, '}'})}},
+		{"fragmented text", [][]byte{
+			maskedFrame(wsOpText, false, []byte("{\"a\":\"")),
+			maskedFrame(wsOpContinuation, true, []byte{0xc3, '"', '}'}),
+		}},
+		{"close reason", [][]byte{maskedFrame(wsOpClose, true, []byte{0x03, 0xe8, 0xff})}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			conn, client := newRawWebSocketPair(t, config)
+			go func() {
+				for _, frame := range tt.frames {
+					if _, err := client.Write(frame); err != nil {
+						return
+					}
+				}
+			}()
+			go conn.Read(context.Background())
+
+			if code := readCloseCode(t, client); code != wsCloseInvalidPayload {
+				t.Errorf("Expected close code %d, got %d", wsCloseInvalidPayload, code)
+			}
+		})
+	}
+}
+
+func TestWebSocketValidUTF8AcrossFragments(t *testing.T) {
+	config := DefaultWebSocketConfig()
+	config.PingInterval = 0
+	conn, client := newRawWebSocketPair(t, config)
+
+	// "é" split between two frames is valid once the message is reassembled
+	go func() {
+		client.Write(maskedFrame(wsOpText, false, []byte(`{"jsonrpc":"2.0","method":"ping","params":{"x":"`+"\xc3")))
+		client.Write(maskedFrame(wsOpContinuation, true, []byte("\xa9"+`"}}`)))
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if _, err := conn.Read(ctx); err != nil {
+		t.Fatalf("Expected message to be delivered, got %v", err)
+	}
+}
+
+func TestWebSocketKeepaliveTimeout(t *testing.T) {
+	config := DefaultWebSocketConfig()
+	config.PingInterval = 50 * time.Millisecond
+	config.PongTimeout = 50 * time.Millisecond
+	conn, client := newRawWebSocketPair(t, config)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	readErr := make(chan error, 1)
+	go func() {
+		_, err := conn.Read(ctx)
+		readErr <- err
+	}()
+
+	// The client never answers, so the read deadline expires
+	if code := readCloseCode(t, client); code != wsCloseGoingAway {
+		t.Errorf("Expected close code %d, got %d", wsCloseGoingAway, code)
+	}
+	if err := <-readErr; err == nil {
+		t.Error("Expected Read to fail after keepalive timeout")
+	}
+}
+
+func TestWebSocketProtocolError(t *testing.T) {
+	config := DefaultWebSocketConfig()
+	config.PingInterval = 0
+	conn, client := newRawWebSocketPair(t, config)
+
+	// Client frames must be masked
+	go client.Write([]byte{0x80 | wsOpText, 2, '{', '}'})
+	go conn.Read(context.Background())
+
+	if code := readCloseCode(t, client); code != wsCloseProtocolError {
+		t.Errorf("Expected close code %d, got %d", wsCloseProtocolError, code)
+	}
+}
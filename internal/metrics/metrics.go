@@ -0,0 +1,404 @@
+// Package metrics 以 Prometheus 文本格式和 OpenMetrics 格式暴露指标，不依赖外部客户端库
+//
+// 各组件通过 CounterVec、HistogramVec 记录指标并注册到 Default，
+// 或实现 Collector 在抓取时按当前状态输出指标。
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DefBuckets 默认的耗时直方图区间（秒），覆盖从缓存命中到多数据源采集的耗时
+var DefBuckets = []float64{0.001, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+// Label 指标标签
+type Label struct {
+	Name  string
+	Value string
+}
+
+// Sample 计数器或仪表盘的一个样本
+type Sample struct {
+	Labels []Label
+	Value  float64
+}
+
+// HistogramSample 直方图的一个样本，Counts 为各区间的累计数量，与 Buckets 一一对应
+type HistogramSample struct {
+	Labels  []Label
+	Buckets []float64
+	Counts  []uint64
+	Count   uint64
+	Sum     float64
+}
+
+// Collector 在抓取时输出指标
+type Collector interface {
+	Collect(w *Writer)
+}
+
+// CollectorFunc 将函数转换为 Collector
+type CollectorFunc func(w *Writer)
+
+// Collect 实现 Collector
+func (f CollectorFunc) Collect(w *Writer) {
+	f(w)
+}
+
+// Writer 按指标族输出 Prometheus 文本格式，OpenMetrics 模式下计数器族名去掉 _total 后缀
+type Writer struct {
+	w           *bufio.Writer
+	openMetrics bool
+	err         error
+}
+
+// NewWriter 创建指标输出器，输出结束后需调用 Flush
+func NewWriter(w io.Writer, openMetrics bool) *Writer {
+	return &Writer{w: bufio.NewWriter(w), openMetrics: openMetrics}
+}
+
+// Counter 输出计数器族，name 应以 _total 结尾
+func (w *Writer) Counter(name, help string, samples ...Sample) {
+	family := name
+	if w.openMetrics {
+		family = strings.TrimSuffix(name, "_total")
+	}
+	w.header(family, help, "counter")
+	for _, s := range samples {
+		w.sample(name, s.Labels, s.Value)
+	}
+}
+
+// Gauge 输出仪表盘族
+func (w *Writer) Gauge(name, help string, samples ...Sample) {
+	w.header(name, help, "gauge")
+	for _, s := range samples {
+		w.sample(name, s.Labels, s.Value)
+	}
+}
+
+// Histogram 输出直方图族
+func (w *Writer) Histogram(name, help string, samples ...HistogramSample) {
+	w.header(name, help, "histogram")
+	for _, s := range samples {
+		for i, bound := range s.Buckets {
+			w.sample(name+"_bucket", withLabel(s.Labels, "le", formatFloat(bound)), float64(s.Counts[i]))
+		}
+		w.sample(name+"_bucket", withLabel(s.Labels, "le", "+Inf"), float64(s.Count))
+		w.sample(name+"_sum", s.Labels, s.Sum)
+		w.sample(name+"_count", s.Labels, float64(s.Count))
+	}
+}
+
+// Flush 写出缓冲的内容，OpenMetrics 模式下追加结束标记
+func (w *Writer) Flush() error {
+	if w.openMetrics {
+		w.printf("# EOF\n")
+	}
+	if w.err != nil {
+		return w.err
+	}
+	return w.w.Flush()
+}
+
+func (w *Writer) header(name, help, typ string) {
+	w.printf("# HELP %s %s\n", name, escapeHelp(help))
+	w.printf("# TYPE %s %s\n", name, typ)
+}
+
+func (w *Writer) sample(name string, labels []Label, value float64) {
+	if len(labels) == 0 {
+		w.printf("%s %s\n", name, formatFloat(value))
+		return
+	}
+	parts := make([]string, len(labels))
+	for i, l := range labels {
+		parts[i] = l.Name + `="` + escapeLabel(l.Value) + `"`
+	}
+	w.printf("%s{%s} %s\n", name, strings.Join(parts, ","), formatFloat(value))
+}
+
+func (w *Writer) printf(format string, args ...interface{}) {
+	if w.err != nil {
+		return
+	}
+	_, w.err = fmt.Fprintf(w.w, format, args...)
+}
+
+// withLabel 返回追加了一个标签的新标签列表
+func withLabel(labels []Label, name, value string) []Label {
+	out := make([]Label, len(labels), len(labels)+1)
+	copy(out, labels)
+	return append(out, Label{Name: name, Value: value})
+}
+
+func formatFloat(v float64) string {
+	switch {
+	case math.IsInf(v, 1):
+		return "+Inf"
+	case math.IsInf(v, -1):
+		return "-Inf"
+	case math.IsNaN(v):
+		return "NaN"
+	}
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpEscaper  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelEscaper = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string  { return helpEscaper.Replace(s) }
+func escapeLabel(s string) string { return labelEscaper.Replace(s) }
+
+// Registry 一组按注册顺序输出的 Collector
+type Registry struct {
+	mu         sync.RWMutex
+	collectors []Collector
+}
+
+// NewRegistry 创建空的注册表
+func NewRegistry() *Registry {
+	return &Registry{}
+}
+
+// Default 进程内共享的注册表，采集器和工具层的指标注册在这里
+var Default = NewRegistry()
+
+// Register 注册 Collector
+func (r *Registry) Register(collectors ...Collector) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.collectors = append(r.collectors, collectors...)
+}
+
+// Collect 实现 Collector，依次输出所有注册的指标
+func (r *Registry) Collect(w *Writer) {
+	r.mu.RLock()
+	collectors := append([]Collector(nil), r.collectors...)
+	r.mu.RUnlock()
+
+	for _, c := range collectors {
+		c.Collect(w)
+	}
+}
+
+// 抓取响应的内容类型
+const (
+	ContentTypeText        = "text/plain; version=0.0.4; charset=utf-8"
+	ContentTypeOpenMetrics = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+)
+
+// Handler 返回输出指定 Collector 的 HTTP 处理器，
+// 请求的 Accept 包含 application/openmetrics-text 时使用 OpenMetrics 格式
+func Handler(collectors ...Collector) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		openMetrics := strings.Contains(r.Header.Get("Accept"), "application/openmetrics-text")
+		if openMetrics {
+			w.Header().Set("Content-Type", ContentTypeOpenMetrics)
+		} else {
+			w.Header().Set("Content-Type", ContentTypeText)
+		}
+
+		mw := NewWriter(w, openMetrics)
+		for _, c := range collectors {
+			c.Collect(mw)
+		}
+		mw.Flush()
+	})
+}
+
+// vec 按标签值分组的指标，键为以 \xff 连接的标签值
+type vec struct {
+	name   string
+	help   string
+	labels []string
+}
+
+func (v *vec) key(values []string) string {
+	if len(values) != len(v.labels) {
+		panic(fmt.Sprintf("metrics: %s 需要 %d 个标签值，实际为 %d", v.name, len(v.labels), len(values)))
+	}
+	return strings.Join(values, "\xff")
+}
+
+func (v *vec) labelsFor(key string) []Label {
+	if len(v.labels) == 0 {
+		return nil
+	}
+	values := strings.Split(key, "\xff")
+	labels := make([]Label, len(v.labels))
+	for i, name := range v.labels {
+		labels[i] = Label{Name: name, Value: values[i]}
+	}
+	return labels
+}
+
+// CounterVec 带标签的计数器
+type CounterVec struct {
+	vec
+	mu     sync.Mutex
+	values map[string]float64
+}
+
+// NewCounterVec 创建带标签的计数器，name 应以 _total 结尾
+func NewCounterVec(name, help string, labels ...string) *CounterVec {
+	return &CounterVec{
+		vec:    vec{name: name, help: help, labels: labels},
+		values: make(map[string]float64),
+	}
+}
+
+// Inc 将对应标签值的计数加一
+func (c *CounterVec) Inc(values ...string) {
+	c.Add(1, values...)
+}
+
+// Add 将对应标签值的计数增加 delta
+func (c *CounterVec) Add(delta float64, values ...string) {
+	key := c.key(values)
+	c.mu.Lock()
+	c.values[key] += delta
+	c.mu.Unlock()
+}
+
+// Value 返回对应标签值的当前计数
+func (c *CounterVec) Value(values ...string) float64 {
+	key := c.key(values)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.values[key]
+}
+
+// Collect 实现 Collector
+func (c *CounterVec) Collect(w *Writer) {
+	c.mu.Lock()
+	keys := sortedKeys(c.values)
+	samples := make([]Sample, len(keys))
+	for i, key := range keys {
+		samples[i] = Sample{Labels: c.labelsFor(key), Value: c.values[key]}
+	}
+	c.mu.Unlock()
+
+	w.Counter(c.name, c.help, samples...)
+}
+
+// Histogram 累计区间计数的直方图
+type Histogram struct {
+	mu      sync.Mutex
+	buckets []float64
+	counts  []uint64
+	count   uint64
+	sum     float64
+}
+
+// NewHistogram 创建直方图，buckets 为升序的区间上界
+func NewHistogram(buckets []float64) *Histogram {
+	return &Histogram{
+		buckets: append([]float64(nil), buckets...),
+		counts:  make([]uint64, len(buckets)),
+	}
+}
+
+// Observe 记录一个观测值
+func (h *Histogram) Observe(v float64) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	for i, bound := range h.buckets {
+		if v <= bound {
+			h.counts[i]++
+		}
+	}
+	h.count++
+	h.sum += v
+}
+
+// Snapshot 返回当前状态
+func (h *Histogram) Snapshot(labels ...Label) HistogramSample {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	return HistogramSample{
+		Labels:  labels,
+		Buckets: h.buckets,
+		Counts:  append([]uint64(nil), h.counts...),
+		Count:   h.count,
+		Sum:     h.sum,
+	}
+}
+
+// Reset 清空所有观测值
+func (h *Histogram) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.counts = make([]uint64, len(h.buckets))
+	h.count = 0
+	h.sum = 0
+}
+
+// HistogramVec 带标签的直方图
+type HistogramVec struct {
+	vec
+	buckets    []float64
+	mu         sync.Mutex
+	histograms map[string]*Histogram
+}
+
+// NewHistogramVec 创建带标签的直方图，buckets 为nil时使用 DefBuckets
+func NewHistogramVec(name, help string, buckets []float64, labels ...string) *HistogramVec {
+	if buckets == nil {
+		buckets = DefBuckets
+	}
+	return &HistogramVec{
+		vec:        vec{name: name, help: help, labels: labels},
+		buckets:    buckets,
+		histograms: make(map[string]*Histogram),
+	}
+}
+
+// Observe 记录对应标签值的一个观测值
+func (h *HistogramVec) Observe(v float64, values ...string) {
+	key := h.key(values)
+	h.mu.Lock()
+	histogram, exists := h.histograms[key]
+	if !exists {
+		histogram = NewHistogram(h.buckets)
+		h.histograms[key] = histogram
+	}
+	h.mu.Unlock()
+
+	histogram.Observe(v)
+}
+
+// Collect 实现 Collector
+func (h *HistogramVec) Collect(w *Writer) {
+	h.mu.Lock()
+	keys := sortedKeys(h.histograms)
+	samples := make([]HistogramSample, len(keys))
+	for i, key := range keys {
+		samples[i] = h.histograms[key].Snapshot(h.labelsFor(key)...)
+	}
+	h.mu.Unlock()
+
+	w.Histogram(h.name, h.help, samples...)
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,99 @@
+package metrics
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCounterVecText(t *testing.T) {
+	counter := NewCounterVec("requests_total", "Requests.", "source", "status")
+	counter.Inc("dev.to", "ok")
+	counter.Add(2, "dev.to", "ok")
+	counter.Inc(`a"b\c`, "error")
+
+	if got := counter.Value("dev.to", "ok"); got != 3 {
+		t.Errorf("Expected 3, got %v", got)
+	}
+
+	var buf strings.Builder
+	w := NewWriter(&buf, false)
+	counter.Collect(w)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+
+	want := "# HELP requests_total Requests.\n" +
+		"# TYPE requests_total counter\n" +
+		`requests_total{source="a\"b\\c",status="error"} 1` + "\n" +
+		`requests_total{source="dev.to",status="ok"} 3` + "\n"
+	if buf.String() != want {
+		t.Errorf("Unexpected output:\n%s\nwant:\n%s", buf.String(), want)
+	}
+}
+
+func TestHistogramVecOpenMetrics(t *testing.T) {
+	histogram := NewHistogramVec("duration_seconds", "Duration.", []float64{0.1, 1}, "tool")
+	histogram.Observe(0.05, "weekly_news")
+	histogram.Observe(0.5, "weekly_news")
+	histogram.Observe(2, "weekly_news")
+
+	counter := NewCounterVec("calls_total", "Calls.", "tool")
+	counter.Inc("weekly_news")
+
+	var buf strings.Builder
+	w := NewWriter(&buf, true)
+	NewRegistry().Collect(w)
+	histogram.Collect(w)
+	counter.Collect(w)
+	if err := w.Flush(); err != nil {
+		t.Fatalf("Flush failed: %v", err)
+	}
+	out := buf.String()
+
+	for _, line := range []string{
+		`duration_seconds_bucket{tool="weekly_news",le="0.1"} 1`,
+		`duration_seconds_bucket{tool="weekly_news",le="1"} 2`,
+		`duration_seconds_bucket{tool="weekly_news",le="+Inf"} 3`,
+		`duration_seconds_sum{tool="weekly_news"} 2.55`,
+		`duration_seconds_count{tool="weekly_news"} 3`,
+		"# TYPE calls counter",
+		`calls_total{tool="weekly_news"} 1`,
+	} {
+		if !strings.Contains(out, line+"\n") {
+			t.Errorf("Expected %q in output:\n%s", line, out)
+		}
+	}
+	if !strings.HasSuffix(out, "# EOF\n") {
+		t.Errorf("Expected OpenMetrics output to end with # EOF:\n%s", out)
+	}
+}
+
+func TestHandlerNegotiatesFormat(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register(CollectorFunc(func(w *Writer) {
+		w.Gauge("up", "Up.", Sample{Value: 1})
+	}))
+	handler := Handler(registry)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); ct != ContentTypeText {
+		t.Errorf("Expected text format, got %q", ct)
+	}
+	if strings.Contains(rec.Body.String(), "# EOF") {
+		t.Error("Text format should not contain # EOF")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Accept", "application/openmetrics-text; version=1.0.0")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	if ct := rec.Header().Get("Content-Type"); ct != ContentTypeOpenMetrics {
+		t.Errorf("Expected OpenMetrics format, got %q", ct)
+	}
+	if !strings.HasSuffix(rec.Body.String(), "up 1\n# EOF\n") {
+		t.Errorf("Unexpected OpenMetrics body:\n%s", rec.Body.String())
+	}
+}
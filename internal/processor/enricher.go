@@ -0,0 +1,265 @@
+package processor
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/models"
+)
+
+// failedPageTTL bounds how long a page that could not be fetched or extracted is remembered
+const failedPageTTL = 10 * time.Minute
+
+// EnrichmentConfig holds full-article enrichment configuration
+type EnrichmentConfig struct {
+	Enabled          bool          `json:"enabled"`
+	MinContentLength int           `json:"minContentLength"` // articles with shorter content are enriched
+	MaxConcurrency   int           `json:"maxConcurrency"`
+	DomainDelay      time.Duration `json:"domainDelay"` // minimum interval between requests to the same host
+	Timeout          time.Duration `json:"timeout"`     // per page
+	CacheTTL         time.Duration `json:"cacheTTL"`
+	CacheSize        int64         `json:"cacheSize"` // bytes of extracted pages kept in memory
+	MaxPageSize      int64         `json:"maxPageSize"`
+}
+
+// DefaultEnrichmentConfig returns default enrichment configuration; enrichment is disabled by default
+func DefaultEnrichmentConfig() EnrichmentConfig {
+	return EnrichmentConfig{
+		Enabled:          false,
+		MinContentLength: 500,
+		MaxConcurrency:   4,
+		DomainDelay:      time.Second,
+		Timeout:          10 * time.Second,
+		CacheTTL:         6 * time.Hour,
+		CacheSize:        32 * 1024 * 1024,
+		MaxPageSize:      2 * 1024 * 1024,
+	}
+}
+
+// Enricher fetches article pages and replaces short content with the extracted full article
+type Enricher struct {
+	config EnrichmentConfig
+	client *http.Client
+	pages  *cache.CacheStorage
+	logger *slog.Logger
+
+	mu        sync.Mutex
+	nextFetch map[string]time.Time // host -> earliest time of the next request
+}
+
+// enrichedPage is the cached result of fetching one URL; Err is set when extraction failed
+type enrichedPage struct {
+	Page *collector.ReadableContent
+	Err  string
+}
+
+// NewEnricher creates a new enricher
+func NewEnricher(config EnrichmentConfig) *Enricher {
+	defaults := DefaultEnrichmentConfig()
+	if config.MaxConcurrency <= 0 {
+		config.MaxConcurrency = defaults.MaxConcurrency
+	}
+	if config.Timeout <= 0 {
+		config.Timeout = defaults.Timeout
+	}
+	if config.CacheTTL <= 0 {
+		config.CacheTTL = defaults.CacheTTL
+	}
+	if config.CacheSize <= 0 {
+		config.CacheSize = defaults.CacheSize
+	}
+	if config.MaxPageSize <= 0 {
+		config.MaxPageSize = defaults.MaxPageSize
+	}
+
+	return &Enricher{
+		config:    config,
+		client:    &http.Client{Timeout: config.Timeout},
+		pages:     cache.NewCacheStorage(config.CacheSize),
+		logger:    slog.Default(),
+		nextFetch: make(map[string]time.Time),
+	}
+}
+
+// SetLogger sets the logger used for enrichment diagnostics; nil restores slog.Default()
+func (e *Enricher) SetLogger(logger *slog.Logger) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.logger = logging.OrDefault(logger)
+}
+
+// NeedsEnrichment reports whether an article's content is too short to summarize well
+func (e *Enricher) NeedsEnrichment(article models.Article) bool {
+	if len(article.Content) >= e.config.MinContentLength {
+		return false
+	}
+	u, err := url.Parse(article.URL)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// Enrich fetches the pages of articles with short content and returns the articles with the
+// extracted content, lead image and reading time; articles that fail are returned unchanged
+func (e *Enricher) Enrich(ctx context.Context, articles []models.Article) []models.Article {
+	e.mu.Lock()
+	logger := e.logger
+	e.mu.Unlock()
+
+	enriched := make([]models.Article, len(articles))
+	copy(enriched, articles)
+
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, e.config.MaxConcurrency)
+
+	for i := range enriched {
+		if !e.NeedsEnrichment(enriched[i]) {
+			continue
+		}
+		wg.Add(1)
+		go func(article *models.Article) {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+			case <-ctx.Done():
+				return
+			}
+			defer func() { <-semaphore }()
+
+			page, err := e.readablePage(ctx, article.URL)
+			if err != nil {
+				logger.DebugContext(ctx, "article enrichment skipped", slog.String("url", article.URL), logging.Err(err))
+				return
+			}
+			applyReadable(article, page)
+		}(&enriched[i])
+	}
+
+	wg.Wait()
+	return enriched
+}
+
+// applyReadable copies the extracted page into the article
+func applyReadable(article *models.Article, page *collector.ReadableContent) {
+	if len(page.Content) > len(article.Content) {
+		article.Content = page.Content
+	}
+	if article.Title == "" {
+		article.Title = page.Title
+	}
+	if page.LeadImage != "" {
+		article.SetMetadata("lead_image", page.LeadImage)
+	}
+	article.SetMetadata("word_count", page.WordCount)
+	article.SetMetadata("reading_time", int(page.ReadingTime/time.Minute))
+	article.SetMetadata("enriched", true)
+}
+
+// readablePage returns the extracted page from cache, fetching it when missing
+func (e *Enricher) readablePage(ctx context.Context, pageURL string) (*collector.ReadableContent, error) {
+	if cached, ok := e.pages.Get(pageURL); ok {
+		if entry, ok := cached.Data.(enrichedPage); ok {
+			if entry.Err != "" {
+				return nil, fmt.Errorf("%s (cached)", entry.Err)
+			}
+			return entry.Page, nil
+		}
+	}
+
+	page, err := e.fetchReadable(ctx, pageURL)
+	if err != nil {
+		// Cancellation says nothing about the page itself
+		if ctx.Err() == nil {
+			ttl := failedPageTTL
+			if e.config.CacheTTL < ttl {
+				ttl = e.config.CacheTTL
+			}
+			e.pages.Set(pageURL, enrichedPage{Err: err.Error()}, ttl)
+		}
+		return nil, err
+	}
+	e.pages.Set(pageURL, enrichedPage{Page: page}, e.config.CacheTTL)
+	return page, nil
+}
+
+// fetchReadable downloads the page, respecting the per-domain delay, and extracts its main content
+func (e *Enricher) fetchReadable(ctx context.Context, pageURL string) (*collector.ReadableContent, error) {
+	u, err := url.Parse(pageURL)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if err := e.waitForHost(ctx, u.Host); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, pageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("User-Agent", "Mozilla/5.0 (compatible; HTMLCollector/1.0)")
+	req.Header.Set("Accept", "text/html,application/xhtml+xml")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("HTTP error %d: %s", resp.StatusCode, resp.Status)
+	}
+	if contentType := resp.Header.Get("Content-Type"); contentType != "" && !strings.Contains(contentType, "html") {
+		return nil, fmt.Errorf("unsupported content type %q", contentType)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, e.config.MaxPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	// Resolve relative images against the final URL after redirects
+	return collector.ExtractReadable(string(body), resp.Request.URL.String())
+}
+
+// waitForHost blocks until the host's next request slot, so that requests to one
+// domain are spaced at least DomainDelay apart while other domains proceed
+func (e *Enricher) waitForHost(ctx context.Context, host string) error {
+	if e.config.DomainDelay <= 0 {
+		return nil
+	}
+
+	e.mu.Lock()
+	now := time.Now()
+	slot := e.nextFetch[host]
+	if slot.Before(now) {
+		slot = now
+	}
+	e.nextFetch[host] = slot.Add(e.config.DomainDelay)
+	for h, next := range e.nextFetch {
+		if next.Before(now) {
+			delete(e.nextFetch, h)
+		}
+	}
+	e.mu.Unlock()
+
+	wait := time.Until(slot)
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
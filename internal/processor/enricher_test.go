@@ -0,0 +1,143 @@
+package processor
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/models"
+)
+
+const enricherTestPage = `<html><head><meta property="og:image" content="/cover.png"></head><body>
+<nav><a href="/">Home</a></nav>
+<article>
+	<p>Vite 6 ships the Environment API, which lets frameworks describe how code runs on the client, the server and at the edge.</p>
+	<p>The release also refreshes the default browser targets, updates Sass handling and drops support for Node.js versions that reached end of life.</p>
+	<pre><code class="language-js">export default defineConfig({ environments: { ssr: {} } })</code></pre>
+</article>
+</body></html>`
+
+// newEnricherTestServer serves an article page on /post, a second one on /other and 404 elsewhere, counting requests per path
+func newEnricherTestServer(t *testing.T) (*httptest.Server, func(path string) int) {
+	var mu sync.Mutex
+	hits := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		hits[r.URL.Path]++
+		mu.Unlock()
+		switch r.URL.Path {
+		case "/post", "/other":
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			w.Write([]byte(enricherTestPage))
+		case "/feed.json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, func(path string) int {
+		mu.Lock()
+		defer mu.Unlock()
+		return hits[path]
+	}
+}
+
+func TestEnricher_Enrich(t *testing.T) {
+	server, hits := newEnricherTestServer(t)
+
+	config := DefaultEnrichmentConfig()
+	config.Enabled = true
+	config.DomainDelay = 0
+	enricher := NewEnricher(config)
+
+	long := strings.Repeat("Long RSS content. ", 40)
+	articles := []models.Article{
+		{Title: "Vite 6", URL: server.URL + "/post", Summary: "Vite 6 is out", Content: "Vite 6 is out"},
+		{Title: "Full feed", URL: server.URL + "/full", Content: long},
+		{Title: "Missing", URL: server.URL + "/missing"},
+		{Title: "Not HTML", URL: server.URL + "/feed.json"},
+	}
+
+	enriched := enricher.Enrich(context.Background(), articles)
+	if len(enriched) != len(articles) {
+		t.Fatalf("Expected %d articles, got %d", len(articles), len(enriched))
+	}
+
+	vite := enriched[0]
+	if !strings.Contains(vite.Content, "Environment API") || !strings.Contains(vite.Content, "```js\nexport default defineConfig") {
+		t.Errorf("Expected extracted article with code block, got %q", vite.Content)
+	}
+	if strings.Contains(vite.Content, "Home") {
+		t.Errorf("Expected navigation to be removed, got %q", vite.Content)
+	}
+	if vite.Metadata["lead_image"] != server.URL+"/cover.png" || vite.Metadata["reading_time"] != 1 || vite.Metadata["enriched"] != true {
+		t.Errorf("Unexpected enrichment metadata: %v", vite.Metadata)
+	}
+	if articles[0].Content != "Vite 6 is out" {
+		t.Error("Expected input articles to be left unchanged")
+	}
+
+	if enriched[1].Content != long || hits("/full") != 0 {
+		t.Errorf("Expected article with enough content not to be fetched, got %d requests", hits("/full"))
+	}
+	for _, article := range enriched[2:] {
+		if article.Content != "" || article.Metadata != nil {
+			t.Errorf("Expected failed article to be unchanged, got %+v", article)
+		}
+	}
+
+	// Both successful and failed pages are served from cache
+	enricher.Enrich(context.Background(), articles)
+	if hits("/post") != 1 || hits("/missing") != 1 || hits("/feed.json") != 1 {
+		t.Errorf("Expected cached pages not to be fetched again, got %d, %d and %d requests",
+			hits("/post"), hits("/missing"), hits("/feed.json"))
+	}
+}
+
+func TestEnricher_DomainDelay(t *testing.T) {
+	server, hits := newEnricherTestServer(t)
+
+	config := DefaultEnrichmentConfig()
+	config.DomainDelay = 100 * time.Millisecond
+	enricher := NewEnricher(config)
+
+	started := time.Now()
+	enricher.Enrich(context.Background(), []models.Article{
+		{URL: server.URL + "/post"},
+		{URL: server.URL + "/other"},
+	})
+	if elapsed := time.Since(started); elapsed < config.DomainDelay {
+		t.Errorf("Expected requests to the same host to be spaced by %v, took %v", config.DomainDelay, elapsed)
+	}
+	if hits("/post") != 1 || hits("/other") != 1 {
+		t.Errorf("Expected both pages to be fetched once, got %d and %d", hits("/post"), hits("/other"))
+	}
+}
+
+func TestProcessor_EnrichArticles(t *testing.T) {
+	server, hits := newEnricherTestServer(t)
+	articles := []models.Article{{Title: "Vite 6", URL: server.URL + "/post", Source: "dev.to"}}
+
+	disabled := NewProcessor(DefaultConfig())
+	if result := disabled.EnrichArticles(context.Background(), articles); result[0].Content != "" || hits("/post") != 0 {
+		t.Errorf("Expected no enrichment when disabled, got %+v", result[0])
+	}
+
+	config := DefaultConfig()
+	config.Enrichment.Enabled = true
+	processor := NewProcessor(config)
+	result := processor.EnrichArticles(context.Background(), articles)
+
+	if !strings.Contains(result[0].Summary, "Environment API") {
+		t.Errorf("Expected summary generated from the full article, got %q", result[0].Summary)
+	}
+	if result[0].Quality == 0 {
+		t.Error("Expected quality to be reassessed")
+	}
+}
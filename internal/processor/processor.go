@@ -4,10 +4,15 @@ package processor
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"sync"
 	"time"
 
+	"go.opentelemetry.io/otel/attribute"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
 	"github.com/ZephyrDeng/dev-context/internal/models"
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
 )
 
 // Processor provides unified data processing functionality
@@ -16,16 +21,19 @@ type Processor struct {
 	summarizer *Summarizer
 	sorter     *ArticleSorter
 	converter  *Converter
+	enricher   *Enricher // nil when enrichment is disabled
+	logger     *slog.Logger
 	mu         sync.RWMutex
 }
 
 // Config holds processor configuration
 type Config struct {
-	EnableSummarization bool          `json:"enableSummarization"`
-	EnableSorting       bool          `json:"enableSorting"`
-	MaxSummaryLength    int           `json:"maxSummaryLength"`
-	ProcessingTimeout   time.Duration `json:"processingTimeout"`
-	MaxConcurrency      int           `json:"maxConcurrency"`
+	EnableSummarization bool             `json:"enableSummarization"`
+	EnableSorting       bool             `json:"enableSorting"`
+	MaxSummaryLength    int              `json:"maxSummaryLength"`
+	ProcessingTimeout   time.Duration    `json:"processingTimeout"`
+	MaxConcurrency      int              `json:"maxConcurrency"`
+	Enrichment          EnrichmentConfig `json:"enrichment"`
 }
 
 // DefaultConfig returns default processor configuration
@@ -36,6 +44,7 @@ func DefaultConfig() *Config {
 		MaxSummaryLength:    200,
 		ProcessingTimeout:   30 * time.Second,
 		MaxConcurrency:      10,
+		Enrichment:          DefaultEnrichmentConfig(),
 	}
 }
 
@@ -45,6 +54,11 @@ func NewProcessor(config *Config) *Processor {
 		config = DefaultConfig()
 	}
 
+	var enricher *Enricher
+	if config.Enrichment.Enabled {
+		enricher = NewEnricher(config.Enrichment)
+	}
+
 	return &Processor{
 		config:     config,
 		summarizer: NewSummarizer(),
@@ -54,11 +68,63 @@ func NewProcessor(config *Config) *Processor {
 			MaxTitleLength:   500,
 			MaxContentLength: 50000,
 		}),
+		enricher: enricher,
+		logger:   slog.Default(),
+	}
+}
+
+// SetLogger sets the logger used for processing diagnostics; nil restores slog.Default()
+func (p *Processor) SetLogger(logger *slog.Logger) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.logger = logging.OrDefault(logger)
+	if p.enricher != nil {
+		p.enricher.SetLogger(logger)
 	}
 }
 
+// EnrichArticles replaces the short content of articles with the full article fetched from
+// their URL, regenerates short summaries from it and reassesses quality; it is a no-op when
+// enrichment is disabled
+func (p *Processor) EnrichArticles(ctx context.Context, articles []models.Article) []models.Article {
+	if p.enricher == nil || len(articles) == 0 {
+		return articles
+	}
+
+	ctx, span := tracing.Start(ctx, "processor.EnrichArticles", attribute.Int("processor.articles_in", len(articles)))
+	defer span.End()
+
+	started := time.Now()
+	enriched := p.enricher.Enrich(ctx, articles)
+
+	count := 0
+	for i := range enriched {
+		if value, _ := enriched[i].Metadata["enriched"].(bool); !value {
+			continue
+		}
+		count++
+		if p.config.EnableSummarization && len(enriched[i].Summary) < 50 {
+			if summary, err := p.summarizer.GenerateSummary(enriched[i].Content); err == nil && summary != "" {
+				enriched[i].Summary = summary
+			}
+		}
+		enriched[i].UpdateQuality()
+	}
+
+	span.SetAttributes(attribute.Int("processor.articles_enriched", count))
+	p.logger.DebugContext(ctx, "articles enriched",
+		slog.Int("articles_in", len(articles)),
+		slog.Int("enriched", count),
+		logging.Duration(time.Since(started)),
+	)
+	return enriched
+}
+
 // ProcessArticles processes a slice of articles with various enhancements
 func (p *Processor) ProcessArticles(ctx context.Context, articles []models.Article, options ProcessOptions) ([]models.Article, error) {
+	ctx, span := tracing.Start(ctx, "processor.ProcessArticles", attribute.Int("processor.articles_in", len(articles)))
+	defer span.End()
+
 	p.mu.RLock()
 	defer p.mu.RUnlock()
 
@@ -66,6 +132,8 @@ func (p *Processor) ProcessArticles(ctx context.Context, articles []models.Artic
 		return articles, nil
 	}
 
+	started := time.Now()
+
 	// Apply processing timeout
 	ctx, cancel := context.WithTimeout(ctx, p.config.ProcessingTimeout)
 	defer cancel()
@@ -102,7 +170,15 @@ func (p *Processor) ProcessArticles(ctx context.Context, articles []models.Artic
 	wg.Wait()
 
 	if ctx.Err() != nil {
-		return processed, fmt.Errorf("processing timeout: %v", ctx.Err())
+		err := fmt.Errorf("processing timeout: %v", ctx.Err())
+		tracing.RecordError(span, err)
+		p.logger.WarnContext(ctx, "article processing stopped early",
+			slog.Int("articles_in", len(articles)),
+			slog.Int("articles_out", len(processed)),
+			logging.Duration(time.Since(started)),
+			logging.Err(err),
+		)
+		return processed, err
 	}
 
 	// Apply sorting if enabled and requested
@@ -127,6 +203,12 @@ func (p *Processor) ProcessArticles(ctx context.Context, articles []models.Artic
 		processed = processed[:options.Limit]
 	}
 
+	span.SetAttributes(attribute.Int("processor.articles_out", len(processed)))
+	p.logger.DebugContext(ctx, "processed articles",
+		slog.Int("articles_in", len(articles)),
+		slog.Int("articles_out", len(processed)),
+		logging.Duration(time.Since(started)),
+	)
 	return processed, nil
 }
 
@@ -0,0 +1,272 @@
+package sources
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+)
+
+const defaultUserAgent = "FrontendNews-MCP/1.0"
+
+// DefaultSources 返回内置数据源，未提供配置时各工具使用这些数据源
+func DefaultSources() []Source {
+	sources := []Source{
+		devToWeekly("dev.to", "frontend", 30, 1.0),
+		devToWeekly("dev.to-react", "react", 20, 0.9),
+		devToWeekly("dev.to-vue", "vue", 20, 0.9),
+		devToWeekly("dev.to-javascript", "javascript", 25, 0.9),
+		hackerNewsWeekly("hackernews", "javascript", 0.8),
+		hackerNewsWeekly("hackernews-react", "react", 0.7),
+		hackerNewsWeekly("hackernews-css", "css", 0.7),
+		{
+			Name:     "github_repos",
+			Tools:    []string{ToolTopicSearch},
+			Platform: "github",
+			Kind:     KindRepositories,
+			Weight:   1.0,
+			Enabled:  true,
+			Config: collector.CollectConfig{
+				URL:     "https://api.github.com/search/repositories?q={query}+language:{language}&sort=stars&order=desc&per_page=30",
+				Headers: githubHeaders(),
+				Timeout: 25 * time.Second,
+			},
+		},
+		{
+			Name:     "devto",
+			Tools:    []string{ToolTopicSearch},
+			Platform: "dev.to",
+			Kind:     KindArticles,
+			Weight:   1.0,
+			Enabled:  true,
+			Config: collector.CollectConfig{
+				// Dev.to API不支持query参数，由工具将查询映射为标签
+				URL: "https://dev.to/api/articles?tag={tag}&per_page=30",
+				Headers: map[string]string{
+					"User-Agent": defaultUserAgent,
+				},
+				Timeout: 25 * time.Second,
+			},
+		},
+		{
+			Name:     "github_trending",
+			Tools:    []string{ToolTrendingRepos},
+			Platform: "github",
+			Kind:     KindRepositories,
+			Weight:   1.0,
+			Enabled:  true,
+			Config: collector.CollectConfig{
+				URL:     "https://api.github.com/search/repositories?q=language:{language}+created:>{since}&sort=stars&order=desc&per_page=20",
+				Headers: githubHeaders(),
+				Timeout: 15 * time.Second,
+			},
+		},
+	}
+
+	// 主流框架的 GitHub 发布，用于周报的版本发布部分
+	watched := []struct{ name, repo, category string }{
+		{"react", "facebook/react", "react"},
+		{"vue", "vuejs/core", "vue"},
+		{"angular", "angular/angular", "angular"},
+		{"svelte", "sveltejs/svelte", "svelte"},
+		{"nextjs", "vercel/next.js", "react"},
+		{"nuxt", "nuxt/nuxt", "vue"},
+		{"vite", "vitejs/vite", "vite"},
+		{"typescript", "microsoft/TypeScript", "typescript"},
+	}
+	for _, w := range watched {
+		sources = append(sources, githubReleases(w.name, w.repo, w.category))
+	}
+
+	// npm 前端包：周报列出新的主版本和下载量快速增长的包，热门仓库以周下载量排名
+	sources = append(sources, Source{
+		Name:     "npm_frontend",
+		Tools:    []string{ToolWeeklyNews, ToolTrendingRepos},
+		Platform: "npm",
+		Kind:     KindRepositories,
+		Weight:   0.95,
+		Enabled:  true,
+		Config: collector.CollectConfig{
+			URL: "https://registry.npmjs.org/-/v1/search?text=keywords:frontend&size=25",
+			Headers: map[string]string{
+				"User-Agent": defaultUserAgent,
+			},
+			MaxArticles: 25,
+			Timeout:     25 * time.Second,
+			Language:    "JavaScript",
+			Tags:        []string{"frontend"},
+			Metadata: map[string]string{
+				"source_type": "npm",
+				"platform":    "npm",
+			},
+		},
+	})
+
+	// Stack Overflow 问答，优先返回有回答的问题
+	sources = append(sources, Source{
+		Name:     "stackoverflow",
+		Tools:    []string{ToolTopicSearch},
+		Platform: "stackoverflow",
+		Kind:     KindDiscussions,
+		Weight:   1.0,
+		Enabled:  true,
+		Config: collector.CollectConfig{
+			URL: "https://api.stackexchange.com/2.3/search/advanced?order=desc&sort=relevance&q={query}&answers=1&site=stackoverflow&filter=withbody&pagesize=25",
+			Headers: map[string]string{
+				"User-Agent": defaultUserAgent,
+			},
+			Timeout: 15 * time.Second,
+		},
+	})
+
+	// Hacker News 讨论搜索
+	sources = append(sources, Source{
+		Name:     "hackernews_search",
+		Tools:    []string{ToolTopicSearch},
+		Platform: "hackernews",
+		Kind:     KindDiscussions,
+		Weight:   0.8,
+		Enabled:  true,
+		Config: collector.CollectConfig{
+			URL: "https://hn.algolia.com/api/v1/search?query={query}&tags=story&hitsPerPage=25",
+			Headers: map[string]string{
+				"User-Agent": defaultUserAgent,
+			},
+			Timeout: 15 * time.Second,
+		},
+	})
+
+	// Reddit 前端相关 subreddit 的讨论搜索
+	for _, subreddit := range []string{"webdev", "reactjs", "javascript", "vuejs"} {
+		sources = append(sources, redditSearch(subreddit))
+	}
+
+	// GitHub Topics（获取特定主题的仓库），权重递减以保持原有的选择顺序
+	topics := []string{"frontend", "react", "vue", "angular", "javascript", "typescript"}
+	for i, topic := range topics {
+		sources = append(sources, Source{
+			Name:       "github_topic_" + topic,
+			Tools:      []string{ToolTrendingRepos},
+			Categories: []string{topic},
+			Platform:   "github",
+			Kind:       KindRepositories,
+			Weight:     0.9 - float64(i)*0.05,
+			Enabled:    true,
+			Config: collector.CollectConfig{
+				URL:     "https://api.github.com/search/repositories?q=topic:" + topic + "+created:>{since}&sort=stars&order=desc&per_page=15",
+				Headers: githubHeaders(),
+				Timeout: 15 * time.Second,
+			},
+		})
+	}
+
+	return sources
+}
+
+// devToWeekly 构建周报使用的dev.to标签数据源
+func devToWeekly(name, tag string, perPage int, weight float64) Source {
+	return Source{
+		Name:       name,
+		Tools:      []string{ToolWeeklyNews},
+		Categories: []string{tag},
+		Platform:   "dev.to",
+		Kind:       KindArticles,
+		Weight:     weight,
+		Enabled:    true,
+		Config: collector.CollectConfig{
+			URL: "https://dev.to/api/articles?tag=" + tag + "&per_page=" + strconv.Itoa(perPage),
+			Headers: map[string]string{
+				"User-Agent": defaultUserAgent,
+				"Accept":     "application/json",
+			},
+			MaxArticles: perPage,
+			Tags:        []string{tag, "dev.to"},
+			Metadata: map[string]string{
+				"source_type": "api",
+				"platform":    "dev.to",
+			},
+		},
+	}
+}
+
+// hackerNewsWeekly 构建周报使用的Hacker News数据源，通过Algolia搜索周期内得分超过20的帖子
+func hackerNewsWeekly(name, query string, weight float64) Source {
+	return Source{
+		Name:       name,
+		Tools:      []string{ToolWeeklyNews},
+		Categories: []string{query},
+		Platform:   "hackernews",
+		Kind:       KindArticles,
+		Weight:     weight,
+		Enabled:    true,
+		Config: collector.CollectConfig{
+			URL: "https://hn.algolia.com/api/v1/search?query=" + query +
+				"&tags=story&numericFilters=created_at_i%3E{since_unix},created_at_i%3C{until_unix},points%3E20&hitsPerPage=30",
+			Headers: map[string]string{
+				"User-Agent": defaultUserAgent,
+				"Accept":     "application/json",
+			},
+			MaxArticles: 30,
+			Tags:        []string{query},
+			Metadata: map[string]string{
+				"source_type": "hackernews",
+				"platform":    "hackernews",
+			},
+		},
+	}
+}
+
+// githubReleases 构建周报使用的仓库发布数据源，category 为周报分类过滤使用的分类
+func githubReleases(name, repo, category string) Source {
+	tags := []string{category}
+	if name != category {
+		tags = append(tags, name)
+	}
+
+	return Source{
+		Name:       "releases-" + name,
+		Tools:      []string{ToolWeeklyNews},
+		Categories: []string{category},
+		Platform:   "github",
+		Kind:       KindArticles,
+		Weight:     0.9,
+		Enabled:    true,
+		Config: collector.CollectConfig{
+			URL:         "https://api.github.com/repos/" + repo + "/releases?per_page=10",
+			Headers:     githubHeaders(),
+			MaxArticles: 10,
+			Timeout:     15 * time.Second,
+			Tags:        tags,
+			Metadata: map[string]string{
+				"source_type": "github_releases",
+				"platform":    "github",
+			},
+		},
+	}
+}
+
+// redditSearch 构建主题搜索使用的 subreddit 搜索数据源
+func redditSearch(subreddit string) Source {
+	return Source{
+		Name:     "reddit_" + subreddit,
+		Tools:    []string{ToolTopicSearch},
+		Platform: "reddit",
+		Kind:     KindDiscussions,
+		Weight:   0.8,
+		Enabled:  true,
+		Config: collector.CollectConfig{
+			URL: "https://www.reddit.com/r/" + subreddit + "/search.json?q={query}&restrict_sr=1&sort=relevance&t={time_range}&limit=25",
+			Headers: map[string]string{
+				"User-Agent": defaultUserAgent,
+			},
+			Timeout: 15 * time.Second,
+		},
+	}
+}
+
+func githubHeaders() map[string]string {
+	return map[string]string{
+		"Accept":     "application/vnd.github.v3+json",
+		"User-Agent": defaultUserAgent,
+	}
+}
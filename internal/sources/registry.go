@@ -0,0 +1,314 @@
+// Package sources 维护各MCP工具使用的数据源注册表，支持从配置加载和运行时修改
+package sources
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+)
+
+// 使用数据源的工具名称
+const (
+	ToolWeeklyNews    = "weekly_news"
+	ToolTopicSearch   = "topic_search"
+	ToolTrendingRepos = "trending_repos"
+)
+
+// 数据源返回的结果类型，决定工具如何解释采集结果
+const (
+	KindArticles     = "articles"
+	KindRepositories = "repositories"
+	KindDiscussions  = "discussions"
+)
+
+// KnownTools 返回所有支持数据源的工具名称
+func KnownTools() []string {
+	return []string{ToolWeeklyNews, ToolTopicSearch, ToolTrendingRepos}
+}
+
+// Source 数据源定义
+//
+// Config.URL 支持 {name} 形式的模板变量，由工具在采集时填充（值会做URL转义），
+// 例如 {query}、{language}、{tag}、{time_range}、{category}、{since}、{since_unix}。
+type Source struct {
+	Name       string                  `json:"name"`
+	Tools      []string                `json:"tools"`      // 使用该数据源的工具
+	Categories []string                `json:"categories"` // 分类标签，如 react、vue；为空表示通用
+	Platform   string                  `json:"platform"`   // 平台名称，如 github、dev.to
+	Kind       string                  `json:"kind"`       // 结果类型：articles、repositories、discussions
+	Weight     float64                 `json:"weight"`     // 权重，数据源数量受限时优先选择权重高的
+	Enabled    bool                    `json:"enabled"`
+	Config     collector.CollectConfig `json:"config"`
+}
+
+// UsedBy 数据源是否被指定工具使用
+func (s Source) UsedBy(tool string) bool {
+	for _, t := range s.Tools {
+		if t == tool {
+			return true
+		}
+	}
+	return false
+}
+
+// HasCategory 数据源是否属于指定分类（不区分大小写）
+func (s Source) HasCategory(category string) bool {
+	for _, c := range s.Categories {
+		if strings.EqualFold(c, category) {
+			return true
+		}
+	}
+	return false
+}
+
+// ResultKind 返回结果类型，未设置时视为文章
+func (s Source) ResultKind() string {
+	if s.Kind == "" {
+		return KindArticles
+	}
+	return s.Kind
+}
+
+// Resolve 填充URL模板变量，返回独立的采集配置副本
+func (s Source) Resolve(vars map[string]string) collector.CollectConfig {
+	config := s.clone().Config
+	config.Name = s.Name
+	for key, value := range vars {
+		config.URL = strings.ReplaceAll(config.URL, "{"+key+"}", url.QueryEscape(value))
+	}
+	return config
+}
+
+// Validate 校验数据源定义
+func (s Source) Validate() error {
+	if s.Name == "" {
+		return fmt.Errorf("数据源名称不能为空")
+	}
+	if s.Config.URL == "" {
+		return fmt.Errorf("数据源 %s 缺少URL", s.Name)
+	}
+	if len(s.Tools) == 0 {
+		return fmt.Errorf("数据源 %s 至少需要指定一个工具", s.Name)
+	}
+	for _, tool := range s.Tools {
+		if !IsKnownTool(tool) {
+			return fmt.Errorf("数据源 %s 使用了未知的工具 %q", s.Name, tool)
+		}
+	}
+	switch s.Kind {
+	case "", KindArticles, KindRepositories, KindDiscussions:
+	default:
+		return fmt.Errorf("数据源 %s 的结果类型 %q 无效", s.Name, s.Kind)
+	}
+	if s.Weight < 0 {
+		return fmt.Errorf("数据源 %s 的权重不能为负数", s.Name)
+	}
+	return nil
+}
+
+// Fingerprint 返回一组数据源定义的短哈希，与顺序无关
+//
+// 工具把它拼入缓存键：数据源变化后新请求使用新键，旧数据源采集的结果
+// （包括重新加载时仍在进行的采集）不会再被返回，未受影响的查询不受干扰。
+func Fingerprint(list []Source) string {
+	sorted := append([]Source(nil), list...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Name < sorted[j].Name
+	})
+	data, _ := json.Marshal(sorted)
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:6])
+}
+
+// IsKnownTool 是否为支持数据源的工具名称
+func IsKnownTool(tool string) bool {
+	for _, known := range KnownTools() {
+		if tool == known {
+			return true
+		}
+	}
+	return false
+}
+
+// clone 深拷贝数据源，避免调用方修改注册表内部状态
+func (s Source) clone() Source {
+	c := s
+	c.Tools = append([]string(nil), s.Tools...)
+	c.Categories = append([]string(nil), s.Categories...)
+	c.Config.Tags = append([]string(nil), s.Config.Tags...)
+	if s.Config.Headers != nil {
+		c.Config.Headers = make(map[string]string, len(s.Config.Headers))
+		for k, v := range s.Config.Headers {
+			c.Config.Headers[k] = v
+		}
+	}
+	if s.Config.Metadata != nil {
+		c.Config.Metadata = make(map[string]string, len(s.Config.Metadata))
+		for k, v := range s.Config.Metadata {
+			c.Config.Metadata[k] = v
+		}
+	}
+	if s.Config.Selector != nil {
+		selector := *s.Config.Selector
+		c.Config.Selector = &selector
+	}
+	return c
+}
+
+// Registry 线程安全的数据源注册表
+type Registry struct {
+	sources map[string]Source
+	mutex   sync.RWMutex
+}
+
+// NewRegistry 创建空的数据源注册表
+func NewRegistry() *Registry {
+	return &Registry{
+		sources: make(map[string]Source),
+	}
+}
+
+// NewDefaultRegistry 创建包含内置数据源的注册表
+func NewDefaultRegistry() *Registry {
+	r := NewRegistry()
+	for _, source := range DefaultSources() {
+		r.sources[source.Name] = source
+	}
+	return r
+}
+
+// Add 添加数据源，名称已存在时返回错误
+func (r *Registry) Add(source Source) error {
+	if err := source.Validate(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.sources[source.Name]; exists {
+		return fmt.Errorf("数据源 %s 已存在", source.Name)
+	}
+	r.sources[source.Name] = source.clone()
+	return nil
+}
+
+// Set 添加或替换数据源
+func (r *Registry) Set(source Source) error {
+	if err := source.Validate(); err != nil {
+		return err
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sources[source.Name] = source.clone()
+	return nil
+}
+
+// Remove 删除数据源，返回是否存在
+func (r *Registry) Remove(name string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	if _, exists := r.sources[name]; !exists {
+		return false
+	}
+	delete(r.sources, name)
+	return true
+}
+
+// SetEnabled 启用或禁用数据源
+func (r *Registry) SetEnabled(name string, enabled bool) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	source, exists := r.sources[name]
+	if !exists {
+		return fmt.Errorf("数据源 %s 不存在", name)
+	}
+	source.Enabled = enabled
+	r.sources[name] = source
+	return nil
+}
+
+// Replace 原子替换全部数据源，任一数据源无效时注册表保持不变
+func (r *Registry) Replace(sources []Source) error {
+	next := make(map[string]Source, len(sources))
+	for _, source := range sources {
+		if err := source.Validate(); err != nil {
+			return err
+		}
+		if _, exists := next[source.Name]; exists {
+			return fmt.Errorf("数据源 %s 重复", source.Name)
+		}
+		next[source.Name] = source.clone()
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sources = next
+	return nil
+}
+
+// Get 获取数据源
+func (r *Registry) Get(name string) (Source, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	source, exists := r.sources[name]
+	if !exists {
+		return Source{}, false
+	}
+	return source.clone(), true
+}
+
+// List 返回全部数据源，按名称排序
+func (r *Registry) List() []Source {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	list := make([]Source, 0, len(r.sources))
+	for _, source := range r.sources {
+		list = append(list, source.clone())
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// ForTool 返回指定工具已启用的数据源，按权重降序、名称升序排列
+func (r *Registry) ForTool(tool string) []Source {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var list []Source
+	for _, source := range r.sources {
+		if source.Enabled && source.UsedBy(tool) {
+			list = append(list, source.clone())
+		}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Weight != list[j].Weight {
+			return list[i].Weight > list[j].Weight
+		}
+		return list[i].Name < list[j].Name
+	})
+	return list
+}
+
+// Len 返回数据源数量
+func (r *Registry) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.sources)
+}
@@ -0,0 +1,188 @@
+package sources
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+)
+
+func newTestSource(name string, weight float64) Source {
+	return Source{
+		Name:    name,
+		Tools:   []string{ToolWeeklyNews},
+		Weight:  weight,
+		Enabled: true,
+		Config: collector.CollectConfig{
+			URL:     "https://example.com/" + name,
+			Headers: map[string]string{"User-Agent": "test"},
+		},
+	}
+}
+
+func TestDefaultSourcesAreValid(t *testing.T) {
+	registry := NewDefaultRegistry()
+	for _, source := range registry.List() {
+		if err := source.Validate(); err != nil {
+			t.Errorf("Default source %s is invalid: %v", source.Name, err)
+		}
+	}
+
+	for _, tool := range KnownTools() {
+		if len(registry.ForTool(tool)) == 0 {
+			t.Errorf("Expected default sources for tool %s", tool)
+		}
+	}
+}
+
+func TestRegistryAddRemove(t *testing.T) {
+	registry := NewRegistry()
+
+	if err := registry.Add(newTestSource("a", 1)); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if err := registry.Add(newTestSource("a", 1)); err == nil {
+		t.Error("Expected error when adding duplicate source")
+	}
+	if err := registry.Set(newTestSource("a", 2)); err != nil {
+		t.Errorf("Set should replace existing source: %v", err)
+	}
+	if source, _ := registry.Get("a"); source.Weight != 2 {
+		t.Errorf("Expected replaced weight 2, got %v", source.Weight)
+	}
+
+	if !registry.Remove("a") {
+		t.Error("Expected Remove to report existing source")
+	}
+	if registry.Remove("a") {
+		t.Error("Expected Remove to report missing source")
+	}
+	if registry.Len() != 0 {
+		t.Errorf("Expected empty registry, got %d", registry.Len())
+	}
+}
+
+func TestRegistryValidation(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Source)
+	}{
+		{"empty name", func(s *Source) { s.Name = "" }},
+		{"missing url", func(s *Source) { s.Config.URL = "" }},
+		{"no tools", func(s *Source) { s.Tools = nil }},
+		{"unknown tool", func(s *Source) { s.Tools = []string{"unknown"} }},
+		{"invalid kind", func(s *Source) { s.Kind = "videos" }},
+		{"negative weight", func(s *Source) { s.Weight = -1 }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			source := newTestSource("x", 1)
+			tt.modify(&source)
+			if err := NewRegistry().Add(source); err == nil {
+				t.Error("Expected validation error")
+			}
+		})
+	}
+}
+
+func TestRegistryForTool(t *testing.T) {
+	registry := NewRegistry()
+	registry.Add(newTestSource("low", 0.1))
+	registry.Add(newTestSource("high", 0.9))
+	registry.Add(newTestSource("mid-b", 0.5))
+	registry.Add(newTestSource("mid-a", 0.5))
+
+	other := newTestSource("other-tool", 1)
+	other.Tools = []string{ToolTrendingRepos}
+	registry.Add(other)
+
+	if err := registry.SetEnabled("low", false); err != nil {
+		t.Fatalf("SetEnabled failed: %v", err)
+	}
+	if err := registry.SetEnabled("missing", false); err == nil {
+		t.Error("Expected error for missing source")
+	}
+
+	var names []string
+	for _, source := range registry.ForTool(ToolWeeklyNews) {
+		names = append(names, source.Name)
+	}
+	if got := strings.Join(names, ","); got != "high,mid-a,mid-b" {
+		t.Errorf("Expected weight-ordered enabled sources, got %s", got)
+	}
+}
+
+func TestRegistryReplaceIsAtomic(t *testing.T) {
+	registry := NewRegistry()
+	registry.Add(newTestSource("keep", 1))
+
+	invalid := newTestSource("", 1)
+	if err := registry.Replace([]Source{newTestSource("new", 1), invalid}); err == nil {
+		t.Fatal("Expected Replace to fail with invalid source")
+	}
+	if _, ok := registry.Get("keep"); !ok {
+		t.Error("Registry should be unchanged after failed Replace")
+	}
+
+	if err := registry.Replace([]Source{newTestSource("new", 1), newTestSource("new", 1)}); err == nil {
+		t.Error("Expected Replace to reject duplicate names")
+	}
+
+	if err := registry.Replace([]Source{newTestSource("new", 1)}); err != nil {
+		t.Fatalf("Replace failed: %v", err)
+	}
+	if _, ok := registry.Get("keep"); ok {
+		t.Error("Expected old sources to be removed by Replace")
+	}
+}
+
+func TestRegistryReturnsCopies(t *testing.T) {
+	registry := NewRegistry()
+	registry.Add(newTestSource("a", 1))
+
+	source, _ := registry.Get("a")
+	source.Config.Headers["User-Agent"] = "modified"
+	source.Tools[0] = ToolTrendingRepos
+
+	stored, _ := registry.Get("a")
+	if stored.Config.Headers["User-Agent"] != "test" || stored.Tools[0] != ToolWeeklyNews {
+		t.Error("Modifying a returned source must not affect the registry")
+	}
+}
+
+func TestSourceResolve(t *testing.T) {
+	source := newTestSource("search", 1)
+	source.Config.URL = "https://api.example.com/search?q={query}+language:{language}&since={since}"
+
+	config := source.Resolve(map[string]string{
+		"query":    "next.js 15",
+		"language": "typescript",
+		"since":    "2024-01-01",
+	})
+
+	want := "https://api.example.com/search?q=next.js+15+language:typescript&since=2024-01-01"
+	if config.URL != want {
+		t.Errorf("Expected %s, got %s", want, config.URL)
+	}
+	if source.Config.URL == config.URL {
+		t.Error("Resolve must not modify the source template")
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	a, b := newTestSource("a", 1), newTestSource("b", 2)
+
+	if Fingerprint([]Source{a, b}) != Fingerprint([]Source{b, a}) {
+		t.Error("Fingerprint must not depend on order")
+	}
+
+	changed := newTestSource("b", 2)
+	changed.Config.URL = "https://other.example.com/feed"
+	if Fingerprint([]Source{a, b}) == Fingerprint([]Source{a, changed}) {
+		t.Error("Fingerprint must change when a source definition changes")
+	}
+	if Fingerprint([]Source{a, b}) == Fingerprint([]Source{a}) {
+		t.Error("Fingerprint must change when a source is removed")
+	}
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"unsafe"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+	"github.com/ZephyrDeng/dev-context/internal/models"
+)
+
+// 以下 EstimateSize 实现 cache.Sizer，写入缓存时直接累计文章、仓库等字段的长度，
+// 避免对整个结果做反射遍历；只有结构不固定的 Metadata 仍交给 cache.EstimateDataSize。
+
+// EstimateSize 估算周报结果占用的内存字节数
+func (r *WeeklyNewsResult) EstimateSize() int64 {
+	size := int64(unsafe.Sizeof(*r)) + articlesSize(r.Articles) + articlesSize(r.Releases) + int64(len(r.Summary))
+	for _, s := range r.Sources {
+		size += int64(unsafe.Sizeof(s)) + int64(len(s.Name)+len(s.Type))
+	}
+	return size + sourceErrorsSize(r.Errors) + cacheInfoSize(r.Cache)
+}
+
+// EstimateSize 估算主题搜索结果占用的内存字节数
+func (r *TopicSearchResult) EstimateSize() int64 {
+	size := int64(unsafe.Sizeof(*r)) + int64(len(r.Query))
+	size += articlesSize(r.Articles) + repositoriesSize(r.Repositories)
+	for _, d := range r.Discussions {
+		size += int64(unsafe.Sizeof(d))
+		size += int64(len(d.ID) + len(d.Title) + len(d.URL) + len(d.Platform) + len(d.Author) + len(d.Content))
+		size += stringsSize(d.Tags)
+		for _, b := range d.CodeBlocks {
+			size += int64(unsafe.Sizeof(b)) + int64(len(b.Language)+len(b.Code)+len(b.Context))
+		}
+		size += cache.EstimateDataSize(d.Metadata)
+	}
+	size += stringsSize(r.Summary.TopicKeywords) + stringsSize(r.Summary.PopularLanguages)
+	size += stringsSize(r.Summary.TrendingTopics) + countsSize(r.Summary.SearchStats)
+	for _, p := range r.Sources {
+		size += int64(unsafe.Sizeof(p)) + int64(len(p.Name)+len(p.Type))
+	}
+	return size + sourceErrorsSize(r.Errors) + cacheInfoSize(r.Cache)
+}
+
+// EstimateSize 估算热门仓库结果占用的内存字节数
+func (r *TrendingReposResult) EstimateSize() int64 {
+	size := int64(unsafe.Sizeof(*r)) + repositoriesSize(r.Repositories)
+	size += int64(len(r.TimeRange) + len(r.Language))
+	for _, l := range r.Summary.TopLanguages {
+		size += int64(unsafe.Sizeof(l)) + int64(len(l.Name))
+	}
+	size += countsSize(r.Summary.CategoryStats) + countsSize(r.Summary.StarDistribution)
+	size += countsSize(r.Summary.ActivityLevel) + stringsSize(r.Summary.TrendingTopics)
+	for _, s := range r.Sources {
+		size += int64(unsafe.Sizeof(s)) + int64(len(s.Name))
+	}
+	return size + sourceErrorsSize(r.Errors) + cacheInfoSize(r.Cache)
+}
+
+// articlesSize 估算文章列表占用的字节数
+func articlesSize(articles []models.Article) int64 {
+	size := int64(0)
+	for i := range articles {
+		a := &articles[i]
+		size += int64(unsafe.Sizeof(*a))
+		size += int64(len(a.ID) + len(a.Title) + len(a.URL) + len(a.Source) + len(a.SourceType))
+		size += int64(len(a.Summary) + len(a.Content))
+		size += stringsSize(a.Tags) + cache.EstimateDataSize(a.Metadata)
+	}
+	return size
+}
+
+// repositoriesSize 估算仓库列表占用的字节数
+func repositoriesSize(repos []models.Repository) int64 {
+	size := int64(0)
+	for i := range repos {
+		r := &repos[i]
+		size += int64(unsafe.Sizeof(*r))
+		size += int64(len(r.ID) + len(r.Name) + len(r.FullName) + len(r.Description) + len(r.URL) + len(r.Language))
+		size += cache.EstimateDataSize(r.Metadata)
+	}
+	return size
+}
+
+// sourceErrorsSize 估算数据源失败信息占用的字节数
+func sourceErrorsSize(errors []SourceError) int64 {
+	size := int64(0)
+	for _, e := range errors {
+		size += int64(unsafe.Sizeof(e)) + int64(len(e.Source)+len(e.Kind)+len(e.Message))
+	}
+	return size
+}
+
+// stringsSize 估算字符串切片占用的字节数
+func stringsSize(values []string) int64 {
+	size := int64(len(values)) * int64(unsafe.Sizeof(""))
+	for _, v := range values {
+		size += int64(len(v))
+	}
+	return size
+}
+
+// countsSize 估算计数映射占用的字节数
+func countsSize(counts map[string]int) int64 {
+	size := int64(0)
+	for k := range counts {
+		// 16 为映射项的桶和哈希开销，与 cache 包的估算一致
+		size += int64(len(k)) + int64(unsafe.Sizeof("")+unsafe.Sizeof(0)) + 16
+	}
+	return size
+}
+
+// cacheInfoSize 缓存状态信息占用的字节数
+func cacheInfoSize(info *CacheInfo) int64 {
+	if info == nil {
+		return 0
+	}
+	return int64(unsafe.Sizeof(*info))
+}
@@ -0,0 +1,67 @@
+package tools
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+)
+
+// fillStrings 将值中所有可设置的字符串设为 s，列表添加一个同样填充的元素
+func fillStrings(v reflect.Value, s string) {
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if field := v.Field(i); field.CanSet() {
+				fillStrings(field, s)
+			}
+		}
+	case reflect.Slice:
+		elem := reflect.New(v.Type().Elem()).Elem()
+		fillStrings(elem, s)
+		v.Set(reflect.Append(v, elem))
+	}
+}
+
+// slicePaths 返回结构体及其嵌套结构体字段中所有列表字段的索引路径
+func slicePaths(t reflect.Type, prefix []int) [][]int {
+	var paths [][]int
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		path := append(append([]int{}, prefix...), i)
+		switch field.Type.Kind() {
+		case reflect.Slice:
+			paths = append(paths, path)
+		case reflect.Struct:
+			paths = append(paths, slicePaths(field.Type, path)...)
+		}
+	}
+	return paths
+}
+
+// TestResultEstimateSizeCoversSlices 每个列表字段中的内容都要计入结果大小，新增字段忘记累计时失败
+func TestResultEstimateSizeCoversSlices(t *testing.T) {
+	content := strings.Repeat("x", 1000)
+
+	for _, result := range []cache.Sizer{&WeeklyNewsResult{}, &TopicSearchResult{}, &TrendingReposResult{}} {
+		resultType := reflect.TypeOf(result).Elem()
+		empty := result.EstimateSize()
+
+		for _, path := range slicePaths(resultType, nil) {
+			value := reflect.New(resultType)
+			fillStrings(value.Elem().FieldByIndex(path), content)
+
+			name := resultType.FieldByIndex(path).Name
+			grown := value.Interface().(cache.Sizer).EstimateSize() - empty
+			if grown < int64(len(content)) {
+				t.Errorf("%s.%s: expected its content to add at least %d bytes, got %d", resultType.Name(), name, len(content), grown)
+			}
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package tools
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+)
+
+// 注册工具结果类型，使其可以写入磁盘缓存并在重启后还原
+func init() {
+	cache.RegisterType(sources.ToolWeeklyNews, &WeeklyNewsResult{})
+	cache.RegisterType(sources.ToolTopicSearch, &TopicSearchResult{})
+	cache.RegisterType(sources.ToolTrendingRepos, &TrendingReposResult{})
+}
+
+// DefaultCacheTTLs 各工具结果的默认缓存时间
+func DefaultCacheTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		sources.ToolWeeklyNews:    time.Hour,
+		sources.ToolTopicSearch:   30 * time.Minute,
+		sources.ToolTrendingRepos: 15 * time.Minute,
+	}
+}
+
+// DefaultStaleTTLs 各工具结果过了缓存时间后仍可返回旧结果（同时后台刷新）的时长
+func DefaultStaleTTLs() map[string]time.Duration {
+	return map[string]time.Duration{
+		sources.ToolWeeklyNews:    6 * time.Hour,
+		sources.ToolTopicSearch:   30 * time.Minute,
+		sources.ToolTrendingRepos: time.Hour,
+	}
+}
+
+// cacheTTLs 线程安全的工具缓存时间表，由Handler在三个工具间共享
+type cacheTTLs struct {
+	ttls  map[string]time.Duration
+	stale map[string]time.Duration
+	mu    sync.RWMutex
+}
+
+// newCacheTTLs 创建使用默认缓存时间的表
+func newCacheTTLs() *cacheTTLs {
+	return &cacheTTLs{ttls: DefaultCacheTTLs(), stale: DefaultStaleTTLs()}
+}
+
+// get 获取工具的缓存时间
+func (c *cacheTTLs) get(tool string) time.Duration {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ttls[tool]
+}
+
+// set 设置工具的缓存时间
+func (c *cacheTTLs) set(tool string, ttl time.Duration) error {
+	if !sources.IsKnownTool(tool) {
+		return fmt.Errorf("未知的工具 %q", tool)
+	}
+	if ttl <= 0 {
+		return fmt.Errorf("工具 %s 的缓存时间必须大于0", tool)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.ttls[tool] = ttl
+	return nil
+}
+
+// getStale 获取工具的软/硬TTL：结果在软TTL后变旧，在硬TTL后过期
+func (c *cacheTTLs) getStale(tool string) (soft, hard time.Duration) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.ttls[tool], c.ttls[tool] + c.stale[tool]
+}
+
+// setStale 设置工具结果过期后仍可返回旧结果的时长，0 表示关闭
+func (c *cacheTTLs) setStale(tool string, ttl time.Duration) error {
+	if !sources.IsKnownTool(tool) {
+		return fmt.Errorf("未知的工具 %q", tool)
+	}
+	if ttl < 0 {
+		return fmt.Errorf("工具 %s 的旧结果保留时间不能为负数", tool)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.stale[tool] = ttl
+	return nil
+}
+
+// CacheInfo 工具结果的缓存状态，随结果一起返回
+type CacheInfo struct {
+	AgeSeconds int64 `json:"ageSeconds"` // 结果写入缓存后经过的秒数
+	Stale      bool  `json:"stale"`      // 结果已超过缓存时间，正在后台刷新
+}
+
+// newCacheInfo 根据结果的年龄生成缓存状态，新采集的结果返回nil
+func newCacheInfo(age, softTTL time.Duration) *CacheInfo {
+	if age <= 0 {
+		return nil
+	}
+	return &CacheInfo{
+		AgeSeconds: int64(age / time.Second),
+		Stale:      age >= softTTL,
+	}
+}
+
+// String 返回文本和Markdown输出中附加的缓存说明
+func (c *CacheInfo) String() string {
+	if c == nil {
+		return ""
+	}
+	age := (time.Duration(c.AgeSeconds) * time.Second).String()
+	if c.Stale {
+		return fmt.Sprintf("Cached result from %s ago, refreshing in background", age)
+	}
+	return fmt.Sprintf("Cached result from %s ago", age)
+}
+
+// appendCacheNote 在格式化输出末尾附加缓存说明
+func appendCacheNote(output string, info *CacheInfo) string {
+	if info == nil {
+		return output
+	}
+	return output + "\n\n" + info.String() + "\n"
+}
@@ -4,14 +4,19 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"time"
 
 	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
 
 	"github.com/ZephyrDeng/dev-context/internal/cache"
 	"github.com/ZephyrDeng/dev-context/internal/collector"
 	"github.com/ZephyrDeng/dev-context/internal/formatter"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
 	"github.com/ZephyrDeng/dev-context/internal/processor"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
 )
 
 // Handler MCP工具处理器，负责注册和处理所有MCP工具调用
@@ -20,6 +25,9 @@ type Handler struct {
 	topicSearchService   *TopicSearchService
 	trendingReposService *TrendingReposService
 	validator            *Validator
+	sourceRegistry       *sources.Registry
+	cacheTTLs            *cacheTTLs
+	logger               *slog.Logger
 }
 
 // NewHandler 创建新的MCP工具处理器
@@ -29,12 +37,64 @@ func NewHandler(
 	processor *processor.Processor,
 	formatterFactory *formatter.FormatterFactory,
 ) *Handler {
-	return &Handler{
+	handler := &Handler{
 		weeklyNewsService:    NewWeeklyNewsService(cacheManager, collectorMgr, processor, formatterFactory),
 		topicSearchService:   NewTopicSearchService(cacheManager, collectorMgr, processor, formatterFactory),
 		trendingReposService: NewTrendingReposService(cacheManager, collectorMgr, processor, formatterFactory),
 		validator:            NewValidator(),
+		sourceRegistry:       sources.NewDefaultRegistry(),
+		cacheTTLs:            newCacheTTLs(),
 	}
+
+	// 三个工具共享同一个数据源注册表和缓存时间表，运行时修改对所有工具生效
+	handler.weeklyNewsService.sourceRegistry = handler.sourceRegistry
+	handler.topicSearchService.sourceRegistry = handler.sourceRegistry
+	handler.trendingReposService.sourceRegistry = handler.sourceRegistry
+	handler.weeklyNewsService.cacheTTLs = handler.cacheTTLs
+	handler.topicSearchService.cacheTTLs = handler.cacheTTLs
+	handler.trendingReposService.cacheTTLs = handler.cacheTTLs
+	handler.SetLogger(nil)
+
+	return handler
+}
+
+// SetLogger 设置处理器和各工具服务的日志记录器，服务的日志带有工具名；需在注册工具前调用，
+// logger 为nil时使用 slog.Default()
+func (h *Handler) SetLogger(logger *slog.Logger) {
+	logger = logging.OrDefault(logger)
+	h.logger = logger
+	h.weeklyNewsService.logger = logger.With(logging.Tool(sources.ToolWeeklyNews))
+	h.topicSearchService.logger = logger.With(logging.Tool(sources.ToolTopicSearch))
+	h.trendingReposService.logger = logger.With(logging.Tool(sources.ToolTrendingRepos))
+}
+
+// logToolCall 记录一次工具调用的耗时和结果
+func (h *Handler) logToolCall(ctx context.Context, tool string, started time.Time, err error) {
+	if err != nil {
+		h.logger.WarnContext(ctx, "工具调用失败", logging.Tool(tool), logging.Duration(time.Since(started)), logging.Err(err))
+		return
+	}
+	h.logger.InfoContext(ctx, "工具调用完成", logging.Tool(tool), logging.Duration(time.Since(started)))
+}
+
+// SourceRegistry 获取工具共享的数据源注册表
+func (h *Handler) SourceRegistry() *sources.Registry {
+	return h.sourceRegistry
+}
+
+// CacheTTL 获取工具结果的缓存时间
+func (h *Handler) CacheTTL(tool string) time.Duration {
+	return h.cacheTTLs.get(tool)
+}
+
+// SetCacheTTL 设置工具结果的缓存时间，只影响之后写入的结果
+func (h *Handler) SetCacheTTL(tool string, ttl time.Duration) error {
+	return h.cacheTTLs.set(tool, ttl)
+}
+
+// SetStaleTTL 设置工具结果超过缓存时间后仍可返回旧结果（同时后台刷新）的时长，0 表示关闭
+func (h *Handler) SetStaleTTL(tool string, ttl time.Duration) error {
+	return h.cacheTTLs.setStale(tool, ttl)
 }
 
 // RegisterTools 注册所有MCP工具到服务器
@@ -59,7 +119,7 @@ func (h *Handler) RegisterTools(server *mcp.Server) error {
 	}
 	registeredCount++
 
-	log.Printf("成功注册 %d 个MCP工具", registeredCount)
+	h.logger.Info("成功注册MCP工具", slog.Int("tools", registeredCount))
 	return nil
 }
 
@@ -83,6 +143,10 @@ func (h *Handler) registerWeeklyNewsTools(server *mcp.Server) error {
 		Name:        "weekly_news",
 		Description: "Get curated weekly frontend development news from multiple sources",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args WeeklyNewsArgs) (*mcp.CallToolResult, any, error) {
+		ctx = logging.WithSession(ctx, req.Session)
+		ctx, span := startToolSpan(ctx, req, sources.ToolWeeklyNews)
+		defer span.End()
+
 		// 转换参数
 		params := WeeklyNewsParams{
 			StartDate:      args.StartDate,
@@ -97,8 +161,12 @@ func (h *Handler) registerWeeklyNewsTools(server *mcp.Server) error {
 		}
 
 		// 调用服务
+		started := time.Now()
 		result, err := h.weeklyNewsService.GetWeeklyFrontendNews(ctx, params)
+		observeToolCall(sources.ToolWeeklyNews, started, err)
+		h.logToolCall(ctx, sources.ToolWeeklyNews, started, err)
 		if err != nil {
+			tracing.RecordError(span, err)
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
 					&mcp.TextContent{Text: fmt.Sprintf("Error getting weekly news: %v", err)},
@@ -112,6 +180,7 @@ func (h *Handler) registerWeeklyNewsTools(server *mcp.Server) error {
 			format = "json"
 		}
 
+		_, formatSpan := tracing.Start(ctx, "format", attribute.String("format", format))
 		var output string
 		switch format {
 		case "json":
@@ -128,6 +197,7 @@ func (h *Handler) registerWeeklyNewsTools(server *mcp.Server) error {
 			jsonData, _ := json.MarshalIndent(result, "", "  ")
 			output = string(jsonData)
 		}
+		formatSpan.End()
 
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -136,7 +206,7 @@ func (h *Handler) registerWeeklyNewsTools(server *mcp.Server) error {
 		}, nil, nil
 	})
 
-	log.Printf("周报新闻工具注册成功")
+	h.logger.Debug("工具注册成功", logging.Tool(sources.ToolWeeklyNews))
 	return nil
 }
 
@@ -161,6 +231,10 @@ func (h *Handler) registerTopicSearchTools(server *mcp.Server) error {
 		Name:        "topic_search",
 		Description: "Search and analyze specific frontend technologies and topics",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args TopicSearchArgs) (*mcp.CallToolResult, any, error) {
+		ctx = logging.WithSession(ctx, req.Session)
+		ctx, span := startToolSpan(ctx, req, sources.ToolTopicSearch)
+		defer span.End()
+
 		// 检查必需参数
 		if args.Query == "" {
 			return &mcp.CallToolResult{
@@ -185,8 +259,12 @@ func (h *Handler) registerTopicSearchTools(server *mcp.Server) error {
 		}
 
 		// 调用服务
+		started := time.Now()
 		result, err := h.topicSearchService.SearchFrontendTopic(ctx, params)
+		observeToolCall(sources.ToolTopicSearch, started, err)
+		h.logToolCall(ctx, sources.ToolTopicSearch, started, err)
 		if err != nil {
+			tracing.RecordError(span, err)
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
 					&mcp.TextContent{Text: fmt.Sprintf("Error searching topic: %v", err)},
@@ -200,6 +278,7 @@ func (h *Handler) registerTopicSearchTools(server *mcp.Server) error {
 			format = "json"
 		}
 
+		_, formatSpan := tracing.Start(ctx, "format", attribute.String("format", format))
 		var output string
 		switch format {
 		case "json":
@@ -216,6 +295,7 @@ func (h *Handler) registerTopicSearchTools(server *mcp.Server) error {
 			jsonData, _ := json.MarshalIndent(result, "", "  ")
 			output = string(jsonData)
 		}
+		formatSpan.End()
 
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -224,7 +304,7 @@ func (h *Handler) registerTopicSearchTools(server *mcp.Server) error {
 		}, nil, nil
 	})
 
-	log.Printf("主题搜索工具注册成功")
+	h.logger.Debug("工具注册成功", logging.Tool(sources.ToolTopicSearch))
 	return nil
 }
 
@@ -249,6 +329,10 @@ func (h *Handler) registerTrendingReposTools(server *mcp.Server) error {
 		Name:        "trending_repos",
 		Description: "Get GitHub trending repositories for frontend technologies",
 	}, func(ctx context.Context, req *mcp.CallToolRequest, args TrendingReposArgs) (*mcp.CallToolResult, any, error) {
+		ctx = logging.WithSession(ctx, req.Session)
+		ctx, span := startToolSpan(ctx, req, sources.ToolTrendingRepos)
+		defer span.End()
+
 		// 转换参数
 		params := TrendingReposParams{
 			Language:           args.Language,
@@ -264,8 +348,12 @@ func (h *Handler) registerTrendingReposTools(server *mcp.Server) error {
 		}
 
 		// 调用服务
+		started := time.Now()
 		result, err := h.trendingReposService.GetTrendingRepositories(ctx, params)
+		observeToolCall(sources.ToolTrendingRepos, started, err)
+		h.logToolCall(ctx, sources.ToolTrendingRepos, started, err)
 		if err != nil {
+			tracing.RecordError(span, err)
 			return &mcp.CallToolResult{
 				Content: []mcp.Content{
 					&mcp.TextContent{Text: fmt.Sprintf("Error getting trending repos: %v", err)},
@@ -279,6 +367,7 @@ func (h *Handler) registerTrendingReposTools(server *mcp.Server) error {
 			format = "json"
 		}
 
+		_, formatSpan := tracing.Start(ctx, "format", attribute.String("format", format))
 		var output string
 		switch format {
 		case "json":
@@ -295,6 +384,7 @@ func (h *Handler) registerTrendingReposTools(server *mcp.Server) error {
 			jsonData, _ := json.MarshalIndent(result, "", "  ")
 			output = string(jsonData)
 		}
+		formatSpan.End()
 
 		return &mcp.CallToolResult{
 			Content: []mcp.Content{
@@ -303,7 +393,7 @@ func (h *Handler) registerTrendingReposTools(server *mcp.Server) error {
 		}, nil, nil
 	})
 
-	log.Printf("热门仓库工具注册成功")
+	h.logger.Debug("工具注册成功", logging.Tool(sources.ToolTrendingRepos))
 	return nil
 }
 
@@ -313,8 +403,11 @@ func (h *Handler) handleGetWeeklyFrontendNews(
 	req *mcp.CallToolRequest,
 	args WeeklyNewsParams,
 ) (*mcp.CallToolResult, any, error) {
-	log.Printf("处理 get_weekly_frontend_news 请求: category=%s, timeRange=%s-%s",
-		args.Category, args.StartDate, args.EndDate)
+	h.logger.DebugContext(ctx, "处理 get_weekly_frontend_news 请求",
+		slog.String("category", args.Category),
+		slog.String("start", args.StartDate),
+		slog.String("end", args.EndDate),
+	)
 
 	// 参数验证
 	if err := h.validator.ValidateWeeklyNewsParams(args); err != nil {
@@ -362,8 +455,10 @@ func (h *Handler) handleSearchFrontendTopic(
 	req *mcp.CallToolRequest,
 	args TopicSearchParams,
 ) (*mcp.CallToolResult, any, error) {
-	log.Printf("处理 search_frontend_topic 请求: query=%s, platform=%s",
-		args.Query, args.Platform)
+	h.logger.DebugContext(ctx, "处理 search_frontend_topic 请求",
+		slog.String("query", args.Query),
+		slog.String("platform", args.Platform),
+	)
 
 	// 参数验证
 	if err := h.validator.ValidateTopicSearchParams(args); err != nil {
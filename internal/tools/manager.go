@@ -2,6 +2,7 @@ package tools
 
 import (
 	"context"
+	"log/slog"
 	"sync"
 	"time"
 
@@ -9,6 +10,7 @@ import (
 	"github.com/ZephyrDeng/dev-context/internal/collector"
 	"github.com/ZephyrDeng/dev-context/internal/formatter"
 	"github.com/ZephyrDeng/dev-context/internal/processor"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
 )
 
 // ToolsManager 工具管理器，提供并发请求处理和缓存优化
@@ -16,6 +18,7 @@ type ToolsManager struct {
 	handler     *Handler
 	concurrency *ConcurrencyManager
 	cache       *cache.CacheManager
+	refresh     *RefreshScheduler
 	mu          sync.RWMutex
 }
 
@@ -50,6 +53,7 @@ func NewToolsManager(
 		handler:     handler,
 		concurrency: concurrency,
 		cache:       cacheManager,
+		refresh:     NewRefreshScheduler(handler),
 	}
 }
 
@@ -58,6 +62,22 @@ func (tm *ToolsManager) GetHandler() *Handler {
 	return tm.handler
 }
 
+// SourceRegistry 获取数据源注册表，可在运行时增删或启停数据源
+func (tm *ToolsManager) SourceRegistry() *sources.Registry {
+	return tm.handler.SourceRegistry()
+}
+
+// RefreshScheduler 获取定时刷新调度器
+func (tm *ToolsManager) RefreshScheduler() *RefreshScheduler {
+	return tm.refresh
+}
+
+// SetLogger 设置工具处理器、各工具服务和定时刷新的日志记录器，需在注册工具前调用
+func (tm *ToolsManager) SetLogger(logger *slog.Logger) {
+	tm.handler.SetLogger(logger)
+	tm.refresh.SetLogger(logger)
+}
+
 // ExecuteWithConcurrency 并发执行工具调用
 func (tm *ToolsManager) ExecuteWithConcurrency(ctx context.Context, jobID string, fn func() error) error {
 	// 获取并发信号量
@@ -141,30 +161,11 @@ type ManagerStats struct {
 	UpdatedAt      time.Time `json:"updatedAt"`
 }
 
-// WarmupCache 预热缓存，为常见查询预加载数据
+// WarmupCache 预热缓存，立即为默认刷新配置中的常见查询采集一次数据
+//
+// 需要持续保持缓存有效时使用 RefreshScheduler。
 func (tm *ToolsManager) WarmupCache(ctx context.Context) error {
-	// 预热周报新闻缓存
-	go func() {
-		params := WeeklyNewsParams{
-			MaxResults: 30,
-			MinQuality: 0.5,
-			Format:     "json",
-		}
-		_, _ = tm.handler.weeklyNewsService.GetWeeklyFrontendNews(ctx, params)
-	}()
-
-	// 预热热门仓库缓存
-	go func() {
-		params := TrendingReposParams{
-			TimeRange:  "weekly",
-			MaxResults: 30,
-			MinStars:   10,
-			Format:     "json",
-		}
-		_, _ = tm.handler.trendingReposService.GetTrendingRepositories(ctx, params)
-	}()
-
-	return nil
+	return tm.refresh.RunOnce(ctx, DefaultRefreshConfig())
 }
 
 // HealthCheck 健康检查
@@ -196,5 +197,8 @@ func (tm *ToolsManager) HealthCheck(ctx context.Context) map[string]interface{}
 	}
 	health["tools"] = toolsHealth
 
+	// 定时刷新状态
+	health["refresh"] = tm.refresh.Status()
+
 	return health
 }
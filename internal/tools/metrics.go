@@ -0,0 +1,29 @@
+package tools
+
+import (
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/metrics"
+)
+
+// 各 MCP 工具的调用指标，注册到 metrics.Default
+var (
+	toolCalls = metrics.NewCounterVec("devcontext_tool_calls_total",
+		"MCP tool calls per tool and result.", "tool", "status")
+	toolDuration = metrics.NewHistogramVec("devcontext_tool_duration_seconds",
+		"MCP tool call latency including collection, processing and caching.", nil, "tool")
+)
+
+func init() {
+	metrics.Default.Register(toolCalls, toolDuration)
+}
+
+// observeToolCall 记录一次工具调用的耗时和结果
+func observeToolCall(tool string, started time.Time, err error) {
+	status := "ok"
+	if err != nil {
+		status = "error"
+	}
+	toolCalls.Inc(tool, status)
+	toolDuration.Observe(time.Since(started).Seconds(), tool)
+}
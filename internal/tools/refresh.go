@@ -0,0 +1,378 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/logging"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
+)
+
+// refreshRetryDelay 刷新失败后的首次重试间隔，之后每次失败翻倍直到 MaxBackoff
+const refreshRetryDelay = time.Minute
+
+// RefreshConfig 定时刷新配置
+type RefreshConfig struct {
+	Ahead      time.Duration // 在工具缓存时间到期前多久刷新，使用户请求始终命中新结果
+	Jitter     float64       // 刷新时间的随机浮动比例（0-1），避免多个查询或实例同时采集
+	MaxBackoff time.Duration // 采集失败后重试间隔的上限
+
+	// ActiveFrom 和 ActiveTo 为每天执行刷新的时段（距本地零点的时长），两者相等表示全天。
+	// ActiveFrom 大于 ActiveTo 时时段跨过零点
+	ActiveFrom time.Duration
+	ActiveTo   time.Duration
+
+	WeeklyNewsCategories []string // 刷新的周报分类，空字符串表示不限分类
+	TrendingLanguages    []string // 刷新的热门仓库语言，空字符串表示不限语言
+	TrendingTimeRanges   []string // 刷新的热门仓库时间范围：daily、weekly、monthly
+}
+
+// DefaultRefreshConfig 默认刷新工具默认参数下的周报和本周热门仓库
+func DefaultRefreshConfig() *RefreshConfig {
+	return &RefreshConfig{
+		Ahead:                5 * time.Minute,
+		Jitter:               0.1,
+		MaxBackoff:           30 * time.Minute,
+		WeeklyNewsCategories: []string{""},
+		TrendingLanguages:    []string{""},
+		TrendingTimeRanges:   []string{"weekly"},
+	}
+}
+
+// RefreshStatus 定时刷新任务的状态
+type RefreshStatus struct {
+	Name                string        `json:"name"`
+	Tool                string        `json:"tool"`
+	Runs                int64         `json:"runs"`
+	Skips               int64         `json:"skips"` // 缓存结果仍然新鲜而未采集的次数
+	Failures            int64         `json:"failures"`
+	ConsecutiveFailures int           `json:"consecutiveFailures"`
+	LastRun             time.Time     `json:"lastRun,omitempty"`
+	LastSuccess         time.Time     `json:"lastSuccess,omitempty"`
+	LastDuration        time.Duration `json:"lastDuration"`
+	LastError           string        `json:"lastError,omitempty"`
+	NextRun             time.Time     `json:"nextRun,omitempty"`
+}
+
+// refreshJob 一个定时刷新的查询
+type refreshJob struct {
+	name   string
+	tool   string
+	run    func(ctx context.Context, maxAge time.Duration) (time.Duration, error) // 返回之后缓存结果的年龄
+	status RefreshStatus
+}
+
+// RefreshScheduler 定时刷新常用查询的缓存
+//
+// 每个查询在其缓存结果到期前 Ahead 刷新一次，刷新时间带随机浮动；
+// 采集失败时按指数退避重试，ActiveFrom/ActiveTo 之外的时段不刷新。
+// 启动时立即检查一次：缓存中没有结果或结果即将到期时采集，否则按结果的剩余时间安排下次刷新，
+// 因此重启后保留的磁盘缓存和其他实例写入共享后端的结果不会被重复采集。
+type RefreshScheduler struct {
+	handler *Handler
+	mu      sync.Mutex
+	jobs    map[string]*refreshJob
+	parent  context.Context
+	cancel  context.CancelFunc
+	wg      sync.WaitGroup
+	now     func() time.Time
+	random  func() float64
+	logger  *slog.Logger
+}
+
+// NewRefreshScheduler 创建定时刷新调度器
+func NewRefreshScheduler(handler *Handler) *RefreshScheduler {
+	return &RefreshScheduler{
+		handler: handler,
+		jobs:    make(map[string]*refreshJob),
+		now:     time.Now,
+		random:  rand.Float64,
+		logger:  slog.Default(),
+	}
+}
+
+// SetLogger 设置记录刷新失败的日志记录器，logger 为nil时使用 slog.Default()
+func (s *RefreshScheduler) SetLogger(logger *slog.Logger) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.logger = logging.OrDefault(logger)
+}
+
+// Start 按配置启动刷新任务，ctx 取消时所有任务退出
+func (s *RefreshScheduler) Start(ctx context.Context, config *RefreshConfig) {
+	s.mu.Lock()
+	s.parent = ctx
+	s.mu.Unlock()
+	s.Update(config)
+}
+
+// Update 以新配置替换正在运行的任务，同名任务保留其状态；config 为nil时停止所有任务
+func (s *RefreshScheduler) Update(config *RefreshConfig) {
+	s.Stop()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if config == nil || s.parent == nil {
+		s.jobs = make(map[string]*refreshJob)
+		return
+	}
+
+	jobs := make(map[string]*refreshJob)
+	for _, job := range s.buildJobs(config) {
+		if old, exists := s.jobs[job.name]; exists {
+			job.status = old.status
+		}
+		job.status.NextRun = time.Time{}
+		jobs[job.name] = job
+	}
+	s.jobs = jobs
+
+	ctx, cancel := context.WithCancel(s.parent)
+	s.cancel = cancel
+	for _, job := range jobs {
+		s.wg.Add(1)
+		go s.loop(ctx, job, config)
+	}
+}
+
+// Stop 停止所有任务并等待进行中的刷新结束
+func (s *RefreshScheduler) Stop() {
+	s.mu.Lock()
+	cancel := s.cancel
+	s.cancel = nil
+	s.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	s.wg.Wait()
+}
+
+// RunOnce 立即执行一次配置中的所有刷新，缓存结果仍然新鲜的查询跳过，返回失败的合并错误
+func (s *RefreshScheduler) RunOnce(ctx context.Context, config *RefreshConfig) error {
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	for _, job := range s.buildJobs(config) {
+		wg.Add(1)
+		go func(job *refreshJob) {
+			defer wg.Done()
+			if _, err := job.run(ctx, config.interval(s.handler.CacheTTL(job.tool))); err != nil {
+				mu.Lock()
+				errs = append(errs, fmt.Errorf("%s: %w", job.name, err))
+				mu.Unlock()
+			}
+		}(job)
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Status 返回各任务的状态，按名称排序
+func (s *RefreshScheduler) Status() []RefreshStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	statuses := make([]RefreshStatus, 0, len(s.jobs))
+	for _, job := range s.jobs {
+		statuses = append(statuses, job.status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Name < statuses[j].Name
+	})
+	return statuses
+}
+
+// buildJobs 根据配置生成刷新任务
+func (s *RefreshScheduler) buildJobs(config *RefreshConfig) []*refreshJob {
+	var jobs []*refreshJob
+
+	for _, category := range config.WeeklyNewsCategories {
+		params := WeeklyNewsParams{Category: category}
+		jobs = append(jobs, &refreshJob{
+			name: jobName(sources.ToolWeeklyNews, category),
+			tool: sources.ToolWeeklyNews,
+			run: func(ctx context.Context, maxAge time.Duration) (time.Duration, error) {
+				return s.handler.weeklyNewsService.RefreshWeeklyFrontendNews(ctx, params, maxAge)
+			},
+		})
+	}
+
+	for _, language := range config.TrendingLanguages {
+		for _, timeRange := range config.TrendingTimeRanges {
+			params := TrendingReposParams{Language: language, TimeRange: timeRange}
+			jobs = append(jobs, &refreshJob{
+				name: jobName(sources.ToolTrendingRepos, language, timeRange),
+				tool: sources.ToolTrendingRepos,
+				run: func(ctx context.Context, maxAge time.Duration) (time.Duration, error) {
+					return s.handler.trendingReposService.RefreshTrendingRepositories(ctx, params, maxAge)
+				},
+			})
+		}
+	}
+
+	for _, job := range jobs {
+		job.status = RefreshStatus{Name: job.name, Tool: job.tool}
+	}
+	return jobs
+}
+
+// jobName 由工具名和非空参数组成任务名，例如 trending_repos:weekly
+func jobName(tool string, parts ...string) string {
+	name := tool
+	for _, part := range parts {
+		if part != "" {
+			name += ":" + part
+		}
+	}
+	return name
+}
+
+// loop 执行单个任务直到 ctx 取消，首次立即检查缓存结果
+func (s *RefreshScheduler) loop(ctx context.Context, job *refreshJob, config *RefreshConfig) {
+	defer s.wg.Done()
+
+	delay := time.Duration(0)
+	for {
+		s.mu.Lock()
+		job.status.NextRun = s.now().Add(delay)
+		s.mu.Unlock()
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+		}
+
+		now := s.now()
+		if !config.active(now) {
+			delay = config.untilActive(now)
+			continue
+		}
+
+		age, err := job.run(ctx, config.interval(s.handler.CacheTTL(job.tool)))
+		if ctx.Err() != nil {
+			return
+		}
+		delay = s.record(job, config, now, age, err)
+	}
+}
+
+// record 记录一次执行的结果并返回距下次执行的时间
+//
+// age 为执行后缓存结果的年龄：大于0表示结果仍然新鲜而未采集，下次在结果剩余的刷新间隔后执行。
+func (s *RefreshScheduler) record(job *refreshJob, config *RefreshConfig, started time.Time, age time.Duration, err error) time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	status := &job.status
+	interval := config.interval(s.handler.CacheTTL(job.tool))
+	if err == nil && age > 0 {
+		status.Skips++
+		remaining := interval - age
+		if remaining < 0 {
+			remaining = 0
+		}
+		s.logger.Debug("缓存结果仍然新鲜，跳过刷新",
+			logging.Tool(job.tool),
+			slog.String("job", job.name),
+			slog.Duration("age", age.Round(time.Second)),
+			slog.Duration("next_in", remaining.Round(time.Second)),
+		)
+		return config.jitter(remaining, s.random())
+	}
+
+	status.Runs++
+	status.LastRun = started
+	status.LastDuration = s.now().Sub(started)
+
+	if err != nil {
+		status.Failures++
+		status.ConsecutiveFailures++
+		status.LastError = err.Error()
+		delay := config.jitter(config.backoff(status.ConsecutiveFailures), s.random())
+		s.logger.Warn("定时刷新失败",
+			logging.Tool(job.tool),
+			slog.String("job", job.name),
+			slog.Int("consecutive_failures", status.ConsecutiveFailures),
+			slog.Duration("retry_in", delay.Round(time.Second)),
+			logging.Err(err),
+		)
+		return delay
+	}
+
+	status.ConsecutiveFailures = 0
+	status.LastError = ""
+	status.LastSuccess = started
+	return config.jitter(interval, s.random())
+}
+
+// interval 根据工具的缓存时间计算刷新间隔，至少为缓存时间的一半
+func (c *RefreshConfig) interval(ttl time.Duration) time.Duration {
+	interval := ttl - c.Ahead
+	if interval < ttl/2 {
+		interval = ttl / 2
+	}
+	if interval < refreshRetryDelay {
+		interval = refreshRetryDelay
+	}
+	return interval
+}
+
+// backoff 连续失败 failures 次后的重试间隔
+func (c *RefreshConfig) backoff(failures int) time.Duration {
+	delay := refreshRetryDelay
+	for i := 1; i < failures && delay < c.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if c.MaxBackoff > 0 && delay > c.MaxBackoff {
+		delay = c.MaxBackoff
+	}
+	return delay
+}
+
+// jitter 按 Jitter 比例随机调整间隔，r 为 [0,1) 的随机数
+func (c *RefreshConfig) jitter(d time.Duration, r float64) time.Duration {
+	if c.Jitter <= 0 {
+		return d
+	}
+	return d + time.Duration(float64(d)*c.Jitter*(2*r-1))
+}
+
+// active 判断 t 是否在刷新时段内
+func (c *RefreshConfig) active(t time.Time) bool {
+	if c.ActiveFrom == c.ActiveTo {
+		return true
+	}
+	offset := t.Sub(midnight(t))
+	if c.ActiveFrom < c.ActiveTo {
+		return offset >= c.ActiveFrom && offset < c.ActiveTo
+	}
+	return offset >= c.ActiveFrom || offset < c.ActiveTo
+}
+
+// untilActive 距离下一个刷新时段开始的时长
+func (c *RefreshConfig) untilActive(t time.Time) time.Duration {
+	start := midnight(t).Add(c.ActiveFrom)
+	if !start.After(t) {
+		start = start.AddDate(0, 0, 1)
+	}
+	return start.Sub(t)
+}
+
+// midnight 返回 t 当天的本地零点
+func midnight(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/cache"
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+)
+
+func TestRefreshConfigSchedule(t *testing.T) {
+	config := &RefreshConfig{Ahead: 5 * time.Minute, Jitter: 0.1, MaxBackoff: 10 * time.Minute}
+
+	if got := config.interval(time.Hour); got != 55*time.Minute {
+		t.Errorf("Expected refresh 5m before a 1h TTL, got %v", got)
+	}
+	if got := config.interval(6 * time.Minute); got != 3*time.Minute {
+		t.Errorf("Expected at least half of a short TTL, got %v", got)
+	}
+
+	backoffs := []time.Duration{time.Minute, 2 * time.Minute, 4 * time.Minute, 8 * time.Minute, 10 * time.Minute, 10 * time.Minute}
+	for i, want := range backoffs {
+		if got := config.backoff(i + 1); got != want {
+			t.Errorf("Expected backoff %v after %d failures, got %v", want, i+1, got)
+		}
+	}
+
+	if got := config.jitter(time.Hour, 0); got != 54*time.Minute {
+		t.Errorf("Expected -10%% jitter, got %v", got)
+	}
+	if got := config.jitter(time.Hour, 0.5); got != time.Hour {
+		t.Errorf("Expected no jitter at the midpoint, got %v", got)
+	}
+}
+
+func TestRefreshConfigActiveHours(t *testing.T) {
+	day := func(hour, minute int) time.Time {
+		return time.Date(2024, 3, 4, hour, minute, 0, 0, time.Local)
+	}
+
+	always := &RefreshConfig{}
+	if !always.active(day(3, 0)) {
+		t.Error("Expected empty active hours to cover the whole day")
+	}
+
+	work := &RefreshConfig{ActiveFrom: 8 * time.Hour, ActiveTo: 20 * time.Hour}
+	if !work.active(day(8, 0)) || !work.active(day(19, 59)) || work.active(day(20, 0)) || work.active(day(7, 59)) {
+		t.Error("Unexpected activity for 08:00-20:00")
+	}
+	if got := work.untilActive(day(6, 30)); got != 90*time.Minute {
+		t.Errorf("Expected to wait until 08:00 today, got %v", got)
+	}
+	if got := work.untilActive(day(21, 0)); got != 11*time.Hour {
+		t.Errorf("Expected to wait until 08:00 tomorrow, got %v", got)
+	}
+
+	night := &RefreshConfig{ActiveFrom: 22 * time.Hour, ActiveTo: 6 * time.Hour}
+	if !night.active(day(23, 0)) || !night.active(day(5, 0)) || night.active(day(12, 0)) {
+		t.Error("Unexpected activity for 22:00-06:00")
+	}
+}
+
+func TestRefreshSchedulerRecord(t *testing.T) {
+	now := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	scheduler := NewRefreshScheduler(NewHandler(nil, nil, nil, nil))
+	scheduler.now = func() time.Time { return now }
+	scheduler.random = func() float64 { return 0.5 }
+
+	config := &RefreshConfig{Ahead: 5 * time.Minute, MaxBackoff: time.Hour}
+	job := scheduler.buildJobs(DefaultRefreshConfig())[0]
+
+	if delay := scheduler.record(job, config, now, 0, errors.New("source down")); delay != time.Minute {
+		t.Errorf("Expected first retry after 1m, got %v", delay)
+	}
+	if delay := scheduler.record(job, config, now, 0, errors.New("source down")); delay != 2*time.Minute {
+		t.Errorf("Expected second retry after 2m, got %v", delay)
+	}
+	if job.status.ConsecutiveFailures != 2 || job.status.LastError != "source down" {
+		t.Errorf("Unexpected status after failures: %+v", job.status)
+	}
+
+	delay := scheduler.record(job, config, now, 0, nil)
+	if want := scheduler.handler.CacheTTL(job.tool) - 5*time.Minute; delay != want {
+		t.Errorf("Expected next refresh after %v, got %v", want, delay)
+	}
+	if job.status.Runs != 3 || job.status.Failures != 2 || job.status.ConsecutiveFailures != 0 || !job.status.LastSuccess.Equal(now) {
+		t.Errorf("Unexpected status after success: %+v", job.status)
+	}
+
+	// 缓存结果仍然新鲜时按剩余时间安排下次刷新，不计为一次采集
+	interval := scheduler.handler.CacheTTL(job.tool) - 5*time.Minute
+	if delay := scheduler.record(job, config, now, 10*time.Minute, nil); delay != interval-10*time.Minute {
+		t.Errorf("Expected next refresh after the remaining %v, got %v", interval-10*time.Minute, delay)
+	}
+	if job.status.Runs != 3 || job.status.Skips != 1 {
+		t.Errorf("Unexpected status after skip: %+v", job.status)
+	}
+}
+
+// countingCollectorManager 记录采集次数，不访问网络
+type countingCollectorManager struct {
+	collector.CollectorManager
+	calls atomic.Int32
+}
+
+func (m *countingCollectorManager) CollectAll(ctx context.Context, configs []collector.CollectConfig) []collector.CollectResult {
+	m.calls.Add(1)
+	return nil
+}
+
+func TestRefreshSchedulerSkipsFreshEntries(t *testing.T) {
+	cacheManager := cache.NewCacheManager(nil)
+	defer cacheManager.Close()
+
+	collectors := &countingCollectorManager{}
+	var collectorMgr collector.CollectorManager = collectors
+	handler := NewHandler(cacheManager, &collectorMgr, nil, nil)
+
+	// 启动前缓存中已有刚写入的结果，例如重启后的磁盘缓存或其他实例写入的结果
+	params := TrendingReposParams{TimeRange: "weekly"}
+	service := handler.trendingReposService
+	if err := service.validateParams(&params); err != nil {
+		t.Fatalf("validateParams failed: %v", err)
+	}
+	key := service.generateCacheKey(params, service.selectSources(params))
+	if err := cacheManager.SetWithTTL(key, &TrendingReposResult{}, time.Hour); err != nil {
+		t.Fatalf("SetWithTTL failed: %v", err)
+	}
+
+	scheduler := NewRefreshScheduler(handler)
+	config := &RefreshConfig{Ahead: 5 * time.Minute, TrendingLanguages: []string{""}, TrendingTimeRanges: []string{"weekly"}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	scheduler.Start(ctx, config)
+	defer scheduler.Stop()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		statuses := scheduler.Status()
+		if len(statuses) == 1 && statuses[0].Skips == 1 {
+			status := statuses[0]
+			if status.Runs != 0 || collectors.calls.Load() != 0 {
+				t.Errorf("Expected fresh entry not to be collected again, got %d runs and %d collections", status.Runs, collectors.calls.Load())
+			}
+			interval := config.interval(handler.CacheTTL(status.Tool))
+			if until := time.Until(status.NextRun); until < interval-time.Minute || until > interval {
+				t.Errorf("Expected next refresh after the entry's remaining %v, got %v", interval, until)
+			}
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected the job to check the cached entry, got %+v (%d collections)", statuses, collectors.calls.Load())
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,70 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+)
+
+// SourceError 单个数据源的采集失败信息，随结果返回，让调用方知道结果缺少了哪些数据源
+type SourceError struct {
+	Source            string `json:"source"`
+	Kind              string `json:"kind"`                        // collector.ErrorKind 的分类，如 http、rate_limit、parse
+	StatusCode        int    `json:"statusCode,omitempty"`        // HTTP 状态码
+	RetryAfterSeconds int64  `json:"retryAfterSeconds,omitempty"` // 服务器要求的等待时间
+	Message           string `json:"message"`
+}
+
+// newSourceError 根据数据源名称和采集错误创建失败信息
+func newSourceError(source string, err error) SourceError {
+	sourceErr := SourceError{
+		Source:  source,
+		Kind:    collector.ErrorKind(err),
+		Message: err.Error(),
+	}
+
+	var statusErr *collector.HTTPStatusError
+	if errors.As(err, &statusErr) {
+		sourceErr.StatusCode = statusErr.StatusCode
+		sourceErr.RetryAfterSeconds = int64(statusErr.RetryAfter / time.Second)
+	}
+	var rateLimitErr *collector.RateLimitError
+	if errors.As(err, &rateLimitErr) && rateLimitErr.RetryAfter > 0 {
+		sourceErr.RetryAfterSeconds = int64(rateLimitErr.RetryAfter / time.Second)
+	}
+	return sourceErr
+}
+
+// appendSourceErrors 在格式化结果后追加采集失败的数据源列表
+func appendSourceErrors(output string, sourceErrors []SourceError, format string) string {
+	if len(sourceErrors) == 0 {
+		return output
+	}
+
+	var b strings.Builder
+	b.WriteString(output)
+	if format == "markdown" {
+		fmt.Fprintf(&b, "\n\n## Failed sources (%d)\n\n", len(sourceErrors))
+	} else {
+		fmt.Fprintf(&b, "\n\nFAILED SOURCES (%d)\n", len(sourceErrors))
+	}
+
+	for _, sourceErr := range sourceErrors {
+		detail := sourceErr.Kind
+		if sourceErr.StatusCode != 0 {
+			detail += fmt.Sprintf(" %d", sourceErr.StatusCode)
+		}
+		if sourceErr.RetryAfterSeconds > 0 {
+			detail += fmt.Sprintf(", retry after %s", time.Duration(sourceErr.RetryAfterSeconds)*time.Second)
+		}
+		if format == "markdown" {
+			fmt.Fprintf(&b, "- **%s** (%s): %s\n", sourceErr.Source, detail, sourceErr.Message)
+		} else {
+			fmt.Fprintf(&b, "\n- %s (%s)\n  %s\n", sourceErr.Source, detail, sourceErr.Message)
+		}
+	}
+	return b.String()
+}
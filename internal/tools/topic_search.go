@@ -3,7 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"math"
 	"sort"
 	"strconv"
@@ -14,8 +14,10 @@ import (
 	"github.com/ZephyrDeng/dev-context/internal/cache"
 	"github.com/ZephyrDeng/dev-context/internal/collector"
 	"github.com/ZephyrDeng/dev-context/internal/formatter"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
 	"github.com/ZephyrDeng/dev-context/internal/models"
 	"github.com/ZephyrDeng/dev-context/internal/processor"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
 )
 
 // TopicSearchParams 主题搜索参数
@@ -61,6 +63,8 @@ type TopicSearchResult struct {
 	SearchTime   time.Time           `json:"searchTime"`
 	TotalResults int                 `json:"totalResults"`
 	Sources      []PlatformInfo      `json:"sources"`
+	Errors       []SourceError       `json:"errors,omitempty"` // 搜索失败的数据源
+	Cache        *CacheInfo          `json:"cache,omitempty"`
 }
 
 // Discussion 讨论信息
@@ -110,6 +114,9 @@ type TopicSearchService struct {
 	collectorMgr     *collector.CollectorManager
 	processor        *processor.Processor
 	formatterFactory *formatter.FormatterFactory
+	sourceRegistry   *sources.Registry
+	cacheTTLs        *cacheTTLs
+	logger           *slog.Logger
 	mu               sync.RWMutex
 }
 
@@ -125,6 +132,9 @@ func NewTopicSearchService(
 		collectorMgr:     collectorMgr,
 		processor:        processor,
 		formatterFactory: formatterFactory,
+		sourceRegistry:   sources.NewDefaultRegistry(),
+		cacheTTLs:        newCacheTTLs(),
+		logger:           slog.Default(),
 	}
 }
 
@@ -135,35 +145,55 @@ func (t *TopicSearchService) SearchFrontendTopic(ctx context.Context, params Top
 		return nil, fmt.Errorf("参数验证失败: %w", err)
 	}
 
-	// 2. 生成缓存键
-	cacheKey := t.generateCacheKey(params)
+	// 2. 选定数据源并生成缓存键，搜索使用同一份数据源快照
+	selected := t.selectSources(params)
+	cacheKey := t.generateCacheKey(params, selected)
 
-	// 3. 检查缓存
-	if cached, found := t.cacheManager.Get(cacheKey); found {
-		if result, ok := cached.(*TopicSearchResult); ok {
-			log.Printf("从缓存返回主题搜索结果: %s", params.Query)
-			return result, nil
+	// 3. 搜索、处理并缓存结果，结果变旧后先返回旧结果并在后台刷新
+	softTTL, hardTTL := t.cacheTTLs.getStale(sources.ToolTopicSearch)
+	cached, age, err := t.cacheManager.GetOrRevalidate(ctx, cacheKey, softTTL, hardTTL, func(ctx context.Context) (interface{}, error) {
+		started := time.Now()
+
+		// 并发搜索多个平台
+		searchResults, err := t.searchAcrossPlatforms(ctx, params, selected)
+		if err != nil {
+			return nil, fmt.Errorf("跨平台搜索失败: %w", err)
 		}
-	}
 
-	// 4. 并发搜索多个平台
-	searchResults, err := t.searchAcrossPlatforms(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("跨平台搜索失败: %w", err)
-	}
+		// 处理和排序结果
+		result, err := t.processSearchResults(searchResults, params)
+		if err != nil {
+			return nil, fmt.Errorf("结果处理失败: %w", err)
+		}
 
-	// 5. 处理和排序结果
-	result, err := t.processSearchResults(searchResults, params)
+		t.logger.InfoContext(ctx, "成功搜索主题",
+			slog.String("query", params.Query),
+			slog.Int("results", result.TotalResults),
+			logging.Duration(time.Since(started)),
+		)
+		return result, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("结果处理失败: %w", err)
+		return nil, err
 	}
 
-	// 6. 缓存结果 (缓存30分钟)
-	t.cacheManager.SetWithTTL(cacheKey, result, 30*time.Minute)
+	stored, ok := cached.(*TopicSearchResult)
+	if !ok {
+		return nil, fmt.Errorf("缓存中的主题搜索结果类型错误: %T", cached)
+	}
 
-	log.Printf("成功搜索主题 '%s'，找到 %d 个结果", params.Query, result.TotalResults)
+	// 缓存中的结果是共享的，复制后再附加缓存状态
+	result := *stored
+	result.Cache = newCacheInfo(age, softTTL)
+	if result.Cache != nil {
+		t.logger.InfoContext(ctx, "从缓存返回主题搜索结果",
+			logging.CacheKey(cacheKey),
+			slog.String("query", params.Query),
+			slog.Any("cache", result.Cache),
+		)
+	}
 
-	return result, nil
+	return &result, nil
 }
 
 // validateParams 验证参数并设置默认值
@@ -225,9 +255,9 @@ func (t *TopicSearchService) validateParams(params *TopicSearchParams) error {
 	return nil
 }
 
-// generateCacheKey 生成缓存键
-func (t *TopicSearchService) generateCacheKey(params TopicSearchParams) string {
-	return fmt.Sprintf("topic_search:%s:%s:%s:%s:%s:%d:%.1f",
+// generateCacheKey 生成缓存键，包含所选数据源的指纹
+func (t *TopicSearchService) generateCacheKey(params TopicSearchParams, selected []sources.Source) string {
+	return fmt.Sprintf("topic_search:%s:%s:%s:%s:%s:%d:%.1f:%s",
 		params.Query,
 		params.Language,
 		params.Platform,
@@ -235,24 +265,25 @@ func (t *TopicSearchService) generateCacheKey(params TopicSearchParams) string {
 		params.TimeRange,
 		params.MaxResults,
 		params.MinScore,
+		sources.Fingerprint(selected),
 	)
 }
 
 // searchAcrossPlatforms 跨平台搜索
-func (t *TopicSearchService) searchAcrossPlatforms(ctx context.Context, params TopicSearchParams) (*multiPlatformResults, error) {
+func (t *TopicSearchService) searchAcrossPlatforms(ctx context.Context, params TopicSearchParams, selected []sources.Source) (*multiPlatformResults, error) {
 	var wg sync.WaitGroup
 	results := &multiPlatformResults{}
 
 	// 获取搜索配置
-	searchConfigs := t.getSearchConfigs(params)
+	searchConfigs := t.getSearchConfigs(params, selected)
 
 	// 并发搜索各个平台，但限制并发数避免资源争抢
 	semaphore := make(chan struct{}, 2) // 最大并发数为2
 
 	// 为每个平台启动goroutine，使用信号量控制并发
-	for platform, config := range searchConfigs {
+	for _, source := range searchConfigs {
 		wg.Add(1)
-		go func(platformName string, cfg collector.CollectConfig) {
+		go func(source sources.Source) {
 			defer wg.Done()
 
 			// 获取信号量
@@ -267,14 +298,17 @@ func (t *TopicSearchService) searchAcrossPlatforms(ctx context.Context, params T
 			platformCtx, cancel := context.WithTimeout(ctx, 20*time.Second)
 			defer cancel()
 
-			t.searchSinglePlatform(platformCtx, platformName, cfg, params, results)
-		}(platform, config)
+			t.searchSinglePlatform(platformCtx, source, params, results)
+		}(source)
 	}
 
 	wg.Wait()
 
-	log.Printf("搜索完成，结果统计 - 文章: %d, 仓库: %d, 讨论: %d",
-		len(results.Articles), len(results.Repositories), len(results.Discussions))
+	t.logger.DebugContext(ctx, "搜索完成",
+		slog.Int("articles", len(results.Articles)),
+		slog.Int("repositories", len(results.Repositories)),
+		slog.Int("discussions", len(results.Discussions)),
+	)
 
 	return results, nil
 }
@@ -284,6 +318,7 @@ type multiPlatformResults struct {
 	Articles     []models.Article    `json:"articles"`
 	Repositories []models.Repository `json:"repositories"`
 	Discussions  []Discussion        `json:"discussions"`
+	Errors       []SourceError       `json:"errors"`
 	mu           sync.Mutex
 }
 
@@ -308,85 +343,92 @@ func (m *multiPlatformResults) addDiscussion(discussion Discussion) {
 	m.Discussions = append(m.Discussions, discussion)
 }
 
-// getSearchConfigs 获取搜索配置
-func (t *TopicSearchService) getSearchConfigs(params TopicSearchParams) map[string]collector.CollectConfig {
-	configs := make(map[string]collector.CollectConfig)
-
-	// GitHub搜索配置
-	if params.Platform == "" || params.Platform == "github" {
-		if params.SearchType == "all" || params.SearchType == "repositories" {
-			configs["github_repos"] = collector.CollectConfig{
-				URL: fmt.Sprintf("https://api.github.com/search/repositories?q=%s+language:%s&sort=stars&order=desc&per_page=30",
-					params.Query, getLanguageParam(params.Language)),
-				Headers: map[string]string{
-					"Accept":     "application/vnd.github.v3+json",
-					"User-Agent": "FrontendNews-MCP/1.0",
-				},
-				Timeout: 25 * time.Second, // 增加超时时间
-			}
+// addError 线程安全记录失败的数据源
+func (m *multiPlatformResults) addError(source string, err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Errors = append(m.Errors, newSourceError(source, err))
+}
+
+// selectSources 从数据源注册表选出与平台和搜索类型匹配的数据源
+func (t *TopicSearchService) selectSources(params TopicSearchParams) []sources.Source {
+	var selected []sources.Source
+	for _, source := range t.sourceRegistry.ForTool(sources.ToolTopicSearch) {
+		if params.Platform != "" && params.Platform != source.Platform {
+			continue
 		}
+		if params.SearchType != "all" && params.SearchType != source.ResultKind() {
+			continue
+		}
+		selected = append(selected, source)
 	}
+	return selected
+}
 
-	// Dev.to搜索配置
-	if params.Platform == "" || params.Platform == "dev.to" {
-		if params.SearchType == "all" || params.SearchType == "articles" {
-			// Dev.to API不支持query参数，所以使用相关标签
-			tag := params.Query
-			if tag == "react" {
-				tag = "react"
-			} else if tag == "vue" {
-				tag = "vue"
-			} else if tag == "angular" {
-				tag = "angular"
-			} else {
-				tag = "javascript" // 默认使用javascript标签
-			}
+// getSearchConfigs 为所选数据源填充搜索参数
+func (t *TopicSearchService) getSearchConfigs(params TopicSearchParams, selected []sources.Source) map[string]sources.Source {
+	configs := make(map[string]sources.Source, len(selected))
 
-			configs["devto"] = collector.CollectConfig{
-				URL: fmt.Sprintf("https://dev.to/api/articles?tag=%s&per_page=30", tag),
-				Headers: map[string]string{
-					"User-Agent": "FrontendNews-MCP/1.0",
-				},
-				Timeout: 25 * time.Second, // 增加超时时间
-			}
-		}
+	vars := map[string]string{
+		"query":      params.Query,
+		"language":   getLanguageParam(params.Language),
+		"tag":        devToTagForQuery(params.Query),
+		"time_range": params.TimeRange,
+	}
+
+	for _, source := range selected {
+		source.Config = source.Resolve(vars)
+		configs[source.Name] = source
 	}
 
 	return configs
 }
 
-// searchSinglePlatform 搜索单个平台
-func (t *TopicSearchService) searchSinglePlatform(ctx context.Context, platform string, config collector.CollectConfig, params TopicSearchParams, results *multiPlatformResults) {
+// devToTagForQuery Dev.to API不支持query参数，所以将查询映射为相关标签
+func devToTagForQuery(query string) string {
+	switch query {
+	case "react", "vue", "angular":
+		return query
+	default:
+		return "javascript" // 默认使用javascript标签
+	}
+}
+
+// searchSinglePlatform 搜索单个数据源
+func (t *TopicSearchService) searchSinglePlatform(ctx context.Context, source sources.Source, params TopicSearchParams, results *multiPlatformResults) {
 	// 使用defer处理panic，确保其他平台不受影响
 	defer func() {
 		if r := recover(); r != nil {
-			log.Printf("平台 %s 搜索发生panic: %v", platform, r)
+			t.logger.ErrorContext(ctx, "平台搜索发生panic", logging.Source(source.Name), slog.Any("panic", r))
 		}
 	}()
 
-	// 根据平台类型处理不同的响应
-	switch {
-	case strings.Contains(platform, "github_repos"):
-		t.handleGitHubRepos(ctx, config, params, results)
-	case strings.Contains(platform, "devto"):
-		t.handleDevTo(ctx, config, params, results)
+	// 根据数据源的结果类型处理不同的响应
+	switch source.ResultKind() {
+	case sources.KindRepositories:
+		t.handleRepositorySource(ctx, source, params, results)
+	case sources.KindArticles:
+		t.handleArticleSource(ctx, source, params, results)
+	case sources.KindDiscussions:
+		t.handleDiscussionSource(ctx, source, params, results)
 	default:
-		log.Printf("平台 %s 暂未实现，跳过", platform)
+		t.logger.DebugContext(ctx, "数据源的结果类型暂未实现，跳过", logging.Source(source.Name), slog.String("kind", source.ResultKind()))
 	}
 }
 
-// handleGitHubRepos 处理GitHub仓库搜索
-func (t *TopicSearchService) handleGitHubRepos(ctx context.Context, config collector.CollectConfig, params TopicSearchParams, results *multiPlatformResults) {
+// handleRepositorySource 处理返回仓库信息的数据源（如GitHub仓库搜索）
+func (t *TopicSearchService) handleRepositorySource(ctx context.Context, source sources.Source, params TopicSearchParams, results *multiPlatformResults) {
 	// 使用collector进行API调用
-	resultList := (*t.collectorMgr).CollectAll(ctx, []collector.CollectConfig{config})
+	resultList := (*t.collectorMgr).CollectAll(ctx, []collector.CollectConfig{source.Config})
 	if len(resultList) == 0 {
-		log.Printf("GitHub仓库搜索失败: 没有返回结果")
+		t.logger.WarnContext(ctx, "仓库搜索没有返回结果", logging.Source(source.Name))
 		return
 	}
 
 	result := resultList[0]
 	if result.Error != nil {
-		log.Printf("GitHub仓库搜索失败: %v", result.Error)
+		t.logger.WarnContext(ctx, "仓库搜索失败", logging.Source(source.Name), logging.Err(result.Error))
+		results.addError(source.Name, result.Error)
 		return
 	}
 
@@ -396,32 +438,58 @@ func (t *TopicSearchService) handleGitHubRepos(ctx context.Context, config colle
 		results.addRepository(repo)
 	}
 
-	log.Printf("GitHub仓库搜索完成，找到 %d 个仓库", len(result.Articles))
+	t.logger.DebugContext(ctx, "仓库搜索完成", logging.Source(source.Name), slog.Int("repositories", len(result.Articles)))
 }
 
-// handleDevTo 处理Dev.to搜索
-func (t *TopicSearchService) handleDevTo(ctx context.Context, config collector.CollectConfig, params TopicSearchParams, results *multiPlatformResults) {
+// handleArticleSource 处理返回文章的数据源（如Dev.to）
+func (t *TopicSearchService) handleArticleSource(ctx context.Context, source sources.Source, params TopicSearchParams, results *multiPlatformResults) {
 	// 使用collector进行API调用
-	resultList := (*t.collectorMgr).CollectAll(ctx, []collector.CollectConfig{config})
+	resultList := (*t.collectorMgr).CollectAll(ctx, []collector.CollectConfig{source.Config})
+
+	if len(resultList) == 0 {
+		t.logger.WarnContext(ctx, "搜索没有返回结果", logging.Source(source.Name))
+		return
+	}
+
+	result := resultList[0]
+	if result.Error != nil {
+		t.logger.WarnContext(ctx, "搜索失败", logging.Source(source.Name), logging.Err(result.Error))
+		results.addError(source.Name, result.Error)
+		return
+	}
+
+	// 将collector.Article转换为models.Article，内容过短的文章抓取原文补全后添加到结果中
+	articles := make([]models.Article, 0, len(result.Articles))
+	for _, collectorArticle := range result.Articles {
+		articles = append(articles, convertCollectorToModelArticle(collectorArticle))
+	}
+	for _, article := range t.processor.EnrichArticles(ctx, articles) {
+		results.addArticle(article)
+	}
+
+	t.logger.DebugContext(ctx, "搜索完成", logging.Source(source.Name), slog.Int("articles", len(result.Articles)))
+}
 
+// handleDiscussionSource 处理返回讨论的数据源（如Reddit）
+func (t *TopicSearchService) handleDiscussionSource(ctx context.Context, source sources.Source, params TopicSearchParams, results *multiPlatformResults) {
+	resultList := (*t.collectorMgr).CollectAll(ctx, []collector.CollectConfig{source.Config})
 	if len(resultList) == 0 {
-		log.Printf("Dev.to搜索失败: 没有返回结果")
+		t.logger.WarnContext(ctx, "讨论搜索没有返回结果", logging.Source(source.Name))
 		return
 	}
 
 	result := resultList[0]
 	if result.Error != nil {
-		log.Printf("Dev.to搜索失败: %v", result.Error)
+		t.logger.WarnContext(ctx, "讨论搜索失败", logging.Source(source.Name), logging.Err(result.Error))
+		results.addError(source.Name, result.Error)
 		return
 	}
 
-	// 将collector.Article转换为models.Article并添加到结果中
 	for _, collectorArticle := range result.Articles {
-		modelArticle := convertCollectorToModelArticle(collectorArticle)
-		results.addArticle(modelArticle)
+		results.addDiscussion(convertArticleToDiscussion(collectorArticle, source.Platform, params.IncludeCode))
 	}
 
-	log.Printf("Dev.to搜索完成，找到 %d 篇文章", len(result.Articles))
+	t.logger.DebugContext(ctx, "讨论搜索完成", logging.Source(source.Name), slog.Int("discussions", len(result.Articles)))
 }
 
 // processSearchResults 处理搜索结果
@@ -432,8 +500,14 @@ func (t *TopicSearchService) processSearchResults(searchResults *multiPlatformRe
 		Articles:     searchResults.Articles,
 		Repositories: searchResults.Repositories,
 		Discussions:  searchResults.Discussions,
+		Errors:       searchResults.Errors,
 	}
 
+	// 各平台并发搜索，按数据源名称排序使输出稳定
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Source < result.Errors[j].Source
+	})
+
 	// 计算相关性分数
 	t.calculateRelevanceScores(result, params)
 
@@ -444,7 +518,7 @@ func (t *TopicSearchService) processSearchResults(searchResults *multiPlatformRe
 	t.sortResults(result, params.SortBy)
 
 	// 限制结果数量
-	t.limitResults(result, params.MaxResults)
+	t.limitResults(result, params.MaxResults, params.SearchType)
 
 	// 生成统计摘要
 	result.Summary = t.generateSearchSummary(result)
@@ -784,12 +858,20 @@ func (t *TopicSearchService) sortResults(result *TopicSearchResult, sortBy strin
 	}
 }
 
-// limitResults 限制结果数量
-func (t *TopicSearchService) limitResults(result *TopicSearchResult, maxResults int) {
+// limitResults 限制结果数量，只搜索一种类型时该类型可使用全部名额
+func (t *TopicSearchService) limitResults(result *TopicSearchResult, maxResults int, searchType string) {
 	// 按比例分配各类型结果数量
 	articlesLimit := maxResults / 3
 	reposLimit := maxResults / 3
 	discussionsLimit := maxResults - articlesLimit - reposLimit
+	switch searchType {
+	case sources.KindArticles:
+		articlesLimit = maxResults
+	case sources.KindRepositories:
+		reposLimit = maxResults
+	case sources.KindDiscussions:
+		discussionsLimit = maxResults
+	}
 
 	if len(result.Articles) > articlesLimit {
 		result.Articles = result.Articles[:articlesLimit]
@@ -907,22 +989,50 @@ func (t *TopicSearchService) calculatePlatformInfo(result *TopicSearchResult) []
 
 // FormatResult 格式化结果输出
 func (t *TopicSearchService) FormatResult(result *TopicSearchResult, format string) (string, error) {
-	// 设置格式化配置
-	config := formatter.DefaultConfig()
+	// 使用配置文件中的格式化默认值，只覆盖输出格式
+	config := t.formatterFactory.GetConfig()
 	config.Format = formatter.OutputFormat(format)
-	config.IncludeMetadata = true
-	config.MaxSummaryLength = 150
 
-	t.formatterFactory.UpdateConfig(config)
+	// 创建格式化器（不修改共享的工厂配置）
+	fmt := formatter.NewFormatter(&config)
 
-	// 创建格式化器
-	fmt, err := t.formatterFactory.CreateFormatter()
+	// 格式化混合结果
+	output, err := fmt.FormatMixed(result.Articles, result.Repositories)
 	if err != nil {
 		return "", err
 	}
+	output = appendDiscussions(output, result.Discussions, format)
+	output = appendSourceErrors(output, result.Errors, format)
+	return appendCacheNote(output, result.Cache), nil
+}
 
-	// 格式化混合结果
-	return fmt.FormatMixed(result.Articles, result.Repositories)
+// appendDiscussions 在格式化结果后追加讨论列表，格式化器只处理文章和仓库
+func appendDiscussions(output string, discussions []Discussion, format string) string {
+	if len(discussions) == 0 {
+		return output
+	}
+
+	var b strings.Builder
+	b.WriteString(output)
+	if format == "markdown" {
+		fmt.Fprintf(&b, "\n\n## Discussions (%d)\n", len(discussions))
+	} else {
+		fmt.Fprintf(&b, "\n\nDISCUSSIONS (%d)\n", len(discussions))
+	}
+
+	for i, discussion := range discussions {
+		if format == "markdown" {
+			fmt.Fprintf(&b, "\n### %d. [%s](%s)\n\n", i+1, discussion.Title, discussion.URL)
+			fmt.Fprintf(&b, "**%s** · %d points · %d replies · by %s\n", discussion.Platform, discussion.Score, discussion.Replies, discussion.Author)
+			for _, block := range discussion.CodeBlocks {
+				fmt.Fprintf(&b, "\n```%s\n%s\n```\n", block.Language, block.Code)
+			}
+		} else {
+			fmt.Fprintf(&b, "\n%d. %s\n   %s | %d points | %d replies | by %s\n   %s\n",
+				i+1, discussion.Title, discussion.Platform, discussion.Score, discussion.Replies, discussion.Author, discussion.URL)
+		}
+	}
+	return b.String()
 }
 
 // 辅助函数
@@ -999,6 +1109,108 @@ func convertArticleToRepository(article collector.Article) models.Repository {
 	return repo
 }
 
+// convertArticleToDiscussion 转换Article到Discussion（用于Reddit、Stack Overflow、Hacker News等讨论数据）
+func convertArticleToDiscussion(article collector.Article, platform string, includeCode bool) Discussion {
+	discussion := Discussion{
+		ID:        article.ID,
+		Title:     article.Title,
+		URL:       article.URL,
+		Platform:  platform,
+		Author:    article.Author,
+		CreatedAt: article.PublishedAt,
+		Tags:      article.Tags,
+		Content:   article.Content,
+		Relevance: 0.5,
+		Metadata:  convertStringMapToInterface(article.Metadata),
+	}
+
+	// 从metadata中获取得分和回复数，问答平台以回答数作为回复数
+	if score, err := parseIntFromString(article.Metadata["score"]); err == nil {
+		discussion.Score = score
+	} else if points, err := parseIntFromString(article.Metadata["points"]); err == nil {
+		discussion.Score = points
+	}
+	if replies, err := parseIntFromString(article.Metadata["num_comments"]); err == nil {
+		discussion.Replies = replies
+	} else if answers, err := parseIntFromString(article.Metadata["answer_count"]); err == nil {
+		discussion.Replies = answers
+	}
+
+	// 链接帖子的 URL 是原文，讨论应指向讨论页
+	if discussionURL := article.Metadata["discussion_url"]; discussionURL != "" {
+		discussion.URL = discussionURL
+	}
+
+	if includeCode {
+		discussion.CodeBlocks = extractCodeBlocks(article.Content)
+	}
+
+	return discussion
+}
+
+// extractCodeBlocks 提取Markdown中的代码块，包括 ``` 或 ~~~ 围起的代码块和缩进四个空格的代码块，
+// Context 为代码块之前最近的一行文字
+func extractCodeBlocks(markdown string) []CodeBlock {
+	var blocks []CodeBlock
+	var code []string
+	var fence, language, lastText string
+	indented := false
+	previousBlank := true
+
+	flush := func() {
+		text := strings.Trim(strings.Join(code, "\n"), "\n")
+		if strings.TrimSpace(text) != "" {
+			blocks = append(blocks, CodeBlock{Language: language, Code: text, Context: lastText})
+		}
+		code, fence, language, indented = nil, "", "", false
+	}
+
+	for _, line := range strings.Split(strings.ReplaceAll(markdown, "\r\n", "\n"), "\n") {
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case fence != "":
+			if strings.HasPrefix(trimmed, fence) && strings.Trim(trimmed, fence[:1]) == "" {
+				flush()
+			} else {
+				code = append(code, line)
+			}
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			if indented {
+				flush()
+			}
+			marker := trimmed[:3]
+			fence = marker
+			if fields := strings.Fields(strings.TrimLeft(trimmed, marker[:1])); len(fields) > 0 {
+				language = strings.ToLower(fields[0])
+			}
+		case (indented || previousBlank) && (strings.HasPrefix(line, "    ") || strings.HasPrefix(line, "\t")):
+			indented = true
+			code = append(code, strings.TrimPrefix(strings.TrimPrefix(line, "\t"), "    "))
+		case indented && trimmed == "":
+			code = append(code, "")
+		default:
+			if indented {
+				flush()
+			}
+			if trimmed != "" {
+				lastText = trimmed
+				if runes := []rune(trimmed); len(runes) > 200 {
+					lastText = string(runes[:200])
+				}
+			}
+		}
+		previousBlank = trimmed == ""
+	}
+
+	// 未闭合的代码块保留到文末
+	if fence != "" || indented {
+		flush()
+	}
+
+	return blocks
+}
+
 // convertStringMapToInterface 转换map[string]string到map[string]interface{}
 func convertStringMapToInterface(stringMap map[string]string) map[string]interface{} {
 	interfaceMap := make(map[string]interface{})
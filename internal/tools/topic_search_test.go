@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+)
+
+func TestExtractCodeBlocks(t *testing.T) {
+	markdown := strings.Join([]string{
+		"My effect runs twice:",
+		"",
+		"```JSX title=App.jsx",
+		"useEffect(() => {",
+		"  console.log('mount')",
+		"}, [])",
+		"```",
+		"",
+		"Old reddit style:",
+		"",
+		"    const a = 1;",
+		"",
+		"    const b = 2;",
+		"Back to text.",
+		"~~~",
+		"unclosed",
+	}, "\n")
+
+	blocks := extractCodeBlocks(markdown)
+	if len(blocks) != 3 {
+		t.Fatalf("Expected 3 code blocks, got %d: %+v", len(blocks), blocks)
+	}
+
+	if blocks[0].Language != "jsx" || blocks[0].Context != "My effect runs twice:" {
+		t.Errorf("Unexpected fenced block: %+v", blocks[0])
+	}
+	if blocks[0].Code != "useEffect(() => {\n  console.log('mount')\n}, [])" {
+		t.Errorf("Expected code without fences, got %q", blocks[0].Code)
+	}
+	if blocks[1].Code != "const a = 1;\n\nconst b = 2;" || blocks[1].Context != "Old reddit style:" {
+		t.Errorf("Unexpected indented block: %+v", blocks[1])
+	}
+	if blocks[2].Code != "unclosed" || blocks[2].Language != "" {
+		t.Errorf("Expected unclosed fence to run to the end, got %+v", blocks[2])
+	}
+
+	if blocks := extractCodeBlocks("Just text\n  - a list item"); len(blocks) != 0 {
+		t.Errorf("Expected no code blocks, got %+v", blocks)
+	}
+}
+
+func TestConvertArticleToDiscussion(t *testing.T) {
+	created := time.Date(2024, 3, 4, 5, 6, 7, 0, time.UTC)
+	article := collector.Article{
+		ID:          "abc123",
+		Title:       "useEffect runs twice",
+		Content:     "Why?\n\n```js\nuseEffect(fn, [])\n```",
+		Author:      "alice",
+		URL:         "https://www.reddit.com/r/reactjs/comments/abc123/",
+		PublishedAt: created,
+		Tags:        []string{"reactjs"},
+		Metadata: map[string]string{
+			"score":        "42",
+			"num_comments": "17",
+			"subreddit":    "reactjs",
+		},
+	}
+
+	discussion := convertArticleToDiscussion(article, "reddit", true)
+	if discussion.Platform != "reddit" || discussion.Score != 42 || discussion.Replies != 17 {
+		t.Errorf("Expected platform, score and replies, got %+v", discussion)
+	}
+	if !discussion.CreatedAt.Equal(created) || discussion.Author != "alice" {
+		t.Errorf("Expected author and creation time, got %+v", discussion)
+	}
+	if len(discussion.CodeBlocks) != 1 || discussion.CodeBlocks[0].Language != "js" {
+		t.Errorf("Expected one js code block, got %+v", discussion.CodeBlocks)
+	}
+	if discussion.Metadata["subreddit"] != "reactjs" {
+		t.Errorf("Expected metadata to be kept, got %v", discussion.Metadata)
+	}
+
+	if withoutCode := convertArticleToDiscussion(article, "reddit", false); len(withoutCode.CodeBlocks) != 0 {
+		t.Errorf("Expected no code blocks when includeCode is false, got %+v", withoutCode.CodeBlocks)
+	}
+
+	story := collector.Article{
+		URL:      "https://example.com/post",
+		Metadata: map[string]string{"points": "312", "num_comments": "140", "discussion_url": "https://news.ycombinator.com/item?id=1"},
+	}
+	if discussion := convertArticleToDiscussion(story, "hackernews", true); discussion.Score != 312 || discussion.Replies != 140 || discussion.URL != "https://news.ycombinator.com/item?id=1" {
+		t.Errorf("Expected points, comments and discussion URL, got %+v", discussion)
+	}
+
+	question := collector.Article{Metadata: map[string]string{"score": "5", "answer_count": "3"}}
+	if discussion := convertArticleToDiscussion(question, "stackoverflow", true); discussion.Replies != 3 {
+		t.Errorf("Expected answer count as replies, got %d", discussion.Replies)
+	}
+}
+
+func TestLimitResultsBySearchType(t *testing.T) {
+	service := &TopicSearchService{}
+	newResult := func() *TopicSearchResult {
+		return &TopicSearchResult{Discussions: make([]Discussion, 20)}
+	}
+
+	all := newResult()
+	service.limitResults(all, 9, "all")
+	if len(all.Discussions) != 3 {
+		t.Errorf("Expected a third of the results for discussions, got %d", len(all.Discussions))
+	}
+
+	discussions := newResult()
+	service.limitResults(discussions, 9, "discussions")
+	if len(discussions.Discussions) != 9 {
+		t.Errorf("Expected the full budget for discussions, got %d", len(discussions.Discussions))
+	}
+}
@@ -0,0 +1,19 @@
+package tools
+
+import (
+	"context"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/ZephyrDeng/dev-context/internal/tracing"
+)
+
+// startToolSpan 为工具调用创建 span，请求 _meta 中带有 traceparent 时挂在客户端的链路下
+func startToolSpan(ctx context.Context, req *mcp.CallToolRequest, tool string) (context.Context, trace.Span) {
+	if req != nil && req.Params != nil {
+		ctx = tracing.Extract(ctx, req.Params.Meta)
+	}
+	return tracing.Start(ctx, "tool "+tool, attribute.String("mcp.tool", tool))
+}
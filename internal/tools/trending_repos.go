@@ -3,7 +3,7 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"sort"
 	"strconv"
 	"strings"
@@ -13,8 +13,10 @@ import (
 	"github.com/ZephyrDeng/dev-context/internal/cache"
 	"github.com/ZephyrDeng/dev-context/internal/collector"
 	"github.com/ZephyrDeng/dev-context/internal/formatter"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
 	"github.com/ZephyrDeng/dev-context/internal/models"
 	"github.com/ZephyrDeng/dev-context/internal/processor"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
 )
 
 // TrendingReposParams 热门仓库参数
@@ -60,6 +62,8 @@ type TrendingReposResult struct {
 	FilterCount  int                 `json:"filterCount"`
 	UpdatedAt    time.Time           `json:"updatedAt"`
 	Sources      []RepoSource        `json:"sources"`
+	Errors       []SourceError       `json:"errors,omitempty"` // 采集失败的数据源
+	Cache        *CacheInfo          `json:"cache,omitempty"`
 }
 
 // RepoSummary 仓库摘要
@@ -95,6 +99,9 @@ type TrendingReposService struct {
 	collectorMgr     *collector.CollectorManager
 	processor        *processor.Processor
 	formatterFactory *formatter.FormatterFactory
+	sourceRegistry   *sources.Registry
+	cacheTTLs        *cacheTTLs
+	logger           *slog.Logger
 	mu               sync.RWMutex
 }
 
@@ -110,6 +117,9 @@ func NewTrendingReposService(
 		collectorMgr:     collectorMgr,
 		processor:        processor,
 		formatterFactory: formatterFactory,
+		sourceRegistry:   sources.NewDefaultRegistry(),
+		cacheTTLs:        newCacheTTLs(),
+		logger:           slog.Default(),
 	}
 }
 
@@ -120,48 +130,86 @@ func (t *TrendingReposService) GetTrendingRepositories(ctx context.Context, para
 		return nil, fmt.Errorf("参数验证失败: %w", err)
 	}
 
-	// 2. 生成缓存键
-	cacheKey := t.generateCacheKey(params)
+	// 2. 选定数据源并生成缓存键，采集使用同一份数据源快照
+	selected := t.selectSources(params)
+	cacheKey := t.generateCacheKey(params, selected)
 
-	// 3. 检查缓存
-	if cached, found := t.cacheManager.Get(cacheKey); found {
-		if result, ok := cached.(*TrendingReposResult); ok {
-			log.Printf("从缓存返回热门仓库，语言: %s，时间: %s", params.Language, params.TimeRange)
-			return result, nil
-		}
+	// 3. 收集、处理并缓存数据，结果变旧后先返回旧结果并在后台刷新
+	softTTL, hardTTL := t.cacheTTLs.getStale(sources.ToolTrendingRepos)
+	cached, age, err := t.cacheManager.GetOrRevalidate(ctx, cacheKey, softTTL, hardTTL, t.fetch(params, selected))
+	if err != nil {
+		return nil, err
 	}
 
-	// 4. 并发收集多个源的数据
-	repositories, err := t.collectTrendingRepos(ctx, params)
-	if err != nil {
-		return nil, fmt.Errorf("收集热门仓库失败: %w", err)
+	stored, ok := cached.(*TrendingReposResult)
+	if !ok {
+		return nil, fmt.Errorf("缓存中的热门仓库类型错误: %T", cached)
 	}
 
-	// 5. 处理和过滤数据
-	filteredRepos, err := t.processAndFilterRepos(repositories, params)
-	if err != nil {
-		return nil, fmt.Errorf("处理仓库数据失败: %w", err)
+	// 缓存中的结果是共享的，复制后再附加缓存状态
+	result := *stored
+	result.Cache = newCacheInfo(age, softTTL)
+	if result.Cache != nil {
+		t.logger.InfoContext(ctx, "从缓存返回热门仓库",
+			logging.CacheKey(cacheKey),
+			slog.String("language", params.Language),
+			slog.String("time_range", params.TimeRange),
+			slog.Any("cache", result.Cache),
+		)
 	}
 
-	// 6. 构建结果
-	result := &TrendingReposResult{
-		Repositories: filteredRepos,
-		TimeRange:    params.TimeRange,
-		Language:     params.Language,
-		TotalCount:   len(filteredRepos),
-		FilterCount:  len(filteredRepos),
-		UpdatedAt:    time.Now(),
-		Summary:      t.generateRepoSummary(filteredRepos),
-		Sources:      t.calculateRepoSources(filteredRepos),
+	return &result, nil
+}
+
+// RefreshTrendingRepositories 在缓存的热门仓库写入超过 maxAge 时重新采集并写入缓存，供定时刷新在结果变旧前调用。
+// 返回之后缓存结果的年龄
+func (t *TrendingReposService) RefreshTrendingRepositories(ctx context.Context, params TrendingReposParams, maxAge time.Duration) (time.Duration, error) {
+	if err := t.validateParams(&params); err != nil {
+		return 0, fmt.Errorf("参数验证失败: %w", err)
 	}
 
-	// 7. 缓存结果 (缓存15分钟，热门仓库变化较快)
-	t.cacheManager.SetWithTTL(cacheKey, result, 15*time.Minute)
+	selected := t.selectSources(params)
+	cacheKey := t.generateCacheKey(params, selected)
+	_, hardTTL := t.cacheTTLs.getStale(sources.ToolTrendingRepos)
+	return t.cacheManager.Refresh(ctx, cacheKey, hardTTL, maxAge, t.fetch(params, selected))
+}
 
-	log.Printf("成功获取热门仓库 %d 个，语言: %s，时间范围: %s",
-		len(filteredRepos), params.Language, params.TimeRange)
+// fetch 返回采集并处理热门仓库的函数，结果写入缓存
+func (t *TrendingReposService) fetch(params TrendingReposParams, selected []sources.Source) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		started := time.Now()
 
-	return result, nil
+		// 并发收集多个源的数据
+		repositories, sourceErrors, err := t.collectTrendingRepos(ctx, params, selected)
+		if err != nil {
+			return nil, fmt.Errorf("收集热门仓库失败: %w", err)
+		}
+
+		// 处理和过滤数据
+		filteredRepos, err := t.processAndFilterRepos(repositories, params)
+		if err != nil {
+			return nil, fmt.Errorf("处理仓库数据失败: %w", err)
+		}
+
+		t.logger.InfoContext(ctx, "成功获取热门仓库",
+			slog.Int("repositories", len(filteredRepos)),
+			slog.String("language", params.Language),
+			slog.String("time_range", params.TimeRange),
+			logging.Duration(time.Since(started)),
+		)
+
+		return &TrendingReposResult{
+			Repositories: filteredRepos,
+			TimeRange:    params.TimeRange,
+			Language:     params.Language,
+			TotalCount:   len(filteredRepos),
+			FilterCount:  len(filteredRepos),
+			UpdatedAt:    time.Now(),
+			Summary:      t.generateRepoSummary(filteredRepos),
+			Sources:      t.calculateRepoSources(filteredRepos),
+			Errors:       sourceErrors,
+		}, nil
+	}
 }
 
 // validateParams 验证参数并设置默认值
@@ -212,9 +260,9 @@ func (t *TrendingReposService) validateParams(params *TrendingReposParams) error
 	return nil
 }
 
-// generateCacheKey 生成缓存键
-func (t *TrendingReposService) generateCacheKey(params TrendingReposParams) string {
-	return fmt.Sprintf("trending_repos:%s:%s:%d:%d:%s:%t:%t",
+// generateCacheKey 生成缓存键，包含所选数据源的指纹
+func (t *TrendingReposService) generateCacheKey(params TrendingReposParams, selected []sources.Source) string {
+	return fmt.Sprintf("trending_repos:%s:%s:%d:%d:%s:%t:%t:%s",
 		params.Language,
 		params.TimeRange,
 		params.MinStars,
@@ -222,15 +270,17 @@ func (t *TrendingReposService) generateCacheKey(params TrendingReposParams) stri
 		params.Category,
 		params.IncludeForks,
 		params.FrontendOnly,
+		sources.Fingerprint(selected),
 	)
 }
 
-// collectTrendingRepos 收集热门仓库数据
-func (t *TrendingReposService) collectTrendingRepos(ctx context.Context, params TrendingReposParams) ([]models.Repository, error) {
+// collectTrendingRepos 收集热门仓库数据，同时返回采集失败的数据源
+func (t *TrendingReposService) collectTrendingRepos(ctx context.Context, params TrendingReposParams, selected []sources.Source) ([]models.Repository, []SourceError, error) {
 	// 获取数据源配置
-	configs := t.getTrendingConfigs(params)
+	configs := t.getTrendingConfigs(params, selected)
 
 	var allRepos []models.Repository
+	var sourceErrors []SourceError
 	var mu sync.Mutex
 	var wg sync.WaitGroup
 
@@ -242,7 +292,10 @@ func (t *TrendingReposService) collectTrendingRepos(ctx context.Context, params
 
 			repos, err := t.collectFromSource(ctx, sourceName, cfg, params)
 			if err != nil {
-				log.Printf("收集 %s 失败: %v", sourceName, err)
+				t.logger.WarnContext(ctx, "数据源收集失败", logging.Source(sourceName), logging.Err(err))
+				mu.Lock()
+				sourceErrors = append(sourceErrors, newSourceError(sourceName, err))
+				mu.Unlock()
 				return
 			}
 
@@ -257,54 +310,48 @@ func (t *TrendingReposService) collectTrendingRepos(ctx context.Context, params
 	// 去重
 	uniqueRepos := t.deduplicateRepos(allRepos)
 
-	return uniqueRepos, nil
-}
+	// 各数据源并发收集，按名称排序使输出稳定
+	sort.Slice(sourceErrors, func(i, j int) bool {
+		return sourceErrors[i].Source < sourceErrors[j].Source
+	})
 
-// getTrendingConfigs 获取热门仓库数据源配置
-func (t *TrendingReposService) getTrendingConfigs(params TrendingReposParams) map[string]collector.CollectConfig {
-	configs := make(map[string]collector.CollectConfig)
+	return uniqueRepos, sourceErrors, nil
+}
 
-	// GitHub Trending API
-	var githubURL string
-	if params.Language != "" {
-		githubURL = fmt.Sprintf("https://api.github.com/search/repositories?q=language:%s+created:>%s&sort=stars&order=desc&per_page=20",
-			params.Language, t.getDateForTimeRange(params.TimeRange))
-	} else {
-		// 使用JavaScript作为默认前端语言
-		githubURL = fmt.Sprintf("https://api.github.com/search/repositories?q=language:javascript+created:>%s&sort=stars&order=desc&per_page=20",
-			t.getDateForTimeRange(params.TimeRange))
-	}
+// maxTrendingSources 限制数据源数量避免过多并发请求
+const maxTrendingSources = 4
 
-	configs["github_trending"] = collector.CollectConfig{
-		URL: githubURL,
-		Headers: map[string]string{
-			"Accept":     "application/vnd.github.v3+json",
-			"User-Agent": "FrontendNews-MCP/1.0",
-		},
-		Timeout: 15 * time.Second,
-	}
+// selectSources 从数据源注册表选出热门仓库数据源
+func (t *TrendingReposService) selectSources(params TrendingReposParams) []sources.Source {
+	var selected []sources.Source
 
-	// GitHub Topics API (获取特定主题的仓库)
-	frontendTopics := []string{"frontend", "react", "vue", "angular", "javascript", "typescript"}
-	for _, topic := range frontendTopics {
-		if params.Category != "" && topic != params.Category {
+	// 数据源已按权重排序；带分类的数据源只在分类匹配时使用
+	for _, source := range t.sourceRegistry.ForTool(sources.ToolTrendingRepos) {
+		if params.Category != "" && len(source.Categories) > 0 && !source.HasCategory(params.Category) {
 			continue
 		}
-
-		// 限制topics数量避免过多并发请求
-		if len(configs) >= 4 {
+		if len(selected) >= maxTrendingSources {
 			break
 		}
+		selected = append(selected, source)
+	}
 
-		configs[fmt.Sprintf("github_topic_%s", topic)] = collector.CollectConfig{
-			URL: fmt.Sprintf("https://api.github.com/search/repositories?q=topic:%s+created:>%s&sort=stars&order=desc&per_page=15",
-				topic, t.getDateForTimeRange(params.TimeRange)),
-			Headers: map[string]string{
-				"Accept":     "application/vnd.github.v3+json",
-				"User-Agent": "FrontendNews-MCP/1.0",
-			},
-			Timeout: 15 * time.Second,
-		}
+	return selected
+}
+
+// getTrendingConfigs 为所选数据源生成采集配置
+func (t *TrendingReposService) getTrendingConfigs(params TrendingReposParams, selected []sources.Source) map[string]collector.CollectConfig {
+	configs := make(map[string]collector.CollectConfig, len(selected))
+
+	vars := map[string]string{
+		// 使用JavaScript作为默认前端语言
+		"language": getLanguageParam(params.Language),
+		"since":    t.getDateForTimeRange(params.TimeRange),
+		"category": params.Category,
+	}
+
+	for _, source := range selected {
+		configs[source.Name] = source.Resolve(vars)
 	}
 
 	return configs
@@ -397,7 +444,7 @@ func (t *TrendingReposService) convertArticleToRepository(article collector.Arti
 // handleGitHubTrendingAPI 处理GitHub Trending API
 func (t *TrendingReposService) handleGitHubTrendingAPI(ctx context.Context, config collector.CollectConfig, params TrendingReposParams) []models.Repository {
 	// TODO: 实际实现需要调用collector并解析GitHub API响应
-	log.Printf("处理GitHub Trending API")
+	t.logger.DebugContext(ctx, "处理GitHub Trending API", logging.Source(config.Name))
 
 	// 这里应该:
 	// 1. 调用collector获取数据
@@ -410,7 +457,7 @@ func (t *TrendingReposService) handleGitHubTrendingAPI(ctx context.Context, conf
 // handleGitHubTopicAPI 处理GitHub Topic API
 func (t *TrendingReposService) handleGitHubTopicAPI(ctx context.Context, config collector.CollectConfig, params TrendingReposParams) []models.Repository {
 	// TODO: 实际实现需要调用collector并解析GitHub API响应
-	log.Printf("处理GitHub Topic API")
+	t.logger.DebugContext(ctx, "处理GitHub Topic API", logging.Source(config.Name))
 
 	return []models.Repository{} // 暂时返回空数组
 }
@@ -420,8 +467,8 @@ func (t *TrendingReposService) processAndFilterRepos(repositories []models.Repos
 	var filtered []models.Repository
 
 	for _, repo := range repositories {
-		// 星标数过滤
-		if repo.Stars < params.MinStars {
+		// 星标数过滤，npm 包没有星标，不参与该过滤
+		if repo.Stars < params.MinStars && !isNpmPackage(repo) {
 			continue
 		}
 
@@ -442,6 +489,7 @@ func (t *TrendingReposService) processAndFilterRepos(repositories []models.Repos
 
 		// 计算趋势分数
 		repo.CalculateTrendScore()
+		t.applyPackageTrend(&repo)
 
 		// 更新仓库活跃度信息
 		t.updateRepoActivityInfo(&repo)
@@ -460,6 +508,39 @@ func (t *TrendingReposService) processAndFilterRepos(repositories []models.Repos
 	return filtered, nil
 }
 
+// isNpmPackage 判断是否为npm采集器返回的包
+func isNpmPackage(repo models.Repository) bool {
+	isPackage, _ := repo.Metadata["npm_package"].(string)
+	return isPackage == "true"
+}
+
+// applyPackageTrend 以周下载量和下载增长代替星标数计算npm包的趋势分数
+func (t *TrendingReposService) applyPackageTrend(repo *models.Repository) {
+	if !isNpmPackage(*repo) {
+		return
+	}
+
+	if downloads, ok := repo.Metadata["weekly_downloads"].(string); ok {
+		if weekly, err := parseIntFromString(downloads); err == nil {
+			switch {
+			case weekly >= 100000:
+				repo.TrendScore += 0.3
+			case weekly >= 10000:
+				repo.TrendScore += 0.2
+			case weekly > 0:
+				repo.TrendScore += 0.1
+			}
+		}
+	}
+	if fastGrowing, _ := repo.Metadata["fast_growing"].(string); fastGrowing == "true" {
+		repo.TrendScore += 0.2
+	}
+
+	if repo.TrendScore > 1.0 {
+		repo.TrendScore = 1.0
+	}
+}
+
 // isForkRepo 判断是否为Fork仓库
 func (t *TrendingReposService) isForkRepo(repo models.Repository) bool {
 	// 检查metadata中的is_fork字段
@@ -744,19 +825,18 @@ func (t *TrendingReposService) calculateRepoSources(repositories []models.Reposi
 
 // FormatResult 格式化结果输出
 func (t *TrendingReposService) FormatResult(result *TrendingReposResult, format string) (string, error) {
-	// 设置格式化配置
-	config := formatter.DefaultConfig()
+	// 使用配置文件中的格式化默认值，只覆盖输出格式
+	config := t.formatterFactory.GetConfig()
 	config.Format = formatter.OutputFormat(format)
-	config.IncludeMetadata = true
 
-	t.formatterFactory.UpdateConfig(config)
+	// 创建格式化器（不修改共享的工厂配置）
+	fmt := formatter.NewFormatter(&config)
 
-	// 创建格式化器
-	fmt, err := t.formatterFactory.CreateFormatter()
+	// 格式化仓库
+	output, err := fmt.FormatRepositories(result.Repositories)
 	if err != nil {
 		return "", err
 	}
-
-	// 格式化仓库
-	return fmt.FormatRepositories(result.Repositories)
+	output = appendSourceErrors(output, result.Errors, format)
+	return appendCacheNote(output, result.Cache), nil
 }
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/models"
+)
+
+func TestProcessAndFilterReposNpmPackages(t *testing.T) {
+	service := &TrendingReposService{}
+	updated := time.Now().Add(-48 * time.Hour)
+	npmPackage := func(name, weekly, fastGrowing string) models.Repository {
+		return models.Repository{
+			Name:      name,
+			FullName:  name,
+			URL:       "https://www.npmjs.com/package/" + name,
+			Language:  "JavaScript",
+			UpdatedAt: updated,
+			Metadata: map[string]interface{}{
+				"npm_package":      "true",
+				"weekly_downloads": weekly,
+				"fast_growing":     fastGrowing,
+			},
+		}
+	}
+
+	repos := []models.Repository{
+		npmPackage("steady-lib", "50000", "false"),
+		npmPackage("rising-lib", "50000", "true"),
+		{Name: "tiny", FullName: "someone/tiny", URL: "https://github.com/someone/tiny", Stars: 1, UpdatedAt: updated},
+	}
+
+	filtered, err := service.processAndFilterRepos(repos, TrendingReposParams{MinStars: 5, MaxResults: 30, SortBy: "trending"})
+	if err != nil {
+		t.Fatalf("processAndFilterRepos failed: %v", err)
+	}
+	if len(filtered) != 2 {
+		t.Fatalf("Expected npm packages to skip the star filter, got %d repositories", len(filtered))
+	}
+	if filtered[0].Name != "rising-lib" || filtered[0].TrendScore <= filtered[1].TrendScore {
+		t.Errorf("Expected fast growing package to rank first, got %s (%.2f) and %s (%.2f)",
+			filtered[0].Name, filtered[0].TrendScore, filtered[1].Name, filtered[1].TrendScore)
+	}
+}
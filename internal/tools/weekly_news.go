@@ -3,8 +3,9 @@ package tools
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"sort"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -12,8 +13,10 @@ import (
 	"github.com/ZephyrDeng/dev-context/internal/cache"
 	"github.com/ZephyrDeng/dev-context/internal/collector"
 	"github.com/ZephyrDeng/dev-context/internal/formatter"
+	"github.com/ZephyrDeng/dev-context/internal/logging"
 	"github.com/ZephyrDeng/dev-context/internal/models"
 	"github.com/ZephyrDeng/dev-context/internal/processor"
+	"github.com/ZephyrDeng/dev-context/internal/sources"
 )
 
 // WeeklyNewsParams 周报新闻参数
@@ -49,11 +52,14 @@ type WeeklyNewsParams struct {
 // WeeklyNewsResult 周报新闻结果
 type WeeklyNewsResult struct {
 	Articles    []models.Article `json:"articles"`
+	Releases    []models.Article `json:"releases,omitempty"` // 期间发布的框架版本
 	Summary     string           `json:"summary"`
 	Period      Period           `json:"period"`
 	TotalCount  int              `json:"totalCount"`
 	FilterCount int              `json:"filterCount"`
 	Sources     []SourceInfo     `json:"sources"`
+	Errors      []SourceError    `json:"errors,omitempty"` // 采集失败的数据源
+	Cache       *CacheInfo       `json:"cache,omitempty"`
 }
 
 // Period 时间范围信息
@@ -76,6 +82,9 @@ type WeeklyNewsService struct {
 	collectorMgr     *collector.CollectorManager
 	processor        *processor.Processor
 	formatterFactory *formatter.FormatterFactory
+	sourceRegistry   *sources.Registry
+	cacheTTLs        *cacheTTLs
+	logger           *slog.Logger
 	mu               sync.RWMutex
 }
 
@@ -91,6 +100,9 @@ func NewWeeklyNewsService(
 		collectorMgr:     collectorMgr,
 		processor:        processor,
 		formatterFactory: formatterFactory,
+		sourceRegistry:   sources.NewDefaultRegistry(),
+		cacheTTLs:        newCacheTTLs(),
+		logger:           slog.Default(),
 	}
 }
 
@@ -107,46 +119,96 @@ func (w *WeeklyNewsService) GetWeeklyFrontendNews(ctx context.Context, params We
 		return nil, fmt.Errorf("时间范围解析失败: %w", err)
 	}
 
-	// 3. 生成缓存键
-	cacheKey := w.generateCacheKey(params, period)
+	// 3. 选定数据源并生成缓存键，采集使用同一份数据源快照
+	selected := w.selectSources(params.Sources)
+	cacheKey := w.generateCacheKey(params, period, selected)
 
-	// 4. 检查缓存
-	if cached, found := w.cacheManager.Get(cacheKey); found {
-		if result, ok := cached.(*WeeklyNewsResult); ok {
-			log.Printf("从缓存返回周报新闻，期间: %s 到 %s", period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"))
-			return result, nil
-		}
+	// 4. 收集、处理并缓存数据。相同键的并发请求（配置了分布式锁时包括其他实例）只采集一次，
+	//    结果变旧后先返回旧结果并在后台刷新
+	softTTL, hardTTL := w.cacheTTLs.getStale(sources.ToolWeeklyNews)
+	cached, age, err := w.cacheManager.GetOrRevalidate(ctx, cacheKey, softTTL, hardTTL, w.fetch(params, period, selected))
+	if err != nil {
+		return nil, err
 	}
 
-	// 5. 并发收集数据
-	articles, err := w.collectArticles(ctx, period, params)
-	if err != nil {
-		return nil, fmt.Errorf("数据收集失败: %w", err)
+	stored, ok := cached.(*WeeklyNewsResult)
+	if !ok {
+		return nil, fmt.Errorf("缓存中的周报新闻类型错误: %T", cached)
 	}
 
-	// 6. 处理和过滤数据
-	filteredArticles, err := w.processAndFilter(articles, params, period)
-	if err != nil {
-		return nil, fmt.Errorf("数据处理失败: %w", err)
+	// 缓存中的结果是共享的，复制后再附加缓存状态
+	result := *stored
+	result.Cache = newCacheInfo(age, softTTL)
+	if result.Cache != nil {
+		w.logger.InfoContext(ctx, "从缓存返回周报新闻",
+			logging.CacheKey(cacheKey),
+			slog.String("start", period.Start.Format("2006-01-02")),
+			slog.String("end", period.End.Format("2006-01-02")),
+			slog.Any("cache", result.Cache),
+		)
 	}
 
-	// 7. 构建结果
-	result := &WeeklyNewsResult{
-		Articles:    filteredArticles,
-		Period:      *period,
-		TotalCount:  len(articles),
-		FilterCount: len(filteredArticles),
-		Sources:     w.calculateSourceInfo(articles),
-		Summary:     w.generateSummary(filteredArticles, period),
+	return &result, nil
+}
+
+// RefreshWeeklyFrontendNews 在缓存的周报新闻写入超过 maxAge 时重新采集并写入缓存，供定时刷新在结果变旧前调用。
+// 返回之后缓存结果的年龄
+func (w *WeeklyNewsService) RefreshWeeklyFrontendNews(ctx context.Context, params WeeklyNewsParams, maxAge time.Duration) (time.Duration, error) {
+	if err := w.validateParams(&params); err != nil {
+		return 0, fmt.Errorf("参数验证失败: %w", err)
+	}
+	period, err := w.parsePeriod(params.StartDate, params.EndDate)
+	if err != nil {
+		return 0, fmt.Errorf("时间范围解析失败: %w", err)
 	}
 
-	// 8. 缓存结果 (缓存1小时)
-	w.cacheManager.SetWithTTL(cacheKey, result, time.Hour)
+	selected := w.selectSources(params.Sources)
+	cacheKey := w.generateCacheKey(params, period, selected)
+	_, hardTTL := w.cacheTTLs.getStale(sources.ToolWeeklyNews)
+	return w.cacheManager.Refresh(ctx, cacheKey, hardTTL, maxAge, w.fetch(params, period, selected))
+}
+
+// fetch 返回采集并处理周报新闻的函数，结果写入缓存
+func (w *WeeklyNewsService) fetch(params WeeklyNewsParams, period *Period, selected []sources.Source) func(ctx context.Context) (interface{}, error) {
+	return func(ctx context.Context) (interface{}, error) {
+		started := time.Now()
+		articles, sourceErrors, err := w.collectArticles(ctx, period, selected)
+		if err != nil {
+			return nil, fmt.Errorf("数据收集失败: %w", err)
+		}
 
-	log.Printf("成功获取周报新闻 %d 篇，期间: %s 到 %s",
-		len(filteredArticles), period.Start.Format("2006-01-02"), period.End.Format("2006-01-02"))
+		// 版本发布单独列出，不参与新闻的质量过滤和数量限制
+		news, releases := splitReleases(articles)
+		filteredArticles, err := w.processAndFilter(news, params, period)
+		if err != nil {
+			return nil, fmt.Errorf("数据处理失败: %w", err)
+		}
+		releases = w.filterReleases(releases, params, period)
+
+		summary := w.generateSummary(filteredArticles, period)
+		if len(releases) > 0 {
+			summary += fmt.Sprintf(" 期间共有 %d 个框架版本发布。", len(releases))
+		}
 
-	return result, nil
+		w.logger.InfoContext(ctx, "成功获取周报新闻",
+			slog.Int("articles", len(filteredArticles)),
+			slog.Int("releases", len(releases)),
+			slog.String("start", period.Start.Format("2006-01-02")),
+			slog.String("end", period.End.Format("2006-01-02")),
+			logging.Duration(time.Since(started)),
+		)
+
+		return &WeeklyNewsResult{
+			Articles:    filteredArticles,
+			Releases:    releases,
+			Period:      *period,
+			TotalCount:  len(articles),
+			FilterCount: len(filteredArticles),
+			Sources:     w.calculateSourceInfo(articles),
+			Errors:      sourceErrors,
+			Summary:     summary,
+		}, nil
+	}
 }
 
 // validateParams 验证参数并设置默认值
@@ -230,9 +292,9 @@ func (w *WeeklyNewsService) parsePeriod(startDate, endDate string) (*Period, err
 	}, nil
 }
 
-// generateCacheKey 生成缓存键
-func (w *WeeklyNewsService) generateCacheKey(params WeeklyNewsParams, period *Period) string {
-	return fmt.Sprintf("weekly_news:%s:%s:%s:%.1f:%d:%s:%s",
+// generateCacheKey 生成缓存键，包含所选数据源的指纹
+func (w *WeeklyNewsService) generateCacheKey(params WeeklyNewsParams, period *Period, selected []sources.Source) string {
+	return fmt.Sprintf("weekly_news:%s:%s:%s:%.1f:%d:%s:%s:%s",
 		period.Start.Format("2006-01-02"),
 		period.End.Format("2006-01-02"),
 		params.Category,
@@ -240,15 +302,20 @@ func (w *WeeklyNewsService) generateCacheKey(params WeeklyNewsParams, period *Pe
 		params.MaxResults,
 		params.SortBy,
 		params.Sources,
+		sources.Fingerprint(selected),
 	)
 }
 
-// collectArticles 并发收集文章数据
-func (w *WeeklyNewsService) collectArticles(ctx context.Context, period *Period, params WeeklyNewsParams) ([]models.Article, error) {
+// collectArticles 并发收集文章数据，同时返回采集失败的数据源
+func (w *WeeklyNewsService) collectArticles(ctx context.Context, period *Period, selected []sources.Source) ([]models.Article, []SourceError, error) {
 	// 定义前端开发相关的数据源配置
-	configs := w.getFrontendCollectConfigs(period, params.Sources)
+	configs := w.getFrontendCollectConfigs(period, selected)
 
-	log.Printf("开始收集前端新闻数据，配置数量: %d", len(configs))
+	w.logger.DebugContext(ctx, "开始收集前端新闻数据", slog.Int("sources", len(configs)))
+	names := make(map[string]string, len(configs))
+	for _, config := range configs {
+		names[config.URL] = config.Name
+	}
 
 	// 使用collector管理器并发收集数据
 	results := (*w.collectorMgr).CollectAll(ctx, configs)
@@ -256,109 +323,78 @@ func (w *WeeklyNewsService) collectArticles(ctx context.Context, period *Period,
 	// 聚合所有文章
 	var articles []models.Article
 	var errors []error
+	var sourceErrors []SourceError
 
 	for _, result := range results {
 		if result.Error != nil {
 			errors = append(errors, result.Error)
-			log.Printf("数据源 %s 收集失败: %v", result.Source, result.Error)
+			sourceErrors = append(sourceErrors, newSourceError(sourceName(names, result.Source), result.Error))
+			w.logger.WarnContext(ctx, "数据源收集失败", logging.Source(sourceName(names, result.Source)), logging.Err(result.Error))
 		} else {
 			// 转换collector.Article到models.Article
 			for _, collectorArticle := range result.Articles {
 				modelArticle := w.convertToModelArticle(collectorArticle)
 				articles = append(articles, modelArticle)
 			}
-			log.Printf("从 %s 收集到 %d 篇文章", result.Source, len(result.Articles))
+			w.logger.DebugContext(ctx, "数据源收集完成", logging.Source(sourceName(names, result.Source)), slog.Int("articles", len(result.Articles)))
 		}
 	}
 
 	// 如果所有数据源都失败了，返回错误
 	if len(articles) == 0 && len(errors) > 0 {
-		return nil, fmt.Errorf("所有数据源收集失败: %v", errors)
+		return nil, nil, fmt.Errorf("所有数据源收集失败: %v", errors)
 	}
 
 	// 去重
 	uniqueArticles := w.deduplicateArticles(articles)
 
-	log.Printf("收集到 %d 篇文章，去重后 %d 篇", len(articles), len(uniqueArticles))
-	return uniqueArticles, nil
+	// 内容过短的文章抓取原文补全，供摘要和质量评估使用
+	uniqueArticles = w.processor.EnrichArticles(ctx, uniqueArticles)
+
+	w.logger.DebugContext(ctx, "收集完成", slog.Int("articles", len(articles)), slog.Int("unique", len(uniqueArticles)))
+	return uniqueArticles, sourceErrors, nil
 }
 
-// getFrontendCollectConfigs 获取前端相关数据源配置
-func (w *WeeklyNewsService) getFrontendCollectConfigs(period *Period, sources string) []collector.CollectConfig {
-	var configs []collector.CollectConfig
-
-	// 基础前端新闻源
-	frontendSources := map[string]collector.CollectConfig{
-		"dev.to": {
-			URL: "https://dev.to/api/articles?tag=frontend&per_page=30",
-			Headers: map[string]string{
-				"User-Agent": "FrontendNews-MCP/1.0",
-				"Accept":     "application/json",
-			},
-			MaxArticles: 30,
-			Tags:        []string{"frontend", "dev.to"},
-			Metadata: map[string]string{
-				"source_type": "api",
-				"platform":    "dev.to",
-			},
-		},
-		"dev.to-react": {
-			URL: "https://dev.to/api/articles?tag=react&per_page=20",
-			Headers: map[string]string{
-				"User-Agent": "FrontendNews-MCP/1.0",
-				"Accept":     "application/json",
-			},
-			MaxArticles: 20,
-			Tags:        []string{"react", "dev.to"},
-			Metadata: map[string]string{
-				"source_type": "api",
-				"platform":    "dev.to",
-			},
-		},
-		"dev.to-vue": {
-			URL: "https://dev.to/api/articles?tag=vue&per_page=20",
-			Headers: map[string]string{
-				"User-Agent": "FrontendNews-MCP/1.0",
-				"Accept":     "application/json",
-			},
-			MaxArticles: 20,
-			Tags:        []string{"vue", "dev.to"},
-			Metadata: map[string]string{
-				"source_type": "api",
-				"platform":    "dev.to",
-			},
-		},
-		"dev.to-javascript": {
-			URL: "https://dev.to/api/articles?tag=javascript&per_page=25",
-			Headers: map[string]string{
-				"User-Agent": "FrontendNews-MCP/1.0",
-				"Accept":     "application/json",
-			},
-			MaxArticles: 25,
-			Tags:        []string{"javascript", "dev.to"},
-			Metadata: map[string]string{
-				"source_type": "api",
-				"platform":    "dev.to",
-			},
-		},
-	}
-
-	// 如果指定了特定数据源
-	if sources != "" {
-		sourceList := splitAndTrim(sources, ",")
-		for _, sourceName := range sourceList {
-			if config, exists := frontendSources[sourceName]; exists {
-				configs = append(configs, config)
+// sourceName 返回采集结果对应的数据源名称，未找到时返回结果中的地址
+func sourceName(names map[string]string, url string) string {
+	if name := names[url]; name != "" {
+		return name
+	}
+	return url
+}
+
+// selectSources 从数据源注册表选出本次请求使用的周报数据源，sourceNames 为空时使用全部
+func (w *WeeklyNewsService) selectSources(sourceNames string) []sources.Source {
+	frontendSources := w.sourceRegistry.ForTool(sources.ToolWeeklyNews)
+	if sourceNames == "" {
+		return frontendSources
+	}
+
+	var selected []sources.Source
+	for _, sourceName := range splitAndTrim(sourceNames, ",") {
+		for _, source := range frontendSources {
+			if source.Name == sourceName {
+				selected = append(selected, source)
 			}
 		}
-	} else {
-		// 使用所有数据源
-		for _, config := range frontendSources {
-			configs = append(configs, config)
-		}
+	}
+	return selected
+}
+
+// getFrontendCollectConfigs 为所选数据源生成采集配置
+func (w *WeeklyNewsService) getFrontendCollectConfigs(period *Period, selected []sources.Source) []collector.CollectConfig {
+	vars := map[string]string{
+		"since":      period.Start.Format("2006-01-02"),
+		"until":      period.End.Format("2006-01-02"),
+		"since_unix": strconv.FormatInt(period.Start.Unix(), 10),
+		"until_unix": strconv.FormatInt(period.End.Unix(), 10),
+	}
+
+	configs := make([]collector.CollectConfig, 0, len(selected))
+	for _, source := range selected {
+		configs = append(configs, source.Resolve(vars))
 	}
 
-	log.Printf("配置了 %d 个数据源进行采集", len(configs))
 	return configs
 }
 
@@ -367,8 +403,9 @@ func (w *WeeklyNewsService) processAndFilter(articles []models.Article, params W
 	var filtered []models.Article
 
 	for _, article := range articles {
-		// 时间范围过滤
-		if !article.PublishedAt.After(period.Start.Add(-time.Hour)) || !article.PublishedAt.Before(period.End.Add(time.Hour)) {
+		// 时间范围过滤；快速增长的npm包反映的是最近一周的下载量，不按最新版本的发布时间过滤
+		outside := !article.PublishedAt.After(period.Start.Add(-time.Hour)) || !article.PublishedAt.Before(period.End.Add(time.Hour))
+		if outside && !isFastGrowingPackage(article) {
 			continue
 		}
 
@@ -404,6 +441,58 @@ func (w *WeeklyNewsService) processAndFilter(articles []models.Article, params W
 	return filtered, nil
 }
 
+// splitReleases 将采集结果分为新闻和版本发布
+//
+// npm 包只保留新的主版本（列入版本发布）和下载量快速增长的包（作为新闻），其余的包不出现在周报中。
+func splitReleases(articles []models.Article) (news, releases []models.Article) {
+	for _, article := range articles {
+		if release, _ := article.Metadata["github_release"].(string); release == "true" {
+			releases = append(releases, article)
+			continue
+		}
+
+		if npmPackage, _ := article.Metadata["npm_package"].(string); npmPackage == "true" {
+			level, _ := article.Metadata["semver_level"].(string)
+			switch {
+			case level == "major":
+				releases = append(releases, article)
+			case isFastGrowingPackage(article):
+				news = append(news, article)
+			}
+			continue
+		}
+
+		news = append(news, article)
+	}
+	return news, releases
+}
+
+// isFastGrowingPackage 判断是否为下载量快速增长的npm包
+func isFastGrowingPackage(article models.Article) bool {
+	fastGrowing, _ := article.Metadata["fast_growing"].(string)
+	return fastGrowing == "true"
+}
+
+// filterReleases 按时间范围和分类过滤版本发布，按发布时间倒序排列
+func (w *WeeklyNewsService) filterReleases(releases []models.Article, params WeeklyNewsParams, period *Period) []models.Article {
+	var filtered []models.Article
+	for _, release := range releases {
+		if !release.PublishedAt.After(period.Start.Add(-time.Hour)) || !release.PublishedAt.Before(period.End.Add(time.Hour)) {
+			continue
+		}
+		if params.Category != "" && !w.matchesCategory(release, params.Category) {
+			continue
+		}
+		filtered = append(filtered, release)
+	}
+
+	w.sortArticles(filtered, "date")
+	if len(filtered) > params.MaxResults {
+		filtered = filtered[:params.MaxResults]
+	}
+	return filtered
+}
+
 // matchesCategory 检查文章是否匹配指定分类
 func (w *WeeklyNewsService) matchesCategory(article models.Article, category string) bool {
 	// 检查标签
@@ -575,22 +664,55 @@ func (w *WeeklyNewsService) generateSummary(articles []models.Article, period *P
 
 // FormatResult 格式化结果输出
 func (w *WeeklyNewsService) FormatResult(result *WeeklyNewsResult, format string) (string, error) {
-	// 设置格式化配置
-	config := formatter.DefaultConfig()
+	// 使用配置文件中的格式化默认值，只覆盖输出格式
+	config := w.formatterFactory.GetConfig()
 	config.Format = formatter.OutputFormat(format)
-	config.IncludeMetadata = true
-	config.MaxSummaryLength = 200
 
-	w.formatterFactory.UpdateConfig(config)
+	// 创建格式化器（不修改共享的工厂配置）
+	fmt := formatter.NewFormatter(&config)
 
-	// 创建格式化器
-	fmt, err := w.formatterFactory.CreateFormatter()
+	// 格式化文章
+	output, err := fmt.FormatArticles(result.Articles)
 	if err != nil {
 		return "", err
 	}
+	output = appendReleases(output, result.Releases, format)
+	output = appendSourceErrors(output, result.Errors, format)
+	return appendCacheNote(output, result.Cache), nil
+}
 
-	// 格式化文章
-	return fmt.FormatArticles(result.Articles)
+// appendReleases 在格式化结果后追加"本周发布"列表
+func appendReleases(output string, releases []models.Article, format string) string {
+	if len(releases) == 0 {
+		return output
+	}
+
+	var b strings.Builder
+	b.WriteString(output)
+	if format == "markdown" {
+		fmt.Fprintf(&b, "\n\n## What shipped (%d)\n\n", len(releases))
+	} else {
+		fmt.Fprintf(&b, "\n\nWHAT SHIPPED (%d)\n", len(releases))
+	}
+
+	for _, release := range releases {
+		level, _ := release.Metadata["semver_level"].(string)
+		if level == "" {
+			level = "release"
+		}
+		// npm 包的标题只有包名
+		title := release.Title
+		if version, _ := release.Metadata["version"].(string); version != "" && !strings.Contains(title, version) {
+			title += " " + version
+		}
+		date := release.PublishedAt.Format("2006-01-02")
+		if format == "markdown" {
+			fmt.Fprintf(&b, "- [%s](%s) · %s · %s\n", title, release.URL, level, date)
+		} else {
+			fmt.Fprintf(&b, "\n- %s (%s, %s)\n  %s\n", title, level, date, release.URL)
+		}
+	}
+	return b.String()
 }
 
 // 辅助函数
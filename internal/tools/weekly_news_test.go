@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ZephyrDeng/dev-context/internal/collector"
+	"github.com/ZephyrDeng/dev-context/internal/models"
+)
+
+func TestWeeklyNewsReleases(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	period := &Period{Start: start, End: start.AddDate(0, 0, 7), Days: 7}
+	release := func(title, level string, published time.Time, tags ...string) models.Article {
+		return models.Article{
+			Title:       title,
+			URL:         "https://github.com/" + title,
+			PublishedAt: published,
+			Tags:        tags,
+			Metadata:    map[string]interface{}{"github_release": "true", "semver_level": level},
+		}
+	}
+
+	articles := []models.Article{
+		{Title: "React Compiler explained", PublishedAt: start.AddDate(0, 0, 1)},
+		release("vitejs/vite v5.1.0", "minor", start.AddDate(0, 0, 2), "vite"),
+		release("vuejs/core v3.4.21", "patch", start.AddDate(0, 0, 3), "vue"),
+		release("facebook/react v18.2.0", "minor", start.AddDate(0, -1, 0), "react"),
+	}
+
+	news, releases := splitReleases(articles)
+	if len(news) != 1 || len(releases) != 3 {
+		t.Fatalf("Expected 1 news article and 3 releases, got %d and %d", len(news), len(releases))
+	}
+
+	service := &WeeklyNewsService{}
+	shipped := service.filterReleases(releases, WeeklyNewsParams{MaxResults: 50}, period)
+	if len(shipped) != 2 || shipped[0].Title != "vuejs/core v3.4.21" {
+		t.Fatalf("Expected releases in the period, newest first, got %+v", shipped)
+	}
+
+	if vue := service.filterReleases(releases, WeeklyNewsParams{MaxResults: 50, Category: "vue"}, period); len(vue) != 1 {
+		t.Errorf("Expected category filter to apply to releases, got %d", len(vue))
+	}
+
+	output := appendReleases("articles", shipped, "markdown")
+	if !strings.Contains(output, "## What shipped (2)") || !strings.Contains(output, "- [vitejs/vite v5.1.0](https://github.com/vitejs/vite v5.1.0) · minor · 2024-03-03") {
+		t.Errorf("Unexpected markdown output: %q", output)
+	}
+	if output := appendReleases("articles", nil, "markdown"); output != "articles" {
+		t.Errorf("Expected output to be unchanged without releases, got %q", output)
+	}
+}
+
+func TestWeeklyNewsNpmPackages(t *testing.T) {
+	start := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	period := &Period{Start: start, End: start.AddDate(0, 0, 7), Days: 7}
+	npmPackage := func(name, version, level, fastGrowing string, published time.Time) models.Article {
+		return models.Article{
+			Title:       name,
+			URL:         "https://www.npmjs.com/package/" + name,
+			Source:      "npm",
+			Summary:     "A frontend package",
+			Quality:     0.5,
+			PublishedAt: published,
+			Tags:        []string{"npm", "frontend"},
+			Metadata: map[string]interface{}{
+				"npm_package":  "true",
+				"version":      version,
+				"semver_level": level,
+				"fast_growing": fastGrowing,
+			},
+		}
+	}
+
+	news, releases := splitReleases([]models.Article{
+		npmPackage("vite-plugin-new", "2.0.0", "major", "false", start.AddDate(0, 0, 2)),
+		npmPackage("rising-lib", "1.4.2", "patch", "true", start.AddDate(0, -2, 0)),
+		npmPackage("steady-lib", "3.1.4", "patch", "false", start.AddDate(0, 0, 3)),
+	})
+	if len(releases) != 1 || releases[0].Title != "vite-plugin-new" {
+		t.Errorf("Expected new major as a release, got %+v", releases)
+	}
+	if len(news) != 1 || news[0].Title != "rising-lib" {
+		t.Fatalf("Expected only the fast growing package as news, got %+v", news)
+	}
+
+	service := &WeeklyNewsService{}
+	filtered, err := service.processAndFilter(news, WeeklyNewsParams{MinQuality: 0.1, MaxResults: 50, SortBy: "date"}, period)
+	if err != nil || len(filtered) != 1 {
+		t.Errorf("Expected fast growing package regardless of publish date, got %d (%v)", len(filtered), err)
+	}
+
+	if output := appendReleases("", releases, "markdown"); !strings.Contains(output, "[vite-plugin-new 2.0.0]") {
+		t.Errorf("Expected version after package name, got %q", output)
+	}
+}
+
+func TestWeeklyNewsSourceErrors(t *testing.T) {
+	rateLimited := fmt.Errorf("collection failed after 3 retries: %w", &collector.RateLimitError{
+		RetryAfter: 30 * time.Second,
+		Err:        &collector.HTTPStatusError{StatusCode: 429, Status: "429 Too Many Requests"},
+	})
+	sourceErrors := []SourceError{
+		newSourceError("Reddit r/webdev", rateLimited),
+		newSourceError("CSS-Tricks", &collector.ParseError{What: "feed", Err: errors.New("unexpected EOF")}),
+	}
+
+	if got := sourceErrors[0]; got.Kind != collector.ErrorKindRateLimit || got.StatusCode != 429 || got.RetryAfterSeconds != 30 {
+		t.Errorf("Unexpected rate limit error: %+v", got)
+	}
+	if got := sourceErrors[1]; got.Kind != collector.ErrorKindParse || got.StatusCode != 0 || got.Message != "failed to parse feed: unexpected EOF" {
+		t.Errorf("Unexpected parse error: %+v", got)
+	}
+
+	output := appendSourceErrors("articles", sourceErrors, "markdown")
+	if !strings.Contains(output, "## Failed sources (2)") || !strings.Contains(output, "- **Reddit r/webdev** (rate_limit 429, retry after 30s)") {
+		t.Errorf("Unexpected markdown output: %q", output)
+	}
+	if output := appendSourceErrors("articles", sourceErrors, "text"); !strings.Contains(output, "FAILED SOURCES (2)") || !strings.Contains(output, "- CSS-Tricks (parse)") {
+		t.Errorf("Unexpected text output: %q", output)
+	}
+	if output := appendSourceErrors("articles", nil, "markdown"); output != "articles" {
+		t.Errorf("Expected output to be unchanged without errors, got %q", output)
+	}
+}
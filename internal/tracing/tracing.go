@@ -0,0 +1,134 @@
+// Package tracing 基于 OpenTelemetry 记录工具调用、数据采集、处理和格式化的链路
+//
+// 未配置导出器时使用 OpenTelemetry 默认的空实现，Start 创建的 span 不产生开销；
+// 配置 otlp 后通过 OTLP/HTTP 导出。MCP 客户端可在请求的 _meta 中携带 W3C traceparent，
+// 工具调用的 span 会挂在客户端的链路下。
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName 本项目各组件共用的 tracer 名称
+const instrumentationName = "github.com/ZephyrDeng/dev-context"
+
+// 导出器类型
+const (
+	ExporterNone = "none"
+	ExporterOTLP = "otlp"
+)
+
+// Config 链路追踪配置
+type Config struct {
+	Exporter       string            // none 或 otlp
+	Endpoint       string            // OTLP/HTTP 地址（host:port），为空时使用 OTEL_EXPORTER_OTLP_ENDPOINT 或 localhost:4318
+	Insecure       bool              // 使用 HTTP 而非 HTTPS
+	Headers        map[string]string // 导出请求附加的头，例如认证信息
+	SampleRatio    float64           // 根 span 的采样比例（0-1），已采样的上游链路始终记录
+	ServiceName    string
+	ServiceVersion string
+}
+
+// DefaultConfig 返回默认配置，不导出链路
+func DefaultConfig() *Config {
+	return &Config{
+		Exporter:    ExporterNone,
+		SampleRatio: 1,
+		ServiceName: "dev-context",
+	}
+}
+
+// Setup 按配置设置全局 TracerProvider 和 W3C 上下文传播，返回的函数在退出时导出剩余的 span
+func Setup(ctx context.Context, config *Config) (func(context.Context) error, error) {
+	if config == nil {
+		config = DefaultConfig()
+	}
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{}, propagation.Baggage{},
+	))
+
+	switch config.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterOTLP:
+	default:
+		return nil, fmt.Errorf("unknown trace exporter: %s", config.Exporter)
+	}
+
+	var options []otlptracehttp.Option
+	if config.Endpoint != "" {
+		options = append(options, otlptracehttp.WithEndpoint(config.Endpoint))
+	}
+	if config.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	if len(config.Headers) > 0 {
+		options = append(options, otlptracehttp.WithHeaders(config.Headers))
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(config.ServiceName),
+		semconv.ServiceVersion(config.ServiceVersion),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+// Start 创建子 span，结束时需调用 End
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End 结束 span，err 不为nil时记录错误
+func End(span trace.Span, err error) {
+	RecordError(span, err)
+	span.End()
+}
+
+// RecordError 记录错误并将 span 状态设为 Error，err 为nil时不做处理
+func RecordError(span trace.Span, err error) {
+	if err == nil {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
+
+// Extract 从 MCP 请求的 _meta 中提取 traceparent、tracestate 和 baggage
+func Extract(ctx context.Context, meta map[string]any) context.Context {
+	if len(meta) == 0 {
+		return ctx
+	}
+	carrier := make(propagation.MapCarrier, len(meta))
+	for key, value := range meta {
+		if s, ok := value.(string); ok {
+			carrier[key] = s
+		}
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, carrier)
+}
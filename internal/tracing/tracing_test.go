@@ -0,0 +1,66 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans 将全局 TracerProvider 替换为内存记录器，测试结束后恢复
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func TestExtractContinuesClientTrace(t *testing.T) {
+	if _, err := Setup(context.Background(), DefaultConfig()); err != nil {
+		t.Fatalf("Setup failed: %v", err)
+	}
+	recorder := recordSpans(t)
+
+	meta := map[string]any{
+		"traceparent":   "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"progressToken": 42,
+	}
+	ctx := Extract(context.Background(), meta)
+	ctx, parent := Start(ctx, "tool topic_search")
+	_, child := Start(ctx, "collect github")
+	End(child, errors.New("rate limited"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("Expected 2 spans, got %d", len(spans))
+	}
+	collect, tool := spans[0], spans[1]
+	if got := tool.SpanContext().TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the tool span to continue the client trace, got %s", got)
+	}
+	if got := tool.Parent().SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("Expected the client span as parent, got %s", got)
+	}
+	if collect.Parent().SpanID() != tool.SpanContext().SpanID() {
+		t.Error("Expected the collect span to be a child of the tool span")
+	}
+	if collect.Status().Code != codes.Error || len(collect.Events()) != 1 {
+		t.Errorf("Expected the error to be recorded, got status %v and %d events", collect.Status(), len(collect.Events()))
+	}
+	if tool.Status().Code != codes.Unset {
+		t.Errorf("Expected no error status on the tool span, got %v", tool.Status())
+	}
+}
+
+func TestSetupRejectsUnknownExporter(t *testing.T) {
+	if _, err := Setup(context.Background(), &Config{Exporter: "zipkin"}); err == nil {
+		t.Error("Expected error for unknown exporter")
+	}
+}